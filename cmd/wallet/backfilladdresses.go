@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/usecase"
+
+	"github.com/segmentio/ksuid"
+	"github.com/spf13/cobra"
+)
+
+func backfillAddresses(appProvider AppProvider) *cobra.Command {
+	cliCommand := &cobra.Command{
+		Use:   "backfill-addresses",
+		Short: "Derive the litecoin and dogecoin addresses of the wallets created before those coins were supported",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := helper.ContextWithRequestId(context.Background(), ksuid.New().String())
+
+			app, closeResourcesFn, err := appProvider.BuildContainer(ctx, buildOptions{
+				Postgres: true,
+				Bitcoin:  true,
+			})
+			if err != nil {
+				return err
+			}
+			if closeResourcesFn != nil {
+				defer closeResourcesFn()
+			}
+
+			updated, err := usecase.NewWallet(app).BackfillAddresses(ctx)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Addresses backfilled on %d wallets\n", updated)
+			return nil
+		},
+	}
+	return cliCommand
+}
@@ -31,6 +31,7 @@ func grpc(appProvider AppProvider) *cobra.Command {
 				defer closeResourcesFn()
 			}
 
+			serveMetrics(ctx, cfg)
 			controllergrpc.StartgRPC(app, cfg)
 			return nil
 		},
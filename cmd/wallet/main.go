@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
 	"time"
@@ -12,9 +13,15 @@ import (
 	"github.com/aalexanderkevin/crypto-wallet/repository/gormrepo"
 	"github.com/aalexanderkevin/crypto-wallet/service"
 	"github.com/aalexanderkevin/crypto-wallet/service/btc"
+	"github.com/aalexanderkevin/crypto-wallet/service/chain"
+	"github.com/aalexanderkevin/crypto-wallet/service/confirmation"
 	"github.com/aalexanderkevin/crypto-wallet/service/eth"
+	"github.com/aalexanderkevin/crypto-wallet/service/eventbus"
+	"github.com/aalexanderkevin/crypto-wallet/service/outbox"
+	"github.com/aalexanderkevin/crypto-wallet/service/password"
 	"github.com/aalexanderkevin/crypto-wallet/service/redis"
 	"github.com/aalexanderkevin/crypto-wallet/service/trx"
+	"github.com/aalexanderkevin/crypto-wallet/service/webhook"
 	"github.com/aalexanderkevin/crypto-wallet/storage"
 
 	"github.com/sirupsen/logrus"
@@ -69,6 +76,12 @@ func registerCommands(appProvider AppProvider) *cobra.Command {
 	rootCmd.AddCommand(grpc(appProvider))
 	rootCmd.AddCommand(restapi(appProvider))
 	rootCmd.AddCommand(migrate(appProvider))
+	rootCmd.AddCommand(webhookWorker(appProvider))
+	rootCmd.AddCommand(outboxRelay(appProvider))
+	rootCmd.AddCommand(worker(appProvider))
+	rootCmd.AddCommand(reconcile(appProvider))
+	rootCmd.AddCommand(verifyAudit(appProvider))
+	rootCmd.AddCommand(backfillAddresses(appProvider))
 
 	return rootCmd
 }
@@ -85,6 +98,8 @@ type buildOptions struct {
 	Tron     bool
 	Postgres bool
 	Redis    bool
+	Webhook  bool
+	Outbox   bool
 }
 
 type defaultAppProvider struct {
@@ -92,58 +107,185 @@ type defaultAppProvider struct {
 
 func (defaultAppProvider) BuildContainer(ctx context.Context, options buildOptions) (*container.Container, func(), error) {
 	var ethSvc service.Ethereum
+	var evmSvcs []service.Ethereum
 	var btcSvc service.Bitcoin
 	var trxSvc service.Tron
 	var db *gorm.DB
 	var redisSvc service.Cache
+	var outboxSink service.OutboxSink
+	var eventBus *eventbus.PostgresBus
 
 	cfg := config.Instance()
 
 	// Init app container
 	appContainer := container.NewContainer()
 	appContainer.SetConfig(cfg)
+	appContainer.SetEventBus(eventbus.NewMemoryBus(cfg.EventBus.BufferSize))
+
+	evmNetworks, err := cfg.Evm.GetNetworks()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	confirmationPolicy, err := confirmation.NewPolicy(cfg.Confirmation, evmNetworks...)
+	if err != nil {
+		return nil, nil, err
+	}
+	appContainer.SetConfirmationPolicy(confirmationPolicy)
+
+	// fail at start up rather than at the first login when the password parameters are wrong
+	if _, err := password.NewHasher(cfg.Password); err != nil {
+		return nil, nil, err
+	}
 
 	// Init Postgres
 	if options.Postgres {
 		db = storage.GetPostgresDb()
 		appContainer.SetDb(db)
 
+		userRepo := gormrepo.NewUserRepository(db)
+		appContainer.SetUserRepo(userRepo)
+		refreshTokenRepo := gormrepo.NewRefreshTokenRepository(db)
+		appContainer.SetRefreshTokenRepo(refreshTokenRepo)
+		spendingLimitRepo := gormrepo.NewSpendingLimitRepository(db)
+		appContainer.SetSpendingLimitRepo(spendingLimitRepo)
+		withdrawalAddressRepo := gormrepo.NewWithdrawalAddressRepository(db)
+		appContainer.SetWithdrawalAddressRepo(withdrawalAddressRepo)
+		approvalPolicyRepo := gormrepo.NewApprovalPolicyRepository(db)
+		appContainer.SetApprovalPolicyRepo(approvalPolicyRepo)
+		withdrawalRequestRepo := gormrepo.NewWithdrawalRequestRepository(db)
+		appContainer.SetWithdrawalRequestRepo(withdrawalRequestRepo)
+		auditEventRepo := gormrepo.NewAuditEventRepository(db)
+		appContainer.SetAuditEventRepo(auditEventRepo)
+		walletEventRepo := gormrepo.NewWalletEventRepository(db)
+		appContainer.SetWalletEventRepo(walletEventRepo)
+
+		// the events are published by the worker and the rest api and streamed by the grpc server
+		dbName := cfg.Postgres.Database
+		eventBus = eventbus.NewPostgresBus(walletEventRepo, storage.NewPostgresNotifier(&dbName), cfg.EventBus.BufferSize,
+			time.Duration(cfg.EventBus.PollIntervalSeconds)*time.Second)
+		appContainer.SetEventBus(eventBus)
 		walletRepo := gormrepo.NewWalletRepository(db)
 		appContainer.SetWalletRepo(walletRepo)
 
 		transactionBtcRepo := gormrepo.NewBtcTransactionRepository(db)
 		appContainer.SetTransactionBtcRepo(transactionBtcRepo)
+		appContainer.SetTransactionRepo("ltc", gormrepo.NewUtxoTransactionRepository(db, "ltc"))
+		appContainer.SetTransactionRepo("doge", gormrepo.NewUtxoTransactionRepository(db, "doge"))
 		transactionTrxRepo := gormrepo.NewTrxTransactionRepository(db)
 		appContainer.SetTransactionTrxRepo(transactionTrxRepo)
 		transactionEthRepo := gormrepo.NewEthTransactionRepository(db)
 		appContainer.SetTransactionEthRepo(transactionEthRepo)
+		for _, network := range evmNetworks {
+			appContainer.SetTransactionRepo(network.Chain, gormrepo.NewEvmTransactionRepository(db, network.Chain))
+		}
+
+		webhookEndpointRepo := gormrepo.NewWebhookEndpointRepository(db)
+		appContainer.SetWebhookEndpointRepo(webhookEndpointRepo)
+		webhookDeliveryRepo := gormrepo.NewWebhookDeliveryRepository(db)
+		appContainer.SetWebhookDeliveryRepo(webhookDeliveryRepo)
+
+		outboxRepo := gormrepo.NewOutboxRepository(db)
+		appContainer.SetOutboxRepo(outboxRepo)
+
+		jobRepo := gormrepo.NewJobRepository(db)
+		appContainer.SetJobRepo(jobRepo)
+	}
+
+	if options.Redis {
+		redisSvc = redis.NewRedis(cfg.Redis)
+		appContainer.SetRedis(redisSvc)
 	}
 
 	// Init Service
+	chains := chain.NewRegistry()
+	appContainer.SetChainRegistry(chains)
+
+	// balances and heights are cached when there is a cache to keep them in
+	register := func(adapter service.ChainAdapter, aliases ...string) {
+		if redisSvc != nil {
+			adapter = chain.NewCachedAdapter(adapter, redisSvc, cfg.ChainCache)
+		}
+		chains.Register(adapter, aliases...)
+	}
+
 	if options.Ethereum {
-		ethSvc = eth.NewEthereumImpl(cfg)
+		ethNetwork, err := cfg.Ethereum.Network()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		ethSvc = eth.NewEvmImpl(ethNetwork, cfg.Failover)
 		appContainer.SetEthereum(ethSvc)
+		register(chain.NewEthereumAdapter(ethSvc, ethNetwork), "ethereum")
+
+		// the other EVM networks reuse the address derived for ethereum
+		for _, network := range evmNetworks {
+			evmSvc := eth.NewEvmImpl(network, cfg.Failover)
+			evmSvcs = append(evmSvcs, evmSvc)
+			register(chain.NewEthereumAdapter(evmSvc, network), network.Name)
+		}
 	}
 
 	if options.Bitcoin {
 		btcSvc = btc.NewBitcoinImpl(cfg)
 		appContainer.SetBitcoin(btcSvc)
+		register(chain.NewBitcoinAdapter(btcSvc), "bitcoin")
+
+		ltcSvc := btc.NewLitecoinImpl(cfg)
+		appContainer.SetUtxo(ltcSvc)
+		register(chain.NewLitecoinAdapter(ltcSvc), "litecoin")
+
+		dogeSvc := btc.NewDogecoinImpl(cfg)
+		appContainer.SetUtxo(dogeSvc)
+		register(chain.NewDogecoinAdapter(dogeSvc), "dogecoin")
 	}
 
 	if options.Tron {
-		trxSvc = trx.NewTronImpl(cfg)
+		trxSvc, err = trx.NewTronImpl(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
 		appContainer.SetTron(trxSvc)
+		register(chain.NewTronAdapter(trxSvc), "tron")
 	}
 
-	if options.Redis {
-		redisSvc = redis.NewRedis(cfg.Redis)
-		appContainer.SetRedis(redisSvc)
+	if options.Webhook {
+		appContainer.SetWebhookSender(webhook.NewHttpSender(cfg))
+	}
+
+	if options.Outbox {
+		switch cfg.Outbox.Sink {
+		case "log":
+			outboxSink = outbox.NewLogSink()
+		case "http":
+			outboxSink = outbox.NewHttpSink(cfg)
+		case "nats":
+			publisher, err := outbox.NewNatsPublisher(cfg)
+			if err != nil {
+				return nil, nil, err
+			}
+			outboxSink = outbox.NewBrokerSink(cfg, publisher)
+		default:
+			return nil, nil, fmt.Errorf("unknown outbox sink %q", cfg.Outbox.Sink)
+		}
+		appContainer.SetOutboxSink(outboxSink)
 	}
 
 	deferFn := func() {
 		if ethSvc != nil {
 			ethSvc.Close()
 		}
+		for _, evmSvc := range evmSvcs {
+			evmSvc.Close()
+		}
+		if trxSvc != nil {
+			trxSvc.Close()
+		}
+
+		if eventBus != nil {
+			eventBus.Close()
+		}
 
 		if db != nil {
 			storage.CloseDB(db)
@@ -153,6 +295,10 @@ func (defaultAppProvider) BuildContainer(ctx context.Context, options buildOptio
 			redisSvc.Close()
 		}
 
+		if outboxSink != nil {
+			outboxSink.Close()
+		}
+
 	}
 
 	return appContainer, deferFn, nil
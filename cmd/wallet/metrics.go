@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"expvar"
+	"net/http"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+)
+
+// serveMetrics serves the expvar metrics, among them the status of the chain endpoints, on /debug/vars when a metrics
+// port is configured. Only the long running commands serve them, a one-off command would take the port of the server
+// running next to it
+func serveMetrics(ctx context.Context, cfg config.Config) {
+	if cfg.Service.MetricsPort == "" {
+		return
+	}
+	logger := helper.GetLogger(ctx).WithField("method", "serveMetrics")
+
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	addr := cfg.Service.Host + ":" + cfg.Service.MetricsPort
+	go func() {
+		logger.Info("metrics listening at " + addr)
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			logger.WithError(err).Error("Error serving metrics")
+		}
+	}()
+}
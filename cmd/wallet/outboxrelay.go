@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/usecase"
+
+	"github.com/segmentio/ksuid"
+	"github.com/spf13/cobra"
+)
+
+func outboxRelay(appProvider AppProvider) *cobra.Command {
+	cliCommand := &cobra.Command{
+		Use:   "run-outbox-relay",
+		Short: "Run the transactional outbox relay",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+			ctx = helper.ContextWithRequestId(ctx, ksuid.New().String())
+			logger := helper.GetLogger(ctx).WithField("method", "outboxRelay")
+
+			app, closeResourcesFn, err := appProvider.BuildContainer(ctx, buildOptions{
+				Postgres: true,
+				Outbox:   true,
+			})
+			if err != nil {
+				return err
+			}
+			if closeResourcesFn != nil {
+				defer closeResourcesFn()
+			}
+
+			logger.Info("Outbox relay started")
+			usecase.NewOutboxRelay(app).Run(ctx)
+			logger.Info("Outbox relay has been stopped")
+
+			return nil
+		},
+	}
+	return cliCommand
+}
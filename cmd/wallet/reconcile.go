@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/usecase"
+
+	"github.com/segmentio/ksuid"
+	"github.com/spf13/cobra"
+)
+
+var reconcileDryRun bool
+
+func reconcile(appProvider AppProvider) *cobra.Command {
+	cliCommand := &cobra.Command{
+		Use:   "reconcile",
+		Short: "Refresh the pending transactions against their chain",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := helper.ContextWithRequestId(context.Background(), ksuid.New().String())
+
+			app, closeResourcesFn, err := appProvider.BuildContainer(ctx, buildOptions{
+				Postgres: true,
+				Bitcoin:  true,
+				Ethereum: true,
+				Tron:     true,
+			})
+			if err != nil {
+				return err
+			}
+			if closeResourcesFn != nil {
+				defer closeResourcesFn()
+			}
+
+			changes, err := usecase.NewReconciler(app).Reconcile(ctx, reconcileDryRun)
+			if err != nil {
+				return err
+			}
+
+			for _, change := range changes {
+				fmt.Printf("%s %s: %s (%d confirmations) -> %s (%d confirmations)\n", *change.Token, *change.TransactionId,
+					helper.Val(change.PreviousStatus), helper.Val(change.PreviousConfirmation), helper.Val(change.Status), helper.Val(change.Confirmation))
+			}
+
+			if reconcileDryRun {
+				fmt.Printf("Dry run, %d transactions would change\n", len(changes))
+				return nil
+			}
+
+			fmt.Printf("Finish reconciling, %d transactions changed\n", len(changes))
+			return nil
+		},
+	}
+
+	cliCommand.Flags().BoolVarP(&reconcileDryRun, "dry-run", "d", false, "Only report the changes")
+	return cliCommand
+}
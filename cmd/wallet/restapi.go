@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 
+	"github.com/aalexanderkevin/crypto-wallet/config"
 	controllerrestapi "github.com/aalexanderkevin/crypto-wallet/controller/restapi"
 	"github.com/aalexanderkevin/crypto-wallet/helper"
 
@@ -18,8 +19,13 @@ func restapi(appProvider AppProvider) *cobra.Command {
 			ctx := helper.ContextWithRequestId(context.Background(), ksuid.New().String())
 			logger := helper.GetLogger(ctx).WithField("method", "server")
 
+			if err := config.Instance().Bitcoin.CheckWebhookVerification(); err != nil {
+				return err
+			}
+
 			app, closeResourcesFn, err := appProvider.BuildContainer(ctx, buildOptions{
 				Postgres: true,
+				Redis:    true,
 				Ethereum: true,
 				Bitcoin:  true,
 			})
@@ -30,6 +36,8 @@ func restapi(appProvider AppProvider) *cobra.Command {
 				defer closeResourcesFn()
 			}
 
+			serveMetrics(ctx, config.Instance())
+
 			// Start Http Server
 			err = controllerrestapi.NewHttpServer(app).Start()
 			if err != nil {
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/usecase"
+
+	"github.com/segmentio/ksuid"
+	"github.com/spf13/cobra"
+)
+
+func verifyAudit(appProvider AppProvider) *cobra.Command {
+	cliCommand := &cobra.Command{
+		Use:   "verify-audit",
+		Short: "Check the hash chain of the audit log for tampering",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := helper.ContextWithRequestId(context.Background(), ksuid.New().String())
+
+			app, closeResourcesFn, err := appProvider.BuildContainer(ctx, buildOptions{
+				Postgres: true,
+			})
+			if err != nil {
+				return err
+			}
+			if closeResourcesFn != nil {
+				defer closeResourcesFn()
+			}
+
+			verification, err := usecase.NewAudit(app).Verify(ctx)
+			if err != nil {
+				return err
+			}
+
+			if verification.BrokenAt != nil {
+				return fmt.Errorf("audit log broken at event %d after %d valid events: %s", *verification.BrokenAt,
+					verification.Checked, helper.Val(verification.Reason))
+			}
+
+			// the head is compared with the one of an earlier run to find events cut off the end of the log
+			fmt.Printf("Audit log intact, %d events checked, last event %d with hash %s\n", verification.Checked,
+				helper.Val(verification.LastId), helper.Val(verification.LastHash))
+			return nil
+		},
+	}
+	return cliCommand
+}
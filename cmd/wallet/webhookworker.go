@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/usecase"
+
+	"github.com/segmentio/ksuid"
+	"github.com/spf13/cobra"
+)
+
+func webhookWorker(appProvider AppProvider) *cobra.Command {
+	cliCommand := &cobra.Command{
+		Use:   "run-webhook-worker",
+		Short: "Run the outbound webhook delivery worker",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+			ctx = helper.ContextWithRequestId(ctx, ksuid.New().String())
+			logger := helper.GetLogger(ctx).WithField("method", "webhookWorker")
+
+			app, closeResourcesFn, err := appProvider.BuildContainer(ctx, buildOptions{
+				Postgres: true,
+				Webhook:  true,
+			})
+			if err != nil {
+				return err
+			}
+			if closeResourcesFn != nil {
+				defer closeResourcesFn()
+			}
+
+			logger.Info("Webhook worker started")
+			usecase.NewOutboundWebhook(app).RunWorker(ctx)
+			logger.Info("Webhook worker has been stopped")
+
+			return nil
+		},
+	}
+	return cliCommand
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/usecase"
+
+	"github.com/segmentio/ksuid"
+	"github.com/spf13/cobra"
+)
+
+func worker(appProvider AppProvider) *cobra.Command {
+	cliCommand := &cobra.Command{
+		Use:   "run-worker",
+		Short: "Run the background job worker",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+			ctx = helper.ContextWithRequestId(ctx, ksuid.New().String())
+			logger := helper.GetLogger(ctx).WithField("method", "worker")
+
+			app, closeResourcesFn, err := appProvider.BuildContainer(ctx, buildOptions{
+				Postgres: true,
+				Bitcoin:  true,
+				Ethereum: true,
+				Tron:     true,
+			})
+			if err != nil {
+				return err
+			}
+			if closeResourcesFn != nil {
+				defer closeResourcesFn()
+			}
+
+			serveMetrics(ctx, config.Instance())
+			logger.Info("Worker started")
+			// the reconciler catches up on what was missed while no worker was running
+			go usecase.NewReconciler(app).Run(ctx)
+			go usecase.NewWithdrawalRequest(app).RunExpiry(ctx)
+			go usecase.NewEvent(app).RunRetention(ctx)
+			usecase.NewJobRunner(app).RunWorker(ctx)
+			logger.Info("Worker has been stopped")
+
+			return nil
+		},
+	}
+	return cliCommand
+}
@@ -1,22 +1,46 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/jinzhu/configor"
 )
 
 type Config struct {
-	Service   Service
-	LogLevel  string `default:"INFO" env:"LOG_LEVEL"`
-	LogFormat string `default:"json" env:"LOG_FORMAT"`
-	Version   string
-	Redis     Redis
-	Ethereum  Ethereum
-	Tron      Tron
-	Bitcoin   Bitcoin
-	Postgres  Postgres
-	JwtSecret string `required:"true" env:"JWT_SECRET"`
+	Service         Service
+	LogLevel        string `default:"INFO" env:"LOG_LEVEL"`
+	LogFormat       string `default:"json" env:"LOG_FORMAT"`
+	Version         string
+	Redis           Redis
+	EventBus        EventBus
+	OutboundWebhook OutboundWebhook
+	Outbox          Outbox
+	Job             Job
+	Reconciler      Reconciler
+	Ethereum        Ethereum
+	Evm             Evm
+	Tron            Tron
+	Bitcoin         Bitcoin
+	Litecoin        Litecoin
+	Dogecoin        Dogecoin
+	Confirmation    Confirmation
+	Failover        Failover
+	RateLimit       RateLimit
+	ChainCache      ChainCache
+	Postgres        Postgres
+	Password        Password
+	Jwt             Jwt
+	Totp            Totp
+	Allowlist       Allowlist
+	Withdrawal      Withdrawal
+	JwtSecret       string `required:"true" env:"JWT_SECRET"`
+	JwtTtlSeconds   int    `default:"3600" env:"JWT_TTL_SECONDS"`
+	// RefreshTokenTtlSeconds is how long a login can be refreshed without entering the password again
+	RefreshTokenTtlSeconds int `default:"2592000" env:"REFRESH_TOKEN_TTL_SECONDS"`
 }
 
 type Postgres struct {
@@ -47,27 +71,403 @@ type Service struct {
 	}
 
 	SeedPhraseEncryptionKey string `env:"SEED_PHRASE_ENCRYPTION_KEY"`
+	// MetricsPort serves the expvar metrics on /debug/vars of its own listener when set
+	MetricsPort string `env:"SERVICE_METRICS_PORT"`
 }
 
 type Ethereum struct {
-	NetUrl      string `default:"https://cloudflare-eth.com" env:"ETH_NET_URL"`
+	NetUrl string `default:"https://cloudflare-eth.com" env:"ETH_NET_URL"`
+	WsUrl  string `env:"ETH_WS_URL"`
+	// Endpoints and WsEndpoints are JSON lists of Endpoint, they replace NetUrl and WsUrl when set
+	Endpoints   string `env:"ETH_ENDPOINTS"`
+	WsEndpoints string `env:"ETH_WS_ENDPOINTS"`
+	// ChainId is asked to the node when zero
+	ChainId     int64  `env:"ETH_CHAIN_ID"`
+	ExplorerUrl string `env:"ETH_EXPLORER_URL"`
 	Passphrase  string `default:"passphrase" env:"ETH_PASSPHRASE"`
 	KeyStoreDir string `default:"./keystore" env:"ETH_KEY_STORE_DIR"`
 }
 
+// Evm holds the EVM networks served next to ethereum, Networks is a JSON list of EvmNetwork
+type Evm struct {
+	Networks string `env:"EVM_NETWORKS"`
+}
+
+// EvmNetwork is an EVM compatible network, Chain is the token it is selected by and Confirmations the depth a
+// transaction needs to be final on it
+type EvmNetwork struct {
+	Chain             string `json:"chain"`
+	Name              string `json:"name"`
+	ChainId           int64  `json:"chain_id"`
+	RpcUrl            string `json:"rpc_url"`
+	WsUrl             string `json:"ws_url"`
+	Symbol            string `json:"symbol"`
+	Confirmations     int    `json:"confirmations"`
+	ConfirmationTiers string `json:"confirmation_tiers"`
+	ExplorerUrl       string `json:"explorer_url"`
+	// Endpoints and WsEndpoints replace RpcUrl and WsUrl when set
+	Endpoints   []Endpoint `json:"endpoints"`
+	WsEndpoints []Endpoint `json:"ws_endpoints"`
+}
+
+// builtinChains are the chains and the aliases of the chains served without configuration, the evm networks cannot
+// take them over
+var builtinChains = []string{"btc", "eth", "trx", "ltc", "doge", "bitcoin", "ethereum", "tron", "litecoin", "dogecoin"}
+
+// GetNetworks parses the configured networks, every network needs its own chain, a chain id and a rpc url. The chain
+// and the name of a network are both used to select it, so neither may be used by another chain
+func (e Evm) GetNetworks() ([]EvmNetwork, error) {
+	networks := []EvmNetwork{}
+	if strings.TrimSpace(e.Networks) == "" {
+		return networks, nil
+	}
+
+	if err := json.Unmarshal([]byte(e.Networks), &networks); err != nil {
+		return nil, fmt.Errorf("invalid evm networks: %w", err)
+	}
+
+	seen := map[string]bool{}
+	for _, chain := range builtinChains {
+		seen[chain] = true
+	}
+	for i, network := range networks {
+		network.Chain = strings.ToLower(strings.TrimSpace(network.Chain))
+		name := strings.ToLower(strings.TrimSpace(network.Name))
+		switch {
+		case network.Chain == "":
+			return nil, fmt.Errorf("evm network %d has no chain", i)
+		case seen[network.Chain]:
+			return nil, fmt.Errorf("evm network chain %s is already used", network.Chain)
+		case name != "" && name != network.Chain && seen[name]:
+			return nil, fmt.Errorf("evm network name %s is already used", network.Name)
+		case network.ChainId <= 0:
+			return nil, fmt.Errorf("evm network %s has no chain id", network.Chain)
+		case network.RpcUrl == "" && len(network.Endpoints) == 0:
+			return nil, fmt.Errorf("evm network %s has no rpc url", network.Chain)
+		}
+		if err := validateEndpoints(network.Endpoints); err != nil {
+			return nil, fmt.Errorf("evm network %s: %w", network.Chain, err)
+		}
+		if err := validateEndpoints(network.WsEndpoints); err != nil {
+			return nil, fmt.Errorf("evm network %s websocket: %w", network.Chain, err)
+		}
+		seen[network.Chain] = true
+		if name != "" {
+			seen[name] = true
+		}
+		networks[i] = network
+	}
+
+	return networks, nil
+}
+
+// RpcEndpoints are the endpoints of the network, RpcUrl is the only one when no list is configured
+func (n EvmNetwork) RpcEndpoints() []Endpoint {
+	return endpointsOrUrl(n.Endpoints, n.RpcUrl)
+}
+
+// WebsocketEndpoints are the websocket endpoints of the network, WsUrl is the only one when no list is configured
+func (n EvmNetwork) WebsocketEndpoints() []Endpoint {
+	return endpointsOrUrl(n.WsEndpoints, n.WsUrl)
+}
+
+// Network describes the ethereum network in the same shape as the other EVM networks
+func (e Ethereum) Network() (EvmNetwork, error) {
+	endpoints, err := ParseEndpoints(e.Endpoints)
+	if err != nil {
+		return EvmNetwork{}, fmt.Errorf("invalid eth endpoints: %w", err)
+	}
+
+	wsEndpoints, err := ParseEndpoints(e.WsEndpoints)
+	if err != nil {
+		return EvmNetwork{}, fmt.Errorf("invalid eth ws endpoints: %w", err)
+	}
+
+	return EvmNetwork{
+		Chain:       "eth",
+		Name:        "ethereum",
+		ChainId:     e.ChainId,
+		RpcUrl:      e.NetUrl,
+		WsUrl:       e.WsUrl,
+		Endpoints:   endpoints,
+		WsEndpoints: wsEndpoints,
+		Symbol:      "ETH",
+		ExplorerUrl: e.ExplorerUrl,
+	}, nil
+}
+
+// Endpoint is a node serving a chain, the healthy endpoint with the lowest priority is used
+type Endpoint struct {
+	Url      string `json:"url"`
+	Priority int    `json:"priority"`
+}
+
+// ParseEndpoints parses a JSON list of Endpoint, an empty value is no endpoint
+func ParseEndpoints(raw string) ([]Endpoint, error) {
+	endpoints := []Endpoint{}
+	if strings.TrimSpace(raw) == "" {
+		return endpoints, nil
+	}
+
+	if err := json.Unmarshal([]byte(raw), &endpoints); err != nil {
+		return nil, err
+	}
+
+	if err := validateEndpoints(endpoints); err != nil {
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
+func validateEndpoints(endpoints []Endpoint) error {
+	for i, endpoint := range endpoints {
+		if strings.TrimSpace(endpoint.Url) == "" {
+			return fmt.Errorf("endpoint %d has no url", i)
+		}
+	}
+
+	return nil
+}
+
+func endpointsOrUrl(endpoints []Endpoint, url string) []Endpoint {
+	if len(endpoints) > 0 {
+		return endpoints
+	}
+
+	if url == "" {
+		return nil
+	}
+
+	return []Endpoint{{Url: url}}
+}
+
 type Tron struct {
 	NetgRPCUrl  string `default:"grpc.shasta.trongrid.io:50051" env:"TRON_NET_GRPC_URL"`
 	NetUrl      string `default:"https://api.shasta.trongrid.io" env:"TRON_NET_URL"`
 	ApiKey      string `default:"09c32c49-d972-494c-96fd-eb5b1b0a4414" env:"TRON_API_KEY"`
 	Passphrase  string `default:"passphrase" env:"TRON_PASSPHRASE"`
 	KeyStoreDir string `default:"./keystore" env:"TRON_KEY_STORE_DIR"`
+	// GrpcEndpoints is a JSON list of Endpoint, it replaces NetgRPCUrl when set
+	GrpcEndpoints string `env:"TRON_GRPC_ENDPOINTS"`
+}
+
+// GetGrpcEndpoints are the gRPC endpoints of the node, NetgRPCUrl is the only one when no list is configured
+func (t Tron) GetGrpcEndpoints() ([]Endpoint, error) {
+	endpoints, err := ParseEndpoints(t.GrpcEndpoints)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tron grpc endpoints: %w", err)
+	}
+
+	return endpointsOrUrl(endpoints, t.NetgRPCUrl), nil
 }
 
 type Bitcoin struct {
-	Chain               string `default:"test3" env:"BTC_CHAIN"`
-	Token               string `default:"a843ce1e9a1c48ac9c621e12b9e8762a" env:"BTC_TOKEN"`
-	WebhookURL          string `env:"BTC_WEBHOOK_URL"`
-	MinimalConfirmation int    `default:"6" env:"BTC_MINIMAL_CONFIRMATION"`
+	Chain      string `default:"test3" env:"BTC_CHAIN"`
+	Token      string `default:"a843ce1e9a1c48ac9c621e12b9e8762a" env:"BTC_TOKEN"`
+	WebhookURL string `env:"BTC_WEBHOOK_URL"`
+	// WebhookPublicKey is the PEM encoded ECDSA key BlockCypher signs its callbacks with
+	WebhookPublicKey string `env:"BTC_WEBHOOK_PUBLIC_KEY"`
+	// WebhookToken is a shared secret appended to the callback path, at least one of the key and the token is required
+	WebhookToken         string `env:"BTC_WEBHOOK_TOKEN"`
+	WebhookMaxAgeSeconds int    `default:"300" env:"BTC_WEBHOOK_MAX_AGE_SECONDS"`
+}
+
+// CheckWebhookVerification fails when the callbacks cannot be verified, the rest api would accept anyone's deposits
+func (b Bitcoin) CheckWebhookVerification() error {
+	if b.WebhookPublicKey == "" && b.WebhookToken == "" {
+		return errors.New("BTC_WEBHOOK_PUBLIC_KEY or BTC_WEBHOOK_TOKEN is required to verify the webhooks")
+	}
+
+	return nil
+}
+
+// Litecoin and Dogecoin share the BlockCypher token and webhook settings of Bitcoin, BlockCypher only serves
+// their main chain
+type Litecoin struct {
+	Chain string `default:"main" env:"LTC_CHAIN"`
+}
+
+type Dogecoin struct {
+	Chain string `default:"main" env:"DOGE_CHAIN"`
+}
+
+// Confirmation holds the confirmations a transaction needs to be final per chain, the tiers raise it for large
+// transfers and are written as "<min amount>:<confirmations>,..." with amounts in the smallest unit of the chain
+type Confirmation struct {
+	Btc       int    `default:"6" env:"CONFIRMATION_BTC"`
+	BtcTiers  string `env:"CONFIRMATION_BTC_TIERS"`
+	Eth       int    `default:"12" env:"CONFIRMATION_ETH"`
+	EthTiers  string `env:"CONFIRMATION_ETH_TIERS"`
+	Trx       int    `default:"19" env:"CONFIRMATION_TRX"`
+	TrxTiers  string `env:"CONFIRMATION_TRX_TIERS"`
+	Ltc       int    `default:"6" env:"CONFIRMATION_LTC"`
+	LtcTiers  string `env:"CONFIRMATION_LTC_TIERS"`
+	Doge      int    `default:"40" env:"CONFIRMATION_DOGE"`
+	DogeTiers string `env:"CONFIRMATION_DOGE_TIERS"`
+}
+
+// Failover decides when a chain endpoint is unhealthy, the next healthy endpoint by priority then serves the
+// calls. An endpoint is unhealthy when it lags the highest block seen by more than MaxBlockLag, answers slower
+// than MaxLatencyMillis or failed more than MaxErrorRate of its last ErrorWindow calls
+type Failover struct {
+	HealthCheckIntervalSeconds int     `default:"15" env:"FAILOVER_HEALTH_CHECK_INTERVAL_SECONDS"`
+	HealthCheckTimeoutSeconds  int     `default:"5" env:"FAILOVER_HEALTH_CHECK_TIMEOUT_SECONDS"`
+	MaxBlockLag                int64   `default:"5" env:"FAILOVER_MAX_BLOCK_LAG"`
+	MaxLatencyMillis           int64   `default:"3000" env:"FAILOVER_MAX_LATENCY_MILLIS"`
+	MaxErrorRate               float64 `default:"0.5" env:"FAILOVER_MAX_ERROR_RATE"`
+	ErrorWindow                int     `default:"20" env:"FAILOVER_ERROR_WINDOW"`
+	// an endpoint that failed is dialed again after ReconnectBaseMillis, doubled on every failed attempt up to
+	// ReconnectMaxSeconds
+	ReconnectBaseMillis int `default:"500" env:"FAILOVER_RECONNECT_BASE_MILLIS"`
+	ReconnectMaxSeconds int `default:"60" env:"FAILOVER_RECONNECT_MAX_SECONDS"`
+}
+
+// RateLimit holds the calls per second each third-party provider lets through, a throttled call is retried up
+// to MaxRetries times after the Retry-After of the provider or a backoff starting at RetryBaseMillis
+type RateLimit struct {
+	BlockCypherPerSecond float64 `default:"3" env:"RATE_LIMIT_BLOCKCYPHER_PER_SECOND"`
+	BlockCypherBurst     int     `default:"3" env:"RATE_LIMIT_BLOCKCYPHER_BURST"`
+	TronGridPerSecond    float64 `default:"15" env:"RATE_LIMIT_TRONGRID_PER_SECOND"`
+	TronGridBurst        int     `default:"15" env:"RATE_LIMIT_TRONGRID_BURST"`
+	MaxRetries           int     `default:"3" env:"RATE_LIMIT_MAX_RETRIES"`
+	RetryBaseMillis      int     `default:"500" env:"RATE_LIMIT_RETRY_BASE_MILLIS"`
+	RetryMaxSeconds      int     `default:"30" env:"RATE_LIMIT_RETRY_MAX_SECONDS"`
+}
+
+// ChainCache is how long balances and block heights read from the chains are kept in the cache, zero disables it
+type ChainCache struct {
+	BalanceTtlSeconds int `default:"15" env:"CHAIN_CACHE_BALANCE_TTL_SECONDS"`
+	HeightTtlSeconds  int `default:"5" env:"CHAIN_CACHE_HEIGHT_TTL_SECONDS"`
+}
+
+// Jwt configures the verification of access tokens issued by an identity provider, RS256 and ES256 tokens are checked
+// with the keys of the JWKS read from JwksUrl or JwksFile, HS256 tokens with JwtSecret. The issuer and audience are
+// checked when set, the tokens the service issues itself carry them
+type Jwt struct {
+	JwksUrl  string `env:"JWT_JWKS_URL"`
+	JwksFile string `env:"JWT_JWKS_FILE"`
+	// JwksRefreshSeconds is how long the keys are cached, a token with an unknown kid refreshes them earlier but at
+	// most once per JwksMinRefreshSeconds
+	JwksRefreshSeconds    int    `default:"300" env:"JWT_JWKS_REFRESH_SECONDS"`
+	JwksMinRefreshSeconds int    `default:"30" env:"JWT_JWKS_MIN_REFRESH_SECONDS"`
+	JwksTimeoutSeconds    int    `default:"10" env:"JWT_JWKS_TIMEOUT_SECONDS"`
+	Issuer                string `env:"JWT_ISSUER"`
+	Audience              string `env:"JWT_AUDIENCE"`
+}
+
+// Password holds the parameters new password hashes are made with, a stored hash made with other parameters is
+// re-hashed on the next successful login
+type Password struct {
+	// Algorithm is argon2id or bcrypt
+	Algorithm       string `default:"argon2id" env:"PASSWORD_ALGORITHM"`
+	Argon2Time      uint32 `default:"3" env:"PASSWORD_ARGON2_TIME"`
+	Argon2MemoryKiB uint32 `default:"65536" env:"PASSWORD_ARGON2_MEMORY_KIB"`
+	Argon2Threads   uint8  `default:"2" env:"PASSWORD_ARGON2_THREADS"`
+	Argon2KeyLength uint32 `default:"32" env:"PASSWORD_ARGON2_KEY_LENGTH"`
+	SaltLength      int    `default:"16" env:"PASSWORD_SALT_LENGTH"`
+	BcryptCost      int    `default:"12" env:"PASSWORD_BCRYPT_COST"`
+}
+
+// Totp configures the second factor of the transfers. The secrets are encrypted with EncryptionKey, a 32 bytes AES key,
+// SeedPhraseEncryptionKey is used when it is empty. A user gets MaxAttempts codes per LockoutSeconds
+type Totp struct {
+	Issuer        string `default:"crypto-wallet" env:"TOTP_ISSUER"`
+	EncryptionKey string `env:"TOTP_ENCRYPTION_KEY"`
+	// Skew is the number of 30 seconds steps a code may be early or late
+	Skew           int64 `default:"1" env:"TOTP_SKEW"`
+	MaxAttempts    int64 `default:"5" env:"TOTP_MAX_ATTEMPTS"`
+	LockoutSeconds int   `default:"900" env:"TOTP_LOCKOUT_SECONDS"`
+	// RequiredAbove makes the users enroll before sending more than the amount on a chain, it is written as
+	// "<chain>:<amount>,..." with amounts in the smallest unit of the chain
+	RequiredAbove string `env:"TOTP_REQUIRED_ABOVE"`
+}
+
+// GetRequiredAbove parses RequiredAbove into the amount of each chain
+func (t Totp) GetRequiredAbove() (map[string]int64, error) {
+	thresholds := map[string]int64{}
+	for _, entry := range strings.Split(t.RequiredAbove, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		chain, amount, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid totp threshold %q", entry)
+		}
+
+		var threshold int64
+		if _, err := fmt.Sscanf(strings.TrimSpace(amount), "%d", &threshold); err != nil || threshold < 0 {
+			return nil, fmt.Errorf("invalid totp threshold amount %q", amount)
+		}
+		thresholds[strings.ToLower(strings.TrimSpace(chain))] = threshold
+	}
+
+	return thresholds, nil
+}
+
+// Allowlist is the cooling-off period of the withdrawal allowlist, a new address and switching the allowlist off only
+// take effect once it is over
+type Allowlist struct {
+	CoolingOffSeconds int `default:"86400" env:"ALLOWLIST_COOLING_OFF_SECONDS"`
+}
+
+// Withdrawal configures the withdrawal requests waiting for approvals, a request not approved within ExpirySeconds is
+// expired by the worker every ExpireIntervalSeconds. A request left in signing for SigningTimeoutSeconds is settled
+// by the worker, its sender stopped before it could record how the send went
+type Withdrawal struct {
+	ExpirySeconds         int `default:"86400" env:"WITHDRAWAL_EXPIRY_SECONDS"`
+	ExpireIntervalSeconds int `default:"60" env:"WITHDRAWAL_EXPIRE_INTERVAL_SECONDS"`
+	SigningTimeoutSeconds int `default:"600" env:"WITHDRAWAL_SIGNING_TIMEOUT_SECONDS"`
+}
+
+// EventBus configures the wallet event streams, the events are shared between the processes through Postgres and
+// kept RetentionHours for the subscribers to resume. PollIntervalSeconds is how often the events are read when no
+// notification arrives
+type EventBus struct {
+	BufferSize          int `default:"1000" env:"EVENT_BUS_BUFFER_SIZE"`
+	RetentionHours      int `default:"24" env:"EVENT_BUS_RETENTION_HOURS"`
+	PollIntervalSeconds int `default:"5" env:"EVENT_BUS_POLL_INTERVAL_SECONDS"`
+}
+
+// OutboundWebhook configures the delivery of the wallet events to the merchant endpoints. The endpoints are given by
+// the users, AllowPrivateNetworks lets them resolve to loopback and private addresses and is only meant for local setups
+type OutboundWebhook struct {
+	AllowPrivateNetworks bool `default:"false" env:"OUTBOUND_WEBHOOK_ALLOW_PRIVATE_NETWORKS"`
+	TimeoutSeconds       int  `default:"10" env:"OUTBOUND_WEBHOOK_TIMEOUT_SECONDS"`
+	MaxAttempts          int  `default:"8" env:"OUTBOUND_WEBHOOK_MAX_ATTEMPTS"`
+	RetryBaseSeconds     int  `default:"30" env:"OUTBOUND_WEBHOOK_RETRY_BASE_SECONDS"`
+	RetryMaxSeconds      int  `default:"3600" env:"OUTBOUND_WEBHOOK_RETRY_MAX_SECONDS"`
+	PollIntervalSeconds  int  `default:"5" env:"OUTBOUND_WEBHOOK_POLL_INTERVAL_SECONDS"`
+	BatchSize            int  `default:"20" env:"OUTBOUND_WEBHOOK_BATCH_SIZE"`
+}
+
+type Outbox struct {
+	// Sink is one of log, http or nats
+	Sink                string `default:"log" env:"OUTBOX_SINK"`
+	HttpUrl             string `env:"OUTBOX_HTTP_URL"`
+	HttpTimeoutSeconds  int    `default:"10" env:"OUTBOX_HTTP_TIMEOUT_SECONDS"`
+	BrokerUrl           string `default:"nats://127.0.0.1:4222" env:"OUTBOX_BROKER_URL"`
+	Topic               string `default:"crypto-wallet.outbox" env:"OUTBOX_TOPIC"`
+	PollIntervalSeconds int    `default:"2" env:"OUTBOX_POLL_INTERVAL_SECONDS"`
+	BatchSize           int    `default:"100" env:"OUTBOX_BATCH_SIZE"`
+}
+
+type Job struct {
+	PollIntervalSeconds int `default:"2" env:"JOB_POLL_INTERVAL_SECONDS"`
+	BatchSize           int `default:"20" env:"JOB_BATCH_SIZE"`
+	// LeaseSeconds is how long a claimed batch stays hidden from the other workers
+	LeaseSeconds     int `default:"300" env:"JOB_LEASE_SECONDS"`
+	MaxAttempts      int `default:"10" env:"JOB_MAX_ATTEMPTS"`
+	RetryBaseSeconds int `default:"10" env:"JOB_RETRY_BASE_SECONDS"`
+	RetryMaxSeconds  int `default:"600" env:"JOB_RETRY_MAX_SECONDS"`
+}
+
+type Reconciler struct {
+	IntervalMinutes int `default:"10" env:"RECONCILER_INTERVAL_MINUTES"`
+	// DroppedAfterHours is how long a transaction may stay unknown to its chain before it is marked dropped
+	DroppedAfterHours int `default:"6" env:"RECONCILER_DROPPED_AFTER_HOURS"`
 }
 
 type Redis struct {
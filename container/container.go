@@ -15,18 +15,40 @@ type Container struct {
 	//svc
 	ethereum service.Ethereum
 	bitcoin  service.Bitcoin
+	utxos    map[string]service.Bitcoin
 	tron     service.Tron
 	redis    service.Cache
+	eventBus service.EventBus
+	policy   service.ConfirmationPolicy
+	webhook  service.WebhookSender
+	outbox   service.OutboxSink
+	chains   service.ChainRegistry
 
 	// repo
-	walletRepo         repository.Wallet
-	transactionBtcRepo repository.Transaction
-	transactionEthRepo repository.Transaction
-	transactionTrxRepo repository.Transaction
+	userRepo              repository.User
+	refreshTokenRepo      repository.RefreshToken
+	spendingLimitRepo     repository.SpendingLimit
+	withdrawalAddressRepo repository.WithdrawalAddress
+	approvalPolicyRepo    repository.ApprovalPolicy
+	withdrawalRequestRepo repository.WithdrawalRequest
+	auditEventRepo        repository.AuditEvent
+	walletEventRepo       repository.WalletEvent
+	walletRepo            repository.Wallet
+	transactionBtcRepo    repository.Transaction
+	transactionEthRepo    repository.Transaction
+	transactionTrxRepo    repository.Transaction
+	transactionRepos      map[string]repository.Transaction
+	webhookEndpointRepo   repository.WebhookEndpoint
+	webhookDeliveryRepo   repository.WebhookDelivery
+	outboxRepo            repository.Outbox
+	jobRepo               repository.Job
 }
 
 func NewContainer() *Container {
-	return &Container{}
+	return &Container{
+		utxos:            map[string]service.Bitcoin{},
+		transactionRepos: map[string]repository.Transaction{},
+	}
 }
 
 func (c *Container) Config() config.Config {
@@ -53,6 +75,38 @@ func (c *Container) SetRedis(redis service.Cache) {
 	c.redis = redis
 }
 
+func (c *Container) OutboxSink() service.OutboxSink {
+	return c.outbox
+}
+
+func (c *Container) SetOutboxSink(outbox service.OutboxSink) {
+	c.outbox = outbox
+}
+
+func (c *Container) ConfirmationPolicy() service.ConfirmationPolicy {
+	return c.policy
+}
+
+func (c *Container) SetConfirmationPolicy(policy service.ConfirmationPolicy) {
+	c.policy = policy
+}
+
+func (c *Container) EventBus() service.EventBus {
+	return c.eventBus
+}
+
+func (c *Container) SetEventBus(eventBus service.EventBus) {
+	c.eventBus = eventBus
+}
+
+func (c *Container) WebhookSender() service.WebhookSender {
+	return c.webhook
+}
+
+func (c *Container) SetWebhookSender(webhook service.WebhookSender) {
+	c.webhook = webhook
+}
+
 func (c *Container) Ethereum() service.Ethereum {
 	return c.ethereum
 }
@@ -67,6 +121,16 @@ func (c *Container) Bitcoin() service.Bitcoin {
 
 func (c *Container) SetBitcoin(bitcoin service.Bitcoin) {
 	c.bitcoin = bitcoin
+	c.utxos["btc"] = bitcoin
+}
+
+// Utxo is the service of the UTXO coin of the chain id, bitcoin included
+func (c *Container) Utxo(chain string) service.Bitcoin {
+	return c.utxos[chain]
+}
+
+func (c *Container) SetUtxo(utxo service.Bitcoin) {
+	c.utxos[utxo.Coin()] = utxo
 }
 
 func (c *Container) Tron() service.Tron {
@@ -77,6 +141,78 @@ func (c *Container) SetTron(tron service.Tron) {
 	c.tron = tron
 }
 
+func (c *Container) ChainRegistry() service.ChainRegistry {
+	return c.chains
+}
+
+func (c *Container) SetChainRegistry(chains service.ChainRegistry) {
+	c.chains = chains
+}
+
+func (c *Container) UserRepo() repository.User {
+	return c.userRepo
+}
+
+func (c *Container) SetUserRepo(userRepo repository.User) {
+	c.userRepo = userRepo
+}
+
+func (c *Container) RefreshTokenRepo() repository.RefreshToken {
+	return c.refreshTokenRepo
+}
+
+func (c *Container) SetRefreshTokenRepo(refreshTokenRepo repository.RefreshToken) {
+	c.refreshTokenRepo = refreshTokenRepo
+}
+
+func (c *Container) SpendingLimitRepo() repository.SpendingLimit {
+	return c.spendingLimitRepo
+}
+
+func (c *Container) SetSpendingLimitRepo(spendingLimitRepo repository.SpendingLimit) {
+	c.spendingLimitRepo = spendingLimitRepo
+}
+
+func (c *Container) WithdrawalAddressRepo() repository.WithdrawalAddress {
+	return c.withdrawalAddressRepo
+}
+
+func (c *Container) SetWithdrawalAddressRepo(withdrawalAddressRepo repository.WithdrawalAddress) {
+	c.withdrawalAddressRepo = withdrawalAddressRepo
+}
+
+func (c *Container) ApprovalPolicyRepo() repository.ApprovalPolicy {
+	return c.approvalPolicyRepo
+}
+
+func (c *Container) SetApprovalPolicyRepo(approvalPolicyRepo repository.ApprovalPolicy) {
+	c.approvalPolicyRepo = approvalPolicyRepo
+}
+
+func (c *Container) WithdrawalRequestRepo() repository.WithdrawalRequest {
+	return c.withdrawalRequestRepo
+}
+
+func (c *Container) SetWithdrawalRequestRepo(withdrawalRequestRepo repository.WithdrawalRequest) {
+	c.withdrawalRequestRepo = withdrawalRequestRepo
+}
+
+func (c *Container) AuditEventRepo() repository.AuditEvent {
+	return c.auditEventRepo
+}
+
+func (c *Container) SetAuditEventRepo(auditEventRepo repository.AuditEvent) {
+	c.auditEventRepo = auditEventRepo
+}
+
+func (c *Container) WalletEventRepo() repository.WalletEvent {
+	return c.walletEventRepo
+}
+
+func (c *Container) SetWalletEventRepo(walletEventRepo repository.WalletEvent) {
+	c.walletEventRepo = walletEventRepo
+}
+
 func (c *Container) WalletRepo() repository.Wallet {
 	return c.walletRepo
 }
@@ -91,6 +227,7 @@ func (c *Container) TransactionEthRepo() repository.Transaction {
 
 func (c *Container) SetTransactionEthRepo(transactionEthRepo repository.Transaction) {
 	c.transactionEthRepo = transactionEthRepo
+	c.transactionRepos["eth"] = transactionEthRepo
 }
 
 func (c *Container) TransactionBtcRepo() repository.Transaction {
@@ -99,6 +236,7 @@ func (c *Container) TransactionBtcRepo() repository.Transaction {
 
 func (c *Container) SetTransactionBtcRepo(transactionBtcRepo repository.Transaction) {
 	c.transactionBtcRepo = transactionBtcRepo
+	c.transactionRepos["btc"] = transactionBtcRepo
 }
 
 func (c *Container) TransactionTrxRepo() repository.Transaction {
@@ -107,4 +245,46 @@ func (c *Container) TransactionTrxRepo() repository.Transaction {
 
 func (c *Container) SetTransactionTrxRepo(transactionTrxRepo repository.Transaction) {
 	c.transactionTrxRepo = transactionTrxRepo
+	c.transactionRepos["trx"] = transactionTrxRepo
+}
+
+// TransactionRepo is the transaction repository of the chain id
+func (c *Container) TransactionRepo(chain string) repository.Transaction {
+	return c.transactionRepos[chain]
+}
+
+func (c *Container) SetTransactionRepo(chain string, transactionRepo repository.Transaction) {
+	c.transactionRepos[chain] = transactionRepo
+}
+
+func (c *Container) WebhookEndpointRepo() repository.WebhookEndpoint {
+	return c.webhookEndpointRepo
+}
+
+func (c *Container) SetWebhookEndpointRepo(webhookEndpointRepo repository.WebhookEndpoint) {
+	c.webhookEndpointRepo = webhookEndpointRepo
+}
+
+func (c *Container) WebhookDeliveryRepo() repository.WebhookDelivery {
+	return c.webhookDeliveryRepo
+}
+
+func (c *Container) SetWebhookDeliveryRepo(webhookDeliveryRepo repository.WebhookDelivery) {
+	c.webhookDeliveryRepo = webhookDeliveryRepo
+}
+
+func (c *Container) OutboxRepo() repository.Outbox {
+	return c.outboxRepo
+}
+
+func (c *Container) SetOutboxRepo(outboxRepo repository.Outbox) {
+	c.outboxRepo = outboxRepo
+}
+
+func (c *Container) JobRepo() repository.Job {
+	return c.jobRepo
+}
+
+func (c *Container) SetJobRepo(jobRepo repository.Job) {
+	c.jobRepo = jobRepo
 }
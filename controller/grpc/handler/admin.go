@@ -0,0 +1,306 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/controller/grpc/response"
+	"github.com/aalexanderkevin/crypto-wallet/controller/middleware"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	cegrpc "github.com/aalexanderkevin/crypto-wallet/transport/grpc/crypto-wallet"
+	"github.com/aalexanderkevin/crypto-wallet/usecase"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// maxAuditEventsLimit is the largest page of the audit log, it is the page size when the request sets none
+const maxAuditEventsLimit = 500
+
+// Admin serves the methods working on the wallet of any user, the policy of the interceptor only lets the tokens with
+// the admin scope in
+type Admin struct {
+	appContainer *container.Container
+}
+
+func NewAdminHandler(appContainer *container.Container) *Admin {
+	return &Admin{appContainer: appContainer}
+}
+
+func (a *Admin) AdminGetWallet(ctx context.Context, r *cegrpc.AdminGetWalletRequest) (*cegrpc.CreteWalletResponse, error) {
+	if r.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	a.audit(ctx, "Handler.Admin.AdminGetWallet", r.GetUserId())
+
+	walletUseCase := usecase.NewWallet(a.appContainer)
+	wallet, err := walletUseCase.GetWallet(ctx, helper.Pointer(r.GetUserId()))
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return &cegrpc.CreteWalletResponse{
+		Id:          helper.Val(wallet.Id),
+		Email:       helper.Val(wallet.Email),
+		BtcAddress:  helper.Val(wallet.BtcAddress),
+		EthAddress:  helper.Val(wallet.EthAddress),
+		TrxAddress:  helper.Val(wallet.TrxAddress),
+		LtcAddress:  helper.Val(wallet.LtcAddress),
+		DogeAddress: helper.Val(wallet.DogeAddress),
+	}, nil
+}
+
+func (a *Admin) AdminGetBalance(ctx context.Context, r *cegrpc.AdminGetBalanceRequest) (*cegrpc.Balance, error) {
+	if r.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if r.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+	a.audit(ctx, "Handler.Admin.AdminGetBalance", r.GetUserId())
+
+	transactionUseCase := usecase.NewTransaction(a.appContainer)
+	balance, err := transactionUseCase.GetBalance(ctx, helper.Pointer(r.GetUserId()), helper.Pointer(r.GetToken()))
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return &cegrpc.Balance{
+		Token:   r.GetToken(),
+		Address: helper.Val(balance.Address),
+		Balance: balance.Balance.String(),
+		Symbol:  helper.Val(balance.Symbol),
+	}, nil
+}
+
+func (a *Admin) AdminListTransactions(ctx context.Context, r *cegrpc.AdminListTransactionsRequest) (*cegrpc.ListTransactionsResponse, error) {
+	if r.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if r.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+	a.audit(ctx, "Handler.Admin.AdminListTransactions", r.GetUserId())
+
+	var transactionStatus *string
+	if r.GetStatus() != "" {
+		transactionStatus = helper.Pointer(r.GetStatus())
+	}
+
+	transactionUseCase := usecase.NewTransaction(a.appContainer)
+	transactions, err := transactionUseCase.ListTransactions(ctx, helper.Pointer(r.GetUserId()), helper.Pointer(r.GetToken()), transactionStatus)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	res := &cegrpc.ListTransactionsResponse{}
+	for _, transaction := range transactions {
+		res.Transactions = append(res.Transactions, toTransactionResponse(r.GetToken(), transaction))
+	}
+
+	return res, nil
+}
+
+func (a *Admin) AdminSetSpendingLimit(ctx context.Context, r *cegrpc.SpendingLimit) (*cegrpc.SpendingLimit, error) {
+	if r.GetChain() == "" {
+		return nil, status.Error(codes.InvalidArgument, "chain is required")
+	}
+	a.audit(ctx, "Handler.Admin.AdminSetSpendingLimit", r.GetUserId())
+
+	limit := &model.SpendingLimit{
+		Chain:                  helper.Pointer(r.GetChain()),
+		MaxPerTransaction:      limitValue(r.GetMaxPerTransaction()),
+		MaxPerDay:              limitValue(r.GetMaxPerDay()),
+		MaxTransactionsPerHour: limitValue(r.GetMaxTransactionsPerHour()),
+	}
+	if r.GetUserId() != "" {
+		limit.UserId = helper.Pointer(r.GetUserId())
+	}
+
+	spendingLimitUseCase := usecase.NewSpendingLimit(a.appContainer)
+	limit, err := spendingLimitUseCase.Set(ctx, limit)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return toSpendingLimitResponse(*limit), nil
+}
+
+func (a *Admin) AdminListSpendingLimits(ctx context.Context, r *cegrpc.AdminListSpendingLimitsRequest) (*cegrpc.ListSpendingLimitsResponse, error) {
+	filter := &repository.SpendingLimitFilter{Global: r.GetGlobal()}
+	if r.GetUserId() != "" {
+		filter.UserId = helper.Pointer(r.GetUserId())
+	}
+	if r.GetChain() != "" {
+		filter.Chain = helper.Pointer(strings.ToLower(r.GetChain()))
+	}
+
+	spendingLimitUseCase := usecase.NewSpendingLimit(a.appContainer)
+	limits, err := spendingLimitUseCase.List(ctx, filter)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	res := &cegrpc.ListSpendingLimitsResponse{}
+	for _, limit := range limits {
+		res.SpendingLimits = append(res.SpendingLimits, toSpendingLimitResponse(limit))
+	}
+
+	return res, nil
+}
+
+func (a *Admin) AdminDeleteSpendingLimit(ctx context.Context, r *cegrpc.AdminDeleteSpendingLimitRequest) (*emptypb.Empty, error) {
+	if r.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	a.audit(ctx, "Handler.Admin.AdminDeleteSpendingLimit", "")
+
+	spendingLimitUseCase := usecase.NewSpendingLimit(a.appContainer)
+	if err := spendingLimitUseCase.Delete(ctx, r.GetId()); err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (a *Admin) AdminSetApprovalPolicy(ctx context.Context, r *cegrpc.ApprovalPolicy) (*cegrpc.ApprovalPolicy, error) {
+	if r.GetWalletId() == "" || r.GetChain() == "" {
+		return nil, status.Error(codes.InvalidArgument, "wallet id and chain are required")
+	}
+	a.audit(ctx, "Handler.Admin.AdminSetApprovalPolicy", "")
+
+	policy := &model.ApprovalPolicy{
+		WalletId:          helper.Pointer(r.GetWalletId()),
+		Chain:             helper.Pointer(r.GetChain()),
+		Threshold:         helper.Pointer(r.GetThreshold()),
+		RequiredApprovals: helper.Pointer(int(r.GetRequiredApprovals())),
+		ApproverIds:       r.GetApproverIds(),
+	}
+
+	withdrawalRequestUseCase := usecase.NewWithdrawalRequest(a.appContainer)
+	policy, err := withdrawalRequestUseCase.SetPolicy(ctx, policy)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return toApprovalPolicyResponse(*policy), nil
+}
+
+func (a *Admin) AdminListApprovalPolicies(ctx context.Context, r *cegrpc.AdminListApprovalPoliciesRequest) (*cegrpc.ListApprovalPoliciesResponse, error) {
+	var walletId *string
+	if r.GetWalletId() != "" {
+		walletId = helper.Pointer(r.GetWalletId())
+	}
+
+	approvalPolicyRepo := a.appContainer.ApprovalPolicyRepo()
+	policies, err := approvalPolicyRepo.List(ctx, walletId)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	res := &cegrpc.ListApprovalPoliciesResponse{}
+	for _, policy := range policies {
+		res.ApprovalPolicies = append(res.ApprovalPolicies, toApprovalPolicyResponse(policy))
+	}
+
+	return res, nil
+}
+
+func (a *Admin) AdminListAuditEvents(ctx context.Context, r *cegrpc.AdminListAuditEventsRequest) (*cegrpc.ListAuditEventsResponse, error) {
+	a.audit(ctx, "Handler.Admin.AdminListAuditEvents", r.GetActorId())
+
+	filter := &repository.AuditEventFilter{Limit: int(r.GetLimit())}
+	if filter.Limit <= 0 || filter.Limit > maxAuditEventsLimit {
+		filter.Limit = maxAuditEventsLimit
+	}
+	if r.GetAction() != "" {
+		filter.Action = helper.Pointer(r.GetAction())
+	}
+	if r.GetActorId() != "" {
+		filter.ActorId = helper.Pointer(r.GetActorId())
+	}
+	if r.GetTargetId() != "" {
+		filter.TargetId = helper.Pointer(r.GetTargetId())
+	}
+	if r.GetFrom() != 0 {
+		filter.From = helper.Pointer(time.Unix(r.GetFrom(), 0))
+	}
+	if r.GetTo() != 0 {
+		filter.To = helper.Pointer(time.Unix(r.GetTo(), 0))
+	}
+	if r.GetAfterId() != 0 {
+		filter.AfterId = helper.Pointer(r.GetAfterId())
+	}
+
+	auditUseCase := usecase.NewAudit(a.appContainer)
+	events, err := auditUseCase.List(ctx, filter)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	res := &cegrpc.ListAuditEventsResponse{}
+	for _, event := range events {
+		res.AuditEvents = append(res.AuditEvents, &cegrpc.AuditEvent{
+			Id:        helper.Val(event.Id),
+			Action:    helper.Val(event.Action),
+			ActorId:   helper.Val(event.ActorId),
+			TargetId:  helper.Val(event.TargetId),
+			RequestId: helper.Val(event.RequestId),
+			Metadata:  event.Metadata,
+			PrevHash:  helper.Val(event.PrevHash),
+			Hash:      helper.Val(event.Hash),
+			CreatedAt: helper.ValTimeUnix(event.CreatedAt),
+		})
+	}
+
+	return res, nil
+}
+
+// limitValue maps the zero of the request to no limit
+func limitValue(value int64) *int64 {
+	if value == 0 {
+		return nil
+	}
+
+	return &value
+}
+
+func toSpendingLimitResponse(limit model.SpendingLimit) *cegrpc.SpendingLimit {
+	return &cegrpc.SpendingLimit{
+		Id:                     helper.Val(limit.Id),
+		UserId:                 helper.Val(limit.UserId),
+		Chain:                  helper.Val(limit.Chain),
+		MaxPerTransaction:      helper.Val(limit.MaxPerTransaction),
+		MaxPerDay:              helper.Val(limit.MaxPerDay),
+		MaxTransactionsPerHour: helper.Val(limit.MaxTransactionsPerHour),
+		CreatedAt:              helper.ValTimeUnix(limit.CreatedAt),
+		UpdatedAt:              helper.ValTimeUnix(limit.UpdatedAt),
+	}
+}
+
+func toApprovalPolicyResponse(policy model.ApprovalPolicy) *cegrpc.ApprovalPolicy {
+	return &cegrpc.ApprovalPolicy{
+		Id:                helper.Val(policy.Id),
+		WalletId:          helper.Val(policy.WalletId),
+		Chain:             helper.Val(policy.Chain),
+		Threshold:         helper.Val(policy.Threshold),
+		RequiredApprovals: int32(helper.Val(policy.RequiredApprovals)),
+		ApproverIds:       policy.ApproverIds,
+		CreatedAt:         helper.ValTimeUnix(policy.CreatedAt),
+		UpdatedAt:         helper.ValTimeUnix(policy.UpdatedAt),
+	}
+}
+
+// audit logs the admin and the user whose wallet or limits are read or changed
+func (a *Admin) audit(ctx context.Context, method string, targetUserId string) {
+	helper.GetLogger(ctx).WithField("method", method).
+		WithField("audit", "admin_access").
+		WithField("admin_id", middleware.GetUserId(ctx)).
+		WithField("target_user_id", targetUserId).
+		Info("admin access")
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/controller/grpc/response"
+	"github.com/aalexanderkevin/crypto-wallet/controller/middleware"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	cegrpc "github.com/aalexanderkevin/crypto-wallet/transport/grpc/crypto-wallet"
+	"github.com/aalexanderkevin/crypto-wallet/usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var walletEventTypes = map[string]cegrpc.WalletEventType{
+	model.EventDepositDetected:     cegrpc.WalletEventType_DEPOSIT_DETECTED,
+	model.EventConfirmationChanged: cegrpc.WalletEventType_CONFIRMATION_CHANGED,
+	model.EventSendConfirmed:       cegrpc.WalletEventType_SEND_CONFIRMED,
+	model.EventSendFailed:          cegrpc.WalletEventType_SEND_FAILED,
+}
+
+type Event struct {
+	appContainer *container.Container
+}
+
+func NewEventHandler(appContainer *container.Container) *Event {
+	return &Event{appContainer: appContainer}
+}
+
+func (e *Event) SubscribeWalletEvents(r *cegrpc.SubscribeWalletEventsRequest, stream cegrpc.CryptoWallet_SubscribeWalletEventsServer) error {
+	ctx := stream.Context()
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.Event.SubscribeWalletEvents")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	eventUseCase := usecase.NewEvent(e.appContainer)
+	events, unsubscribe, err := eventUseCase.SubscribeWalletEvents(ctx, &userId, helper.Pointer(r.GetResumeToken()))
+	if err != nil {
+		return response.SendErrorResponse(err)
+	}
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				// the subscription was dropped, the client should resume with its last token
+				return status.Error(codes.Unavailable, "subscription closed, resume with the last token")
+			}
+
+			err := stream.Send(&cegrpc.WalletEvent{
+				ResumeToken:   helper.Val(event.ResumeToken),
+				Type:          walletEventTypes[helper.Val(event.Type)],
+				Token:         helper.Val(event.Token),
+				TransactionId: helper.Val(event.TransactionId),
+				Addresses:     event.Addresses,
+				Amount:        helper.Val(event.Amount),
+				Confirmation:  helper.Val(event.Confirmation),
+				Status:        helper.Val(event.Status),
+				FailureReason: helper.Val(event.FailureReason),
+				CreatedAt:     helper.ValTimeUnix(event.CreatedAt),
+			})
+			if err != nil {
+				logger.WithError(err).Warn("failed send wallet event")
+				return err
+			}
+		}
+	}
+}
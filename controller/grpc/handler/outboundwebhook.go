@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/controller/grpc/response"
+	"github.com/aalexanderkevin/crypto-wallet/controller/middleware"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	cegrpc "github.com/aalexanderkevin/crypto-wallet/transport/grpc/crypto-wallet"
+	"github.com/aalexanderkevin/crypto-wallet/usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type OutboundWebhook struct {
+	appContainer *container.Container
+}
+
+func NewOutboundWebhookHandler(appContainer *container.Container) *OutboundWebhook {
+	return &OutboundWebhook{appContainer: appContainer}
+}
+
+func (o *OutboundWebhook) RegisterWebhookEndpoint(ctx context.Context, r *cegrpc.RegisterWebhookEndpointRequest) (*cegrpc.WebhookEndpoint, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.OutboundWebhook.RegisterWebhookEndpoint")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	outboundWebhookUseCase := usecase.NewOutboundWebhook(o.appContainer)
+	endpoint, err := outboundWebhookUseCase.RegisterEndpoint(ctx, &userId, helper.Pointer(r.GetUrl()))
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	res := toWebhookEndpointResponse(*endpoint)
+	res.Secret = helper.Val(endpoint.Secret)
+
+	return res, nil
+}
+
+func (o *OutboundWebhook) ListWebhookEndpoints(ctx context.Context, r *emptypb.Empty) (*cegrpc.ListWebhookEndpointsResponse, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.OutboundWebhook.ListWebhookEndpoints")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	outboundWebhookUseCase := usecase.NewOutboundWebhook(o.appContainer)
+	endpoints, err := outboundWebhookUseCase.ListEndpoints(ctx, &userId)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	res := &cegrpc.ListWebhookEndpointsResponse{}
+	for _, endpoint := range endpoints {
+		res.Endpoints = append(res.Endpoints, toWebhookEndpointResponse(endpoint))
+	}
+
+	return res, nil
+}
+
+func (o *OutboundWebhook) DeleteWebhookEndpoint(ctx context.Context, r *cegrpc.DeleteWebhookEndpointRequest) (*emptypb.Empty, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.OutboundWebhook.DeleteWebhookEndpoint")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	outboundWebhookUseCase := usecase.NewOutboundWebhook(o.appContainer)
+	err := outboundWebhookUseCase.DeleteEndpoint(ctx, &userId, helper.Pointer(r.GetId()))
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (o *OutboundWebhook) ListWebhookDeliveries(ctx context.Context, r *cegrpc.ListWebhookDeliveriesRequest) (*cegrpc.ListWebhookDeliveriesResponse, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.OutboundWebhook.ListWebhookDeliveries")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	var deliveryStatus *string
+	if r.GetStatus() != "" {
+		deliveryStatus = helper.Pointer(r.GetStatus())
+	}
+
+	outboundWebhookUseCase := usecase.NewOutboundWebhook(o.appContainer)
+	deliveries, err := outboundWebhookUseCase.ListDeliveries(ctx, &userId, deliveryStatus)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	res := &cegrpc.ListWebhookDeliveriesResponse{}
+	for _, delivery := range deliveries {
+		res.Deliveries = append(res.Deliveries, toWebhookDeliveryResponse(delivery))
+	}
+
+	return res, nil
+}
+
+func (o *OutboundWebhook) RedeliverWebhook(ctx context.Context, r *cegrpc.RedeliverWebhookRequest) (*cegrpc.WebhookDelivery, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.OutboundWebhook.RedeliverWebhook")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	outboundWebhookUseCase := usecase.NewOutboundWebhook(o.appContainer)
+	delivery, err := outboundWebhookUseCase.Redeliver(ctx, &userId, helper.Pointer(r.GetId()))
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return toWebhookDeliveryResponse(*delivery), nil
+}
+
+func toWebhookEndpointResponse(endpoint model.WebhookEndpoint) *cegrpc.WebhookEndpoint {
+	return &cegrpc.WebhookEndpoint{
+		Id:        helper.Val(endpoint.Id),
+		Url:       helper.Val(endpoint.Url),
+		Active:    helper.Val(endpoint.Active),
+		CreatedAt: helper.ValTimeUnix(endpoint.CreatedAt),
+	}
+}
+
+func toWebhookDeliveryResponse(delivery model.WebhookDelivery) *cegrpc.WebhookDelivery {
+	return &cegrpc.WebhookDelivery{
+		Id:             helper.Val(delivery.Id),
+		EndpointId:     helper.Val(delivery.EndpointId),
+		EventType:      helper.Val(delivery.EventType),
+		Payload:        helper.Val(delivery.Payload),
+		Status:         helper.Val(delivery.Status),
+		Attempts:       int32(helper.Val(delivery.Attempts)),
+		NextAttemptAt:  helper.ValTimeUnix(delivery.NextAttemptAt),
+		LastStatusCode: int32(helper.Val(delivery.LastStatusCode)),
+		LastError:      helper.Val(delivery.LastError),
+		DeliveredAt:    helper.ValTimeUnix(delivery.DeliveredAt),
+		CreatedAt:      helper.ValTimeUnix(delivery.CreatedAt),
+	}
+}
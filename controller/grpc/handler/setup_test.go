@@ -12,6 +12,8 @@ import (
 	grpccontroller "github.com/aalexanderkevin/crypto-wallet/controller/grpc"
 	"github.com/aalexanderkevin/crypto-wallet/controller/grpc/handler"
 	"github.com/aalexanderkevin/crypto-wallet/controller/middleware"
+	"github.com/aalexanderkevin/crypto-wallet/service/chain"
+	"github.com/aalexanderkevin/crypto-wallet/service/confirmation"
 	cegrpc "github.com/aalexanderkevin/crypto-wallet/transport/grpc/crypto-wallet"
 
 	"google.golang.org/grpc"
@@ -36,18 +38,32 @@ func SetupGRPCConn(t *testing.T, ctx context.Context, cb func(appContainer *cont
 
 	// List of excluded methods (full method names).
 	excludedMethods := []string{
-		"/crypto_wallet.CryptoWallet/Login",    // Exclude Login method
-		"/crypto_wallet.CryptoWallet/Register", // Exclude Register method
+		"/crypto_wallet.CryptoWallet/Login",        // Exclude Login method
+		"/crypto_wallet.CryptoWallet/Register",     // Exclude Register method
+		"/crypto_wallet.CryptoWallet/RefreshToken", // Exclude RefreshToken method
+	}
+
+	verifier, err := middleware.NewVerifier(cfg.JwtSecret, cfg.Jwt)
+	if err != nil {
+		log.Fatalf("Failed to init jwt verifier: %v", err)
 	}
 
 	server := grpc.NewServer(
-		grpc.UnaryInterceptor(middleware.JWTMiddleware(cfg.JwtSecret, excludedMethods)),
+		grpc.UnaryInterceptor(middleware.JWTMiddleware(verifier, excludedMethods, middleware.DefaultPolicy(), appContainer.Redis(), appContainer.AuditEventRepo())),
+		grpc.StreamInterceptor(middleware.JWTStreamMiddleware(verifier, excludedMethods, middleware.DefaultPolicy(), appContainer.Redis(), appContainer.AuditEventRepo())),
 	)
 
 	controllers := &grpccontroller.Controllers{
-		Wallet:      *handler.NewWalletHandler(appContainer),
-		Transaction: *handler.NewTransactionHandler(appContainer),
-		Watcher:     *handler.NewWatcherHandler(appContainer),
+		User:              *handler.NewUserHandler(appContainer),
+		Wallet:            *handler.NewWalletHandler(appContainer),
+		Transaction:       *handler.NewTransactionHandler(appContainer),
+		Watcher:           *handler.NewWatcherHandler(appContainer),
+		Event:             *handler.NewEventHandler(appContainer),
+		OutboundWebhook:   *handler.NewOutboundWebhookHandler(appContainer),
+		TronResource:      *handler.NewTronResourceHandler(appContainer),
+		WithdrawalAddress: *handler.NewWithdrawalAddressHandler(appContainer),
+		WithdrawalRequest: *handler.NewWithdrawalRequestHandler(appContainer),
+		Admin:             *handler.NewAdminHandler(appContainer),
 	}
 	cegrpc.RegisterCryptoWalletServer(server, controllers)
 
@@ -96,5 +112,12 @@ func DefaultAppContainer() *container.Container {
 	appContainer := container.NewContainer()
 	appContainer.SetConfig(config.Instance())
 
+	confirmationPolicy, err := confirmation.NewPolicy(config.Instance().Confirmation)
+	if err != nil {
+		panic(err)
+	}
+	appContainer.SetConfirmationPolicy(confirmationPolicy)
+	appContainer.SetChainRegistry(chain.NewRegistry())
+
 	return appContainer
 }
@@ -26,18 +26,19 @@ func NewTransactionHandler(appContainer *container.Container) *Transaction {
 func (w *Transaction) SendToken(ctx context.Context, r *cegrpc.SendRequest) (*cegrpc.SendResponse, error) {
 	logger := helper.GetLogger(ctx).WithField("method", "Handler.Transaction.SendToken")
 
-	email := middleware.GetJWTData(ctx)
-	if email == "" {
-		err := errors.New("cant find id on token")
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
 		logger.WithError(err)
 		return nil, status.Error(codes.Unauthenticated, err.Error())
 	}
 
 	req := &model.SendToken{
-		Email:           helper.Pointer(email),
+		UserId:          helper.Pointer(userId),
 		ReceiverAddress: helper.Pointer(r.GetToAddress()),
 		Amount:          helper.Pointer(r.GetAmount()),
 		Token:           helper.Pointer(r.GetToken()),
+		Otp:             helper.Pointer(r.GetOtp()),
 	}
 	err := req.Validate()
 	if err != nil {
@@ -46,26 +47,8 @@ func (w *Transaction) SendToken(ctx context.Context, r *cegrpc.SendRequest) (*ce
 	}
 
 	transactionUseCase := usecase.NewTransaction(w.appContainer)
-	var hashTx *string
-
-	switch *req.Token {
-	case "btc", "bitcoin":
-		hashTx, err = transactionUseCase.SendBitcoin(ctx, req)
-		if err != nil {
-			return nil, response.SendErrorResponse(err)
-		}
-	case "trx", "tron":
-		hashTx, err = transactionUseCase.SendTron(ctx, req)
-		if err != nil {
-			return nil, response.SendErrorResponse(err)
-		}
-	case "eth", "ethereum":
-		hashTx, err = transactionUseCase.SendEthereum(ctx, req)
-		if err != nil {
-			return nil, response.SendErrorResponse(err)
-		}
-	default:
-		err = errors.New("invalid transfer token")
+	hashTx, err := transactionUseCase.Send(ctx, req)
+	if err != nil {
 		return nil, response.SendErrorResponse(err)
 	}
 
@@ -74,3 +57,146 @@ func (w *Transaction) SendToken(ctx context.Context, r *cegrpc.SendRequest) (*ce
 		HashTransaction: *hashTx,
 	}, nil
 }
+
+func (w *Transaction) GetTransaction(ctx context.Context, r *cegrpc.GetTransactionRequest) (*cegrpc.Transaction, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.Transaction.GetTransaction")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if r.GetToken() == "" || r.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token and id are required")
+	}
+
+	transactionUseCase := usecase.NewTransaction(w.appContainer)
+	transaction, err := transactionUseCase.GetTransaction(ctx, &userId, helper.Pointer(r.GetToken()), helper.Pointer(r.GetId()))
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return toTransactionResponse(r.GetToken(), *transaction), nil
+}
+
+func (w *Transaction) ListTransactions(ctx context.Context, r *cegrpc.ListTransactionsRequest) (*cegrpc.ListTransactionsResponse, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.Transaction.ListTransactions")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if r.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	var transactionStatus *string
+	if r.GetStatus() != "" {
+		transactionStatus = helper.Pointer(r.GetStatus())
+	}
+
+	transactionUseCase := usecase.NewTransaction(w.appContainer)
+	transactions, err := transactionUseCase.ListTransactions(ctx, &userId, helper.Pointer(r.GetToken()), transactionStatus)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	res := &cegrpc.ListTransactionsResponse{}
+	for _, transaction := range transactions {
+		res.Transactions = append(res.Transactions, toTransactionResponse(r.GetToken(), transaction))
+	}
+
+	return res, nil
+}
+
+func (w *Transaction) EstimateFee(ctx context.Context, r *cegrpc.SendRequest) (*cegrpc.FeeEstimate, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.Transaction.EstimateFee")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	req := &model.SendToken{
+		UserId:          helper.Pointer(userId),
+		ReceiverAddress: helper.Pointer(r.GetToAddress()),
+		Amount:          helper.Pointer(r.GetAmount()),
+		Token:           helper.Pointer(r.GetToken()),
+	}
+	err := req.Validate()
+	if err != nil {
+		logger.WithError(err).Warning("missing required field")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	transactionUseCase := usecase.NewTransaction(w.appContainer)
+	estimate, err := transactionUseCase.EstimateFee(ctx, req)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return &cegrpc.FeeEstimate{
+		Fee:           helper.Val(estimate.Fee),
+		Bandwidth:     helper.Val(estimate.Bandwidth),
+		BandwidthFee:  helper.Val(estimate.BandwidthFee),
+		ActivationFee: helper.Val(estimate.ActivationFee),
+	}, nil
+}
+
+func (w *Transaction) GetBalance(ctx context.Context, r *cegrpc.GetBalanceRequest) (*cegrpc.Balance, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.Transaction.GetBalance")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if r.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	transactionUseCase := usecase.NewTransaction(w.appContainer)
+	balance, err := transactionUseCase.GetBalance(ctx, &userId, helper.Pointer(r.GetToken()))
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return &cegrpc.Balance{
+		Token:   r.GetToken(),
+		Address: helper.Val(balance.Address),
+		Balance: balance.Balance.String(),
+		Symbol:  helper.Val(balance.Symbol),
+	}, nil
+}
+
+func toTransactionResponse(token string, transaction model.Transaction) *cegrpc.Transaction {
+	return &cegrpc.Transaction{
+		Id:                helper.Val(transaction.Id),
+		Token:             token,
+		SenderAddress:     transaction.SenderAddress,
+		ReceiverAddress:   transaction.ReceiverAddress,
+		Amount:            helper.Val(transaction.Amount),
+		Fee:               helper.Val(transaction.Fee),
+		Block:             helper.Val(transaction.Block),
+		Confirmation:      helper.Val(transaction.Confirmation),
+		Status:            helper.Val(transaction.Status),
+		FailureReason:     helper.Val(transaction.FailureReason),
+		GasUsed:           helper.Val(transaction.GasUsed),
+		EffectiveGasPrice: helper.Val(transaction.EffectiveGasPrice),
+		ReceivedAt:        helper.ValTimeUnix(transaction.ReceivedAt),
+		CompletedAt:       helper.ValTimeUnix(transaction.CompletedAt),
+		CreatedAt:         helper.ValTimeUnix(transaction.CreatedAt),
+		Type:              helper.ValOrDefault(transaction.Type, model.TransactionTypeTransfer),
+		Resource:          helper.Val(transaction.Resource),
+		ExplorerUrl:       helper.Val(transaction.ExplorerUrl),
+	}
+}
@@ -0,0 +1,49 @@
+package handler_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/helper/test"
+	"github.com/aalexanderkevin/crypto-wallet/service/chain"
+	"github.com/aalexanderkevin/crypto-wallet/service/mocks"
+	cegrpc "github.com/aalexanderkevin/crypto-wallet/transport/grpc/crypto-wallet"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestTransaction_SendToken(t *testing.T) {
+	t.Run("ShouldReturnInvalidArgument_WhenTheAmountIsNegative", func(t *testing.T) {
+		// INIT
+		adapter := &mocks.ChainAdapter{}
+		adapter.On("Chain").Return("eth")
+
+		conn, deferFn := SetupGRPCConn(t, context.TODO(), func(appContainer *container.Container) *container.Container {
+			registry := chain.NewRegistry()
+			registry.Register(adapter)
+			appContainer.SetChainRegistry(registry)
+			return appContainer
+		})
+		defer deferFn()
+
+		token, _ := test.FakeJwtToken(t, nil)
+		ctx := metadata.AppendToOutgoingContext(context.TODO(), "authorization", "Bearer "+token)
+
+		// CODE UNDER TEST
+		res, err := performGRPCRequest(ctx, cegrpc.NewCryptoWalletClient(conn), "SendToken", &cegrpc.SendRequest{
+			ToAddress: "0x0000000000000000000000000000000000000001",
+			Amount:    -1,
+			Token:     "eth",
+		})
+
+		// EXPECTATION
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+		require.Nil(t, res)
+		adapter.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/controller/grpc/response"
+	"github.com/aalexanderkevin/crypto-wallet/controller/middleware"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	cegrpc "github.com/aalexanderkevin/crypto-wallet/transport/grpc/crypto-wallet"
+	"github.com/aalexanderkevin/crypto-wallet/usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type TronResource struct {
+	appContainer *container.Container
+}
+
+func NewTronResourceHandler(appContainer *container.Container) *TronResource {
+	return &TronResource{appContainer: appContainer}
+}
+
+func (t *TronResource) GetTronResources(ctx context.Context, r *emptypb.Empty) (*cegrpc.TronResources, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.TronResource.GetTronResources")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	tronResourceUseCase := usecase.NewTronResource(t.appContainer)
+	resources, err := tronResourceUseCase.GetResources(ctx, &userId)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return &cegrpc.TronResources{
+		Address:            helper.Val(resources.Address),
+		FreeBandwidthLimit: helper.Val(resources.FreeBandwidthLimit),
+		FreeBandwidthUsed:  helper.Val(resources.FreeBandwidthUsed),
+		BandwidthLimit:     helper.Val(resources.BandwidthLimit),
+		BandwidthUsed:      helper.Val(resources.BandwidthUsed),
+		EnergyLimit:        helper.Val(resources.EnergyLimit),
+		EnergyUsed:         helper.Val(resources.EnergyUsed),
+	}, nil
+}
+
+func (t *TronResource) FreezeTron(ctx context.Context, r *cegrpc.TronStakeRequest) (*cegrpc.SendResponse, error) {
+	return t.stake(ctx, "Handler.TronResource.FreezeTron", r, usecase.NewTronResource(t.appContainer).Freeze)
+}
+
+func (t *TronResource) UnfreezeTron(ctx context.Context, r *cegrpc.TronStakeRequest) (*cegrpc.SendResponse, error) {
+	return t.stake(ctx, "Handler.TronResource.UnfreezeTron", r, usecase.NewTronResource(t.appContainer).Unfreeze)
+}
+
+func (t *TronResource) DelegateTronResource(ctx context.Context, r *cegrpc.TronStakeRequest) (*cegrpc.SendResponse, error) {
+	return t.stake(ctx, "Handler.TronResource.DelegateTronResource", r, usecase.NewTronResource(t.appContainer).Delegate)
+}
+
+func (t *TronResource) UndelegateTronResource(ctx context.Context, r *cegrpc.TronStakeRequest) (*cegrpc.SendResponse, error) {
+	return t.stake(ctx, "Handler.TronResource.UndelegateTronResource", r, usecase.NewTronResource(t.appContainer).Undelegate)
+}
+
+func (t *TronResource) stake(ctx context.Context, method string, r *cegrpc.TronStakeRequest, operation func(ctx context.Context, req *model.TronStake) (*string, error)) (*cegrpc.SendResponse, error) {
+	logger := helper.GetLogger(ctx).WithField("method", method)
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	req := &model.TronStake{
+		UserId:   helper.Pointer(userId),
+		Resource: helper.Pointer(r.GetResource()),
+		Amount:   helper.Pointer(r.GetAmount()),
+		Otp:      helper.Pointer(r.GetOtp()),
+	}
+	if r.GetReceiverAddress() != "" {
+		req.ReceiverAddress = helper.Pointer(r.GetReceiverAddress())
+	}
+	if err := req.Validate(); err != nil {
+		logger.WithError(err).Warning("missing required field")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	hashTx, err := operation(ctx, req)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return &cegrpc.SendResponse{
+		HashTransaction: *hashTx,
+	}, nil
+}
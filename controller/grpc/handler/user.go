@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/controller/grpc/response"
+	"github.com/aalexanderkevin/crypto-wallet/controller/middleware"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	cegrpc "github.com/aalexanderkevin/crypto-wallet/transport/grpc/crypto-wallet"
+	"github.com/aalexanderkevin/crypto-wallet/usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type User struct {
+	appContainer *container.Container
+}
+
+func NewUserHandler(appContainer *container.Container) *User {
+	return &User{appContainer: appContainer}
+}
+
+func (u *User) Register(ctx context.Context, r *cegrpc.RegisterRequest) (*cegrpc.User, error) {
+	userUseCase := usecase.NewUser(u.appContainer)
+	user, err := userUseCase.Register(ctx, &model.User{
+		Username: helper.Pointer(r.GetUsername()),
+		Email:    helper.Pointer(r.GetEmail()),
+		FullName: helper.Pointer(r.GetFullName()),
+		Password: helper.Pointer(r.GetPassword()),
+	})
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	res := &cegrpc.User{
+		Id:       helper.Val(user.Id),
+		Username: helper.Val(user.Username),
+		Email:    helper.Val(user.Email),
+		FullName: helper.Val(user.FullName),
+	}
+	if user.CreatedAt != nil {
+		res.CreatedAt = user.CreatedAt.Unix()
+	}
+
+	return res, nil
+}
+
+func (u *User) Login(ctx context.Context, r *cegrpc.LoginRequest) (*cegrpc.LoginResponse, error) {
+	userUseCase := usecase.NewUser(u.appContainer)
+	user, err := userUseCase.Login(ctx, &model.User{
+		Username: helper.Pointer(r.GetUsername()),
+		Password: helper.Pointer(r.GetPassword()),
+	})
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	refreshToken, stored, err := userUseCase.IssueRefreshToken(ctx, *user.Id, nil)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return u.tokenResponse(ctx, user, *refreshToken, stored)
+}
+
+func (u *User) RefreshToken(ctx context.Context, r *cegrpc.RefreshTokenRequest) (*cegrpc.LoginResponse, error) {
+	if r.GetRefreshToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh_token is required")
+	}
+
+	userUseCase := usecase.NewUser(u.appContainer)
+	user, refreshToken, stored, err := userUseCase.RotateRefreshToken(ctx, r.GetRefreshToken())
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return u.tokenResponse(ctx, user, *refreshToken, stored)
+}
+
+func (u *User) Logout(ctx context.Context, r *emptypb.Empty) (*emptypb.Empty, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.User.Logout")
+
+	claim := middleware.GetJWTClaims(ctx)
+	if claim == nil || claim.ExpiresAt == nil {
+		err := errors.New("cant find jwt data on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	userUseCase := usecase.NewUser(u.appContainer)
+	if err := userUseCase.Logout(ctx, claim.ID, claim.ExpiresAt.Time, claim.FamilyId); err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (u *User) EnrollTOTP(ctx context.Context, r *emptypb.Empty) (*cegrpc.EnrollTOTPResponse, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.User.EnrollTOTP")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	totpUseCase := usecase.NewTotp(u.appContainer)
+	secret, uri, err := totpUseCase.Enroll(ctx, &userId)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return &cegrpc.EnrollTOTPResponse{
+		Secret: secret,
+		Uri:    uri,
+	}, nil
+}
+
+func (u *User) VerifyTOTP(ctx context.Context, r *cegrpc.VerifyTOTPRequest) (*emptypb.Empty, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.User.VerifyTOTP")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if r.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	totpUseCase := usecase.NewTotp(u.appContainer)
+	if err := totpUseCase.Verify(ctx, &userId, r.GetCode()); err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// tokenResponse issues the access token that goes with the refresh token
+func (u *User) tokenResponse(ctx context.Context, user *model.User, refreshToken string, stored *model.RefreshToken) (*cegrpc.LoginResponse, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.User.tokenResponse")
+
+	cfg := u.appContainer.Config()
+	accessToken, expiresAt, err := middleware.GenerateJwt(user, *stored.FamilyId, cfg.JwtSecret, cfg.Jwt, time.Duration(cfg.JwtTtlSeconds)*time.Second)
+	if err != nil {
+		logger.WithError(err).Warn("failed generate jwt")
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return &cegrpc.LoginResponse{
+		AccessToken:      *accessToken,
+		ExpiresAt:        expiresAt.Unix(),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: stored.ExpiresAt.Unix(),
+	}, nil
+}
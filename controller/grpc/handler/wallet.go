@@ -28,25 +28,27 @@ func NewWalletHandler(appContainer *container.Container) *Wallet {
 func (w *Wallet) CreateWallet(ctx context.Context, r *emptypb.Empty) (*cegrpc.CreteWalletResponse, error) {
 	logger := helper.GetLogger(ctx).WithField("method", "Handler.Wallet.CreateWallet")
 
-	email := middleware.GetJWTData(ctx)
-	if email == "" {
-		err := errors.New("cant find email on token")
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
 		logger.WithError(err)
 		return nil, status.Error(codes.Unauthenticated, err.Error())
 	}
 
 	walletUseCase := usecase.NewWallet(w.appContainer)
-	wallet, err := walletUseCase.CreateNewWallet(ctx, &email)
+	wallet, err := walletUseCase.CreateNewWallet(ctx, &userId)
 	if err != nil {
 		return nil, response.SendErrorResponse(err)
 	}
 
 	// Successful authentication, return hash wallet
 	return &cegrpc.CreteWalletResponse{
-		Id:         *wallet.Id,
-		Email:      email,
-		BtcAddress: *wallet.BtcAddress,
-		EthAddress: *wallet.EthAddress,
-		TrxAddress: *wallet.TrxAddress,
+		Id:          *wallet.Id,
+		Email:       helper.Val(wallet.Email),
+		BtcAddress:  *wallet.BtcAddress,
+		EthAddress:  *wallet.EthAddress,
+		TrxAddress:  *wallet.TrxAddress,
+		LtcAddress:  helper.Val(wallet.LtcAddress),
+		DogeAddress: helper.Val(wallet.DogeAddress),
 	}, nil
 }
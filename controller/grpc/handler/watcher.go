@@ -25,9 +25,9 @@ func NewWatcherHandler(appContainer *container.Container) *Watcher {
 func (w *Watcher) TriggerWatcher(ctx context.Context, req *cegrpc.TriggerWatcherRequest) (*cegrpc.TriggerWatcherResponse, error) {
 	logger := helper.GetLogger(ctx).WithField("method", "Handler.Watcher.TriggerWatcher")
 
-	email := middleware.GetJWTData(ctx)
-	if email == "" {
-		err := errors.New("cant find id on token")
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
 		logger.WithError(err)
 		return nil, status.Error(codes.Unauthenticated, err.Error())
 	}
@@ -35,21 +35,8 @@ func (w *Watcher) TriggerWatcher(ctx context.Context, req *cegrpc.TriggerWatcher
 	transactionUseCase := usecase.NewTransaction(w.appContainer)
 	watcherUseCase := usecase.NewWatcher(w.appContainer, *transactionUseCase)
 
-	var address *string
-	var err error
-	switch req.GetToken() {
-	case "eth", "ethereum":
-		address, err = watcherUseCase.TriggerWatcherEth(ctx, &email)
-		if err != nil {
-			return nil, response.SendErrorResponse(err)
-		}
-	case "trx", "tron":
-		address, err = watcherUseCase.TriggerWatcherTrx(ctx, &email)
-		if err != nil {
-			return nil, response.SendErrorResponse(err)
-		}
-	default:
-		err := errors.New("invalid transfer token")
+	address, err := watcherUseCase.TriggerWatcher(ctx, &userId, helper.Pointer(req.GetToken()))
+	if err != nil {
 		return nil, response.SendErrorResponse(err)
 	}
 
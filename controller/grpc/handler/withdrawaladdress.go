@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/controller/grpc/response"
+	"github.com/aalexanderkevin/crypto-wallet/controller/middleware"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	cegrpc "github.com/aalexanderkevin/crypto-wallet/transport/grpc/crypto-wallet"
+	"github.com/aalexanderkevin/crypto-wallet/usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type WithdrawalAddress struct {
+	appContainer *container.Container
+}
+
+func NewWithdrawalAddressHandler(appContainer *container.Container) *WithdrawalAddress {
+	return &WithdrawalAddress{appContainer: appContainer}
+}
+
+func (w *WithdrawalAddress) AddWithdrawalAddress(ctx context.Context, r *cegrpc.AddWithdrawalAddressRequest) (*cegrpc.WithdrawalAddress, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.WithdrawalAddress.AddWithdrawalAddress")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if r.GetToken() == "" || r.GetAddress() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token and address are required")
+	}
+
+	address := &model.WithdrawalAddress{
+		UserId:  &userId,
+		Chain:   helper.Pointer(r.GetToken()),
+		Address: helper.Pointer(r.GetAddress()),
+	}
+	if r.GetLabel() != "" {
+		address.Label = helper.Pointer(r.GetLabel())
+	}
+
+	withdrawalAddressUseCase := usecase.NewWithdrawalAddress(w.appContainer)
+	address, err := withdrawalAddressUseCase.Add(ctx, address)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return toWithdrawalAddressResponse(*address), nil
+}
+
+func (w *WithdrawalAddress) RemoveWithdrawalAddress(ctx context.Context, r *cegrpc.RemoveWithdrawalAddressRequest) (*emptypb.Empty, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.WithdrawalAddress.RemoveWithdrawalAddress")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if r.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	withdrawalAddressUseCase := usecase.NewWithdrawalAddress(w.appContainer)
+	if err := withdrawalAddressUseCase.Delete(ctx, userId, r.GetId()); err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (w *WithdrawalAddress) ListWithdrawalAddresses(ctx context.Context, r *cegrpc.ListWithdrawalAddressesRequest) (*cegrpc.ListWithdrawalAddressesResponse, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.WithdrawalAddress.ListWithdrawalAddresses")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	var chain *string
+	if r.GetToken() != "" {
+		chain = helper.Pointer(r.GetToken())
+	}
+
+	withdrawalAddressUseCase := usecase.NewWithdrawalAddress(w.appContainer)
+	addresses, user, err := withdrawalAddressUseCase.ListAddresses(ctx, &userId, chain)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	res := &cegrpc.ListWithdrawalAddressesResponse{Allowlist: toWithdrawalAllowlistResponse(*user)}
+	for _, address := range addresses {
+		res.Addresses = append(res.Addresses, toWithdrawalAddressResponse(address))
+	}
+
+	return res, nil
+}
+
+func (w *WithdrawalAddress) SetWithdrawalAllowlist(ctx context.Context, r *cegrpc.SetWithdrawalAllowlistRequest) (*cegrpc.WithdrawalAllowlist, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.WithdrawalAddress.SetWithdrawalAllowlist")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	withdrawalAddressUseCase := usecase.NewWithdrawalAddress(w.appContainer)
+	user, err := withdrawalAddressUseCase.SetAllowlist(ctx, &userId, r.GetEnabled())
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return toWithdrawalAllowlistResponse(*user), nil
+}
+
+func toWithdrawalAddressResponse(address model.WithdrawalAddress) *cegrpc.WithdrawalAddress {
+	return &cegrpc.WithdrawalAddress{
+		Id:        helper.Val(address.Id),
+		Token:     helper.Val(address.Chain),
+		Address:   helper.Val(address.Address),
+		Label:     helper.Val(address.Label),
+		UsableAt:  helper.ValTimeUnix(address.UsableAt),
+		CreatedAt: helper.ValTimeUnix(address.CreatedAt),
+	}
+}
+
+func toWithdrawalAllowlistResponse(user model.User) *cegrpc.WithdrawalAllowlist {
+	return &cegrpc.WithdrawalAllowlist{
+		Enabled:    user.AllowlistEnforced(time.Now()),
+		DisabledAt: helper.ValTimeUnix(user.AllowlistDisabledAt),
+	}
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/controller/grpc/response"
+	"github.com/aalexanderkevin/crypto-wallet/controller/middleware"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	cegrpc "github.com/aalexanderkevin/crypto-wallet/transport/grpc/crypto-wallet"
+	"github.com/aalexanderkevin/crypto-wallet/usecase"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type WithdrawalRequest struct {
+	appContainer *container.Container
+}
+
+func NewWithdrawalRequestHandler(appContainer *container.Container) *WithdrawalRequest {
+	return &WithdrawalRequest{appContainer: appContainer}
+}
+
+func (w *WithdrawalRequest) RequestWithdrawal(ctx context.Context, r *cegrpc.SendRequest) (*cegrpc.WithdrawalRequest, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.WithdrawalRequest.RequestWithdrawal")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	req := &model.SendToken{
+		UserId:          helper.Pointer(userId),
+		ReceiverAddress: helper.Pointer(r.GetToAddress()),
+		Amount:          helper.Pointer(r.GetAmount()),
+		Token:           helper.Pointer(r.GetToken()),
+		Otp:             helper.Pointer(r.GetOtp()),
+	}
+	err := req.Validate()
+	if err != nil {
+		logger.WithError(err).Warning("missing required field")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	withdrawalRequestUseCase := usecase.NewWithdrawalRequest(w.appContainer)
+	request, err := withdrawalRequestUseCase.Request(ctx, req)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return toWithdrawalRequestResponse(*request), nil
+}
+
+func (w *WithdrawalRequest) GetWithdrawal(ctx context.Context, r *cegrpc.GetWithdrawalRequest) (*cegrpc.WithdrawalRequest, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.WithdrawalRequest.GetWithdrawal")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if r.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	withdrawalRequestUseCase := usecase.NewWithdrawalRequest(w.appContainer)
+	request, err := withdrawalRequestUseCase.GetRequest(ctx, userId, r.GetId())
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return toWithdrawalRequestResponse(*request), nil
+}
+
+func (w *WithdrawalRequest) ApproveWithdrawal(ctx context.Context, r *cegrpc.ApproveWithdrawalRequest) (*cegrpc.WithdrawalRequest, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.WithdrawalRequest.ApproveWithdrawal")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if r.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	withdrawalRequestUseCase := usecase.NewWithdrawalRequest(w.appContainer)
+	request, err := withdrawalRequestUseCase.Approve(ctx, userId, r.GetId())
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return toWithdrawalRequestResponse(*request), nil
+}
+
+func (w *WithdrawalRequest) RejectWithdrawal(ctx context.Context, r *cegrpc.RejectWithdrawalRequest) (*cegrpc.WithdrawalRequest, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.WithdrawalRequest.RejectWithdrawal")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if r.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	var reason *string
+	if r.GetReason() != "" {
+		reason = helper.Pointer(r.GetReason())
+	}
+
+	withdrawalRequestUseCase := usecase.NewWithdrawalRequest(w.appContainer)
+	request, err := withdrawalRequestUseCase.Reject(ctx, userId, r.GetId(), reason)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	return toWithdrawalRequestResponse(*request), nil
+}
+
+func (w *WithdrawalRequest) ListPendingApprovals(ctx context.Context, _ *emptypb.Empty) (*cegrpc.ListWithdrawalRequestsResponse, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Handler.WithdrawalRequest.ListPendingApprovals")
+
+	userId := middleware.GetUserId(ctx)
+	if userId == "" {
+		err := errors.New("cant find user id on token")
+		logger.WithError(err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	withdrawalRequestUseCase := usecase.NewWithdrawalRequest(w.appContainer)
+	requests, err := withdrawalRequestUseCase.ListPending(ctx, userId)
+	if err != nil {
+		return nil, response.SendErrorResponse(err)
+	}
+
+	res := &cegrpc.ListWithdrawalRequestsResponse{}
+	for _, request := range requests {
+		res.WithdrawalRequests = append(res.WithdrawalRequests, toWithdrawalRequestResponse(request))
+	}
+
+	return res, nil
+}
+
+func toWithdrawalRequestResponse(request model.WithdrawalRequest) *cegrpc.WithdrawalRequest {
+	res := &cegrpc.WithdrawalRequest{
+		Id:                helper.Val(request.Id),
+		WalletId:          helper.Val(request.WalletId),
+		UserId:            helper.Val(request.UserId),
+		Token:             helper.Val(request.Chain),
+		ToAddress:         helper.Val(request.ReceiverAddress),
+		Amount:            helper.Val(request.Amount),
+		Status:            helper.Val(request.Status),
+		RequiredApprovals: int32(helper.Val(request.RequiredApprovals)),
+		ApproverIds:       request.ApproverIds,
+		TransactionId:     helper.Val(request.TransactionId),
+		FailureReason:     helper.Val(request.FailureReason),
+		ExpiresAt:         helper.ValTimeUnix(request.ExpiresAt),
+		CreatedAt:         helper.ValTimeUnix(request.CreatedAt),
+		UpdatedAt:         helper.ValTimeUnix(request.UpdatedAt),
+	}
+	for _, approval := range request.Approvals {
+		res.Approvals = append(res.Approvals, &cegrpc.WithdrawalApproval{
+			ApproverId: helper.Val(approval.ApproverId),
+			Decision:   helper.Val(approval.Decision),
+			Reason:     helper.Val(approval.Reason),
+			CreatedAt:  helper.ValTimeUnix(approval.CreatedAt),
+		})
+	}
+
+	return res
+}
@@ -17,9 +17,16 @@ import (
 )
 
 type Controllers struct {
+	handler.User
 	handler.Wallet
 	handler.Transaction
 	handler.Watcher
+	handler.Event
+	handler.OutboundWebhook
+	handler.TronResource
+	handler.WithdrawalAddress
+	handler.WithdrawalRequest
+	handler.Admin
 }
 
 func StartgRPC(app *container.Container, cfg config.Config) {
@@ -30,16 +37,33 @@ func StartgRPC(app *container.Container, cfg config.Config) {
 	}
 
 	// List of excluded methods (full method names).
-	excludedMethods := []string{}
+	excludedMethods := []string{
+		"/crypto_wallet.CryptoWallet/Login",
+		"/crypto_wallet.CryptoWallet/Register",
+		"/crypto_wallet.CryptoWallet/RefreshToken",
+	}
+
+	verifier, err := middleware.NewVerifier(cfg.JwtSecret, cfg.Jwt)
+	if err != nil {
+		log.Fatalf("failed to init jwt verifier: %v", err)
+	}
 
 	server := grpc.NewServer(
-		grpc.UnaryInterceptor(middleware.JWTMiddleware(cfg.JwtSecret, excludedMethods)),
+		grpc.UnaryInterceptor(middleware.JWTMiddleware(verifier, excludedMethods, middleware.DefaultPolicy(), app.Redis(), app.AuditEventRepo())),
+		grpc.StreamInterceptor(middleware.JWTStreamMiddleware(verifier, excludedMethods, middleware.DefaultPolicy(), app.Redis(), app.AuditEventRepo())),
 	)
 
 	controllers := &Controllers{
-		Wallet:      *handler.NewWalletHandler(app),
-		Transaction: *handler.NewTransactionHandler(app),
-		Watcher:     *handler.NewWatcherHandler(app),
+		User:              *handler.NewUserHandler(app),
+		Wallet:            *handler.NewWalletHandler(app),
+		Transaction:       *handler.NewTransactionHandler(app),
+		Watcher:           *handler.NewWatcherHandler(app),
+		Event:             *handler.NewEventHandler(app),
+		OutboundWebhook:   *handler.NewOutboundWebhookHandler(app),
+		TronResource:      *handler.NewTronResourceHandler(app),
+		WithdrawalAddress: *handler.NewWithdrawalAddressHandler(app),
+		WithdrawalRequest: *handler.NewWithdrawalRequestHandler(app),
+		Admin:             *handler.NewAdminHandler(app),
 	}
 	cegrpc.RegisterCryptoWalletServer(server, controllers)
 
@@ -0,0 +1,214 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/exp/slices"
+)
+
+// BlockCypherWebhook authenticates BlockCypher callbacks: the shared path token when one is configured, and the
+// HTTP signature over the request target, body digest and date when a public key is configured. Every callback is
+// refused when neither is configured. Callbacks whose signed date is older than the configured max age are rejected,
+// and the signature, or the body without one, is remembered in replays for the window so it is only accepted once.
+func BlockCypherWebhook(cfg config.Bitcoin, replays service.Cache) gin.HandlerFunc {
+	var publicKey *ecdsa.PublicKey
+	if cfg.WebhookPublicKey != "" {
+		key, err := ParseEcdsaPublicKey(cfg.WebhookPublicKey)
+		if err != nil {
+			panic(fmt.Errorf("invalid blockcypher webhook public key: %w", err))
+		}
+		publicKey = key
+	}
+	maxAge := time.Duration(cfg.WebhookMaxAgeSeconds) * time.Second
+
+	return func(c *gin.Context) {
+		logger := helper.GetLogger(c).WithField("method", "Middleware.BlockCypherWebhook")
+
+		if err := cfg.CheckWebhookVerification(); err != nil {
+			logger.WithError(err).Warn("webhook verification is not configured")
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		if cfg.WebhookToken != "" && subtle.ConstantTimeCompare([]byte(c.Param("token")), []byte(cfg.WebhookToken)) != 1 {
+			logger.Warn("invalid webhook token")
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			logger.WithError(err).Warn("failed read webhook body")
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// a signed date is accepted maxAge either side of now, the token has no date and is only kept for maxAge
+		nonce, window := body, maxAge
+		if publicKey != nil {
+			if err := VerifyHttpSignature(c.Request, body, publicKey, maxAge, time.Now()); err != nil {
+				logger.WithError(err).Warn("invalid webhook signature")
+				c.AbortWithStatus(http.StatusUnauthorized)
+				return
+			}
+			nonce, window = []byte(signatureParams(c.Request)["signature"]), 2*maxAge
+		}
+
+		if replays == nil {
+			c.Next()
+			return
+		}
+
+		sum := sha256.Sum256(nonce)
+		key := "blockcypher:webhook:" + hex.EncodeToString(sum[:])
+		stored, err := replays.PutIfAbsent(c, key, "1", window)
+		if err != nil {
+			logger.WithError(err).Warn("failed record webhook")
+			c.AbortWithStatus(http.StatusServiceUnavailable)
+			return
+		}
+		if !stored {
+			logger.Warn("webhook replayed")
+			c.AbortWithStatus(http.StatusConflict)
+			return
+		}
+
+		c.Next()
+
+		// a callback that failed is retried by BlockCypher, it must not be taken for a replay
+		if c.Writer.Status() >= http.StatusMultipleChoices {
+			if err := replays.Delete(c, key); err != nil {
+				logger.WithError(err).Warn("failed forget webhook")
+			}
+		}
+	}
+}
+
+// VerifyHttpSignature checks a draft-cavage HTTP signature made with ecdsa-sha256, the signature has to cover
+// the digest of the body and the date so that neither can be swapped or replayed
+func VerifyHttpSignature(req *http.Request, body []byte, publicKey *ecdsa.PublicKey, maxAge time.Duration, now time.Time) error {
+	params := signatureParams(req)
+
+	signature, err := base64.StdEncoding.DecodeString(params["signature"])
+	if err != nil || len(signature) == 0 {
+		return errors.New("missing signature")
+	}
+
+	if algorithm := params["algorithm"]; algorithm != "" && algorithm != "ecdsa-sha256" {
+		return fmt.Errorf("unsupported signature algorithm %s", algorithm)
+	}
+
+	signedHeaders := strings.Fields(strings.ToLower(params["headers"]))
+	if len(signedHeaders) == 0 {
+		signedHeaders = []string{"date"}
+	}
+	if !slices.Contains(signedHeaders, "(request-target)") || !slices.Contains(signedHeaders, "digest") || !slices.Contains(signedHeaders, "date") {
+		return errors.New("signature must cover the request target, digest and date")
+	}
+
+	digest := sha256.Sum256(body)
+	if req.Header.Get("Digest") != "SHA-256="+base64.StdEncoding.EncodeToString(digest[:]) {
+		return errors.New("digest does not match the body")
+	}
+
+	date, err := http.ParseTime(req.Header.Get("Date"))
+	if err != nil {
+		return errors.New("invalid date")
+	}
+	if age := now.Sub(date); age > maxAge || age < -maxAge {
+		return errors.New("date is outside of the accepted window")
+	}
+
+	lines := make([]string, 0, len(signedHeaders))
+	for _, name := range signedHeaders {
+		if name == "(request-target)" {
+			lines = append(lines, fmt.Sprintf("%s: %s %s", name, strings.ToLower(req.Method), req.URL.RequestURI()))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", name, req.Header.Get(name)))
+	}
+	hashed := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+
+	if !verifyEcdsa(publicKey, hashed[:], signature) {
+		return errors.New("signature does not match")
+	}
+
+	return nil
+}
+
+func ParseEcdsaPublicKey(publicKeyPEM string) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicKeyPEM))
+	if block == nil {
+		return nil, errors.New("failed to decode public key")
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	ecdsaPublicKey, ok := publicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not of ECDSA type")
+	}
+
+	return ecdsaPublicKey, nil
+}
+
+// verifyEcdsa accepts both the ASN.1 encoding and the raw r||s concatenation of the signature
+func verifyEcdsa(publicKey *ecdsa.PublicKey, hashed []byte, signature []byte) bool {
+	if ecdsa.VerifyASN1(publicKey, hashed, signature) {
+		return true
+	}
+
+	size := (publicKey.Curve.Params().BitSize + 7) / 8
+	if len(signature) != 2*size {
+		return false
+	}
+	r := new(big.Int).SetBytes(signature[:size])
+	s := new(big.Int).SetBytes(signature[size:])
+	return ecdsa.Verify(publicKey, hashed, r, s)
+}
+
+// signatureParams are the parameters of the Signature header, or of the Authorization header when it is missing
+func signatureParams(req *http.Request) map[string]string {
+	header := req.Header.Get("Signature")
+	if header == "" {
+		header = strings.TrimPrefix(req.Header.Get("Authorization"), "Signature ")
+	}
+
+	return parseSignatureParams(header)
+}
+
+func parseSignatureParams(header string) map[string]string {
+	params := map[string]string{}
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		params[key] = strings.Trim(value, `"`)
+	}
+
+	return params
+}
@@ -0,0 +1,154 @@
+package middleware_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/controller/middleware"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+	"github.com/aalexanderkevin/crypto-wallet/service/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func signedWebhookRequest(t *testing.T, key *ecdsa.PrivateKey, body string, date time.Time) *http.Request {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/btc/webhook/transaction", strings.NewReader(body))
+	digest := sha256.Sum256([]byte(body))
+	req.Header.Set("Digest", "SHA-256="+base64.StdEncoding.EncodeToString(digest[:]))
+	req.Header.Set("Date", date.UTC().Format(http.TimeFormat))
+
+	signingString := fmt.Sprintf("(request-target): post /v1/btc/webhook/transaction\ndigest: %s\ndate: %s", req.Header.Get("Digest"), req.Header.Get("Date"))
+	hashed := sha256.Sum256([]byte(signingString))
+	signature, err := ecdsa.SignASN1(rand.Reader, key, hashed[:])
+	require.NoError(t, err)
+
+	req.Header.Set("Signature", fmt.Sprintf(`keyId="blockcypher",algorithm="ecdsa-sha256",headers="(request-target) digest date",signature="%s"`, base64.StdEncoding.EncodeToString(signature)))
+	return req
+}
+
+func TestVerifyHttpSignature(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	body := `{"hash":"abc"}`
+	now := time.Now()
+
+	t.Run("ShouldAccept_WhenSignatureIsValid", func(t *testing.T) {
+		// INIT
+		req := signedWebhookRequest(t, key, body, now)
+
+		// CODE UNDER TEST
+		err := middleware.VerifyHttpSignature(req, []byte(body), &key.PublicKey, 5*time.Minute, now)
+
+		// EXPECTATION
+		require.NoError(t, err)
+	})
+
+	t.Run("ShouldReject_WhenBodyIsTampered", func(t *testing.T) {
+		// INIT
+		req := signedWebhookRequest(t, key, body, now)
+
+		// CODE UNDER TEST
+		err := middleware.VerifyHttpSignature(req, []byte(`{"hash":"def"}`), &key.PublicKey, 5*time.Minute, now)
+
+		// EXPECTATION
+		require.Error(t, err)
+	})
+
+	t.Run("ShouldReject_WhenDateIsTooOld", func(t *testing.T) {
+		// INIT
+		req := signedWebhookRequest(t, key, body, now.Add(-10*time.Minute))
+
+		// CODE UNDER TEST
+		err := middleware.VerifyHttpSignature(req, []byte(body), &key.PublicKey, 5*time.Minute, now)
+
+		// EXPECTATION
+		require.Error(t, err)
+	})
+
+	t.Run("ShouldReject_WhenSignedByAnotherKey", func(t *testing.T) {
+		// INIT
+		otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		req := signedWebhookRequest(t, otherKey, body, now)
+
+		// CODE UNDER TEST
+		err = middleware.VerifyHttpSignature(req, []byte(body), &key.PublicKey, 5*time.Minute, now)
+
+		// EXPECTATION
+		require.Error(t, err)
+	})
+}
+
+func webhookEngine(cfg config.Bitcoin, replays service.Cache, status int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.POST("/v1/btc/webhook/transaction/:token", middleware.BlockCypherWebhook(cfg, replays), func(c *gin.Context) {
+		c.Status(status)
+	})
+
+	return engine
+}
+
+func TestBlockCypherWebhook(t *testing.T) {
+	t.Run("ShouldReject_WhenNoVerificationIsConfigured", func(t *testing.T) {
+		// INIT
+		engine := webhookEngine(config.Bitcoin{WebhookMaxAgeSeconds: 300}, nil, http.StatusOK)
+		recorder := httptest.NewRecorder()
+
+		// CODE UNDER TEST
+		engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/v1/btc/webhook/transaction/any", strings.NewReader(`{}`)))
+
+		// EXPECTATION
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+
+	t.Run("ShouldReject_WhenTheCallbackIsReplayed", func(t *testing.T) {
+		// INIT
+		replays := &mocks.Cache{}
+		replays.On("PutIfAbsent", mock.Anything, mock.Anything, "1", 300*time.Second).Return(true, nil).Once()
+		replays.On("PutIfAbsent", mock.Anything, mock.Anything, "1", 300*time.Second).Return(false, nil).Once()
+		engine := webhookEngine(config.Bitcoin{WebhookToken: "token", WebhookMaxAgeSeconds: 300}, replays, http.StatusOK)
+
+		first := httptest.NewRecorder()
+		engine.ServeHTTP(first, httptest.NewRequest(http.MethodPost, "/v1/btc/webhook/transaction/token", strings.NewReader(`{"hash":"tx"}`)))
+		require.Equal(t, http.StatusOK, first.Code)
+
+		// CODE UNDER TEST
+		replayed := httptest.NewRecorder()
+		engine.ServeHTTP(replayed, httptest.NewRequest(http.MethodPost, "/v1/btc/webhook/transaction/token", strings.NewReader(`{"hash":"tx"}`)))
+
+		// EXPECTATION
+		require.Equal(t, http.StatusConflict, replayed.Code)
+		replays.AssertExpectations(t)
+	})
+
+	t.Run("ShouldForgetTheCallback_WhenItFailed", func(t *testing.T) {
+		// INIT
+		replays := &mocks.Cache{}
+		replays.On("PutIfAbsent", mock.Anything, mock.Anything, "1", 300*time.Second).Return(true, nil)
+		replays.On("Delete", mock.Anything, mock.Anything).Return(nil)
+		engine := webhookEngine(config.Bitcoin{WebhookToken: "token", WebhookMaxAgeSeconds: 300}, replays, http.StatusInternalServerError)
+		recorder := httptest.NewRecorder()
+
+		// CODE UNDER TEST
+		engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/v1/btc/webhook/transaction/token", strings.NewReader(`{"hash":"tx"}`)))
+
+		// EXPECTATION
+		require.Equal(t, http.StatusInternalServerError, recorder.Code)
+		replays.AssertCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
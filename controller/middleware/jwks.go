@@ -0,0 +1,228 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+
+	"golang.org/x/sync/singleflight"
+)
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// publicKey is a key of the set with the algorithm its jwk is restricted to, an empty alg leaves it to the key type
+type publicKey struct {
+	key crypto.PublicKey
+	alg string
+}
+
+// jwk is a key of a JSON Web Key Set, only the RSA and P-256 EC signing keys are read
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// KeySet caches the public keys of a JWKS by kid. The keys are read again once they are older than the refresh
+// interval, or when a token is signed with an unknown kid so a rotated key is picked up without waiting. The keys are
+// read outside the lock and by a single caller at a time, the other callers wait for its result
+type KeySet struct {
+	url        string
+	file       string
+	refresh    time.Duration
+	minRefresh time.Duration
+	httpClient *http.Client
+
+	loads singleflight.Group
+
+	mu        sync.Mutex
+	keys      map[string]publicKey
+	fetchedAt time.Time
+}
+
+func NewKeySet(cfg config.Jwt) (*KeySet, error) {
+	if cfg.JwksUrl != "" && cfg.JwksFile != "" {
+		return nil, errors.New("set either the JWKS url or the JWKS file")
+	}
+
+	return &KeySet{
+		url:        cfg.JwksUrl,
+		file:       cfg.JwksFile,
+		refresh:    time.Duration(cfg.JwksRefreshSeconds) * time.Second,
+		minRefresh: time.Duration(cfg.JwksMinRefreshSeconds) * time.Second,
+		httpClient: &http.Client{Timeout: time.Duration(cfg.JwksTimeoutSeconds) * time.Second},
+	}, nil
+}
+
+// Key returns the public key with the kid for a token signed with the alg, a key restricted to another algorithm is
+// not handed out
+func (k *KeySet) Key(ctx context.Context, kid string, alg string) (crypto.PublicKey, error) {
+	key, ok, err := k.lookup(ctx, kid)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, kid)
+	}
+	if key.alg != "" && key.alg != alg {
+		return nil, fmt.Errorf("signing key %q is for %s, not %s", kid, key.alg, alg)
+	}
+
+	return key.key, nil
+}
+
+func (k *KeySet) lookup(ctx context.Context, kid string) (publicKey, bool, error) {
+	k.mu.Lock()
+	age := time.Since(k.fetchedAt)
+	key, ok := k.keys[kid]
+	// an unknown kid refreshes the keys, but not more often than the minimum interval so tokens with made up kids
+	// cannot flood the provider
+	stale := k.keys == nil || age >= k.refresh || (!ok && age >= k.minRefresh)
+	k.mu.Unlock()
+
+	if !stale {
+		return key, ok, nil
+	}
+
+	// the keys are read for every waiting caller, so a caller giving up does not cancel the read of the others
+	loadCtx := helper.ContextWithRequestId(context.Background(), helper.Val(helper.GetRequestId(ctx)))
+	_, err, _ := k.loads.Do("jwks", func() (interface{}, error) {
+		return nil, k.load(loadCtx)
+	})
+	if err != nil {
+		if ok {
+			// the cached key stays usable while the provider cannot be reached
+			helper.GetLogger(ctx).WithField("method", "Middleware.KeySet.Key").WithError(err).Warn("failed refresh jwks, using the cached keys")
+			return key, true, nil
+		}
+		return publicKey{}, false, err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	key, ok = k.keys[kid]
+
+	return key, ok, nil
+}
+
+func (k *KeySet) load(ctx context.Context) error {
+	raw, err := k.read(ctx)
+	if err != nil {
+		return err
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return fmt.Errorf("failed decode jwks: %w", err)
+	}
+
+	keys := map[string]publicKey{}
+	for _, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		parsed, err := key.publicKey()
+		if err != nil {
+			helper.GetLogger(ctx).WithField("method", "Middleware.KeySet.load").WithField("kid", key.Kid).WithError(err).Warn("skipping jwk")
+			continue
+		}
+		keys[key.Kid] = publicKey{key: parsed, alg: key.Alg}
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys = keys
+	k.fetchedAt = time.Now()
+
+	return nil
+}
+
+func (k *KeySet) read(ctx context.Context) ([]byte, error) {
+	if k.file != "" {
+		return os.ReadFile(k.file)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := k.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed fetch jwks: HTTP %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+func (j jwk) publicKey() (crypto.PublicKey, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(j.E)
+		if err != nil {
+			return nil, err
+		}
+
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid rsa exponent")
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		if j.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %s", j.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(j.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("invalid ec point")
+		}
+
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", j.Kty)
+	}
+}
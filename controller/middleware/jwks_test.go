@@ -0,0 +1,241 @@
+package middleware_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/controller/middleware"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func rsaJwk(kid string, key *rsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kid": kid,
+		"kty": "RSA",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJwk(kid string, key *ecdsa.PublicKey) map[string]string {
+	return map[string]string{
+		"kid": kid,
+		"kty": "EC",
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		"y":   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+func signedToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.RegisteredClaims) string {
+	t.Helper()
+
+	if claims.ExpiresAt == nil {
+		claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Hour))
+	}
+	token := jwt.NewWithClaims(method, middleware.JWTData{RegisteredClaims: claims, Email: "email@gmail.com"})
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+// jwksServer serves the keys, the keys can be swapped to rotate them and the responses held to slow the server down
+type jwksServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	keys     []map[string]string
+	hold     chan struct{}
+	requests atomic.Int32
+}
+
+func newJwksServer(t *testing.T, keys ...map[string]string) *jwksServer {
+	server := &jwksServer{keys: keys}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.requests.Add(1)
+		server.mu.Lock()
+		hold, keys := server.hold, server.keys
+		server.mu.Unlock()
+		if hold != nil {
+			<-hold
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func (s *jwksServer) setKeys(keys ...map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+// holdResponses makes the server answer only once the returned channel is closed
+func (s *jwksServer) holdResponses() chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hold = make(chan struct{})
+	return s.hold
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("ShouldAcceptTheRS256Token_WhenTheKeyIsInTheJwksFile", func(t *testing.T) {
+		// INIT
+		raw, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{rsaJwk("rsa-1", &rsaKey.PublicKey)}})
+		require.NoError(t, err)
+		file := filepath.Join(t.TempDir(), "jwks.json")
+		require.NoError(t, os.WriteFile(file, raw, 0o600))
+
+		verifier, err := middleware.NewVerifier(jwtSecret, config.Jwt{JwksFile: file, JwksRefreshSeconds: 300})
+		require.NoError(t, err)
+		token := signedToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, jwt.RegisteredClaims{Subject: "user-id"})
+
+		// CODE UNDER TEST
+		claim, err := verifier.Verify(context.TODO(), token)
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.Equal(t, "user-id", claim.Subject)
+	})
+
+	t.Run("ShouldPickUpTheRotatedKey_WhenTheKidIsUnknown", func(t *testing.T) {
+		// INIT
+		server := newJwksServer(t, rsaJwk("rsa-1", &rsaKey.PublicKey))
+		verifier, err := middleware.NewVerifier(jwtSecret, config.Jwt{JwksUrl: server.URL, JwksRefreshSeconds: 300, JwksTimeoutSeconds: 5})
+		require.NoError(t, err)
+
+		_, err = verifier.Verify(context.TODO(), signedToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, jwt.RegisteredClaims{Subject: "user-id"}))
+		require.NoError(t, err)
+		_, err = verifier.Verify(context.TODO(), signedToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, jwt.RegisteredClaims{Subject: "user-id"}))
+		require.NoError(t, err)
+		require.Equal(t, int32(1), server.requests.Load())
+
+		// CODE UNDER TEST
+		server.setKeys(rsaJwk("rsa-1", &rsaKey.PublicKey), ecJwk("ec-2", &ecKey.PublicKey))
+		claim, err := verifier.Verify(context.TODO(), signedToken(t, jwt.SigningMethodES256, "ec-2", ecKey, jwt.RegisteredClaims{Subject: "user-id"}))
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.Equal(t, "user-id", claim.Subject)
+		require.Equal(t, int32(2), server.requests.Load())
+	})
+
+	t.Run("ShouldNotRefetch_WhenUnknownKidsComeTooOften", func(t *testing.T) {
+		// INIT
+		server := newJwksServer(t, rsaJwk("rsa-1", &rsaKey.PublicKey))
+		verifier, err := middleware.NewVerifier(jwtSecret, config.Jwt{JwksUrl: server.URL, JwksRefreshSeconds: 300, JwksMinRefreshSeconds: 30, JwksTimeoutSeconds: 5})
+		require.NoError(t, err)
+		_, err = verifier.Verify(context.TODO(), signedToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, jwt.RegisteredClaims{}))
+		require.NoError(t, err)
+
+		// CODE UNDER TEST
+		_, errFirst := verifier.Verify(context.TODO(), signedToken(t, jwt.SigningMethodES256, "unknown", ecKey, jwt.RegisteredClaims{}))
+		_, errSecond := verifier.Verify(context.TODO(), signedToken(t, jwt.SigningMethodES256, "unknown", ecKey, jwt.RegisteredClaims{}))
+
+		// EXPECTATION
+		require.ErrorIs(t, errFirst, middleware.ErrUnknownKey)
+		require.ErrorIs(t, errSecond, middleware.ErrUnknownKey)
+		require.Equal(t, int32(1), server.requests.Load())
+	})
+
+	t.Run("ShouldRejectTheToken_WhenTheIssuerOrAudienceDiffer", func(t *testing.T) {
+		// INIT
+		server := newJwksServer(t, rsaJwk("rsa-1", &rsaKey.PublicKey))
+		verifier, err := middleware.NewVerifier(jwtSecret, config.Jwt{JwksUrl: server.URL, JwksRefreshSeconds: 300, JwksTimeoutSeconds: 5, Issuer: "https://idp.example.com", Audience: "crypto-wallet"})
+		require.NoError(t, err)
+
+		// CODE UNDER TEST
+		_, errIssuer := verifier.Verify(context.TODO(), signedToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, jwt.RegisteredClaims{Issuer: "https://other.example.com", Audience: jwt.ClaimStrings{"crypto-wallet"}}))
+		_, errAudience := verifier.Verify(context.TODO(), signedToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, jwt.RegisteredClaims{Issuer: "https://idp.example.com", Audience: jwt.ClaimStrings{"other"}}))
+		_, errValid := verifier.Verify(context.TODO(), signedToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, jwt.RegisteredClaims{Issuer: "https://idp.example.com", Audience: jwt.ClaimStrings{"crypto-wallet"}}))
+
+		// EXPECTATION
+		require.ErrorIs(t, errIssuer, jwt.ErrTokenInvalidIssuer)
+		require.ErrorIs(t, errAudience, jwt.ErrTokenInvalidAudience)
+		require.NoError(t, errValid)
+	})
+
+	t.Run("ShouldRejectTheToken_WhenTheKeyIsForAnotherAlgorithm", func(t *testing.T) {
+		// INIT
+		key := rsaJwk("rsa-1", &rsaKey.PublicKey)
+		key["alg"] = "RS512"
+		server := newJwksServer(t, key)
+		verifier, err := middleware.NewVerifier(jwtSecret, config.Jwt{JwksUrl: server.URL, JwksRefreshSeconds: 300, JwksTimeoutSeconds: 5})
+		require.NoError(t, err)
+
+		// CODE UNDER TEST
+		_, err = verifier.Verify(context.TODO(), signedToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, jwt.RegisteredClaims{}))
+
+		// EXPECTATION
+		require.ErrorIs(t, err, jwt.ErrTokenUnverifiable)
+	})
+
+	t.Run("ShouldServeTheCachedKeys_WhileTheKeysAreFetched", func(t *testing.T) {
+		// INIT
+		server := newJwksServer(t, rsaJwk("rsa-1", &rsaKey.PublicKey))
+		verifier, err := middleware.NewVerifier(jwtSecret, config.Jwt{JwksUrl: server.URL, JwksRefreshSeconds: 300, JwksTimeoutSeconds: 5})
+		require.NoError(t, err)
+		_, err = verifier.Verify(context.TODO(), signedToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, jwt.RegisteredClaims{}))
+		require.NoError(t, err)
+
+		hold := server.holdResponses()
+		server.setKeys(rsaJwk("rsa-1", &rsaKey.PublicKey), ecJwk("ec-2", &ecKey.PublicKey))
+		fetched := make(chan error, 3)
+		for i := 0; i < 3; i++ {
+			go func() {
+				_, err := verifier.Verify(context.TODO(), signedToken(t, jwt.SigningMethodES256, "ec-2", ecKey, jwt.RegisteredClaims{}))
+				fetched <- err
+			}()
+		}
+		require.Eventually(t, func() bool { return server.requests.Load() == 2 }, time.Second, 10*time.Millisecond)
+
+		// CODE UNDER TEST
+		_, err = verifier.Verify(context.TODO(), signedToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, jwt.RegisteredClaims{}))
+
+		// EXPECTATION
+		require.NoError(t, err)
+		close(hold)
+		for i := 0; i < 3; i++ {
+			require.NoError(t, <-fetched)
+		}
+		require.Equal(t, int32(2), server.requests.Load())
+	})
+
+	t.Run("ShouldRejectTheRS256Token_WhenNoJwksIsConfigured", func(t *testing.T) {
+		// INIT
+		verifier, err := middleware.NewVerifier(jwtSecret, config.Jwt{})
+		require.NoError(t, err)
+
+		// CODE UNDER TEST
+		_, err = verifier.Verify(context.TODO(), signedToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, jwt.RegisteredClaims{}))
+
+		// EXPECTATION
+		require.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+	})
+}
@@ -4,51 +4,202 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
+	"github.com/aalexanderkevin/crypto-wallet/config"
 	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/service"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/segmentio/ksuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
+// authFailureAuditWindow is how often a source gets a failed authentication written to the audit log
+const authFailureAuditWindow = time.Minute
+
 type JWTData struct {
 	jwt.RegisteredClaims
 	Email string `json:"email"`
+	// FamilyId is the refresh token family the token was issued for, revoking the family revokes the token
+	FamilyId string `json:"fam,omitempty"`
+	// Scope is the space separated list of scopes the token grants
+	Scope string   `json:"scope,omitempty"`
+	Roles []string `json:"roles,omitempty"`
 }
 
-func JWTMiddleware(secretKey string, excludedMethods []string) func(context.Context, interface{}, *grpc.UnaryServerInfo, grpc.UnaryHandler) (interface{}, error) {
+// JWTMiddleware checks the token of every method but the excluded ones, a token in the denylist is rejected. The
+// denylist is skipped when it is nil. The token must grant the scope the policy asks for the method. The rejected calls
+// that present a token are written to the audit log when there is one, at most once per window for each source
+func JWTMiddleware(verifier *Verifier, excludedMethods []string, policy Policy, denylist service.Cache, audit repository.AuditEvent) func(context.Context, interface{}, *grpc.UnaryServerInfo, grpc.UnaryHandler) (interface{}, error) {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		// Check if the method is in the excluded list.
-		for _, method := range excludedMethods {
-			if method == info.FullMethod {
-				// Skip token validation for excluded methods.
-				return handler(ctx, req)
-			}
-		}
-
-		token, err := getTokenAuth(ctx)
+		ctx, err := authenticate(ctx, verifier, info.FullMethod, excludedMethods, policy, denylist, audit)
 		if err != nil {
 			return nil, err
 		}
-		claim, err := decodeJwtData(secretKey, token)
+
+		return handler(ctx, req)
+	}
+}
+
+func JWTStreamMiddleware(verifier *Verifier, excludedMethods []string, policy Policy, denylist service.Cache, audit repository.AuditEvent) func(interface{}, grpc.ServerStream, *grpc.StreamServerInfo, grpc.StreamHandler) error {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), verifier, info.FullMethod, excludedMethods, policy, denylist, audit)
 		if err != nil {
-			return nil, status.Errorf(codes.Unauthenticated, err.Error())
+			return err
 		}
-		if claim.Email == "" {
-			return nil, status.Errorf(codes.Unauthenticated, "missing email")
+
+		return handler(srv, &authenticatedServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticatedServerStream overrides the stream context so handlers can read the jwt data
+type authenticatedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (a *authenticatedServerStream) Context() context.Context {
+	return a.ctx
+}
+
+func authenticate(ctx context.Context, verifier *Verifier, fullMethod string, excludedMethods []string, policy Policy, denylist service.Cache, audit repository.AuditEvent) (context.Context, error) {
+	// Check if the method is in the excluded list.
+	for _, method := range excludedMethods {
+		if method == fullMethod {
+			// Skip token validation for excluded methods.
+			return ctx, nil
 		}
+	}
 
-		// Set the email in the gRPC context.
-		ctx = context.WithValue(ctx, helper.ContextKeyJwtData, claim.Email)
+	token, err := getTokenAuth(ctx)
+	if err != nil {
+		// a call without a token is not audited, it would let anyone fill the audit log
+		return nil, err
+	}
 
-		return handler(ctx, req)
+	claim, err := checkToken(ctx, verifier, token, fullMethod, policy, denylist)
+	if err != nil {
+		auditFailure(ctx, audit, denylist, fullMethod, claim, err)
+		return nil, err
+	}
+
+	// Set the jwt data in the gRPC context.
+	ctx = context.WithValue(ctx, helper.ContextKeyActorId, claim.Subject)
+	return context.WithValue(ctx, helper.ContextKeyJwtData, claim), nil
+}
+
+// checkToken returns the claims of the token of the call once it is verified, not revoked and granted the method, the
+// claims are returned with the error when the token could be verified
+func checkToken(ctx context.Context, verifier *Verifier, token string, fullMethod string, policy Policy, denylist service.Cache) (*JWTData, error) {
+	claim, err := verifier.Verify(ctx, token)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, err.Error())
+	}
+	if claim.Subject == "" {
+		return nil, status.Errorf(codes.Unauthenticated, "missing user id")
+	}
+	if claim.Email == "" {
+		return nil, status.Errorf(codes.Unauthenticated, "missing email")
+	}
+	if err := checkDenylist(ctx, denylist, claim); err != nil {
+		return claim, err
+	}
+	if err := authorize(ctx, policy, claim, fullMethod); err != nil {
+		return claim, err
+	}
+
+	return claim, nil
+}
+
+// auditFailure writes the rejected call to the audit log, the audit log is skipped when it is nil. A source only writes
+// its first failure of the window, the others are logged. Every failure is written when there is no cache to throttle
+// them
+func auditFailure(ctx context.Context, audit repository.AuditEvent, throttle service.Cache, fullMethod string, claim *JWTData, err error) {
+	if audit == nil {
+		return
+	}
+	logger := helper.GetLogger(ctx).WithField("method", "Middleware.auditFailure")
+
+	source := callSource(ctx)
+	if throttle != nil {
+		first, cacheErr := throttle.PutIfAbsent(ctx, "audit:auth_failed:"+source, "1", authFailureAuditWindow)
+		if cacheErr != nil {
+			logger.WithError(cacheErr).Warn("failed throttle audit event")
+			return
+		}
+		if !first {
+			logger.WithField("source", source).WithField("code", status.Code(err).String()).Warn("authentication failed")
+			return
+		}
+	}
+
+	event := &model.AuditEvent{
+		Action: helper.Pointer(model.AuditAuthFailed),
+		Metadata: map[string]string{
+			"method": fullMethod,
+			"code":   status.Code(err).String(),
+			"reason": err.Error(),
+			"source": source,
+		},
+	}
+	if claim != nil {
+		event.ActorId = helper.Pointer(claim.Subject)
+		event.Metadata["jti"] = claim.ID
 	}
+
+	if _, err := audit.Append(ctx, event); err != nil {
+		logger.WithError(err).Warn("failed append audit event")
+	}
+}
+
+// callSource is the host the call came from
+func callSource(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+// checkDenylist rejects a token revoked by its jti or by its refresh token family, the token is rejected as well
+// when the denylist cannot be read
+func checkDenylist(ctx context.Context, denylist service.Cache, claim *JWTData) error {
+	if denylist == nil {
+		return nil
+	}
+
+	keys := []string{model.JwtDenylistKey(claim.ID)}
+	if claim.FamilyId != "" {
+		keys = append(keys, model.TokenFamilyDenylistKey(claim.FamilyId))
+	}
+
+	for _, key := range keys {
+		_, err := denylist.Get(ctx, key)
+		if err == nil {
+			return status.Error(codes.Unauthenticated, "token revoked")
+		}
+		if !errors.Is(err, service.ErrCacheMiss) {
+			helper.GetLogger(ctx).WithField("method", "Middleware.checkDenylist").WithError(err).Warn("failed read token denylist")
+			return status.Error(codes.Unavailable, "failed check token revocation")
+		}
+	}
+
+	return nil
 }
 
 func getTokenAuth(ctx context.Context) (string, error) {
@@ -80,17 +231,62 @@ func getTokenAuth(ctx context.Context) (string, error) {
 	// return splitToken[1], nil
 }
 
-func decodeJwtData(secret string, tokenStr string) (*JWTData, error) {
+// Verifier checks the access tokens, HS256 tokens with the shared secret and RS256 or ES256 tokens with the keys of
+// the JWKS of the identity provider
+type Verifier struct {
+	secret []byte
+	keys   *KeySet
+	parser *jwt.Parser
+}
+
+// NewVerifier accepts HS256 tokens only unless a JWKS url or file is configured
+func NewVerifier(secretKey string, cfg config.Jwt) (*Verifier, error) {
+	verifier := &Verifier{secret: []byte(secretKey)}
+
+	methods := []string{jwt.SigningMethodHS256.Alg()}
+	if cfg.JwksUrl != "" || cfg.JwksFile != "" {
+		keys, err := NewKeySet(cfg)
+		if err != nil {
+			return nil, err
+		}
+		verifier.keys = keys
+		methods = append(methods, jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg())
+	}
+
+	options := []jwt.ParserOption{jwt.WithValidMethods(methods)}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+	verifier.parser = jwt.NewParser(options...)
+
+	return verifier, nil
+}
+
+func (v *Verifier) Verify(ctx context.Context, tokenStr string) (*JWTData, error) {
 	var claim JWTData
 
-	secretFn := func(token *jwt.Token) (interface{}, error) {
-		if _, validSignMethod := token.Method.(*jwt.SigningMethodHMAC); !validSignMethod {
+	keyFn := func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			if len(v.secret) == 0 {
+				return nil, errors.New("no secret for HS256 tokens")
+			}
+			return v.secret, nil
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+			kid, _ := token.Header["kid"].(string)
+			if kid == "" {
+				return nil, errors.New("missing kid")
+			}
+			return v.keys.Key(ctx, kid, token.Method.Alg())
+		default:
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(secret), nil
 	}
 
-	token, err := jwt.ParseWithClaims(tokenStr, &claim, secretFn)
+	token, err := v.parser.ParseWithClaims(tokenStr, &claim, keyFn)
 	if err != nil {
 		return nil, err
 	}
@@ -102,29 +298,58 @@ func decodeJwtData(secret string, tokenStr string) (*JWTData, error) {
 	return nil, fmt.Errorf("invalid token")
 }
 
-func GenerateJwt(email string, secretKey string) (*string, error) {
+// GenerateJwt issues the HS256 access token of the user for the refresh token family, the subject is the user id. The
+// token carries the configured issuer and audience so it passes the checks of Verifier
+func GenerateJwt(user *model.User, familyId string, secretKey string, cfg config.Jwt, ttl time.Duration) (*string, *time.Time, error) {
+	expiresAt := time.Now().Add(ttl)
 	claims := jwt.RegisteredClaims{
 		ID:        ksuid.New().String(),
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
-		Subject:   email,
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		Subject:   helper.Val(user.Id),
+		Issuer:    cfg.Issuer,
+	}
+	if cfg.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{cfg.Audience}
 	}
 
 	// generate token
 	accessClaims := JWTData{
 		RegisteredClaims: claims,
-		Email:            email,
+		Email:            helper.Val(user.Email),
+		FamilyId:         familyId,
+		Scope:            strings.Join(user.Scopes(), " "),
+		Roles:            user.Roles,
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
 	var byteSecret = []byte(secretKey)
 	accessToken, err := token.SignedString(byteSecret)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return &accessToken, nil
+	return &accessToken, &expiresAt, nil
 }
 
+// GetJWTClaims returns the claims of the token of the request, it is nil for the excluded methods
+func GetJWTClaims(ctx context.Context) *JWTData {
+	claim, _ := ctx.Value(helper.ContextKeyJwtData).(*JWTData)
+	return claim
+}
+
+// GetJWTData returns the email of the authenticated user
 func GetJWTData(ctx context.Context) string {
-	claim := ctx.Value(helper.ContextKeyJwtData)
-	return claim.(string)
+	claim, ok := ctx.Value(helper.ContextKeyJwtData).(*JWTData)
+	if !ok {
+		return ""
+	}
+	return claim.Email
+}
+
+// GetUserId returns the id of the authenticated user
+func GetUserId(ctx context.Context) string {
+	claim, ok := ctx.Value(helper.ContextKeyJwtData).(*JWTData)
+	if !ok {
+		return ""
+	}
+	return claim.Subject
 }
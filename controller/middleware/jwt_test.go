@@ -0,0 +1,156 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/controller/middleware"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+	"github.com/aalexanderkevin/crypto-wallet/service/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const jwtSecret = "secret"
+
+func authenticatedCall(t *testing.T, denylist service.Cache) (*middleware.JWTData, error) {
+	t.Helper()
+
+	user := &model.User{Id: helper.Pointer("user-id"), Email: helper.Pointer("email@gmail.com"), Roles: []string{model.RoleUser}}
+	token, _, err := middleware.GenerateJwt(user, "family-id", jwtSecret, config.Jwt{}, time.Hour)
+	require.NoError(t, err)
+	verifier, err := middleware.NewVerifier(jwtSecret, config.Jwt{})
+	require.NoError(t, err)
+	ctx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs("authorization", "Bearer "+*token))
+
+	var claim *middleware.JWTData
+	interceptor := middleware.JWTMiddleware(verifier, nil, middleware.DefaultPolicy(), denylist, nil)
+	_, err = interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/crypto_wallet.CryptoWallet/CreateWallet"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		claim = middleware.GetJWTClaims(ctx)
+		return nil, nil
+	})
+
+	return claim, err
+}
+
+func TestJWTMiddleware(t *testing.T) {
+	t.Run("ShouldSetTheClaims_WhenTheTokenIsNotRevoked", func(t *testing.T) {
+		// INIT
+		denylist := &mocks.Cache{}
+		denylist.On("Get", mock.Anything, mock.Anything).Return("", service.ErrCacheMiss)
+
+		// CODE UNDER TEST
+		claim, err := authenticatedCall(t, denylist)
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.Equal(t, "user-id", claim.Subject)
+		require.Equal(t, "family-id", claim.FamilyId)
+		denylist.AssertCalled(t, "Get", mock.Anything, model.TokenFamilyDenylistKey("family-id"))
+	})
+
+	t.Run("ShouldReturnUnauthenticated_WhenTheFamilyIsRevoked", func(t *testing.T) {
+		// INIT
+		denylist := &mocks.Cache{}
+		denylist.On("Get", mock.Anything, model.TokenFamilyDenylistKey("family-id")).Return("revoked", nil)
+		denylist.On("Get", mock.Anything, mock.Anything).Return("", service.ErrCacheMiss)
+
+		// CODE UNDER TEST
+		claim, err := authenticatedCall(t, denylist)
+
+		// EXPECTATION
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+		require.Nil(t, claim)
+	})
+
+	t.Run("ShouldReturnUnavailable_WhenTheDenylistFails", func(t *testing.T) {
+		// INIT
+		denylist := &mocks.Cache{}
+		denylist.On("Get", mock.Anything, mock.Anything).Return("", errors.New("connection refused"))
+
+		// CODE UNDER TEST
+		_, err := authenticatedCall(t, denylist)
+
+		// EXPECTATION
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("ShouldSkipTheDenylist_WhenThereIsNone", func(t *testing.T) {
+		// CODE UNDER TEST
+		claim, err := authenticatedCall(t, nil)
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.Equal(t, "email@gmail.com", claim.Email)
+	})
+}
+
+type auditEvents struct {
+	repository.AuditEvent
+	appended []*model.AuditEvent
+}
+
+func (a *auditEvents) Append(ctx context.Context, event *model.AuditEvent) (*model.AuditEvent, error) {
+	a.appended = append(a.appended, event)
+	return event, nil
+}
+
+func TestJWTMiddleware_AuditFailure(t *testing.T) {
+	call := func(t *testing.T, ctx context.Context, throttle service.Cache, audit repository.AuditEvent) error {
+		t.Helper()
+
+		verifier, err := middleware.NewVerifier(jwtSecret, config.Jwt{})
+		require.NoError(t, err)
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 40000}})
+
+		interceptor := middleware.JWTMiddleware(verifier, nil, middleware.DefaultPolicy(), throttle, audit)
+		_, err = interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/crypto_wallet.CryptoWallet/CreateWallet"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		return err
+	}
+
+	t.Run("ShouldNotAudit_WhenTheCallHasNoToken", func(t *testing.T) {
+		// INIT
+		audit := &auditEvents{}
+
+		// CODE UNDER TEST
+		err := call(t, context.TODO(), nil, audit)
+
+		// EXPECTATION
+		require.Error(t, err)
+		require.Empty(t, audit.appended)
+	})
+
+	t.Run("ShouldAuditOncePerWindow_WhenASourceKeepsFailing", func(t *testing.T) {
+		// INIT
+		audit := &auditEvents{}
+		throttle := &mocks.Cache{}
+		throttle.On("PutIfAbsent", mock.Anything, "audit:auth_failed:203.0.113.7", "1", mock.Anything).Return(true, nil).Once()
+		throttle.On("PutIfAbsent", mock.Anything, "audit:auth_failed:203.0.113.7", "1", mock.Anything).Return(false, nil)
+		ctx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs("authorization", "Bearer invalid"))
+
+		// CODE UNDER TEST
+		firstErr := call(t, ctx, throttle, audit)
+		secondErr := call(t, ctx, throttle, audit)
+
+		// EXPECTATION
+		require.Equal(t, codes.Unauthenticated, status.Code(firstErr))
+		require.Equal(t, codes.Unauthenticated, status.Code(secondErr))
+		require.Len(t, audit.appended, 1)
+		require.Equal(t, "203.0.113.7", audit.appended[0].Metadata["source"])
+	})
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	cegrpc "github.com/aalexanderkevin/crypto-wallet/transport/grpc/crypto-wallet"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ScopeAuthenticated is the scope of the methods any valid token may call
+const ScopeAuthenticated = ""
+
+// Policy maps the full method names to the scope they need, a method missing from the policy is denied
+type Policy map[string]string
+
+// DefaultPolicy is the policy of the CryptoWallet service, the methods excluded from authentication are not listed
+func DefaultPolicy() Policy {
+	return Policy{
+		cegrpc.CryptoWallet_Logout_FullMethodName:        ScopeAuthenticated,
+		cegrpc.CryptoWallet_GetWithdrawal_FullMethodName: ScopeAuthenticated,
+
+		cegrpc.CryptoWallet_GetTransaction_FullMethodName:          model.ScopeWalletRead,
+		cegrpc.CryptoWallet_ListTransactions_FullMethodName:        model.ScopeWalletRead,
+		cegrpc.CryptoWallet_EstimateFee_FullMethodName:             model.ScopeWalletRead,
+		cegrpc.CryptoWallet_GetBalance_FullMethodName:              model.ScopeWalletRead,
+		cegrpc.CryptoWallet_GetTronResources_FullMethodName:        model.ScopeWalletRead,
+		cegrpc.CryptoWallet_TriggerWatcher_FullMethodName:          model.ScopeWalletRead,
+		cegrpc.CryptoWallet_SubscribeWalletEvents_FullMethodName:   model.ScopeWalletRead,
+		cegrpc.CryptoWallet_ListWebhookEndpoints_FullMethodName:    model.ScopeWalletRead,
+		cegrpc.CryptoWallet_ListWebhookDeliveries_FullMethodName:   model.ScopeWalletRead,
+		cegrpc.CryptoWallet_ListWithdrawalAddresses_FullMethodName: model.ScopeWalletRead,
+
+		cegrpc.CryptoWallet_CreateWallet_FullMethodName:            model.ScopeWalletWrite,
+		cegrpc.CryptoWallet_EnrollTOTP_FullMethodName:              model.ScopeWalletWrite,
+		cegrpc.CryptoWallet_VerifyTOTP_FullMethodName:              model.ScopeWalletWrite,
+		cegrpc.CryptoWallet_RegisterWebhookEndpoint_FullMethodName: model.ScopeWalletWrite,
+		cegrpc.CryptoWallet_DeleteWebhookEndpoint_FullMethodName:   model.ScopeWalletWrite,
+		cegrpc.CryptoWallet_RedeliverWebhook_FullMethodName:        model.ScopeWalletWrite,
+		cegrpc.CryptoWallet_AddWithdrawalAddress_FullMethodName:    model.ScopeWalletWrite,
+		cegrpc.CryptoWallet_RemoveWithdrawalAddress_FullMethodName: model.ScopeWalletWrite,
+		cegrpc.CryptoWallet_SetWithdrawalAllowlist_FullMethodName:  model.ScopeWalletWrite,
+
+		cegrpc.CryptoWallet_SendToken_FullMethodName:              model.ScopeWalletSend,
+		cegrpc.CryptoWallet_FreezeTron_FullMethodName:             model.ScopeWalletSend,
+		cegrpc.CryptoWallet_UnfreezeTron_FullMethodName:           model.ScopeWalletSend,
+		cegrpc.CryptoWallet_DelegateTronResource_FullMethodName:   model.ScopeWalletSend,
+		cegrpc.CryptoWallet_UndelegateTronResource_FullMethodName: model.ScopeWalletSend,
+		cegrpc.CryptoWallet_RequestWithdrawal_FullMethodName:      model.ScopeWalletSend,
+
+		cegrpc.CryptoWallet_ApproveWithdrawal_FullMethodName:    model.ScopeWithdrawalApprove,
+		cegrpc.CryptoWallet_RejectWithdrawal_FullMethodName:     model.ScopeWithdrawalApprove,
+		cegrpc.CryptoWallet_ListPendingApprovals_FullMethodName: model.ScopeWithdrawalApprove,
+
+		cegrpc.CryptoWallet_AdminGetWallet_FullMethodName:            model.ScopeAdmin,
+		cegrpc.CryptoWallet_AdminGetBalance_FullMethodName:           model.ScopeAdmin,
+		cegrpc.CryptoWallet_AdminListTransactions_FullMethodName:     model.ScopeAdmin,
+		cegrpc.CryptoWallet_AdminSetSpendingLimit_FullMethodName:     model.ScopeAdmin,
+		cegrpc.CryptoWallet_AdminListSpendingLimits_FullMethodName:   model.ScopeAdmin,
+		cegrpc.CryptoWallet_AdminDeleteSpendingLimit_FullMethodName:  model.ScopeAdmin,
+		cegrpc.CryptoWallet_AdminSetApprovalPolicy_FullMethodName:    model.ScopeAdmin,
+		cegrpc.CryptoWallet_AdminListApprovalPolicies_FullMethodName: model.ScopeAdmin,
+		cegrpc.CryptoWallet_AdminListAuditEvents_FullMethodName:      model.ScopeAdmin,
+	}
+}
+
+// Scopes returns the scopes of the space separated scope claim
+func (j *JWTData) Scopes() []string {
+	return strings.Fields(j.Scope)
+}
+
+// HasScope tells if the token grants the scope, the admin scope and the admin role grant every scope
+func (j *JWTData) HasScope(scope string) bool {
+	for _, role := range j.Roles {
+		if role == model.RoleAdmin {
+			return true
+		}
+	}
+
+	for _, granted := range j.Scopes() {
+		if granted == scope || granted == model.ScopeAdmin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// authorize checks the token grants the scope the policy asks for the method, a denied call is audit logged
+func authorize(ctx context.Context, policy Policy, claim *JWTData, fullMethod string) error {
+	scope, ok := policy[fullMethod]
+	if ok && (scope == ScopeAuthenticated || claim.HasScope(scope)) {
+		return nil
+	}
+
+	helper.GetLogger(ctx).WithFields(logrus.Fields{
+		"audit":          "permission_denied",
+		"method":         fullMethod,
+		"user_id":        claim.Subject,
+		"jti":            claim.ID,
+		"required_scope": scope,
+		"scopes":         claim.Scope,
+		"roles":          claim.Roles,
+	}).Warn("permission denied")
+
+	return status.Error(codes.PermissionDenied, "permission denied")
+}
@@ -0,0 +1,81 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/controller/middleware"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	cegrpc "github.com/aalexanderkevin/crypto-wallet/transport/grpc/crypto-wallet"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func callAs(t *testing.T, roles []string, fullMethod string) error {
+	t.Helper()
+
+	user := &model.User{Id: helper.Pointer("user-id"), Email: helper.Pointer("email@gmail.com"), Roles: roles}
+	token, _, err := middleware.GenerateJwt(user, "family-id", jwtSecret, config.Jwt{}, time.Hour)
+	require.NoError(t, err)
+	verifier, err := middleware.NewVerifier(jwtSecret, config.Jwt{})
+	require.NoError(t, err)
+	ctx := metadata.NewIncomingContext(context.TODO(), metadata.Pairs("authorization", "Bearer "+*token))
+
+	interceptor := middleware.JWTMiddleware(verifier, nil, middleware.DefaultPolicy(), nil, nil)
+	_, err = interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: fullMethod}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, nil
+	})
+
+	return err
+}
+
+func TestPolicy(t *testing.T) {
+	t.Run("ShouldAllow_WhenTheTokenHasTheScope", func(t *testing.T) {
+		// CODE UNDER TEST
+		err := callAs(t, []string{model.RoleUser}, cegrpc.CryptoWallet_SendToken_FullMethodName)
+
+		// EXPECTATION
+		require.NoError(t, err)
+	})
+
+	t.Run("ShouldReturnPermissionDenied_WhenTheTokenMissesTheScope", func(t *testing.T) {
+		// CODE UNDER TEST
+		err := callAs(t, []string{model.RoleUser}, cegrpc.CryptoWallet_AdminGetWallet_FullMethodName)
+
+		// EXPECTATION
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("ShouldReturnPermissionDenied_WhenTheTokenHasNoRole", func(t *testing.T) {
+		// CODE UNDER TEST
+		err := callAs(t, nil, cegrpc.CryptoWallet_GetBalance_FullMethodName)
+
+		// EXPECTATION
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("ShouldAllowEveryMethod_WhenTheUserIsAdmin", func(t *testing.T) {
+		// CODE UNDER TEST
+		adminErr := callAs(t, []string{model.RoleAdmin}, cegrpc.CryptoWallet_AdminListTransactions_FullMethodName)
+		sendErr := callAs(t, []string{model.RoleAdmin}, cegrpc.CryptoWallet_SendToken_FullMethodName)
+
+		// EXPECTATION
+		require.NoError(t, adminErr)
+		require.NoError(t, sendErr)
+	})
+
+	t.Run("ShouldReturnPermissionDenied_WhenTheMethodIsNotInThePolicy", func(t *testing.T) {
+		// CODE UNDER TEST
+		err := callAs(t, []string{model.RoleAdmin}, "/crypto_wallet.CryptoWallet/Unknown")
+
+		// EXPECTATION
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/aalexanderkevin/crypto-wallet/container"
 	"github.com/aalexanderkevin/crypto-wallet/controller/middleware"
 	"github.com/aalexanderkevin/crypto-wallet/controller/restapi/handler"
+	"github.com/aalexanderkevin/crypto-wallet/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -23,6 +24,8 @@ type httpServer struct {
 	config     config.Config
 	engine     *gin.Engine
 	controller controllers
+	// replays remembers the webhooks already received
+	replays service.Cache
 }
 
 type controllers struct {
@@ -40,7 +43,7 @@ func NewHttpServer(container *container.Container) *httpServer {
 	controllers := controllers{
 		*handler.NewWebhook(container),
 	}
-	requestHandler := &httpServer{container.Config(), engine, controllers}
+	requestHandler := &httpServer{container.Config(), engine, controllers, container.Redis()}
 	requestHandler.setupRouting()
 
 	return requestHandler
@@ -1,5 +1,9 @@
 package restapi
 
+import (
+	"github.com/aalexanderkevin/crypto-wallet/controller/middleware"
+)
+
 // setupRouting contains REST path and handler configuration
 // @title webhook API
 // @version 1.0
@@ -14,10 +18,10 @@ func (h *httpServer) setupRouting() {
 	v1 := router.Group(h.config.Service.Path.V1)
 
 	// public API
-	btcAPI := v1.Group(h.config.Service.Path.Btc)
-	// Use(middleware.Auth(h.config.JobAdder.WebhookUsername, h.config.JobAdder.WebhookPassword))
+	btcAPI := v1.Group(h.config.Service.Path.Btc).Use(middleware.BlockCypherWebhook(h.config.Bitcoin, h.replays))
 	{
 		btcAPI.POST("/webhook/transaction", h.controller.webhook.Transaction)
+		btcAPI.POST("/webhook/transaction/:token", h.controller.webhook.Transaction)
 		// btcAPI.POST("/project/update/:tenant-id", h.controller.webhook.ProjectUpdate)
 	}
 }
@@ -0,0 +1,16 @@
+package helper
+
+import "time"
+
+// Backoff doubles base for every attempt after the first one, capped at max
+func Backoff(attempts int, base time.Duration, max time.Duration) time.Duration {
+	backoff := base
+	for i := 1; i < attempts && backoff < max; i++ {
+		backoff *= 2
+	}
+
+	if backoff > max {
+		return max
+	}
+	return backoff
+}
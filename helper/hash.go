@@ -5,6 +5,8 @@ import (
 	"fmt"
 )
 
+// Hash is the legacy salted SHA-1 password hash, it is only kept to verify the passwords stored before
+// service/password and is not safe for new ones
 func Hash(salt string, password string) string {
 	s := fmt.Sprintf("_%s+%s_", salt, password)
 	hash := sha1.New()
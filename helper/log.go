@@ -11,6 +11,7 @@ const (
 	ContextKeyRequestId   Key = "requestId"
 	ContextKeyJwtData     Key = "jwtData"
 	ContextKeyTokenBearer Key = "tokenBearer"
+	ContextKeyActorId     Key = "actorId"
 )
 
 type Key string
@@ -39,3 +40,23 @@ func ContextWithRequestId(ctx context.Context, requestId string) context.Context
 	}
 	return context.WithValue(defaultContext, ContextKeyRequestId, requestId)
 }
+
+// GetRequestId returns the request id of the context, nil when there is none
+func GetRequestId(ctx context.Context) *string {
+	reqId, ok := ctx.Value(ContextKeyRequestId).(string)
+	if !ok {
+		return nil
+	}
+
+	return &reqId
+}
+
+// GetActorId returns the id of the authenticated user the context acts for, nil when it is not a user request
+func GetActorId(ctx context.Context) *string {
+	actorId, ok := ctx.Value(ContextKeyActorId).(string)
+	if !ok || actorId == "" {
+		return nil
+	}
+
+	return &actorId
+}
@@ -2,6 +2,7 @@ package test
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -40,6 +41,13 @@ func FakeWalletCreate(t *testing.T, db *gorm.DB, callback func(wallet model.Wall
 	cfg := config.Instance()
 
 	fakeData := FakeWallet(t, callback)
+	// every wallet has an owner
+	if fakeData.UserId == nil {
+		fakeData.UserId = FakeUserCreate(t, db, func(user model.User) model.User {
+			user.Email = fakeData.Email
+			return user
+		}).Id
+	}
 
 	repo := gormrepo.NewWalletRepository(db)
 	res, err := repo.Add(context.TODO(), &fakeData, &cfg.Service.SeedPhraseEncryptionKey)
@@ -48,6 +56,34 @@ func FakeWalletCreate(t *testing.T, db *gorm.DB, callback func(wallet model.Wall
 	return res
 }
 
+func FakeUser(t *testing.T, cb func(user model.User) model.User) model.User {
+	t.Helper()
+
+	fakeRp := model.User{
+		Username:     helper.Pointer(fake.UserName()),
+		Email:        helper.Pointer(fake.EmailAddress()),
+		FullName:     helper.Pointer(fake.FullName()),
+		Password:     helper.Pointer(helper.Hash("salt", "password")),
+		PasswordSalt: helper.Pointer("salt"),
+	}
+	if cb != nil {
+		fakeRp = cb(fakeRp)
+	}
+	return fakeRp
+}
+
+func FakeUserCreate(t *testing.T, db *gorm.DB, callback func(user model.User) model.User) *model.User {
+	t.Helper()
+
+	fakeData := FakeUser(t, callback)
+
+	repo := gormrepo.NewUserRepository(db)
+	res, err := repo.Add(context.TODO(), &fakeData)
+	require.NoError(t, err)
+
+	return res
+}
+
 func FakeTransaction(t *testing.T, cb func(transaction model.Transaction) model.Transaction) model.Transaction {
 	t.Helper()
 
@@ -88,8 +124,11 @@ func FakeJwtToken(t *testing.T, data *string) (string, string) {
 	jwtClaims := middleware.JWTData{
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			Subject:   fake.CharactersN(7),
 		},
 		Email: fake.EmailAddress(),
+		Scope: strings.Join(model.User{Roles: []string{model.RoleUser}}.Scopes(), " "),
+		Roles: []string{model.RoleUser},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtClaims)
@@ -99,3 +138,69 @@ func FakeJwtToken(t *testing.T, data *string) (string, string) {
 	}
 	return accessToken, *data
 }
+
+func FakeWebhookEndpointCreate(t *testing.T, db *gorm.DB, callback func(endpoint model.WebhookEndpoint) model.WebhookEndpoint) *model.WebhookEndpoint {
+	t.Helper()
+
+	fakeData := model.WebhookEndpoint{
+		Url:    helper.Pointer("https://" + fake.DomainName() + "/webhook"),
+		Secret: helper.Pointer(fake.CharactersN(32)),
+		Active: helper.Pointer(true),
+	}
+	if callback != nil {
+		fakeData = callback(fakeData)
+	}
+	if fakeData.UserId == nil {
+		fakeData.UserId = FakeUserCreate(t, db, nil).Id
+	}
+
+	repo := gormrepo.NewWebhookEndpointRepository(db)
+	res, err := repo.Add(context.TODO(), &fakeData)
+	require.NoError(t, err)
+
+	return res
+}
+
+func FakeWebhookDeliveryCreate(t *testing.T, db *gorm.DB, callback func(delivery model.WebhookDelivery) model.WebhookDelivery) *model.WebhookDelivery {
+	t.Helper()
+
+	fakeData := model.WebhookDelivery{
+		EndpointId:    helper.Pointer(fake.CharactersN(7)),
+		EventType:     helper.Pointer(model.EventSendConfirmed),
+		Payload:       helper.Pointer(`{}`),
+		Status:        helper.Pointer(model.WebhookDeliveryPending),
+		Attempts:      helper.Pointer(0),
+		NextAttemptAt: helper.Pointer(time.Now().Add(-time.Minute)),
+	}
+	if callback != nil {
+		fakeData = callback(fakeData)
+	}
+
+	repo := gormrepo.NewWebhookDeliveryRepository(db)
+	res, err := repo.Add(context.TODO(), &fakeData)
+	require.NoError(t, err)
+
+	return res
+}
+
+func FakeJobCreate(t *testing.T, db *gorm.DB, callback func(job model.Job) model.Job) *model.Job {
+	t.Helper()
+
+	fakeData := model.Job{
+		Type:        helper.Pointer(model.JobCheckTransaction("eth")),
+		Payload:     helper.Pointer(`{}`),
+		Status:      helper.Pointer(model.JobQueued),
+		Attempts:    helper.Pointer(0),
+		MaxAttempts: helper.Pointer(5),
+		RunAt:       helper.Pointer(time.Now().Add(-time.Minute)),
+	}
+	if callback != nil {
+		fakeData = callback(fakeData)
+	}
+
+	repo := gormrepo.NewJobRepository(db)
+	res, err := repo.Add(context.TODO(), &fakeData)
+	require.NoError(t, err)
+
+	return res
+}
@@ -0,0 +1,28 @@
+CREATE TABLE webhook_endpoints (
+	id VARCHAR(255) PRIMARY KEY,
+	email VARCHAR(255) NOT NULL,
+	url TEXT NOT NULL,
+	secret VARCHAR(255) NOT NULL,
+	active BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NULL
+);
+
+CREATE INDEX webhook_endpoints_email_idx ON webhook_endpoints (email);
+
+CREATE TABLE webhook_deliveries (
+	id VARCHAR(255) PRIMARY KEY,
+	endpoint_id VARCHAR(255) NOT NULL,
+	event_type VARCHAR(50) NOT NULL,
+	payload TEXT NOT NULL,
+	status VARCHAR(10) NOT NULL,
+	attempts INT NOT NULL DEFAULT 0,
+	next_attempt_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	last_status_code INT NULL,
+	last_error TEXT NULL,
+	delivered_at timestamp NULL,
+	created_at timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NULL
+);
+
+CREATE INDEX webhook_deliveries_due_idx ON webhook_deliveries (status, next_attempt_at);
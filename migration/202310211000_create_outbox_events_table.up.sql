@@ -0,0 +1,13 @@
+CREATE TABLE outbox_events (
+	id BIGSERIAL PRIMARY KEY,
+	aggregate_type VARCHAR(50) NOT NULL,
+	aggregate_id VARCHAR(255) NOT NULL,
+	event_type VARCHAR(50) NOT NULL,
+	payload TEXT NOT NULL,
+	attempts INT NOT NULL DEFAULT 0,
+	last_error TEXT NULL,
+	created_at timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+	published_at timestamp NULL
+);
+
+CREATE INDEX outbox_events_unpublished_idx ON outbox_events (id) WHERE published_at IS NULL;
@@ -0,0 +1,15 @@
+CREATE TABLE jobs (
+	id VARCHAR(255) PRIMARY KEY,
+	type VARCHAR(50) NOT NULL,
+	payload TEXT NOT NULL,
+	status VARCHAR(10) NOT NULL,
+	attempts INT NOT NULL DEFAULT 0,
+	max_attempts INT NOT NULL,
+	run_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	last_error TEXT NULL,
+	completed_at timestamp NULL,
+	created_at timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NULL
+);
+
+CREATE INDEX jobs_due_idx ON jobs (status, run_at);
@@ -0,0 +1,7 @@
+ALTER TABLE btc_transactions ADD COLUMN created_at timestamp NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE eth_transactions ADD COLUMN created_at timestamp NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE trx_transactions ADD COLUMN created_at timestamp NULL DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX btc_transactions_status_idx ON btc_transactions (status);
+CREATE INDEX eth_transactions_status_idx ON eth_transactions (status);
+CREATE INDEX trx_transactions_status_idx ON trx_transactions (status);
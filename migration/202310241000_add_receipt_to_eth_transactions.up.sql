@@ -0,0 +1,6 @@
+ALTER TABLE eth_transactions ADD COLUMN gas_used BIGINT NULL;
+ALTER TABLE eth_transactions ADD COLUMN effective_gas_price BIGINT NULL;
+ALTER TABLE eth_transactions ADD COLUMN failure_reason TEXT NULL;
+
+CREATE INDEX eth_transactions_sender_address_idx ON eth_transactions (sender_address);
+CREATE INDEX eth_transactions_receiver_address_idx ON eth_transactions (receiver_address);
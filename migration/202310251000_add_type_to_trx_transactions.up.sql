@@ -0,0 +1,2 @@
+ALTER TABLE trx_transactions ADD COLUMN type VARCHAR(20) NOT NULL DEFAULT 'transfer';
+ALTER TABLE trx_transactions ADD COLUMN resource VARCHAR(10) NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE eth_transactions ADD COLUMN chain VARCHAR(32) NOT NULL DEFAULT 'eth';
+
+ALTER TABLE eth_transactions DROP CONSTRAINT eth_transactions_pkey;
+ALTER TABLE eth_transactions ADD PRIMARY KEY (chain, id);
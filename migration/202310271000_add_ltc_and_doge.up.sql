@@ -0,0 +1,13 @@
+ALTER TABLE wallets ADD COLUMN ltc_address VARCHAR(255) NULL;
+ALTER TABLE wallets ADD COLUMN doge_address VARCHAR(255) NULL;
+
+ALTER TABLE btc_transactions ADD COLUMN chain VARCHAR(32) NOT NULL DEFAULT 'btc';
+
+ALTER TABLE btc_transactions DROP CONSTRAINT btc_transactions_pkey;
+ALTER TABLE btc_transactions ADD PRIMARY KEY (chain, id);
+
+-- DOGE is stored in koinu and TRX in sun, both overflow INT after a few thousand coins
+ALTER TABLE btc_transactions ALTER COLUMN amount TYPE BIGINT;
+ALTER TABLE btc_transactions ALTER COLUMN fee TYPE BIGINT;
+ALTER TABLE trx_transactions ALTER COLUMN amount TYPE BIGINT;
+ALTER TABLE trx_transactions ALTER COLUMN fee TYPE BIGINT;
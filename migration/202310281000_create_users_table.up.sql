@@ -0,0 +1,28 @@
+CREATE TABLE users (
+	id VARCHAR(255) PRIMARY KEY,
+	username VARCHAR(255) NOT NULL UNIQUE,
+	email VARCHAR(255) NOT NULL UNIQUE,
+	full_name VARCHAR(60) NOT NULL,
+	password VARCHAR(255) NOT NULL,
+	password_salt VARCHAR(255) NOT NULL,
+	created_at timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NULL
+);
+
+-- the owners of the wallets and webhook endpoints created before users existed get a user of their email. Its password
+-- is not a valid hash so it can not log in until the password is reset
+INSERT INTO users (id, username, email, full_name, password, password_salt)
+SELECT 'legacy-' || md5(email), email, email, left(email, 60), '!', ''
+FROM (SELECT email FROM wallets UNION SELECT email FROM webhook_endpoints) AS owners;
+
+ALTER TABLE wallets ADD COLUMN user_id VARCHAR(255) NULL REFERENCES users (id);
+UPDATE wallets SET user_id = 'legacy-' || md5(email);
+ALTER TABLE wallets ALTER COLUMN user_id SET NOT NULL;
+CREATE UNIQUE INDEX wallets_user_id_idx ON wallets (user_id);
+
+ALTER TABLE webhook_endpoints ADD COLUMN user_id VARCHAR(255) NULL REFERENCES users (id);
+UPDATE webhook_endpoints SET user_id = 'legacy-' || md5(email);
+ALTER TABLE webhook_endpoints ALTER COLUMN user_id SET NOT NULL;
+DROP INDEX webhook_endpoints_email_idx;
+ALTER TABLE webhook_endpoints DROP COLUMN email;
+CREATE INDEX webhook_endpoints_user_id_idx ON webhook_endpoints (user_id);
@@ -0,0 +1,2 @@
+-- the salt of argon2id and bcrypt hashes is encoded in the hash, only legacy SHA-1 hashes keep a separate salt
+ALTER TABLE users ALTER COLUMN password_salt DROP NOT NULL;
@@ -0,0 +1,11 @@
+CREATE TABLE refresh_tokens (
+	id VARCHAR(255) PRIMARY KEY,
+	user_id VARCHAR(255) NOT NULL REFERENCES users (id),
+	family_id VARCHAR(255) NOT NULL,
+	expires_at timestamp NOT NULL,
+	used_at timestamp NULL,
+	revoked_at timestamp NULL,
+	created_at timestamp NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX refresh_tokens_family_id_idx ON refresh_tokens (family_id);
@@ -0,0 +1,25 @@
+CREATE TABLE spending_limits (
+	id VARCHAR(255) PRIMARY KEY,
+	user_id VARCHAR(255) NULL REFERENCES users (id),
+	chain VARCHAR(32) NOT NULL,
+	max_per_transaction BIGINT NULL,
+	max_per_day BIGINT NULL,
+	max_transactions_per_hour BIGINT NULL,
+	created_at timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- a chain has one global limit and one limit per user
+CREATE UNIQUE INDEX spending_limits_global_chain_idx ON spending_limits (chain) WHERE user_id IS NULL;
+CREATE UNIQUE INDEX spending_limits_user_id_chain_idx ON spending_limits (user_id, chain) WHERE user_id IS NOT NULL;
+
+CREATE TABLE spending_records (
+	id VARCHAR(255) PRIMARY KEY,
+	user_id VARCHAR(255) NOT NULL REFERENCES users (id),
+	chain VARCHAR(32) NOT NULL,
+	amount BIGINT NOT NULL,
+	transaction_id VARCHAR(255) NULL,
+	created_at timestamp NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX spending_records_user_id_chain_created_at_idx ON spending_records (user_id, chain, created_at);
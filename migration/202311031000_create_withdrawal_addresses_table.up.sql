@@ -0,0 +1,14 @@
+CREATE TABLE withdrawal_addresses (
+	id VARCHAR(255) PRIMARY KEY,
+	user_id VARCHAR(255) NOT NULL REFERENCES users (id),
+	chain VARCHAR(32) NOT NULL,
+	address VARCHAR(255) NOT NULL,
+	label VARCHAR(255) NULL,
+	usable_at timestamp NOT NULL,
+	created_at timestamp NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX withdrawal_addresses_user_id_chain_address_idx ON withdrawal_addresses (user_id, chain, address);
+
+ALTER TABLE users ADD COLUMN allowlist_enabled_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN allowlist_disabled_at TIMESTAMP;
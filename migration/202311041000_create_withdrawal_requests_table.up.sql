@@ -0,0 +1,43 @@
+CREATE TABLE approval_policies (
+	id VARCHAR(255) PRIMARY KEY,
+	wallet_id VARCHAR(255) NOT NULL,
+	chain VARCHAR(32) NOT NULL,
+	threshold BIGINT NOT NULL,
+	required_approvals INT NOT NULL,
+	approver_ids TEXT [] NOT NULL,
+	created_at timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX approval_policies_wallet_id_chain_idx ON approval_policies (wallet_id, chain);
+
+CREATE TABLE withdrawal_requests (
+	id VARCHAR(255) PRIMARY KEY,
+	wallet_id VARCHAR(255) NOT NULL,
+	user_id VARCHAR(255) NOT NULL REFERENCES users (id),
+	chain VARCHAR(32) NOT NULL,
+	receiver_address VARCHAR(255) NOT NULL,
+	amount BIGINT NOT NULL,
+	status VARCHAR(32) NOT NULL,
+	required_approvals INT NOT NULL,
+	approver_ids TEXT [] NOT NULL,
+	transaction_id VARCHAR(255) NULL,
+	failure_reason TEXT NULL,
+	expires_at timestamp NOT NULL,
+	created_at timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX withdrawal_requests_status_expires_at_idx ON withdrawal_requests (status, expires_at);
+CREATE INDEX withdrawal_requests_approver_ids_idx ON withdrawal_requests USING GIN (approver_ids);
+
+CREATE TABLE withdrawal_approvals (
+	id VARCHAR(255) PRIMARY KEY,
+	withdrawal_request_id VARCHAR(255) NOT NULL REFERENCES withdrawal_requests (id),
+	approver_id VARCHAR(255) NOT NULL REFERENCES users (id),
+	decision VARCHAR(32) NOT NULL,
+	reason TEXT NULL,
+	created_at timestamp NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX withdrawal_approvals_request_id_approver_id_idx ON withdrawal_approvals (withdrawal_request_id, approver_id);
@@ -0,0 +1,25 @@
+CREATE TABLE audit_events (
+	id BIGINT PRIMARY KEY,
+	action VARCHAR(64) NOT NULL,
+	actor_id VARCHAR(255) NULL,
+	target_id VARCHAR(255) NULL,
+	request_id VARCHAR(255) NULL,
+	metadata TEXT NOT NULL,
+	prev_hash VARCHAR(64) NOT NULL,
+	hash VARCHAR(64) NOT NULL,
+	created_at timestamp NOT NULL
+);
+
+CREATE INDEX audit_events_action_idx ON audit_events (action);
+CREATE INDEX audit_events_actor_id_idx ON audit_events (actor_id);
+CREATE INDEX audit_events_target_id_idx ON audit_events (target_id);
+
+-- the audit log is append-only, the rows cannot be changed or removed once written
+CREATE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only BEFORE UPDATE OR DELETE ON audit_events
+	FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
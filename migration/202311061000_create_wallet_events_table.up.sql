@@ -0,0 +1,9 @@
+CREATE TABLE wallet_events (
+	id BIGSERIAL PRIMARY KEY,
+	addresses TEXT [] NOT NULL,
+	payload TEXT NOT NULL,
+	created_at timestamp NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX wallet_events_addresses_idx ON wallet_events USING GIN (addresses);
+CREATE INDEX wallet_events_created_at_idx ON wallet_events (created_at);
@@ -0,0 +1 @@
+ALTER TABLE eth_transactions ADD COLUMN nonce BIGINT NULL;
@@ -0,0 +1,2 @@
+-- the global limits sum the records of all the users on a chain
+CREATE INDEX spending_records_chain_created_at_idx ON spending_records (chain, created_at);
@@ -0,0 +1,4 @@
+-- the reservation of a withdrawal request tells the worker how far a request left in signing got
+ALTER TABLE spending_records ADD COLUMN withdrawal_request_id VARCHAR(255) NULL;
+
+CREATE UNIQUE INDEX spending_records_withdrawal_request_id_idx ON spending_records (withdrawal_request_id) WHERE withdrawal_request_id IS NOT NULL;
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+)
+
+const (
+	AuditWalletCreated       = "wallet.created"
+	AuditSeedDecrypted       = "wallet.seed_decrypted"
+	AuditTransactionSent     = "transaction.sent"
+	AuditTransactionFailed   = "transaction.send_failed"
+	AuditWithdrawalRequested = "withdrawal.requested"
+	AuditWithdrawalApproved  = "withdrawal.approved"
+	AuditWithdrawalRejected  = "withdrawal.rejected"
+	AuditAuthFailed          = "auth.failed"
+)
+
+// AuditEvent is a row of the append-only audit log, each event carries the hash of the previous one so a changed or
+// removed event breaks the chain. The ids follow each other without gaps
+type AuditEvent struct {
+	Id        *int64            `json:"id"`
+	Action    *string           `json:"action"`
+	ActorId   *string           `json:"actor_id"`
+	TargetId  *string           `json:"target_id"`
+	RequestId *string           `json:"request_id"`
+	Metadata  map[string]string `json:"metadata"`
+	PrevHash  *string           `json:"prev_hash"`
+	Hash      *string           `json:"hash"`
+	CreatedAt *time.Time        `json:"created_at"`
+}
+
+// ComputeHash is the hex SHA-256 of the fields of the event and the hash of the previous one, the time is hashed in
+// microseconds as that is what the database keeps
+func (a AuditEvent) ComputeHash() (string, error) {
+	var createdAt int64
+	if a.CreatedAt != nil {
+		createdAt = a.CreatedAt.UnixMicro()
+	}
+	// an empty metadata reads back as an empty map, it is hashed like a missing one
+	metadata := a.Metadata
+	if len(metadata) == 0 {
+		metadata = nil
+	}
+
+	// the metadata keys are sorted by the encoding, the field order is fixed by the struct
+	data, err := json.Marshal(struct {
+		Id        int64             `json:"id"`
+		Action    string            `json:"action"`
+		ActorId   string            `json:"actor_id"`
+		TargetId  string            `json:"target_id"`
+		RequestId string            `json:"request_id"`
+		Metadata  map[string]string `json:"metadata"`
+		PrevHash  string            `json:"prev_hash"`
+		CreatedAt int64             `json:"created_at"`
+	}{
+		Id:        helper.Val(a.Id),
+		Action:    helper.Val(a.Action),
+		ActorId:   helper.Val(a.ActorId),
+		TargetId:  helper.Val(a.TargetId),
+		RequestId: helper.Val(a.RequestId),
+		Metadata:  metadata,
+		PrevHash:  helper.Val(a.PrevHash),
+		CreatedAt: createdAt,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Follows checks the event is the one after prev in the chain and its hash matches its fields, a nil prev is the
+// start of the chain
+func (a AuditEvent) Follows(prev *AuditEvent) error {
+	expectedId, expectedPrevHash := int64(1), ""
+	if prev != nil {
+		expectedId, expectedPrevHash = helper.Val(prev.Id)+1, helper.Val(prev.Hash)
+	}
+
+	if helper.Val(a.Id) != expectedId {
+		return fmt.Errorf("expected event %d, found %d", expectedId, helper.Val(a.Id))
+	}
+	if helper.Val(a.PrevHash) != expectedPrevHash {
+		return fmt.Errorf("event %d does not link to the previous event", helper.Val(a.Id))
+	}
+
+	hash, err := a.ComputeHash()
+	if err != nil {
+		return err
+	}
+	if hash != helper.Val(a.Hash) {
+		return fmt.Errorf("event %d does not match its hash", helper.Val(a.Id))
+	}
+
+	return nil
+}
+
+// AuditVerification is the result of checking the audit log, BrokenAt is the id of the first event breaking the chain.
+// The chain cannot tell its last events were cut off, LastHash is compared with a head recorded earlier for that
+type AuditVerification struct {
+	Checked  int64   `json:"checked"`
+	BrokenAt *int64  `json:"broken_at"`
+	Reason   *string `json:"reason"`
+	LastId   *int64  `json:"last_id"`
+	LastHash *string `json:"last_hash"`
+}
@@ -14,6 +14,12 @@ const (
 	ErrorDuplicate           codes.Code = codes.AlreadyExists
 	ErrorUnprocessableEntity codes.Code = codes.InvalidArgument
 	ErrorInternalServer      codes.Code = codes.Internal
+	ErrorOutOfRange          codes.Code = codes.OutOfRange
+	ErrorFailedPrecondition  codes.Code = codes.FailedPrecondition
+	ErrorUnavailable         codes.Code = codes.Unavailable
+	ErrorPermissionDenied    codes.Code = codes.PermissionDenied
+	ErrorResourceExhausted   codes.Code = codes.ResourceExhausted
+	ErrorBroadcastUnknown    codes.Code = codes.Unknown
 )
 
 type Error struct {
@@ -56,6 +62,38 @@ func NewInvalidPasswordError() Error {
 	return NewError("invalid password", ErrorUnauthenticated)
 }
 
+func NewResumeTokenExpiredError() Error {
+	return NewError("resume token expired", ErrorOutOfRange)
+}
+
+func NewFailedPreconditionError(msg *string) Error {
+	defaultMessage := "failed precondition"
+	if msg == nil {
+		msg = &defaultMessage
+	}
+	return NewError(*msg, ErrorFailedPrecondition)
+}
+
+func NewUnavailableError(msg *string) Error {
+	defaultMessage := "service unavailable"
+	if msg == nil {
+		msg = &defaultMessage
+	}
+	return NewError(*msg, ErrorUnavailable)
+}
+
+func NewPermissionDeniedError(msg *string) Error {
+	defaultMessage := "permission denied"
+	if msg == nil {
+		msg = &defaultMessage
+	}
+	return NewError(*msg, ErrorPermissionDenied)
+}
+
+func NewTooManyAttemptsError() Error {
+	return NewError("too many attempts, try again later", ErrorResourceExhausted)
+}
+
 func NewBadRequestError(msg *string) Error {
 	defaultMessage := "bad request"
 	if msg == nil {
@@ -64,6 +102,12 @@ func NewBadRequestError(msg *string) Error {
 	return NewError(*msg, ErrorBadRequest)
 }
 
+// NewBroadcastUnknownError reports a broadcast that failed without the chain refusing the transaction, for example on
+// a timeout, the transaction may still reach the chain
+func NewBroadcastUnknownError(err error) Error {
+	return NewError(fmt.Sprintf("the outcome of the broadcast is unknown: %s", err), ErrorBroadcastUnknown)
+}
+
 func IsDuplicateError(e error) bool {
 	var internalErr Error
 	if !errors.As(e, &internalErr) {
@@ -82,11 +126,24 @@ func IsNotFoundError(e error) bool {
 	return internalErr.Code == ErrorNotFound
 }
 
+// NewStatusNotOKError reports the status of a failed request, body is left out when nil
 func NewStatusNotOKError(code int, body []byte) Error {
-	e := fmt.Sprintf("status is not ok, status=%d body=%s", code, body)
+	e := fmt.Sprintf("status is not ok, status=%d", code)
+	if body != nil {
+		e = fmt.Sprintf("%s body=%s", e, body)
+	}
 	return NewError(e, ErrorInternalServer)
 }
 
+func IsUnavailableError(e error) bool {
+	var internalErr Error
+	if !errors.As(e, &internalErr) {
+		return false
+	}
+
+	return internalErr.Code == ErrorUnavailable
+}
+
 func IsParameterError(e error) bool {
 	var internalErr Error
 	if !errors.As(e, &internalErr) {
@@ -104,3 +161,12 @@ func IsBadRequestError(e error) bool {
 
 	return internalErr.Code == ErrorBadRequest
 }
+
+func IsBroadcastUnknownError(e error) bool {
+	var internalErr Error
+	if !errors.As(e, &internalErr) {
+		return false
+	}
+
+	return internalErr.Code == ErrorBroadcastUnknown
+}
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+const (
+	EventDepositDetected     = "deposit_detected"
+	EventConfirmationChanged = "confirmation_changed"
+	EventSendConfirmed       = "send_confirmed"
+	EventSendFailed          = "send_failed"
+)
+
+// WalletEventsChannel is the Postgres notification channel the wallet events are announced on
+const WalletEventsChannel = "wallet_events"
+
+type WalletEvent struct {
+	Sequence      uint64  `json:"-"`
+	ResumeToken   *string `json:"-"`
+	Type          *string `json:"type"`
+	Token         *string `json:"token"`
+	TransactionId *string `json:"transaction_id"`
+	// Addresses are the wallet addresses the event concerns, it is used to route the event to its owner
+	Addresses    []string `json:"addresses"`
+	Amount       *int64   `json:"amount"`
+	Confirmation *int64   `json:"confirmation"`
+	Status       *string  `json:"status"`
+	// FailureReason is set on send_failed events when the chain reports why the transaction failed
+	FailureReason *string    `json:"failure_reason"`
+	CreatedAt     *time.Time `json:"created_at"`
+}
+
+func NewTransactionEvent(eventType string, token string, trx *Transaction, addresses []string) WalletEvent {
+	return WalletEvent{
+		Type:          &eventType,
+		Token:         &token,
+		TransactionId: trx.Id,
+		Addresses:     addresses,
+		Amount:        trx.Amount,
+		Confirmation:  trx.Confirmation,
+		Status:        trx.Status,
+		FailureReason: trx.FailureReason,
+	}
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+const (
+	JobQueued = "queued"
+	JobDone   = "done"
+	JobDead   = "dead"
+)
+
+// JobCheckTransaction is the type of the job that follows a transaction of the chain until it is final
+func JobCheckTransaction(chain string) string {
+	return "check_transaction_" + chain
+}
+
+type Job struct {
+	Id          *string    `json:"id"`
+	Type        *string    `json:"type"`
+	Payload     *string    `json:"payload"`
+	Status      *string    `json:"status"`
+	Attempts    *int       `json:"attempts"`
+	MaxAttempts *int       `json:"max_attempts"`
+	RunAt       *time.Time `json:"run_at"`
+	LastError   *string    `json:"last_error"`
+	CompletedAt *time.Time `json:"completed_at"`
+	CreatedAt   *time.Time `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+const (
+	OutboxTransactionCreated             = "transaction.created"
+	OutboxTransactionStatusChanged       = "transaction.status_changed"
+	OutboxTransactionConfirmationChanged = "transaction.confirmation_changed"
+)
+
+type OutboxEvent struct {
+	Id            *int64     `json:"id"`
+	AggregateType *string    `json:"aggregate_type"`
+	AggregateId   *string    `json:"aggregate_id"`
+	EventType     *string    `json:"event_type"`
+	Payload       *string    `json:"payload"`
+	Attempts      *int       `json:"attempts"`
+	LastError     *string    `json:"last_error"`
+	CreatedAt     *time.Time `json:"created_at"`
+	PublishedAt   *time.Time `json:"published_at"`
+}
+
+type TransactionChangedPayload struct {
+	Token                *string      `json:"token"`
+	Transaction          *Transaction `json:"transaction"`
+	PreviousStatus       *string      `json:"previous_status"`
+	PreviousConfirmation *int64       `json:"previous_confirmation"`
+}
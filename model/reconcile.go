@@ -0,0 +1,10 @@
+package model
+
+type TransactionReconciliation struct {
+	Token                *string `json:"token"`
+	TransactionId        *string `json:"transaction_id"`
+	PreviousStatus       *string `json:"previous_status"`
+	Status               *string `json:"status"`
+	PreviousConfirmation *int64  `json:"previous_confirmation"`
+	Confirmation         *int64  `json:"confirmation"`
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+)
+
+// SpendingLimit caps the transfers of a chain, a nil field is no limit. A user limit is checked against what the user
+// sent, the limit without a user is the global one and is checked against what all the users sent together
+type SpendingLimit struct {
+	Id                     *string    `json:"id"`
+	UserId                 *string    `json:"user_id"`
+	Chain                  *string    `json:"chain"`
+	MaxPerTransaction      *int64     `json:"max_per_transaction"`
+	MaxPerDay              *int64     `json:"max_per_day"`
+	MaxTransactionsPerHour *int64     `json:"max_transactions_per_hour"`
+	CreatedAt              *time.Time `json:"created_at"`
+	UpdatedAt              *time.Time `json:"updated_at"`
+}
+
+// Check returns a failed precondition error when sending the amount on top of the usage breaks the limit
+func (s SpendingLimit) Check(amount int64, usage SpendingUsage) error {
+	if s.MaxPerTransaction != nil && amount > *s.MaxPerTransaction {
+		return NewFailedPreconditionError(helper.Pointer(fmt.Sprintf("amount exceeds the limit of %d per transaction", *s.MaxPerTransaction)))
+	}
+	if s.MaxPerDay != nil && usage.AmountLastDay+amount > *s.MaxPerDay {
+		return NewFailedPreconditionError(helper.Pointer(fmt.Sprintf("amount exceeds the limit of %d per 24 hours, %d already sent", *s.MaxPerDay, usage.AmountLastDay)))
+	}
+	if s.MaxTransactionsPerHour != nil && usage.TransactionsLastHour+1 > *s.MaxTransactionsPerHour {
+		return NewFailedPreconditionError(helper.Pointer(fmt.Sprintf("exceeds the limit of %d transactions per hour", *s.MaxTransactionsPerHour)))
+	}
+
+	return nil
+}
+
+// SpendingUsage is what the owner of a limit sent on a chain over the rolling windows of the limits, all the users
+// are the owner of the global limit
+type SpendingUsage struct {
+	AmountLastDay        int64
+	TransactionsLastHour int64
+}
+
+// SpendingRecord is a transfer counted against the limits, it is recorded before signing and dropped when the
+// transfer fails
+type SpendingRecord struct {
+	Id            *string `json:"id"`
+	UserId        *string `json:"user_id"`
+	Chain         *string `json:"chain"`
+	Amount        *int64  `json:"amount"`
+	TransactionId *string `json:"transaction_id"`
+	// WithdrawalRequestId is set when the transfer executes a withdrawal request
+	WithdrawalRequestId *string    `json:"withdrawal_request_id"`
+	CreatedAt           *time.Time `json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// RefreshToken is stored under the SHA-256 of the token, the token itself is only known to the client. Each refresh
+// uses the token up and issues the next one of the same family, the family is the login it started from
+type RefreshToken struct {
+	Id        *string
+	UserId    *string
+	FamilyId  *string
+	ExpiresAt *time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt *time.Time
+}
+
+// JwtDenylistKey is the cache key that revokes the access token with the jti
+func JwtDenylistKey(jti string) string {
+	return "jwt:denylist:" + jti
+}
+
+// TokenFamilyDenylistKey is the cache key that revokes every access token issued for the refresh token family
+func TokenFamilyDenylistKey(familyId string) string {
+	return "jwt:denylist:family:" + familyId
+}
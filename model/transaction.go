@@ -10,6 +10,23 @@ import (
 	"github.com/blockcypher/gobcy/v2"
 )
 
+const (
+	TransactionStatusPending  = "pending"
+	TransactionStatusSuccess  = "success"
+	TransactionStatusFailed   = "failed"
+	TransactionStatusDropped  = "dropped"
+	TransactionStatusReplaced = "replaced"
+)
+
+const (
+	TransactionTypeTransfer = "transfer"
+	// the tron Stake 2.0 operations
+	TransactionTypeFreeze     = "freeze"
+	TransactionTypeUnfreeze   = "unfreeze"
+	TransactionTypeDelegate   = "delegate"
+	TransactionTypeUndelegate = "undelegate"
+)
+
 type Transaction struct {
 	Id              *string    `json:"id"`
 	SenderAddress   []string   `json:"sender_address"`
@@ -21,6 +38,20 @@ type Transaction struct {
 	Status          *string    `json:"status"`
 	ReceivedAt      *time.Time `json:"received_at"`
 	CompletedAt     *time.Time `json:"completed_at"`
+	CreatedAt       *time.Time `json:"created_at"`
+	// Type is the kind of operation, a transfer unless it is a tron staking operation on the given Resource
+	Type     *string `json:"type,omitempty"`
+	Resource *string `json:"resource,omitempty"`
+	// GasUsed and EffectiveGasPrice come from the receipt of an ethereum transaction, the fee is their product
+	GasUsed           *int64 `json:"gas_used,omitempty"`
+	EffectiveGasPrice *int64 `json:"effective_gas_price,omitempty"`
+	// FailureReason explains a failed transaction, for ethereum it is the revert reason when the node returns one
+	FailureReason *string `json:"failure_reason,omitempty"`
+	// Nonce is the account nonce of an ethereum transaction, once the account moved past it another transaction took
+	// its place
+	Nonce *int64 `json:"nonce,omitempty"`
+	// ExplorerUrl is the page of the transaction on the block explorer of its chain, it is not stored
+	ExplorerUrl *string `json:"explorer_url,omitempty"`
 }
 
 func (t Transaction) FromModel(data gobcy.TX) *Transaction {
@@ -33,12 +64,8 @@ func (t Transaction) FromModel(data gobcy.TX) *Transaction {
 		ouputs = append(ouputs, output.Addresses...)
 	}
 
-	var completedAt *time.Time
-	status := "pending"
-	if data.Confirmations == 6 {
-		status = "success"
-		completedAt = helper.Pointer(time.Now())
-	}
+	// the status is left pending, the confirmation policy decides when the transaction is final
+	status := TransactionStatusPending
 
 	return &Transaction{
 		Id:              &data.Hash,
@@ -49,23 +76,24 @@ func (t Transaction) FromModel(data gobcy.TX) *Transaction {
 		Confirmation:    helper.Pointer(int64(data.Confirmations)),
 		Status:          &status,
 		ReceivedAt:      &data.Received,
-		CompletedAt:     completedAt,
 	}
 }
 
 type SendToken struct {
-	Email           *string `json:"email"`
+	UserId          *string `json:"user_id"`
 	ReceiverAddress *string `json:"receiver_address"`
 	Amount          *int64  `json:"amount"`
 	Token           *string `json:"token"`
+	// Otp is the code of the authenticator app of the user
+	Otp *string `json:"-"`
 }
 
 func (s SendToken) Validate() error {
 	return validation.ValidateStruct(
 		&s,
-		validation.Field(&s.Email, validation.Required),
+		validation.Field(&s.UserId, validation.Required),
 		validation.Field(&s.ReceiverAddress, validation.Required),
-		validation.Field(&s.Amount, validation.Required),
+		validation.Field(&s.Amount, validation.Required, validation.Min(int64(1))),
 		validation.Field(&s.Token, validation.Required),
 	)
 }
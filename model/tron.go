@@ -0,0 +1,71 @@
+package model
+
+import (
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	TronResourceBandwidth = "bandwidth"
+	TronResourceEnergy    = "energy"
+)
+
+// TronAccountResources is the bandwidth and energy of a tron account, the free bandwidth is the daily allowance
+// every account gets while the rest comes from staked TRX
+type TronAccountResources struct {
+	Address            *string
+	FreeBandwidthLimit *int64
+	FreeBandwidthUsed  *int64
+	BandwidthLimit     *int64
+	BandwidthUsed      *int64
+	EnergyLimit        *int64
+	EnergyUsed         *int64
+}
+
+func (t TronAccountResources) AvailableFreeBandwidth() int64 {
+	return max64(0, helper.Val(t.FreeBandwidthLimit)-helper.Val(t.FreeBandwidthUsed))
+}
+
+func (t TronAccountResources) AvailableBandwidth() int64 {
+	return max64(0, helper.Val(t.BandwidthLimit)-helper.Val(t.BandwidthUsed))
+}
+
+func (t TronAccountResources) AvailableEnergy() int64 {
+	return max64(0, helper.Val(t.EnergyLimit)-helper.Val(t.EnergyUsed))
+}
+
+// TronFeeEstimate is the TRX a transfer burns when the sender lacks the bandwidth for it, plus the fee of activating
+// a receiver that does not exist on chain yet
+type TronFeeEstimate struct {
+	Bandwidth     *int64
+	BandwidthFee  *int64
+	ActivationFee *int64
+	Fee           *int64
+}
+
+// TronStake is a Stake 2.0 operation, the receiver is only used to delegate resources
+type TronStake struct {
+	UserId          *string
+	Resource        *string
+	Amount          *int64
+	ReceiverAddress *string
+	// Otp is the code of the authenticator app of the user
+	Otp *string
+}
+
+func (t TronStake) Validate() error {
+	return validation.ValidateStruct(
+		&t,
+		validation.Field(&t.UserId, validation.Required),
+		validation.Field(&t.Resource, validation.Required, validation.In(TronResourceBandwidth, TronResourceEnergy)),
+		validation.Field(&t.Amount, validation.Required, validation.Min(int64(1))),
+	)
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
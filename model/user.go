@@ -7,14 +7,47 @@ import (
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 )
 
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+	// RoleApprover approves the withdrawal requests of the wallets whose policy lists the user
+	RoleApprover = "approver"
+
+	ScopeWalletRead  = "wallet:read"
+	ScopeWalletWrite = "wallet:write"
+	ScopeWalletSend  = "wallet:send"
+	// ScopeWithdrawalApprove decides on the withdrawal requests the user is an approver of
+	ScopeWithdrawalApprove = "withdrawal:approve"
+	// ScopeAdmin grants every other scope and the methods on the wallets of other users
+	ScopeAdmin = "admin"
+)
+
+// roleScopes are the scopes the tokens of a role carry
+var roleScopes = map[string][]string{
+	RoleUser:     {ScopeWalletRead, ScopeWalletWrite, ScopeWalletSend},
+	RoleAdmin:    {ScopeAdmin},
+	RoleApprover: {ScopeWithdrawalApprove},
+}
+
 type User struct {
-	Id           *string    `json:"id"`
-	Username     *string    `json:"username"`
-	Email        *string    `json:"email"`
-	FullName     *string    `json:"full_name"`
-	Password     *string    `json:"-"`
-	PasswordSalt *string    `json:"-"`
-	CreatedAt    *time.Time `json:"created_at"`
+	Id           *string  `json:"id"`
+	Username     *string  `json:"username"`
+	Email        *string  `json:"email"`
+	FullName     *string  `json:"full_name"`
+	Password     *string  `json:"-"`
+	PasswordSalt *string  `json:"-"`
+	Roles        []string `json:"roles"`
+	// TotpSecret is the encrypted secret of the authenticator app, it is only used once TotpEnabledAt is set
+	TotpSecret    []byte     `json:"-"`
+	TotpEnabledAt *time.Time `json:"totp_enabled_at"`
+	// TotpLastStep is the time step of the last accepted code, a code is accepted once
+	TotpLastStep *int64 `json:"-"`
+	// AllowlistEnabledAt turns the withdrawal allowlist on, AllowlistDisabledAt is when a requested switch off takes
+	// effect
+	AllowlistEnabledAt  *time.Time `json:"allowlist_enabled_at"`
+	AllowlistDisabledAt *time.Time `json:"allowlist_disabled_at"`
+	CreatedAt           *time.Time `json:"created_at"`
+	UpdatedAt           *time.Time `json:"updated_at"`
 }
 
 func (u User) Validate() error {
@@ -23,7 +56,7 @@ func (u User) Validate() error {
 		validation.Field(&u.Email, validation.Required, is.Email),
 		validation.Field(&u.Username, validation.Required),
 		validation.Field(&u.FullName, validation.Required, validation.Length(3, 60)),
-		validation.Field(&u.Password, validation.Length(6, 64)),
+		validation.Field(&u.Password, validation.Required, validation.Length(6, 64)),
 	)
 }
 
@@ -34,3 +67,50 @@ func (u User) ValidateLogin() error {
 		validation.Field(&u.Password, validation.Required, validation.Length(6, 64)),
 	)
 }
+
+// Scopes returns the scopes of the roles of the user
+func (u User) Scopes() []string {
+	var scopes []string
+	seen := map[string]bool{}
+	for _, role := range u.Roles {
+		for _, scope := range roleScopes[role] {
+			if !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+
+	return scopes
+}
+
+// HasScope tells if the roles of the user grant the scope, the admin scope grants every scope
+func (u User) HasScope(scope string) bool {
+	for _, granted := range u.Scopes() {
+		if granted == scope || granted == ScopeAdmin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TotpEnabled tells if the user verified an authenticator app
+func (u User) TotpEnabled() bool {
+	return u.TotpEnabledAt != nil
+}
+
+// TotpAttemptsKey is the cache key counting the codes the user tried
+func TotpAttemptsKey(userId string) string {
+	return "totp:attempts:" + userId
+}
+
+// AllowlistEnforced tells if the transfers of the user may only go to the allowlisted addresses, switching the allowlist
+// off only takes effect after the cooling-off period
+func (u User) AllowlistEnforced(now time.Time) bool {
+	if u.AllowlistEnabledAt == nil {
+		return false
+	}
+
+	return u.AllowlistDisabledAt == nil || now.Before(*u.AllowlistDisabledAt)
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"crypto/ecdsa"
+	"math/big"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec"
@@ -11,14 +12,25 @@ import (
 )
 
 type Wallet struct {
-	Id         *string
-	Email      *string
-	SeedPhrase *string
-	BtcAddress *string
-	EthAddress *string
-	TrxAddress *string
-	CreatedAt  *time.Time
-	UpdatedAt  *time.Time
+	Id          *string
+	UserId      *string
+	Email       *string
+	SeedPhrase  *string
+	BtcAddress  *string
+	EthAddress  *string
+	TrxAddress  *string
+	LtcAddress  *string
+	DogeAddress *string
+	CreatedAt   *time.Time
+	UpdatedAt   *time.Time
+}
+
+// Balance is the balance of an address in the smallest unit of the native coin of the chain
+type Balance struct {
+	Chain   *string
+	Symbol  *string
+	Address *string
+	Balance *big.Int
 }
 
 type EthHdWallet struct {
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"regexp"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+type WebhookEndpoint struct {
+	Id        *string    `json:"id"`
+	UserId    *string    `json:"user_id"`
+	Url       *string    `json:"url"`
+	Secret    *string    `json:"-"`
+	Active    *bool      `json:"active"`
+	CreatedAt *time.Time `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+func (w WebhookEndpoint) Validate() error {
+	return validation.ValidateStruct(
+		&w,
+		validation.Field(&w.UserId, validation.Required),
+		// the payloads are signed but not encrypted, they only go over https
+		validation.Field(&w.Url, validation.Required, is.URL, validation.Match(regexp.MustCompile(`^https://`)).Error("must be an https url")),
+	)
+}
+
+type WebhookDelivery struct {
+	Id             *string    `json:"id"`
+	EndpointId     *string    `json:"endpoint_id"`
+	EventType      *string    `json:"event_type"`
+	Payload        *string    `json:"payload"`
+	Status         *string    `json:"status"`
+	Attempts       *int       `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"`
+	LastStatusCode *int       `json:"last_status_code"`
+	LastError      *string    `json:"last_error"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      *time.Time `json:"created_at"`
+	UpdatedAt      *time.Time `json:"updated_at"`
+}
+
+// WebhookPayload is the body posted to the merchant endpoint
+type WebhookPayload struct {
+	DeliveryId    *string `json:"delivery_id"`
+	Type          *string `json:"type"`
+	Token         *string `json:"token"`
+	TransactionId *string `json:"transaction_id"`
+	Address       *string `json:"address"`
+	Amount        *int64  `json:"amount"`
+	Confirmation  *int64  `json:"confirmation"`
+	Status        *string `json:"status"`
+	FailureReason *string `json:"failure_reason,omitempty"`
+	CreatedAt     *int64  `json:"created_at"`
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// WithdrawalAddress is an address the user allows the transfers of the chain to, it is usable once the cooling-off
+// period after it was added is over
+type WithdrawalAddress struct {
+	Id        *string    `json:"id"`
+	UserId    *string    `json:"user_id"`
+	Chain     *string    `json:"chain"`
+	Address   *string    `json:"address"`
+	Label     *string    `json:"label"`
+	UsableAt  *time.Time `json:"usable_at"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+func (w WithdrawalAddress) Usable(now time.Time) bool {
+	return w.UsableAt != nil && !now.Before(*w.UsableAt)
+}
+
+// NormalizeWithdrawalAddress makes the hex addresses of the EVM chains lower case so a checksummed address matches its
+// plain form, the other addresses are case sensitive and kept as they are
+func NormalizeWithdrawalAddress(address string) string {
+	address = strings.TrimSpace(address)
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		return strings.ToLower(address)
+	}
+
+	return address
+}
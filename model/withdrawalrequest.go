@@ -0,0 +1,92 @@
+package model
+
+import "time"
+
+// a withdrawal request goes requested -> approved or rejected or expired, then an approved one goes signing ->
+// broadcast or failed
+const (
+	WithdrawalRequested = "requested"
+	WithdrawalApproved  = "approved"
+	WithdrawalRejected  = "rejected"
+	WithdrawalExpired   = "expired"
+	WithdrawalSigning   = "signing"
+	WithdrawalBroadcast = "broadcast"
+	WithdrawalFailed    = "failed"
+
+	DecisionApproved = "approved"
+	DecisionRejected = "rejected"
+)
+
+// ApprovalPolicy makes the transfers of the wallet on the chain above the threshold wait for RequiredApprovals of
+// the approvers, the threshold is in the smallest unit of the chain
+type ApprovalPolicy struct {
+	Id                *string    `json:"id"`
+	WalletId          *string    `json:"wallet_id"`
+	Chain             *string    `json:"chain"`
+	Threshold         *int64     `json:"threshold"`
+	RequiredApprovals *int       `json:"required_approvals"`
+	ApproverIds       []string   `json:"approver_ids"`
+	CreatedAt         *time.Time `json:"created_at"`
+	UpdatedAt         *time.Time `json:"updated_at"`
+}
+
+// Requires tells if a transfer of the amount needs approvals
+func (a ApprovalPolicy) Requires(amount int64) bool {
+	return a.Threshold != nil && amount > *a.Threshold
+}
+
+// WithdrawalRequest is a transfer waiting for approvals, the quorum and the approvers are copied from the policy
+// when it is requested so a later policy change does not apply to it
+type WithdrawalRequest struct {
+	Id                *string              `json:"id"`
+	WalletId          *string              `json:"wallet_id"`
+	UserId            *string              `json:"user_id"`
+	Chain             *string              `json:"chain"`
+	ReceiverAddress   *string              `json:"receiver_address"`
+	Amount            *int64               `json:"amount"`
+	Status            *string              `json:"status"`
+	RequiredApprovals *int                 `json:"required_approvals"`
+	ApproverIds       []string             `json:"approver_ids"`
+	Approvals         []WithdrawalApproval `json:"approvals"`
+	TransactionId     *string              `json:"transaction_id"`
+	FailureReason     *string              `json:"failure_reason"`
+	ExpiresAt         *time.Time           `json:"expires_at"`
+	CreatedAt         *time.Time           `json:"created_at"`
+	UpdatedAt         *time.Time           `json:"updated_at"`
+}
+
+// IsApprover tells if the user is one of the approvers of the request, the requester never is
+func (w WithdrawalRequest) IsApprover(userId string) bool {
+	if w.UserId != nil && *w.UserId == userId {
+		return false
+	}
+	for _, approverId := range w.ApproverIds {
+		if approverId == userId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ApprovalCount is the number of approvals the request got
+func (w WithdrawalRequest) ApprovalCount() int {
+	count := 0
+	for _, approval := range w.Approvals {
+		if approval.Decision != nil && *approval.Decision == DecisionApproved {
+			count++
+		}
+	}
+
+	return count
+}
+
+// WithdrawalApproval is the decision of an approver on a withdrawal request
+type WithdrawalApproval struct {
+	Id                  *string    `json:"id"`
+	WithdrawalRequestId *string    `json:"withdrawal_request_id"`
+	ApproverId          *string    `json:"approver_id"`
+	Decision            *string    `json:"decision"`
+	Reason              *string    `json:"reason"`
+	CreatedAt           *time.Time `json:"created_at"`
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/model"
+)
+
+type AuditEvent interface {
+	// Append links the event to the last one of the log and adds it, the appends are serialized so the chain does not
+	// fork. The actor and the request id default to the ones of the context
+	Append(ctx context.Context, event *model.AuditEvent) (*model.AuditEvent, error)
+	// List returns the events matching the filter ordered by id
+	List(ctx context.Context, filter *AuditEventFilter) ([]model.AuditEvent, error)
+}
+
+type AuditEventFilter struct {
+	Action   *string
+	ActorId  *string
+	TargetId *string
+	From     *time.Time
+	To       *time.Time
+	// AfterId lists the events after the id, to page through the log
+	AfterId *int64
+	Limit   int
+}
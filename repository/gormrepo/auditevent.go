@@ -0,0 +1,182 @@
+package gormrepo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+
+	"gorm.io/gorm"
+)
+
+// auditEventsLock is the advisory lock serializing the appends to the audit log
+const auditEventsLock = "audit_events"
+
+type auditEvent struct {
+	Id        *int64
+	Action    *string
+	ActorId   *string
+	TargetId  *string
+	RequestId *string
+	Metadata  *string
+	PrevHash  *string
+	Hash      *string
+	CreatedAt *time.Time
+}
+
+func (a auditEvent) FromModel(data model.AuditEvent) (*auditEvent, error) {
+	metadata := data.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auditEvent{
+		Id:        data.Id,
+		Action:    data.Action,
+		ActorId:   data.ActorId,
+		TargetId:  data.TargetId,
+		RequestId: data.RequestId,
+		Metadata:  helper.Pointer(string(encoded)),
+		PrevHash:  data.PrevHash,
+		Hash:      data.Hash,
+		CreatedAt: data.CreatedAt,
+	}, nil
+}
+
+func (a auditEvent) ToModel() (*model.AuditEvent, error) {
+	metadata := map[string]string{}
+	if a.Metadata != nil {
+		if err := json.Unmarshal([]byte(*a.Metadata), &metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	return &model.AuditEvent{
+		Id:        a.Id,
+		Action:    a.Action,
+		ActorId:   a.ActorId,
+		TargetId:  a.TargetId,
+		RequestId: a.RequestId,
+		Metadata:  metadata,
+		PrevHash:  a.PrevHash,
+		Hash:      a.Hash,
+		CreatedAt: a.CreatedAt,
+	}, nil
+}
+
+func (a auditEvent) TableName() string {
+	return "audit_events"
+}
+
+type AuditEventRepo struct {
+	db *gorm.DB
+}
+
+func NewAuditEventRepository(db *gorm.DB) repository.AuditEvent {
+	return &AuditEventRepo{
+		db: db,
+	}
+}
+
+func (a *AuditEventRepo) Append(ctx context.Context, data *model.AuditEvent) (*model.AuditEvent, error) {
+	event := *data
+	if event.ActorId == nil {
+		event.ActorId = helper.GetActorId(ctx)
+	}
+	if event.RequestId == nil {
+		event.RequestId = helper.GetRequestId(ctx)
+	}
+	// the column keeps microseconds, the hash is computed on what is read back
+	event.CreatedAt = helper.Pointer(time.Now().UTC().Truncate(time.Microsecond))
+
+	var res *model.AuditEvent
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", auditEventsLock).Error; err != nil {
+			return err
+		}
+
+		var last auditEvent
+		err := tx.Order("id DESC").Limit(1).Take(&last).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			event.Id = helper.Pointer(int64(1))
+			event.PrevHash = helper.Pointer("")
+		case err != nil:
+			return err
+		default:
+			event.Id = helper.Pointer(*last.Id + 1)
+			event.PrevHash = last.Hash
+		}
+
+		hash, err := event.ComputeHash()
+		if err != nil {
+			return err
+		}
+		event.Hash = &hash
+
+		gormModel, err := auditEvent{}.FromModel(event)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(gormModel).Error; err != nil {
+			return err
+		}
+
+		res = &event
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (a *AuditEventRepo) List(ctx context.Context, filter *repository.AuditEventFilter) ([]model.AuditEvent, error) {
+	q := a.db.WithContext(ctx)
+	if filter.Action != nil {
+		q = q.Where("action = ?", filter.Action)
+	}
+	if filter.ActorId != nil {
+		q = q.Where("actor_id = ?", filter.ActorId)
+	}
+	if filter.TargetId != nil {
+		q = q.Where("target_id = ?", filter.TargetId)
+	}
+	if filter.From != nil {
+		q = q.Where("created_at >= ?", filter.From.UTC())
+	}
+	if filter.To != nil {
+		q = q.Where("created_at < ?", filter.To.UTC())
+	}
+	if filter.AfterId != nil {
+		q = q.Where("id > ?", filter.AfterId)
+	}
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+
+	events := []auditEvent{}
+	if err := q.Order("id").Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]model.AuditEvent, 0, len(events))
+	for _, event := range events {
+		data, err := event.ToModel()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *data)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,89 @@
+//go:build integration
+// +build integration
+
+package gormrepo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/repository/gormrepo"
+	"github.com/aalexanderkevin/crypto-wallet/storage"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditEventRepository_Append(t *testing.T) {
+	t.Run("ShouldLinkEachEventToThePreviousOne", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		auditEventRepo := gormrepo.NewAuditEventRepository(db)
+		ctx := context.WithValue(helper.ContextWithRequestId(context.TODO(), "request-id"), helper.ContextKeyActorId, "actor-id")
+
+		//-- code under test
+		for _, action := range []string{model.AuditWalletCreated, model.AuditSeedDecrypted, model.AuditTransactionSent} {
+			_, err := auditEventRepo.Append(ctx, &model.AuditEvent{
+				Action:   helper.Pointer(action),
+				TargetId: helper.Pointer("wallet-id"),
+				Metadata: map[string]string{"chain": "eth"},
+			})
+			require.NoError(t, err)
+		}
+
+		//-- assert
+		events, err := auditEventRepo.List(context.TODO(), &repository.AuditEventFilter{})
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+
+		var prev *model.AuditEvent
+		for i := range events {
+			require.NoError(t, events[i].Follows(prev))
+			require.Equal(t, "actor-id", *events[i].ActorId)
+			require.Equal(t, "request-id", *events[i].RequestId)
+			prev = &events[i]
+		}
+	})
+
+	t.Run("ShouldBreakTheChain_WhenAnEventIsChanged", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		auditEventRepo := gormrepo.NewAuditEventRepository(db)
+		first, err := auditEventRepo.Append(context.TODO(), &model.AuditEvent{Action: helper.Pointer(model.AuditAuthFailed)})
+		require.NoError(t, err)
+		second, err := auditEventRepo.Append(context.TODO(), &model.AuditEvent{Action: helper.Pointer(model.AuditAuthFailed)})
+		require.NoError(t, err)
+
+		//-- code under test
+		first.Metadata = map[string]string{"reason": "changed"}
+		err = first.Follows(nil)
+
+		//-- assert
+		require.EqualError(t, err, "event 1 does not match its hash")
+		require.NoError(t, second.Follows(&model.AuditEvent{Id: first.Id, Hash: first.Hash}))
+	})
+
+	t.Run("ShouldRejectTheUpdate_WhenAnEventIsChangedInTheDatabase", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		auditEventRepo := gormrepo.NewAuditEventRepository(db)
+		event, err := auditEventRepo.Append(context.TODO(), &model.AuditEvent{Action: helper.Pointer(model.AuditAuthFailed)})
+		require.NoError(t, err)
+
+		//-- code under test
+		errUpdate := db.Exec("UPDATE audit_events SET action = ? WHERE id = ?", model.AuditWalletCreated, event.Id).Error
+		errDelete := db.Exec("DELETE FROM audit_events WHERE id = ?", event.Id).Error
+
+		//-- assert
+		require.ErrorContains(t, errUpdate, "append-only")
+		require.ErrorContains(t, errDelete, "append-only")
+	})
+}
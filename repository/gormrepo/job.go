@@ -0,0 +1,170 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type job struct {
+	Id          *string
+	Type        *string
+	Payload     *string
+	Status      *string
+	Attempts    *int
+	MaxAttempts *int
+	RunAt       *time.Time
+	LastError   *string
+	CompletedAt *time.Time
+	CreatedAt   *time.Time
+	UpdatedAt   *time.Time
+}
+
+func (j job) FromModel(data model.Job) *job {
+	return &job{
+		Id:          data.Id,
+		Type:        data.Type,
+		Payload:     data.Payload,
+		Status:      data.Status,
+		Attempts:    data.Attempts,
+		MaxAttempts: data.MaxAttempts,
+		RunAt:       data.RunAt,
+		LastError:   data.LastError,
+		CompletedAt: data.CompletedAt,
+		CreatedAt:   data.CreatedAt,
+		UpdatedAt:   data.UpdatedAt,
+	}
+}
+
+func (j job) ToModel() *model.Job {
+	return &model.Job{
+		Id:          j.Id,
+		Type:        j.Type,
+		Payload:     j.Payload,
+		Status:      j.Status,
+		Attempts:    j.Attempts,
+		MaxAttempts: j.MaxAttempts,
+		RunAt:       j.RunAt,
+		LastError:   j.LastError,
+		CompletedAt: j.CompletedAt,
+		CreatedAt:   j.CreatedAt,
+		UpdatedAt:   j.UpdatedAt,
+	}
+}
+
+func (j job) TableName() string {
+	return "jobs"
+}
+
+func (j *job) BeforeCreate(db *gorm.DB) error {
+	if j.Id == nil {
+		j.Id = helper.Pointer(ksuid.New().String())
+	}
+
+	return nil
+}
+
+type JobRepo struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) repository.Job {
+	return &JobRepo{
+		db: db,
+	}
+}
+
+func (j *JobRepo) Add(ctx context.Context, data *model.Job) (*model.Job, error) {
+	gormModel := job{}.FromModel(*data)
+
+	if err := j.db.WithContext(ctx).Create(&gormModel).Error; err != nil {
+		return nil, err
+	}
+
+	return gormModel.ToModel(), nil
+}
+
+func (j *JobRepo) Get(ctx context.Context, filter *repository.JobGetFilter) (*model.Job, error) {
+	res := job{}
+
+	q := j.db.WithContext(ctx)
+	if filter.Id != nil {
+		q = q.Where("id = ?", filter.Id)
+	}
+
+	if filter.Type != nil {
+		q = q.Where("type = ?", filter.Type)
+	}
+
+	if filter.Status != nil {
+		q = q.Where("status = ?", filter.Status)
+	}
+
+	err := q.First(&res).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.NewNotFoundError()
+		}
+		return nil, err
+	}
+
+	return res.ToModel(), nil
+}
+
+func (j *JobRepo) Update(ctx context.Context, id string, data *model.Job) (*model.Job, error) {
+	_, err := j.Get(ctx, &repository.JobGetFilter{Id: &id})
+	if err != nil {
+		return nil, err
+	}
+
+	gormModel := job{}.FromModel(*data)
+	gormModel.UpdatedAt = helper.Pointer(time.Now())
+
+	err = j.db.WithContext(ctx).Model(&job{Id: &id}).Updates(&gormModel).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return j.Get(ctx, &repository.JobGetFilter{Id: &id})
+}
+
+func (j *JobRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.Job, error) {
+	jobs := []job{}
+	now := time.Now()
+
+	err := j.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND run_at <= ?", model.JobQueued, now).
+			Order("run_at").
+			Limit(limit).
+			Find(&jobs).Error
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+
+		ids := make([]string, 0, len(jobs))
+		for _, claimed := range jobs {
+			ids = append(ids, *claimed.Id)
+		}
+
+		return tx.Model(&job{}).Where("id IN ?", ids).Update("run_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.Job, 0, len(jobs))
+	for _, claimed := range jobs {
+		res = append(res, *claimed.ToModel())
+	}
+
+	return res, nil
+}
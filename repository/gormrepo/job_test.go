@@ -0,0 +1,66 @@
+//go:build integration
+// +build integration
+
+package gormrepo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/helper/test"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository/gormrepo"
+	"github.com/aalexanderkevin/crypto-wallet/storage"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobRepository_ClaimDue(t *testing.T) {
+	t.Run("ShouldOnlyClaimDueQueuedJobs", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		due := test.FakeJobCreate(t, db, nil)
+		test.FakeJobCreate(t, db, func(job model.Job) model.Job {
+			job.RunAt = helper.Pointer(time.Now().Add(time.Hour))
+			return job
+		})
+		test.FakeJobCreate(t, db, func(job model.Job) model.Job {
+			job.Status = helper.Pointer(model.JobDone)
+			return job
+		})
+
+		//-- code under test
+		jobRepo := gormrepo.NewJobRepository(db)
+		claimed, err := jobRepo.ClaimDue(context.TODO(), 10, time.Minute)
+
+		//-- assert
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		require.Equal(t, *due.Id, *claimed[0].Id)
+	})
+
+	t.Run("ShouldClaimAgain_WhenLeaseExpired", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		test.FakeJobCreate(t, db, nil)
+		jobRepo := gormrepo.NewJobRepository(db)
+		claimed, err := jobRepo.ClaimDue(context.TODO(), 10, time.Millisecond)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		time.Sleep(10 * time.Millisecond)
+
+		//-- code under test
+		reclaimed, err := jobRepo.ClaimDue(context.TODO(), 10, time.Minute)
+
+		//-- assert
+		require.NoError(t, err)
+		require.Len(t, reclaimed, 1)
+		require.Equal(t, *claimed[0].Id, *reclaimed[0].Id)
+	})
+}
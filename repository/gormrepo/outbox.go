@@ -0,0 +1,161 @@
+package gormrepo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type outboxEvent struct {
+	Id            *int64
+	AggregateType *string
+	AggregateId   *string
+	EventType     *string
+	Payload       *string
+	Attempts      *int
+	LastError     *string
+	CreatedAt     *time.Time
+	PublishedAt   *time.Time
+}
+
+func (o outboxEvent) ToModel() *model.OutboxEvent {
+	return &model.OutboxEvent{
+		Id:            o.Id,
+		AggregateType: o.AggregateType,
+		AggregateId:   o.AggregateId,
+		EventType:     o.EventType,
+		Payload:       o.Payload,
+		Attempts:      o.Attempts,
+		LastError:     o.LastError,
+		CreatedAt:     o.CreatedAt,
+		PublishedAt:   o.PublishedAt,
+	}
+}
+
+func (o outboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+type OutboxRepo struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) repository.Outbox {
+	return &OutboxRepo{
+		db: db,
+	}
+}
+
+func (o *OutboxRepo) PublishPending(ctx context.Context, limit int, publish func(ctx context.Context, event model.OutboxEvent) error) (int, error) {
+	published := 0
+
+	err := o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		events := []outboxEvent{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL").
+			Order("id").
+			Limit(limit).
+			Find(&events).Error
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if err := publish(ctx, *event.ToModel()); err != nil {
+				// keep the failure on the row, the next run retries from this event
+				return tx.Model(&outboxEvent{}).Where("id = ?", event.Id).Updates(map[string]interface{}{
+					"attempts":   gorm.Expr("attempts + 1"),
+					"last_error": err.Error(),
+				}).Error
+			}
+
+			err := tx.Model(&outboxEvent{}).Where("id = ?", event.Id).Update("published_at", time.Now()).Error
+			if err != nil {
+				return err
+			}
+			published++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return published, nil
+}
+
+type transactionState struct {
+	Status       *string
+	Confirmation *int64
+}
+
+// upsertTransactionWithOutbox upserts a chain transaction and, in the same database transaction, records an outbox
+// event when the row is new or its status or confirmation changed, the scopes narrow down the row when the id alone
+// does not identify it
+func upsertTransactionWithOutbox(ctx context.Context, db *gorm.DB, token string, tableName string, onConflict clause.OnConflict, gormModel interface{}, transaction *model.Transaction, scopes ...func(*gorm.DB) *gorm.DB) error {
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var previous *transactionState
+		state := transactionState{}
+		err := tx.Table(tableName).
+			Scopes(scopes...).
+			Select("status", "confirmation").
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", transaction.Id).
+			Take(&state).Error
+		if err == nil {
+			previous = &state
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err := tx.Table(tableName).Clauses(onConflict).Create(gormModel).Error; err != nil {
+			return err
+		}
+
+		return addTransactionOutboxEvent(tx, token, tableName, previous, transaction)
+	})
+}
+
+func addTransactionOutboxEvent(tx *gorm.DB, token string, tableName string, previous *transactionState, transaction *model.Transaction) error {
+	eventType := model.OutboxTransactionCreated
+	payload := model.TransactionChangedPayload{
+		Token:       &token,
+		Transaction: transaction,
+	}
+
+	if previous != nil {
+		payload.PreviousStatus = previous.Status
+		payload.PreviousConfirmation = previous.Confirmation
+
+		switch {
+		case !helper.EqualPointerValue(previous.Status, transaction.Status):
+			eventType = model.OutboxTransactionStatusChanged
+		case !helper.EqualPointerValue(previous.Confirmation, transaction.Confirmation):
+			eventType = model.OutboxTransactionConfirmationChanged
+		default:
+			return nil
+		}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&outboxEvent{
+		AggregateType: &tableName,
+		AggregateId:   transaction.Id,
+		EventType:     &eventType,
+		Payload:       helper.Pointer(string(data)),
+		Attempts:      helper.Pointer(0),
+	}).Error
+}
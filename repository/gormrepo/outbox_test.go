@@ -0,0 +1,87 @@
+//go:build integration
+// +build integration
+
+package gormrepo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/helper/test"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository/gormrepo"
+	"github.com/aalexanderkevin/crypto-wallet/storage"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxRepository_PublishPending(t *testing.T) {
+	t.Run("ShouldWriteEvent_OnlyWhenStatusOrConfirmationChanged", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		transactionRepo := gormrepo.NewEthTransactionRepository(db)
+		transaction := test.FakeTransaction(t, nil)
+		_, err := transactionRepo.Upsert(context.TODO(), &transaction)
+		require.NoError(t, err)
+		_, err = transactionRepo.Upsert(context.TODO(), &transaction)
+		require.NoError(t, err)
+		transaction.Confirmation = helper.Pointer(helper.Val(transaction.Confirmation) + 1)
+		_, err = transactionRepo.Upsert(context.TODO(), &transaction)
+		require.NoError(t, err)
+
+		//-- code under test
+		events := []model.OutboxEvent{}
+		outboxRepo := gormrepo.NewOutboxRepository(db)
+		published, err := outboxRepo.PublishPending(context.TODO(), 10, func(ctx context.Context, event model.OutboxEvent) error {
+			events = append(events, event)
+			return nil
+		})
+
+		//-- assert
+		require.NoError(t, err)
+		require.Equal(t, 2, published)
+		require.Equal(t, model.OutboxTransactionCreated, *events[0].EventType)
+		require.Equal(t, model.OutboxTransactionConfirmationChanged, *events[1].EventType)
+		require.Equal(t, *transaction.Id, *events[1].AggregateId)
+
+		published, err = outboxRepo.PublishPending(context.TODO(), 10, func(ctx context.Context, event model.OutboxEvent) error {
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 0, published)
+	})
+
+	t.Run("ShouldKeepEvent_WhenPublishFailed", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		transaction := test.FakeTransaction(t, nil)
+		_, err := gormrepo.NewTrxTransactionRepository(db).Upsert(context.TODO(), &transaction)
+		require.NoError(t, err)
+
+		//-- code under test
+		outboxRepo := gormrepo.NewOutboxRepository(db)
+		published, err := outboxRepo.PublishPending(context.TODO(), 10, func(ctx context.Context, event model.OutboxEvent) error {
+			return errors.New("sink is down")
+		})
+
+		//-- assert
+		require.NoError(t, err)
+		require.Equal(t, 0, published)
+
+		events := []model.OutboxEvent{}
+		_, err = outboxRepo.PublishPending(context.TODO(), 10, func(ctx context.Context, event model.OutboxEvent) error {
+			events = append(events, event)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Equal(t, 1, *events[0].Attempts)
+		require.Equal(t, "sink is down", *events[0].LastError)
+	})
+}
@@ -0,0 +1,109 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type refreshToken struct {
+	Id        *string
+	UserId    *string
+	FamilyId  *string
+	ExpiresAt *time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt *time.Time
+}
+
+func (r refreshToken) FromModel(data model.RefreshToken) *refreshToken {
+	return &refreshToken{
+		Id:        data.Id,
+		UserId:    data.UserId,
+		FamilyId:  data.FamilyId,
+		ExpiresAt: data.ExpiresAt,
+		UsedAt:    data.UsedAt,
+		RevokedAt: data.RevokedAt,
+		CreatedAt: data.CreatedAt,
+	}
+}
+
+func (r refreshToken) ToModel() *model.RefreshToken {
+	return &model.RefreshToken{
+		Id:        r.Id,
+		UserId:    r.UserId,
+		FamilyId:  r.FamilyId,
+		ExpiresAt: r.ExpiresAt,
+		UsedAt:    r.UsedAt,
+		RevokedAt: r.RevokedAt,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func (r refreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+type RefreshTokenRepo struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) repository.RefreshToken {
+	return &RefreshTokenRepo{
+		db: db,
+	}
+}
+
+func (r *RefreshTokenRepo) Add(ctx context.Context, data *model.RefreshToken) (*model.RefreshToken, error) {
+	gormModel := refreshToken{}.FromModel(*data)
+
+	if err := r.db.WithContext(ctx).Create(&gormModel).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return nil, model.NewDuplicateError()
+		}
+		return nil, err
+	}
+
+	return gormModel.ToModel(), nil
+}
+
+func (r *RefreshTokenRepo) Get(ctx context.Context, id string) (*model.RefreshToken, error) {
+	var gormModel refreshToken
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&gormModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.NewNotFoundError()
+		}
+		return nil, err
+	}
+
+	return gormModel.ToModel(), nil
+}
+
+func (r *RefreshTokenRepo) MarkUsed(ctx context.Context, id string) error {
+	// the conditions make concurrent refreshes with the same token race for a single row update
+	res := r.db.WithContext(ctx).Model(&refreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", time.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return model.NewNotFoundError()
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepo) RevokeFamily(ctx context.Context, familyId string) error {
+	return r.db.WithContext(ctx).Model(&refreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyId).
+		Update("revoked_at", time.Now()).Error
+}
@@ -0,0 +1,84 @@
+//go:build integration
+// +build integration
+
+package gormrepo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/helper/test"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository/gormrepo"
+	"github.com/aalexanderkevin/crypto-wallet/storage"
+
+	"github.com/icrowley/fake"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshTokenRepository_MarkUsed(t *testing.T) {
+	t.Run("ShouldReturnNotFoundError_WhenTheTokenIsUsedTwice", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		user := test.FakeUserCreate(t, db, nil)
+		refreshTokenRepo := gormrepo.NewRefreshTokenRepository(db)
+		refreshToken, err := refreshTokenRepo.Add(context.TODO(), &model.RefreshToken{
+			Id:        helper.Pointer(fake.CharactersN(32)),
+			UserId:    user.Id,
+			FamilyId:  helper.Pointer(fake.CharactersN(7)),
+			ExpiresAt: helper.Pointer(time.Now().Add(time.Hour)),
+		})
+		require.NoError(t, err)
+
+		//-- code under test
+		errFirst := refreshTokenRepo.MarkUsed(context.TODO(), *refreshToken.Id)
+		errSecond := refreshTokenRepo.MarkUsed(context.TODO(), *refreshToken.Id)
+
+		//-- assert
+		require.NoError(t, errFirst)
+		require.EqualError(t, errSecond, model.NewNotFoundError().Error())
+
+		res, err := refreshTokenRepo.Get(context.TODO(), *refreshToken.Id)
+		require.NoError(t, err)
+		require.NotNil(t, res.UsedAt)
+	})
+}
+
+func TestRefreshTokenRepository_RevokeFamily(t *testing.T) {
+	t.Run("ShouldOnlyRevokeTheTokensOfTheFamily", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		user := test.FakeUserCreate(t, db, nil)
+		refreshTokenRepo := gormrepo.NewRefreshTokenRepository(db)
+		add := func(familyId string) *model.RefreshToken {
+			refreshToken, err := refreshTokenRepo.Add(context.TODO(), &model.RefreshToken{
+				Id:        helper.Pointer(fake.CharactersN(32)),
+				UserId:    user.Id,
+				FamilyId:  &familyId,
+				ExpiresAt: helper.Pointer(time.Now().Add(time.Hour)),
+			})
+			require.NoError(t, err)
+			return refreshToken
+		}
+		revoked := add("family")
+		other := add("other-family")
+
+		//-- code under test
+		err := refreshTokenRepo.RevokeFamily(context.TODO(), "family")
+
+		//-- assert
+		require.NoError(t, err)
+		res, err := refreshTokenRepo.Get(context.TODO(), *revoked.Id)
+		require.NoError(t, err)
+		require.NotNil(t, res.RevokedAt)
+		res, err = refreshTokenRepo.Get(context.TODO(), *other.Id)
+		require.NoError(t, err)
+		require.Nil(t, res.RevokedAt)
+	})
+}
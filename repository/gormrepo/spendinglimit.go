@@ -0,0 +1,294 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type spendingLimit struct {
+	Id                     *string
+	UserId                 *string
+	Chain                  *string
+	MaxPerTransaction      *int64
+	MaxPerDay              *int64
+	MaxTransactionsPerHour *int64
+	CreatedAt              *time.Time
+	UpdatedAt              *time.Time
+}
+
+func (s spendingLimit) FromModel(data model.SpendingLimit) *spendingLimit {
+	return &spendingLimit{
+		Id:                     data.Id,
+		UserId:                 data.UserId,
+		Chain:                  data.Chain,
+		MaxPerTransaction:      data.MaxPerTransaction,
+		MaxPerDay:              data.MaxPerDay,
+		MaxTransactionsPerHour: data.MaxTransactionsPerHour,
+		CreatedAt:              data.CreatedAt,
+		UpdatedAt:              data.UpdatedAt,
+	}
+}
+
+func (s spendingLimit) ToModel() *model.SpendingLimit {
+	return &model.SpendingLimit{
+		Id:                     s.Id,
+		UserId:                 s.UserId,
+		Chain:                  s.Chain,
+		MaxPerTransaction:      s.MaxPerTransaction,
+		MaxPerDay:              s.MaxPerDay,
+		MaxTransactionsPerHour: s.MaxTransactionsPerHour,
+		CreatedAt:              s.CreatedAt,
+		UpdatedAt:              s.UpdatedAt,
+	}
+}
+
+func (s spendingLimit) TableName() string {
+	return "spending_limits"
+}
+
+func (s *spendingLimit) BeforeCreate(db *gorm.DB) error {
+	if s.Id == nil {
+		s.Id = helper.Pointer(ksuid.New().String())
+	}
+
+	return nil
+}
+
+type spendingRecord struct {
+	Id                  *string
+	UserId              *string
+	Chain               *string
+	Amount              *int64
+	TransactionId       *string
+	WithdrawalRequestId *string
+	CreatedAt           *time.Time
+}
+
+func (s spendingRecord) FromModel(data model.SpendingRecord) *spendingRecord {
+	return &spendingRecord{
+		Id:                  data.Id,
+		UserId:              data.UserId,
+		Chain:               data.Chain,
+		Amount:              data.Amount,
+		TransactionId:       data.TransactionId,
+		WithdrawalRequestId: data.WithdrawalRequestId,
+		CreatedAt:           data.CreatedAt,
+	}
+}
+
+func (s spendingRecord) ToModel() *model.SpendingRecord {
+	return &model.SpendingRecord{
+		Id:                  s.Id,
+		UserId:              s.UserId,
+		Chain:               s.Chain,
+		Amount:              s.Amount,
+		TransactionId:       s.TransactionId,
+		WithdrawalRequestId: s.WithdrawalRequestId,
+		CreatedAt:           s.CreatedAt,
+	}
+}
+
+func (s spendingRecord) TableName() string {
+	return "spending_records"
+}
+
+func (s *spendingRecord) BeforeCreate(db *gorm.DB) error {
+	if s.Id == nil {
+		s.Id = helper.Pointer(ksuid.New().String())
+	}
+
+	return nil
+}
+
+type SpendingLimitRepo struct {
+	db *gorm.DB
+}
+
+func NewSpendingLimitRepository(db *gorm.DB) repository.SpendingLimit {
+	return &SpendingLimitRepo{
+		db: db,
+	}
+}
+
+func (s *SpendingLimitRepo) Upsert(ctx context.Context, data *model.SpendingLimit) (*model.SpendingLimit, error) {
+	gormModel := spendingLimit{}.FromModel(*data)
+	now := time.Now()
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		existing := spendingLimit{}
+		err := scopeLimitOwner(tx.Clauses(clause.Locking{Strength: "UPDATE"}), data.UserId).
+			Where("chain = ?", data.Chain).
+			First(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			gormModel.Id = nil
+			gormModel.CreatedAt = &now
+			gormModel.UpdatedAt = &now
+			return tx.Create(gormModel).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		gormModel.Id = existing.Id
+		gormModel.CreatedAt = existing.CreatedAt
+		gormModel.UpdatedAt = &now
+		// Select writes the nil fields too, a cleared field means no limit
+		return tx.Model(&spendingLimit{}).Where("id = ?", existing.Id).
+			Select("max_per_transaction", "max_per_day", "max_transactions_per_hour", "updated_at").
+			Updates(gormModel).Error
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return nil, model.NewDuplicateError()
+		}
+		return nil, err
+	}
+
+	return gormModel.ToModel(), nil
+}
+
+func (s *SpendingLimitRepo) List(ctx context.Context, filter *repository.SpendingLimitFilter) ([]model.SpendingLimit, error) {
+	q := s.db.WithContext(ctx)
+	if filter.Global {
+		q = q.Where("user_id IS NULL")
+	} else if filter.UserId != nil {
+		q = q.Where("user_id = ?", filter.UserId)
+	}
+	if filter.Chain != nil {
+		q = q.Where("chain = ?", filter.Chain)
+	}
+
+	limits := []spendingLimit{}
+	if err := q.Order("chain, user_id NULLS FIRST").Find(&limits).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]model.SpendingLimit, 0, len(limits))
+	for _, limit := range limits {
+		res = append(res, *limit.ToModel())
+	}
+
+	return res, nil
+}
+
+func (s *SpendingLimitRepo) Delete(ctx context.Context, id string) error {
+	res := s.db.WithContext(ctx).Where("id = ?", id).Delete(&spendingLimit{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return model.NewNotFoundError()
+	}
+
+	return nil
+}
+
+func (s *SpendingLimitRepo) Reserve(ctx context.Context, data *model.SpendingRecord, check func(limit model.SpendingLimit, usage model.SpendingUsage) error) (*model.SpendingRecord, error) {
+	gormModel := spendingRecord{}.FromModel(*data)
+	now := time.Now()
+	gormModel.CreatedAt = &now
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the lock is held until the transaction ends, a concurrent transfer on the chain waits for the record of this
+		// one before reading the usage. The global limit counts the transfers of every user so the lock is per chain
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "spending:"+helper.Val(data.Chain)).Error; err != nil {
+			return err
+		}
+
+		limits := []spendingLimit{}
+		err := tx.Where("chain = ? AND (user_id IS NULL OR user_id = ?)", data.Chain, data.UserId).
+			Order("user_id NULLS LAST").
+			Find(&limits).Error
+		if err != nil {
+			return err
+		}
+
+		for _, limit := range limits {
+			usage, err := spendingUsage(tx, limit.UserId, helper.Val(data.Chain), now)
+			if err != nil {
+				return err
+			}
+			if err := check(*limit.ToModel(), *usage); err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(gormModel).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return gormModel.ToModel(), nil
+}
+
+func (s *SpendingLimitRepo) Release(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Where("id = ?", id).Delete(&spendingRecord{}).Error
+}
+
+func (s *SpendingLimitRepo) SetTransactionId(ctx context.Context, id string, transactionId string) error {
+	return s.db.WithContext(ctx).Model(&spendingRecord{}).Where("id = ?", id).Update("transaction_id", transactionId).Error
+}
+
+func (s *SpendingLimitRepo) GetByWithdrawalRequest(ctx context.Context, withdrawalRequestId string) (*model.SpendingRecord, error) {
+	record := spendingRecord{}
+	err := s.db.WithContext(ctx).Where("withdrawal_request_id = ?", withdrawalRequestId).First(&record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.NewNotFoundError()
+		}
+		return nil, err
+	}
+
+	return record.ToModel(), nil
+}
+
+func scopeLimitOwner(q *gorm.DB, userId *string) *gorm.DB {
+	if userId == nil {
+		return q.Where("user_id IS NULL")
+	}
+
+	return q.Where("user_id = ?", userId)
+}
+
+// spendingUsage sums the records of the user on the chain over the windows of the limits, or the records of all the
+// users when the user id is nil
+func spendingUsage(tx *gorm.DB, userId *string, chain string, now time.Time) (*model.SpendingUsage, error) {
+	usage := model.SpendingUsage{}
+
+	err := scopeRecordOwner(tx.Model(&spendingRecord{}), userId).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("chain = ? AND created_at > ?", chain, now.Add(-24*time.Hour)).
+		Scan(&usage.AmountLastDay).Error
+	if err != nil {
+		return nil, err
+	}
+	err = scopeRecordOwner(tx.Model(&spendingRecord{}), userId).
+		Where("chain = ? AND created_at > ?", chain, now.Add(-time.Hour)).
+		Count(&usage.TransactionsLastHour).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &usage, nil
+}
+
+func scopeRecordOwner(q *gorm.DB, userId *string) *gorm.DB {
+	if userId == nil {
+		return q
+	}
+
+	return q.Where("user_id = ?", userId)
+}
@@ -0,0 +1,240 @@
+//go:build integration
+// +build integration
+
+package gormrepo_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/helper/test"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/repository/gormrepo"
+	"github.com/aalexanderkevin/crypto-wallet/storage"
+
+	"github.com/stretchr/testify/require"
+)
+
+func checkLimit(amount int64) func(limit model.SpendingLimit, usage model.SpendingUsage) error {
+	return func(limit model.SpendingLimit, usage model.SpendingUsage) error {
+		return limit.Check(amount, usage)
+	}
+}
+
+func TestSpendingLimitRepository_Upsert(t *testing.T) {
+	t.Run("ShouldReplaceTheLimitOfTheUserOnTheChain", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		user := test.FakeUserCreate(t, db, nil)
+		spendingLimitRepo := gormrepo.NewSpendingLimitRepository(db)
+		first, err := spendingLimitRepo.Upsert(context.TODO(), &model.SpendingLimit{
+			UserId:    user.Id,
+			Chain:     helper.Pointer("btc"),
+			MaxPerDay: helper.Pointer(int64(1000)),
+		})
+		require.NoError(t, err)
+
+		//-- code under test
+		second, err := spendingLimitRepo.Upsert(context.TODO(), &model.SpendingLimit{
+			UserId:            user.Id,
+			Chain:             helper.Pointer("btc"),
+			MaxPerTransaction: helper.Pointer(int64(100)),
+		})
+
+		//-- assert
+		require.NoError(t, err)
+		require.Equal(t, *first.Id, *second.Id)
+		limits, err := spendingLimitRepo.List(context.TODO(), &repository.SpendingLimitFilter{UserId: user.Id})
+		require.NoError(t, err)
+		require.Len(t, limits, 1)
+		require.Equal(t, int64(100), *limits[0].MaxPerTransaction)
+		require.Nil(t, limits[0].MaxPerDay)
+	})
+}
+
+func TestSpendingLimitRepository_Reserve(t *testing.T) {
+	t.Run("ShouldApplyTheUserLimitAndTheGlobalOne", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		user := test.FakeUserCreate(t, db, nil)
+		spendingLimitRepo := gormrepo.NewSpendingLimitRepository(db)
+		_, err := spendingLimitRepo.Upsert(context.TODO(), &model.SpendingLimit{
+			Chain:             helper.Pointer("eth"),
+			MaxPerTransaction: helper.Pointer(int64(500)),
+		})
+		require.NoError(t, err)
+		_, err = spendingLimitRepo.Upsert(context.TODO(), &model.SpendingLimit{
+			UserId:    user.Id,
+			Chain:     helper.Pointer("eth"),
+			MaxPerDay: helper.Pointer(int64(150)),
+		})
+		require.NoError(t, err)
+		record := func(amount int64) *model.SpendingRecord {
+			return &model.SpendingRecord{UserId: user.Id, Chain: helper.Pointer("eth"), Amount: &amount}
+		}
+
+		//-- code under test
+		_, overGlobalErr := spendingLimitRepo.Reserve(context.TODO(), record(600), checkLimit(600))
+		_, firstErr := spendingLimitRepo.Reserve(context.TODO(), record(120), checkLimit(120))
+		_, overUserErr := spendingLimitRepo.Reserve(context.TODO(), record(40), checkLimit(40))
+
+		//-- assert
+		var modelErr model.Error
+		require.ErrorAs(t, overGlobalErr, &modelErr)
+		require.Equal(t, model.ErrorFailedPrecondition, modelErr.Code)
+		require.NoError(t, firstErr)
+		require.ErrorAs(t, overUserErr, &modelErr)
+		require.Equal(t, model.ErrorFailedPrecondition, modelErr.Code)
+	})
+
+	t.Run("ShouldCountTheTransfersOfAllTheUsers_WhenTheLimitIsGlobal", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		firstUser := test.FakeUserCreate(t, db, nil)
+		secondUser := test.FakeUserCreate(t, db, nil)
+		spendingLimitRepo := gormrepo.NewSpendingLimitRepository(db)
+		_, err := spendingLimitRepo.Upsert(context.TODO(), &model.SpendingLimit{
+			Chain:     helper.Pointer("btc"),
+			MaxPerDay: helper.Pointer(int64(150)),
+		})
+		require.NoError(t, err)
+		_, err = spendingLimitRepo.Reserve(context.TODO(), &model.SpendingRecord{UserId: firstUser.Id, Chain: helper.Pointer("btc"), Amount: helper.Pointer(int64(120))}, checkLimit(120))
+		require.NoError(t, err)
+
+		//-- code under test
+		_, err = spendingLimitRepo.Reserve(context.TODO(), &model.SpendingRecord{UserId: secondUser.Id, Chain: helper.Pointer("btc"), Amount: helper.Pointer(int64(40))}, checkLimit(40))
+
+		//-- assert
+		var modelErr model.Error
+		require.ErrorAs(t, err, &modelErr)
+		require.Equal(t, model.ErrorFailedPrecondition, modelErr.Code)
+	})
+
+	t.Run("ShouldNotLetConcurrentTransfersPassTheLimit", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		users := []*model.User{test.FakeUserCreate(t, db, nil), test.FakeUserCreate(t, db, nil)}
+		spendingLimitRepo := gormrepo.NewSpendingLimitRepository(db)
+		_, err := spendingLimitRepo.Upsert(context.TODO(), &model.SpendingLimit{
+			Chain:                  helper.Pointer("trx"),
+			MaxTransactionsPerHour: helper.Pointer(int64(3)),
+		})
+		require.NoError(t, err)
+
+		//-- code under test
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		reserved := 0
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(user *model.User) {
+				defer wg.Done()
+				_, err := spendingLimitRepo.Reserve(context.TODO(), &model.SpendingRecord{
+					UserId: user.Id,
+					Chain:  helper.Pointer("trx"),
+					Amount: helper.Pointer(int64(1)),
+				}, checkLimit(1))
+				if err == nil {
+					mu.Lock()
+					reserved++
+					mu.Unlock()
+				}
+			}(users[i%len(users)])
+		}
+		wg.Wait()
+
+		//-- assert
+		require.Equal(t, 3, reserved)
+	})
+
+	t.Run("ShouldNotCountTheReleasedTransfer", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		user := test.FakeUserCreate(t, db, nil)
+		spendingLimitRepo := gormrepo.NewSpendingLimitRepository(db)
+		_, err := spendingLimitRepo.Upsert(context.TODO(), &model.SpendingLimit{
+			UserId:                 user.Id,
+			Chain:                  helper.Pointer("btc"),
+			MaxTransactionsPerHour: helper.Pointer(int64(1)),
+		})
+		require.NoError(t, err)
+		record := &model.SpendingRecord{UserId: user.Id, Chain: helper.Pointer("btc"), Amount: helper.Pointer(int64(1))}
+		first, err := spendingLimitRepo.Reserve(context.TODO(), record, checkLimit(1))
+		require.NoError(t, err)
+
+		//-- code under test
+		err = spendingLimitRepo.Release(context.TODO(), *first.Id)
+
+		//-- assert
+		require.NoError(t, err)
+		_, err = spendingLimitRepo.Reserve(context.TODO(), &model.SpendingRecord{UserId: user.Id, Chain: helper.Pointer("btc"), Amount: helper.Pointer(int64(1))}, checkLimit(1))
+		require.NoError(t, err)
+	})
+}
+
+func TestSpendingLimitRepository_GetByWithdrawalRequest(t *testing.T) {
+	t.Run("ShouldReturnTheReservationOfTheWithdrawalRequest", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		user := test.FakeUserCreate(t, db, nil)
+		spendingLimitRepo := gormrepo.NewSpendingLimitRepository(db)
+		_, err := spendingLimitRepo.Reserve(context.TODO(), &model.SpendingRecord{UserId: user.Id, Chain: helper.Pointer("btc"), Amount: helper.Pointer(int64(1))}, checkLimit(1))
+		require.NoError(t, err)
+		reserved, err := spendingLimitRepo.Reserve(context.TODO(), &model.SpendingRecord{
+			UserId:              user.Id,
+			Chain:               helper.Pointer("btc"),
+			Amount:              helper.Pointer(int64(1)),
+			WithdrawalRequestId: helper.Pointer("withdrawal-request-id"),
+		}, checkLimit(1))
+		require.NoError(t, err)
+		err = spendingLimitRepo.SetTransactionId(context.TODO(), *reserved.Id, "tx-id")
+		require.NoError(t, err)
+
+		//-- code under test
+		record, err := spendingLimitRepo.GetByWithdrawalRequest(context.TODO(), "withdrawal-request-id")
+
+		//-- assert
+		require.NoError(t, err)
+		require.Equal(t, *reserved.Id, *record.Id)
+		require.Equal(t, "tx-id", *record.TransactionId)
+	})
+
+	t.Run("ShouldReturnNotFoundError_WhenTheReservationWasReleased", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		user := test.FakeUserCreate(t, db, nil)
+		spendingLimitRepo := gormrepo.NewSpendingLimitRepository(db)
+		reserved, err := spendingLimitRepo.Reserve(context.TODO(), &model.SpendingRecord{
+			UserId:              user.Id,
+			Chain:               helper.Pointer("btc"),
+			Amount:              helper.Pointer(int64(1)),
+			WithdrawalRequestId: helper.Pointer("withdrawal-request-id"),
+		}, checkLimit(1))
+		require.NoError(t, err)
+		err = spendingLimitRepo.Release(context.TODO(), *reserved.Id)
+		require.NoError(t, err)
+
+		//-- code under test
+		_, err = spendingLimitRepo.GetByWithdrawalRequest(context.TODO(), "withdrawal-request-id")
+
+		//-- assert
+		require.True(t, model.IsNotFoundError(err))
+	})
+}
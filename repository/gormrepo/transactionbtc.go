@@ -18,6 +18,7 @@ import (
 
 type btcTransaction struct {
 	Id              *string
+	Chain           *string
 	SenderAddress   pq.StringArray `gorm:"type:text[]"`
 	ReceiverAddress pq.StringArray `gorm:"type:text[]"`
 	Amount          *int64
@@ -26,6 +27,7 @@ type btcTransaction struct {
 	Status          *string
 	ReceivedAt      *time.Time
 	CompletedAt     *time.Time
+	CreatedAt       *time.Time
 }
 
 func (b btcTransaction) FromModel(data model.Transaction) *btcTransaction {
@@ -53,6 +55,7 @@ func (b btcTransaction) ToModel() *model.Transaction {
 		Status:          b.Status,
 		ReceivedAt:      b.ReceivedAt,
 		CompletedAt:     b.CompletedAt,
+		CreatedAt:       b.CreatedAt,
 	}
 }
 
@@ -68,18 +71,37 @@ func (b *btcTransaction) BeforeCreate(db *gorm.DB) error {
 	return nil
 }
 
+// BtcTransactionRepo stores the transactions of one UTXO coin, all coins share the btc_transactions table and are
+// told apart by their chain
 type BtcTransactionRepo struct {
-	db *gorm.DB
+	db    *gorm.DB
+	chain string
 }
 
 func NewBtcTransactionRepository(db *gorm.DB) repository.Transaction {
+	return NewUtxoTransactionRepository(db, "btc")
+}
+
+func NewUtxoTransactionRepository(db *gorm.DB, chain string) repository.Transaction {
 	return &BtcTransactionRepo{
-		db: db,
+		db:    db,
+		chain: chain,
 	}
 }
 
+func (b *BtcTransactionRepo) chainScope(db *gorm.DB) *gorm.DB {
+	return db.Where("chain = ?", b.chain)
+}
+
+func (b *BtcTransactionRepo) fromModel(transaction model.Transaction) *btcTransaction {
+	gormModel := btcTransaction{}.FromModel(transaction)
+	gormModel.Chain = &b.chain
+
+	return gormModel
+}
+
 func (b *BtcTransactionRepo) Add(ctx context.Context, transaction *model.Transaction) (*model.Transaction, error) {
-	gormModel := btcTransaction{}.FromModel(*transaction)
+	gormModel := b.fromModel(*transaction)
 
 	if err := b.db.WithContext(ctx).Create(&gormModel).Error; err != nil {
 		var pgErr *pgconn.PgError
@@ -98,10 +120,10 @@ func (b *BtcTransactionRepo) Update(ctx context.Context, id string, transaction
 		return nil, err
 	}
 
-	gormModel := btcTransaction{}.FromModel(*transaction)
+	gormModel := b.fromModel(*transaction)
 
 	tx := b.db.WithContext(ctx)
-	err = tx.Model(&btcTransaction{Id: &id}).Updates(&gormModel).Error
+	err = tx.Model(&btcTransaction{Id: &id, Chain: &b.chain}).Updates(&gormModel).Error
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
@@ -114,12 +136,13 @@ func (b *BtcTransactionRepo) Update(ctx context.Context, id string, transaction
 }
 
 func (b *BtcTransactionRepo) Upsert(ctx context.Context, transaction *model.Transaction) (*model.Transaction, error) {
-	gormModel := btcTransaction{}.FromModel(*transaction)
+	gormModel := b.fromModel(*transaction)
 
-	if err := b.db.WithContext(ctx).Table(gormModel.TableName()).Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "id"}},
+	err := upsertTransactionWithOutbox(ctx, b.db, b.chain, gormModel.TableName(), clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain"}, {Name: "id"}},
 		DoUpdates: clause.AssignmentColumns([]string{"sender_address", "receiver_address", "amount", "fee", "confirmation", "status", "received_at", "completed_at"}),
-	}).Create(&gormModel).Error; err != nil {
+	}, &gormModel, transaction, b.chainScope)
+	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
 			return nil, model.NewDuplicateError()
@@ -135,7 +158,7 @@ func (b *BtcTransactionRepo) Get(ctx context.Context, filter *repository.Transac
 		Id: filter.Id,
 	}
 
-	q := b.db.WithContext(ctx)
+	q := b.db.WithContext(ctx).Scopes(b.chainScope)
 	if filter.Id != nil {
 		q = q.Where("id = ?", filter.Id)
 	}
@@ -162,3 +185,32 @@ func (b *BtcTransactionRepo) Get(ctx context.Context, filter *repository.Transac
 
 	return transaction.ToModel(), nil
 }
+
+func (b *BtcTransactionRepo) List(ctx context.Context, filter *repository.TransactionGetFilter) ([]model.Transaction, error) {
+	transactions := []btcTransaction{}
+
+	q := b.db.WithContext(ctx).Scopes(b.chainScope)
+	if filter.Address != nil {
+		q = q.Where("(? = ANY(sender_address) OR ? = ANY(receiver_address))", filter.Address, filter.Address)
+	}
+
+	if filter.Status != nil {
+		q = q.Where("status = ?", filter.Status)
+	}
+
+	if filter.CreatedBefore != nil {
+		q = q.Where("created_at < ?", filter.CreatedBefore)
+	}
+
+	err := q.Order("created_at").Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		res = append(res, *transaction.ToModel())
+	}
+
+	return res, nil
+}
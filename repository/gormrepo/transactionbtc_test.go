@@ -120,6 +120,32 @@ func TestBtcTransactionRepository_Get(t *testing.T) {
 
 }
 
+func TestBtcTransactionRepository_List(t *testing.T) {
+	t.Run("ShouldOnlyListTheStatus", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		pending := test.FakeBtcTransactionCreate(t, db, nil)
+		test.FakeBtcTransactionCreate(t, db, func(transaction model.Transaction) model.Transaction {
+			transaction.Status = helper.Pointer(model.TransactionStatusSuccess)
+			return transaction
+		})
+
+		//-- code under test
+		btcTxRepo := gormrepo.NewBtcTransactionRepository(db)
+		txs, err := btcTxRepo.List(context.TODO(), &repository.TransactionGetFilter{
+			Status: helper.Pointer(model.TransactionStatusPending),
+		})
+
+		//-- assert
+		require.NoError(t, err)
+		require.Len(t, txs, 1)
+		require.Equal(t, *pending.Id, *txs[0].Id)
+		require.NotNil(t, txs[0].CreatedAt)
+	})
+}
+
 func TestBtcTransactionRepository_Add(t *testing.T) {
 	t.Run("ShouldInsertTransaction", func(t *testing.T) {
 		//-- init
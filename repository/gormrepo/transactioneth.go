@@ -16,44 +16,59 @@ import (
 )
 
 type ethTransaction struct {
-	Id              *string
-	SenderAddress   *string
-	ReceiverAddress *string
-	Amount          *int64
-	Fee             *int64
-	Block           *int64
-	Confirmation    *int64
-	Status          *string
-	ReceivedAt      *time.Time
-	UpdatedAt       *time.Time
+	Id                *string
+	Chain             *string
+	SenderAddress     *string
+	ReceiverAddress   *string
+	Amount            *int64
+	Fee               *int64
+	Block             *int64
+	Confirmation      *int64
+	Status            *string
+	ReceivedAt        *time.Time
+	GasUsed           *int64
+	EffectiveGasPrice *int64
+	FailureReason     *string
+	Nonce             *int64
+	UpdatedAt         *time.Time
+	CreatedAt         *time.Time
 }
 
 func (e ethTransaction) FromModel(data model.Transaction) *ethTransaction {
 	return &ethTransaction{
-		Id:              data.Id,
-		SenderAddress:   helper.Pointer(data.SenderAddress[0]),
-		ReceiverAddress: helper.Pointer(data.ReceiverAddress[0]),
-		Amount:          data.Amount,
-		Fee:             data.Fee,
-		Block:           data.Block,
-		Confirmation:    data.Confirmation,
-		Status:          data.Status,
-		ReceivedAt:      data.ReceivedAt,
-		UpdatedAt:       helper.Pointer(time.Now()),
+		Id:                data.Id,
+		SenderAddress:     helper.Pointer(data.SenderAddress[0]),
+		ReceiverAddress:   helper.Pointer(data.ReceiverAddress[0]),
+		Amount:            data.Amount,
+		Fee:               data.Fee,
+		Block:             data.Block,
+		Confirmation:      data.Confirmation,
+		Status:            data.Status,
+		ReceivedAt:        data.ReceivedAt,
+		GasUsed:           data.GasUsed,
+		EffectiveGasPrice: data.EffectiveGasPrice,
+		FailureReason:     data.FailureReason,
+		Nonce:             data.Nonce,
+		UpdatedAt:         helper.Pointer(time.Now()),
 	}
 }
 
 func (e ethTransaction) ToModel() *model.Transaction {
 	return &model.Transaction{
-		Id:              e.Id,
-		SenderAddress:   []string{*e.SenderAddress},
-		ReceiverAddress: []string{*e.ReceiverAddress},
-		Amount:          e.Amount,
-		Fee:             e.Fee,
-		Block:           e.Block,
-		Confirmation:    e.Confirmation,
-		Status:          e.Status,
-		ReceivedAt:      e.ReceivedAt,
+		Id:                e.Id,
+		SenderAddress:     []string{*e.SenderAddress},
+		ReceiverAddress:   []string{*e.ReceiverAddress},
+		Amount:            e.Amount,
+		Fee:               e.Fee,
+		Block:             e.Block,
+		Confirmation:      e.Confirmation,
+		Status:            e.Status,
+		ReceivedAt:        e.ReceivedAt,
+		CreatedAt:         e.CreatedAt,
+		GasUsed:           e.GasUsed,
+		EffectiveGasPrice: e.EffectiveGasPrice,
+		FailureReason:     e.FailureReason,
+		Nonce:             e.Nonce,
 	}
 }
 
@@ -69,18 +84,37 @@ func (e *ethTransaction) BeforeCreate(db *gorm.DB) error {
 	return nil
 }
 
+// EthTransactionRepo stores the transactions of one EVM network, all networks share the eth_transactions table and
+// are told apart by their chain
 type EthTransactionRepo struct {
-	db *gorm.DB
+	db    *gorm.DB
+	chain string
 }
 
 func NewEthTransactionRepository(db *gorm.DB) repository.Transaction {
+	return NewEvmTransactionRepository(db, "eth")
+}
+
+func NewEvmTransactionRepository(db *gorm.DB, chain string) repository.Transaction {
 	return &EthTransactionRepo{
-		db: db,
+		db:    db,
+		chain: chain,
 	}
 }
 
+func (e *EthTransactionRepo) chainScope(db *gorm.DB) *gorm.DB {
+	return db.Where("chain = ?", e.chain)
+}
+
+func (e *EthTransactionRepo) fromModel(transaction model.Transaction) *ethTransaction {
+	gormModel := ethTransaction{}.FromModel(transaction)
+	gormModel.Chain = &e.chain
+
+	return gormModel
+}
+
 func (e *EthTransactionRepo) Add(ctx context.Context, transaction *model.Transaction) (*model.Transaction, error) {
-	gormModel := ethTransaction{}.FromModel(*transaction)
+	gormModel := e.fromModel(*transaction)
 
 	if err := e.db.WithContext(ctx).Create(&gormModel).Error; err != nil {
 		var pgErr *pgconn.PgError
@@ -99,10 +133,10 @@ func (e *EthTransactionRepo) Update(ctx context.Context, id string, transaction
 		return nil, err
 	}
 
-	gormModel := ethTransaction{}.FromModel(*transaction)
+	gormModel := e.fromModel(*transaction)
 
 	tx := e.db.WithContext(ctx)
-	err = tx.Model(&ethTransaction{Id: &id}).Updates(&gormModel).Error
+	err = tx.Model(&ethTransaction{Id: &id, Chain: &e.chain}).Updates(&gormModel).Error
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
@@ -115,13 +149,14 @@ func (e *EthTransactionRepo) Update(ctx context.Context, id string, transaction
 }
 
 func (e *EthTransactionRepo) Upsert(ctx context.Context, transaction *model.Transaction) (*model.Transaction, error) {
-	gormModel := ethTransaction{}.FromModel(*transaction)
+	gormModel := e.fromModel(*transaction)
 	gormModel.UpdatedAt = helper.Pointer(time.Now())
 
-	if err := e.db.WithContext(ctx).Table(gormModel.TableName()).Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "id"}},
-		DoUpdates: clause.AssignmentColumns([]string{"sender_address", "receiver_address", "amount", "fee", "block", "confirmation", "status", "received_at", "updated_at"}),
-	}).Create(&gormModel).Error; err != nil {
+	err := upsertTransactionWithOutbox(ctx, e.db, e.chain, gormModel.TableName(), clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain"}, {Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"sender_address", "receiver_address", "amount", "fee", "block", "confirmation", "status", "received_at", "gas_used", "effective_gas_price", "failure_reason", "updated_at"}),
+	}, &gormModel, transaction, e.chainScope)
+	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
 			return nil, model.NewDuplicateError()
@@ -137,7 +172,7 @@ func (e *EthTransactionRepo) Get(ctx context.Context, filter *repository.Transac
 		Id: filter.Id,
 	}
 
-	q := e.db.WithContext(ctx)
+	q := e.db.WithContext(ctx).Scopes(e.chainScope)
 	if filter.Id != nil {
 		q = q.Where("id = ?", filter.Id)
 	}
@@ -164,3 +199,32 @@ func (e *EthTransactionRepo) Get(ctx context.Context, filter *repository.Transac
 
 	return transaction.ToModel(), nil
 }
+
+func (e *EthTransactionRepo) List(ctx context.Context, filter *repository.TransactionGetFilter) ([]model.Transaction, error) {
+	transactions := []ethTransaction{}
+
+	q := e.db.WithContext(ctx).Scopes(e.chainScope)
+	if filter.Address != nil {
+		q = q.Where(e.db.Where("sender_address = ?", filter.Address).Or("receiver_address = ?", filter.Address))
+	}
+
+	if filter.Status != nil {
+		q = q.Where("status = ?", filter.Status)
+	}
+
+	if filter.CreatedBefore != nil {
+		q = q.Where("created_at < ?", filter.CreatedBefore)
+	}
+
+	err := q.Order("created_at").Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		res = append(res, *transaction.ToModel())
+	}
+
+	return res, nil
+}
@@ -0,0 +1,112 @@
+//go:build integration
+// +build integration
+
+package gormrepo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/helper/test"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/repository/gormrepo"
+	"github.com/aalexanderkevin/crypto-wallet/storage"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEthTransactionRepository_Upsert(t *testing.T) {
+	t.Run("ShouldStoreTheReceipt_WhenTheTransactionReverted", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		fakeTransaction := test.FakeTransaction(t, func(transaction model.Transaction) model.Transaction {
+			transaction.Block = helper.Pointer[int64](100)
+			transaction.Status = helper.Pointer(model.TransactionStatusFailed)
+			transaction.GasUsed = helper.Pointer[int64](21000)
+			transaction.EffectiveGasPrice = helper.Pointer[int64](1000000000)
+			transaction.FailureReason = helper.Pointer("insufficient allowance")
+			return transaction
+		})
+
+		//-- code under test
+		ethTxRepo := gormrepo.NewEthTransactionRepository(db)
+		_, err := ethTxRepo.Upsert(context.TODO(), &fakeTransaction)
+		require.NoError(t, err)
+
+		res, err := ethTxRepo.Get(context.TODO(), &repository.TransactionGetFilter{
+			Id: fakeTransaction.Id,
+		})
+
+		//-- assert
+		require.NoError(t, err)
+		require.Equal(t, model.TransactionStatusFailed, *res.Status)
+		require.Equal(t, fakeTransaction.GasUsed, res.GasUsed)
+		require.Equal(t, fakeTransaction.EffectiveGasPrice, res.EffectiveGasPrice)
+		require.Equal(t, fakeTransaction.FailureReason, res.FailureReason)
+	})
+}
+
+func TestEthTransactionRepository_List(t *testing.T) {
+	t.Run("ShouldListTheTransactionsOfTheAddress", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		ethTxRepo := gormrepo.NewEthTransactionRepository(db)
+
+		sent := test.FakeTransaction(t, nil)
+		_, err := ethTxRepo.Upsert(context.TODO(), &sent)
+		require.NoError(t, err)
+
+		received := test.FakeTransaction(t, func(transaction model.Transaction) model.Transaction {
+			transaction.ReceiverAddress = sent.SenderAddress
+			return transaction
+		})
+		_, err = ethTxRepo.Upsert(context.TODO(), &received)
+		require.NoError(t, err)
+
+		other := test.FakeTransaction(t, nil)
+		_, err = ethTxRepo.Upsert(context.TODO(), &other)
+		require.NoError(t, err)
+
+		//-- code under test
+		txs, err := ethTxRepo.List(context.TODO(), &repository.TransactionGetFilter{
+			Address: helper.Pointer(sent.SenderAddress[0]),
+		})
+
+		//-- assert
+		require.NoError(t, err)
+		require.Len(t, txs, 2)
+		require.ElementsMatch(t, []string{*sent.Id, *received.Id}, []string{*txs[0].Id, *txs[1].Id})
+	})
+}
+
+func TestEvmTransactionRepository_Get(t *testing.T) {
+	t.Run("ShouldOnlySeeTheTransactionsOfItsChain", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		ethTxRepo := gormrepo.NewEthTransactionRepository(db)
+		maticTxRepo := gormrepo.NewEvmTransactionRepository(db, "matic")
+
+		fakeTransaction := test.FakeTransaction(t, nil)
+		_, err := ethTxRepo.Upsert(context.TODO(), &fakeTransaction)
+		require.NoError(t, err)
+
+		//-- code under test
+		_, errMatic := maticTxRepo.Get(context.TODO(), &repository.TransactionGetFilter{Id: fakeTransaction.Id})
+		txs, errList := maticTxRepo.List(context.TODO(), &repository.TransactionGetFilter{
+			Address: helper.Pointer(fakeTransaction.SenderAddress[0]),
+		})
+
+		//-- assert
+		require.True(t, model.IsNotFoundError(errMatic))
+		require.NoError(t, errList)
+		require.Empty(t, txs)
+	})
+}
@@ -24,11 +24,19 @@ type trxTransaction struct {
 	Block           *int64
 	Confirmation    *int64
 	Status          *string
+	Type            *string
+	Resource        *string
 	ReceivedAt      *time.Time
 	UpdatedAt       *time.Time
+	CreatedAt       *time.Time
 }
 
 func (t trxTransaction) FromModel(data model.Transaction) *trxTransaction {
+	transactionType := data.Type
+	if transactionType == nil {
+		transactionType = helper.Pointer(model.TransactionTypeTransfer)
+	}
+
 	return &trxTransaction{
 		Id:              data.Id,
 		SenderAddress:   helper.Pointer(data.SenderAddress[0]),
@@ -38,6 +46,8 @@ func (t trxTransaction) FromModel(data model.Transaction) *trxTransaction {
 		Block:           data.Block,
 		Confirmation:    data.Confirmation,
 		Status:          data.Status,
+		Type:            transactionType,
+		Resource:        data.Resource,
 		ReceivedAt:      data.ReceivedAt,
 	}
 }
@@ -52,7 +62,10 @@ func (t trxTransaction) ToModel() *model.Transaction {
 		Block:           t.Block,
 		Confirmation:    t.Confirmation,
 		Status:          t.Status,
+		Type:            t.Type,
+		Resource:        t.Resource,
 		ReceivedAt:      t.ReceivedAt,
+		CreatedAt:       t.CreatedAt,
 	}
 }
 
@@ -117,10 +130,11 @@ func (t *TrxTransactionRepo) Upsert(ctx context.Context, transaction *model.Tran
 	gormModel := trxTransaction{}.FromModel(*transaction)
 	gormModel.UpdatedAt = helper.Pointer(time.Now())
 
-	if err := t.db.WithContext(ctx).Table(gormModel.TableName()).Clauses(clause.OnConflict{
+	err := upsertTransactionWithOutbox(ctx, t.db, "trx", gormModel.TableName(), clause.OnConflict{
 		Columns:   []clause.Column{{Name: "id"}},
 		DoUpdates: clause.AssignmentColumns([]string{"sender_address", "receiver_address", "amount", "fee", "block", "confirmation", "status", "received_at", "updated_at"}),
-	}).Create(&gormModel).Error; err != nil {
+	}, &gormModel, transaction)
+	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
 			return nil, model.NewDuplicateError()
@@ -163,3 +177,32 @@ func (t *TrxTransactionRepo) Get(ctx context.Context, filter *repository.Transac
 
 	return transaction.ToModel(), nil
 }
+
+func (t *TrxTransactionRepo) List(ctx context.Context, filter *repository.TransactionGetFilter) ([]model.Transaction, error) {
+	transactions := []trxTransaction{}
+
+	q := t.db.WithContext(ctx)
+	if filter.Address != nil {
+		q = q.Where("sender_address = ? OR receiver_address = ?", filter.Address, filter.Address)
+	}
+
+	if filter.Status != nil {
+		q = q.Where("status = ?", filter.Status)
+	}
+
+	if filter.CreatedBefore != nil {
+		q = q.Where("created_at < ?", filter.CreatedBefore)
+	}
+
+	err := q.Order("created_at").Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		res = append(res, *transaction.ToModel())
+	}
+
+	return res, nil
+}
@@ -0,0 +1,207 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+)
+
+type user struct {
+	Id                  *string
+	Username            *string
+	Email               *string
+	FullName            *string
+	Password            *string
+	PasswordSalt        *string
+	Roles               pq.StringArray `gorm:"type:text[]"`
+	TotpSecret          []byte
+	TotpEnabledAt       *time.Time
+	TotpLastStep        *int64
+	AllowlistEnabledAt  *time.Time
+	AllowlistDisabledAt *time.Time
+	CreatedAt           *time.Time
+	UpdatedAt           *time.Time
+}
+
+func (u user) FromModel(data model.User) *user {
+	return &user{
+		Id:                  data.Id,
+		Username:            data.Username,
+		Email:               data.Email,
+		FullName:            data.FullName,
+		Password:            data.Password,
+		PasswordSalt:        data.PasswordSalt,
+		Roles:               data.Roles,
+		TotpSecret:          data.TotpSecret,
+		TotpEnabledAt:       data.TotpEnabledAt,
+		TotpLastStep:        data.TotpLastStep,
+		AllowlistEnabledAt:  data.AllowlistEnabledAt,
+		AllowlistDisabledAt: data.AllowlistDisabledAt,
+		CreatedAt:           data.CreatedAt,
+		UpdatedAt:           data.UpdatedAt,
+	}
+}
+
+func (u user) ToModel() *model.User {
+	return &model.User{
+		Id:                  u.Id,
+		Username:            u.Username,
+		Email:               u.Email,
+		FullName:            u.FullName,
+		Password:            u.Password,
+		PasswordSalt:        u.PasswordSalt,
+		Roles:               u.Roles,
+		TotpSecret:          u.TotpSecret,
+		TotpEnabledAt:       u.TotpEnabledAt,
+		TotpLastStep:        u.TotpLastStep,
+		AllowlistEnabledAt:  u.AllowlistEnabledAt,
+		AllowlistDisabledAt: u.AllowlistDisabledAt,
+		CreatedAt:           u.CreatedAt,
+		UpdatedAt:           u.UpdatedAt,
+	}
+}
+
+func (u user) TableName() string {
+	return "users"
+}
+
+func (u *user) BeforeCreate(db *gorm.DB) error {
+	if u.Id == nil {
+		u.Id = helper.Pointer(ksuid.New().String())
+	}
+
+	return nil
+}
+
+type UserRepo struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) repository.User {
+	return &UserRepo{
+		db: db,
+	}
+}
+
+func (u *UserRepo) Add(ctx context.Context, data *model.User) (*model.User, error) {
+	gormModel := user{}.FromModel(*data)
+
+	if err := u.db.WithContext(ctx).Create(&gormModel).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return nil, model.NewDuplicateError()
+		}
+		return nil, err
+	}
+
+	return gormModel.ToModel(), nil
+}
+
+func (u *UserRepo) Get(ctx context.Context, filter *repository.UserGetFilter) (*model.User, error) {
+	q := u.db.WithContext(ctx)
+	if filter.Id != nil {
+		q = q.Where("id = ?", filter.Id)
+	}
+	if filter.Username != nil {
+		q = q.Where("username = ?", filter.Username)
+	}
+	if filter.Email != nil {
+		q = q.Where("email = ?", filter.Email)
+	}
+
+	var gormModel user
+	if err := q.First(&gormModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.NewNotFoundError()
+		}
+		return nil, err
+	}
+
+	return gormModel.ToModel(), nil
+}
+
+func (u *UserRepo) UpdatePassword(ctx context.Context, id string, password string) error {
+	res := u.db.WithContext(ctx).Model(&user{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"password":      password,
+		"password_salt": nil,
+		"updated_at":    time.Now(),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return model.NewNotFoundError()
+	}
+
+	return nil
+}
+
+func (u *UserRepo) SetTotpSecret(ctx context.Context, id string, secret []byte) error {
+	res := u.db.WithContext(ctx).Model(&user{}).Where("id = ? AND totp_enabled_at IS NULL", id).Updates(map[string]interface{}{
+		"totp_secret":    secret,
+		"totp_last_step": nil,
+		"updated_at":     time.Now(),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return model.NewNotFoundError()
+	}
+
+	return nil
+}
+
+func (u *UserRepo) EnableTotp(ctx context.Context, id string, step int64) error {
+	res := u.db.WithContext(ctx).Model(&user{}).Where("id = ? AND totp_enabled_at IS NULL AND totp_secret IS NOT NULL", id).Updates(map[string]interface{}{
+		"totp_enabled_at": time.Now(),
+		"totp_last_step":  step,
+		"updated_at":      time.Now(),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return model.NewNotFoundError()
+	}
+
+	return nil
+}
+
+func (u *UserRepo) UseTotpStep(ctx context.Context, id string, step int64) (bool, error) {
+	// the condition makes the check and the update atomic, two requests with the same code cannot both pass
+	res := u.db.WithContext(ctx).Model(&user{}).
+		Where("id = ? AND (totp_last_step IS NULL OR totp_last_step < ?)", id, step).
+		Update("totp_last_step", step)
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (u *UserRepo) SetAllowlist(ctx context.Context, id string, enabledAt *time.Time, disabledAt *time.Time) error {
+	res := u.db.WithContext(ctx).Model(&user{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"allowlist_enabled_at":  enabledAt,
+		"allowlist_disabled_at": disabledAt,
+		"updated_at":            time.Now(),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return model.NewNotFoundError()
+	}
+
+	return nil
+}
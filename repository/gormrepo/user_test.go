@@ -0,0 +1,157 @@
+//go:build integration
+// +build integration
+
+package gormrepo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/helper/test"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/repository/gormrepo"
+	"github.com/aalexanderkevin/crypto-wallet/storage"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository_Add(t *testing.T) {
+	t.Run("ShouldReturnDuplicateError_WhenTheUsernameIsTaken", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		existing := test.FakeUserCreate(t, db, nil)
+		user := test.FakeUser(t, func(user model.User) model.User {
+			user.Username = existing.Username
+			return user
+		})
+
+		//-- code under test
+		userRepo := gormrepo.NewUserRepository(db)
+		res, err := userRepo.Add(context.TODO(), &user)
+
+		//-- assert
+		require.EqualError(t, err, model.NewDuplicateError().Error())
+		require.Nil(t, res)
+	})
+}
+
+func TestUserRepository_Get(t *testing.T) {
+	t.Run("ShouldReturnNotFoundError_WhenTheUsernameIsNotExist", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		//-- code under test
+		userRepo := gormrepo.NewUserRepository(db)
+		res, err := userRepo.Get(context.TODO(), &repository.UserGetFilter{Username: helper.Pointer("invalid-username")})
+
+		//-- assert
+		require.EqualError(t, err, model.NewNotFoundError().Error())
+		require.Nil(t, res)
+	})
+
+	t.Run("ShouldGet_WhenTheUsernameExist", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		user := test.FakeUserCreate(t, db, nil)
+
+		//-- code under test
+		userRepo := gormrepo.NewUserRepository(db)
+		res, err := userRepo.Get(context.TODO(), &repository.UserGetFilter{Username: user.Username})
+
+		//-- assert
+		require.NoError(t, err)
+		require.Equal(t, *user.Id, *res.Id)
+		require.Equal(t, *user.Email, *res.Email)
+		require.Equal(t, *user.Password, *res.Password)
+		require.Equal(t, *user.PasswordSalt, *res.PasswordSalt)
+	})
+}
+
+func TestUserRepository_UpdatePassword(t *testing.T) {
+	t.Run("ShouldReplaceTheHashAndDropTheLegacySalt", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		user := test.FakeUserCreate(t, db, nil)
+
+		//-- code under test
+		userRepo := gormrepo.NewUserRepository(db)
+		err := userRepo.UpdatePassword(context.TODO(), *user.Id, "$argon2id$hash")
+
+		//-- assert
+		require.NoError(t, err)
+		res, err := userRepo.Get(context.TODO(), &repository.UserGetFilter{Id: user.Id})
+		require.NoError(t, err)
+		require.Equal(t, "$argon2id$hash", *res.Password)
+		require.Nil(t, res.PasswordSalt)
+	})
+
+	t.Run("ShouldReturnNotFoundError_WhenTheIdIsNotExist", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		//-- code under test
+		userRepo := gormrepo.NewUserRepository(db)
+		err := userRepo.UpdatePassword(context.TODO(), "invalid-id", "$argon2id$hash")
+
+		//-- assert
+		require.EqualError(t, err, model.NewNotFoundError().Error())
+	})
+}
+
+func TestUserRepository_UseTotpStep(t *testing.T) {
+	t.Run("ShouldAcceptAStepOnce", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		user := test.FakeUserCreate(t, db, nil)
+		userRepo := gormrepo.NewUserRepository(db)
+		require.NoError(t, userRepo.SetTotpSecret(context.TODO(), *user.Id, []byte("encrypted-secret")))
+		require.NoError(t, userRepo.EnableTotp(context.TODO(), *user.Id, 100))
+
+		//-- code under test
+		sameStep, err := userRepo.UseTotpStep(context.TODO(), *user.Id, 100)
+		require.NoError(t, err)
+		nextStep, err := userRepo.UseTotpStep(context.TODO(), *user.Id, 101)
+		require.NoError(t, err)
+		replayed, err := userRepo.UseTotpStep(context.TODO(), *user.Id, 101)
+		require.NoError(t, err)
+
+		//-- assert
+		require.False(t, sameStep)
+		require.True(t, nextStep)
+		require.False(t, replayed)
+		res, err := userRepo.Get(context.TODO(), &repository.UserGetFilter{Id: user.Id})
+		require.NoError(t, err)
+		require.True(t, res.TotpEnabled())
+		require.Equal(t, []byte("encrypted-secret"), res.TotpSecret)
+		require.Equal(t, int64(101), *res.TotpLastStep)
+	})
+
+	t.Run("ShouldNotReplaceTheSecret_WhenTotpIsEnabled", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		user := test.FakeUserCreate(t, db, nil)
+		userRepo := gormrepo.NewUserRepository(db)
+		require.NoError(t, userRepo.SetTotpSecret(context.TODO(), *user.Id, []byte("encrypted-secret")))
+		require.NoError(t, userRepo.EnableTotp(context.TODO(), *user.Id, 100))
+
+		//-- code under test
+		err := userRepo.SetTotpSecret(context.TODO(), *user.Id, []byte("other-secret"))
+
+		//-- assert
+		require.EqualError(t, err, model.NewNotFoundError().Error())
+	})
+}
@@ -16,24 +16,29 @@ import (
 )
 
 type WalletRepo struct {
-	db *gorm.DB
+	db        *gorm.DB
+	auditRepo repository.AuditEvent
 }
 
 func NewWalletRepository(db *gorm.DB) repository.Wallet {
 	return &WalletRepo{
-		db: db,
+		db:        db,
+		auditRepo: NewAuditEventRepository(db),
 	}
 }
 
 type Wallet struct {
-	Id         *string
-	Email      *string
-	SeedPhrase []byte
-	BtcAddress *string
-	EthAddress *string
-	TrxAddress *string
-	CreatedAt  *time.Time
-	UpdatedAt  *time.Time
+	Id          *string
+	UserId      *string
+	Email       *string
+	SeedPhrase  []byte
+	BtcAddress  *string
+	EthAddress  *string
+	TrxAddress  *string
+	LtcAddress  *string
+	DogeAddress *string
+	CreatedAt   *time.Time
+	UpdatedAt   *time.Time
 }
 
 func (w Wallet) FromModel(data *model.Wallet, key *string) (wallet *Wallet, err error) {
@@ -49,14 +54,17 @@ func (w Wallet) FromModel(data *model.Wallet, key *string) (wallet *Wallet, err
 	}
 
 	return &Wallet{
-		Id:         data.Id,
-		Email:      data.Email,
-		SeedPhrase: seedPhrase,
-		BtcAddress: data.BtcAddress,
-		EthAddress: data.EthAddress,
-		TrxAddress: data.TrxAddress,
-		CreatedAt:  data.CreatedAt,
-		UpdatedAt:  data.UpdatedAt,
+		Id:          data.Id,
+		UserId:      data.UserId,
+		Email:       data.Email,
+		SeedPhrase:  seedPhrase,
+		BtcAddress:  data.BtcAddress,
+		EthAddress:  data.EthAddress,
+		TrxAddress:  data.TrxAddress,
+		LtcAddress:  data.LtcAddress,
+		DogeAddress: data.DogeAddress,
+		CreatedAt:   data.CreatedAt,
+		UpdatedAt:   data.UpdatedAt,
 	}, nil
 }
 
@@ -70,14 +78,17 @@ func (w Wallet) ToModel(key *string) (wallet *model.Wallet, err error) {
 	}
 
 	return &model.Wallet{
-		Id:         w.Id,
-		Email:      w.Email,
-		SeedPhrase: helper.Pointer(string(seedPhrase)),
-		BtcAddress: w.BtcAddress,
-		EthAddress: w.EthAddress,
-		TrxAddress: w.TrxAddress,
-		CreatedAt:  w.CreatedAt,
-		UpdatedAt:  w.UpdatedAt,
+		Id:          w.Id,
+		UserId:      w.UserId,
+		Email:       w.Email,
+		SeedPhrase:  helper.Pointer(string(seedPhrase)),
+		BtcAddress:  w.BtcAddress,
+		EthAddress:  w.EthAddress,
+		TrxAddress:  w.TrxAddress,
+		LtcAddress:  w.LtcAddress,
+		DogeAddress: w.DogeAddress,
+		CreatedAt:   w.CreatedAt,
+		UpdatedAt:   w.UpdatedAt,
 	}, nil
 }
 
@@ -120,9 +131,15 @@ func (w *WalletRepo) Get(ctx context.Context, filter *repository.WalletGetFilter
 	if filter.Id != nil {
 		q = q.Where("id = ?", filter.Id)
 	}
+	if filter.UserId != nil {
+		q = q.Where("user_id = ?", filter.UserId)
+	}
 	if filter.Email != nil {
 		q = q.Where("email = ?", filter.Email)
 	}
+	if filter.Address != nil {
+		q = q.Where("(btc_address = ? OR eth_address = ? OR trx_address = ? OR ltc_address = ? OR doge_address = ?)", filter.Address, filter.Address, filter.Address, filter.Address, filter.Address)
+	}
 
 	err := q.First(&wallet).Error
 	if err != nil {
@@ -132,7 +149,22 @@ func (w *WalletRepo) Get(ctx context.Context, filter *repository.WalletGetFilter
 		return nil, err
 	}
 
-	return wallet.ToModel(encryptionKey)
+	res, err := wallet.ToModel(encryptionKey)
+	if err != nil || encryptionKey == nil {
+		return res, err
+	}
+
+	// the seed phrase is not handed out when its decryption cannot be audited
+	_, err = w.auditRepo.Append(ctx, &model.AuditEvent{
+		Action:   helper.Pointer(model.AuditSeedDecrypted),
+		TargetId: res.Id,
+		Metadata: map[string]string{"user_id": helper.Val(res.UserId)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
 }
 
 func (w *WalletRepo) Update(ctx context.Context, id string, wallet *model.Wallet) (*model.Wallet, error) {
@@ -158,3 +190,26 @@ func (w *WalletRepo) Update(ctx context.Context, id string, wallet *model.Wallet
 
 	return w.Get(ctx, &repository.WalletGetFilter{Id: &id}, nil)
 }
+
+func (w *WalletRepo) ListMissingAddresses(ctx context.Context, afterId *string, limit int) ([]model.Wallet, error) {
+	q := w.db.WithContext(ctx).Where("(ltc_address IS NULL OR doge_address IS NULL)")
+	if afterId != nil {
+		q = q.Where("id > ?", afterId)
+	}
+
+	wallets := []Wallet{}
+	if err := q.Order("id").Limit(limit).Find(&wallets).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]model.Wallet, 0, len(wallets))
+	for _, wallet := range wallets {
+		data, err := wallet.ToModel(nil)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *data)
+	}
+
+	return res, nil
+}
@@ -62,6 +62,30 @@ func TestWalletRepository_Get(t *testing.T) {
 		require.Equal(t, *fakeBtcTx.EthAddress, *tx.EthAddress)
 	})
 
+	t.Run("ShouldGet_WhenTheUserIdExist", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		user := test.FakeUserCreate(t, db, nil)
+		fakeWallet := test.FakeWalletCreate(t, db, func(wallet model.Wallet) model.Wallet {
+			wallet.UserId = user.Id
+			wallet.Email = user.Email
+			return wallet
+		})
+
+		//-- code under test
+		walletRepo := gormrepo.NewWalletRepository(db)
+		res, err := walletRepo.Get(context.TODO(), &repository.WalletGetFilter{
+			UserId: user.Id,
+		}, nil)
+		require.NoError(t, err)
+
+		//-- assert
+		require.Equal(t, *fakeWallet.Id, *res.Id)
+		require.Equal(t, *user.Id, *res.UserId)
+	})
+
 }
 
 func TestWalletRepository_Add(t *testing.T) {
@@ -71,7 +95,11 @@ func TestWalletRepository_Add(t *testing.T) {
 		db := storage.PostgresDbConn(&dbName)
 		defer cleanDB(t, db)
 
-		fakeWallet := test.FakeWallet(t, nil)
+		user := test.FakeUserCreate(t, db, nil)
+		fakeWallet := test.FakeWallet(t, func(wallet model.Wallet) model.Wallet {
+			wallet.UserId = user.Id
+			return wallet
+		})
 
 		//-- code under test
 		walletRepo := gormrepo.NewWalletRepository(db)
@@ -153,3 +181,31 @@ func TestWalletRepository_Update(t *testing.T) {
 	})
 
 }
+
+func TestWalletRepository_ListMissingAddresses(t *testing.T) {
+	t.Run("ShouldListOnlyTheWalletsWithoutALitecoinOrADogecoinAddress", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		missing := test.FakeWalletCreate(t, db, nil)
+		test.FakeWalletCreate(t, db, func(wallet model.Wallet) model.Wallet {
+			wallet.LtcAddress = helper.Pointer(fake.CharactersN(34))
+			wallet.DogeAddress = helper.Pointer(fake.CharactersN(34))
+			return wallet
+		})
+
+		//-- code under test
+		walletRepo := gormrepo.NewWalletRepository(db)
+		wallets, err := walletRepo.ListMissingAddresses(context.TODO(), nil, 10)
+
+		//-- assert
+		require.NoError(t, err)
+		require.Len(t, wallets, 1)
+		require.Equal(t, *missing.Id, *wallets[0].Id)
+
+		wallets, err = walletRepo.ListMissingAddresses(context.TODO(), missing.Id, 10)
+		require.NoError(t, err)
+		require.Empty(t, wallets)
+	})
+}
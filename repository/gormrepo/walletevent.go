@@ -0,0 +1,129 @@
+package gormrepo
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+type walletEvent struct {
+	Id        *int64
+	Addresses pq.StringArray `gorm:"type:text[]"`
+	Payload   *string
+	CreatedAt *time.Time
+}
+
+func (w walletEvent) FromModel(data model.WalletEvent) (*walletEvent, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &walletEvent{
+		Addresses: data.Addresses,
+		Payload:   helper.Pointer(string(payload)),
+		CreatedAt: data.CreatedAt,
+	}, nil
+}
+
+func (w walletEvent) ToModel() (*model.WalletEvent, error) {
+	var res model.WalletEvent
+	if err := json.Unmarshal([]byte(helper.Val(w.Payload)), &res); err != nil {
+		return nil, err
+	}
+	res.Sequence = uint64(helper.Val(w.Id))
+	res.ResumeToken = helper.Pointer(strconv.FormatUint(res.Sequence, 10))
+
+	return &res, nil
+}
+
+func (w walletEvent) TableName() string {
+	return "wallet_events"
+}
+
+type WalletEventRepo struct {
+	db *gorm.DB
+}
+
+func NewWalletEventRepository(db *gorm.DB) repository.WalletEvent {
+	return &WalletEventRepo{
+		db: db,
+	}
+}
+
+func (w *WalletEventRepo) Add(ctx context.Context, data *model.WalletEvent) (*model.WalletEvent, error) {
+	gormModel, err := walletEvent{}.FromModel(*data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the id is taken under the lock and the lock is held until the commit, so the ids become visible in order
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", model.WalletEventsChannel).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(gormModel).Error; err != nil {
+			return err
+		}
+
+		// the notification is delivered on commit
+		return tx.Exec("SELECT pg_notify(?, ?)", model.WalletEventsChannel, strconv.FormatInt(*gormModel.Id, 10)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return gormModel.ToModel()
+}
+
+func (w *WalletEventRepo) ListAfter(ctx context.Context, afterId uint64, addresses []string, limit int) ([]model.WalletEvent, error) {
+	q := w.db.WithContext(ctx).Where("id > ?", afterId)
+	if len(addresses) > 0 {
+		q = q.Where("addresses && ?", pq.StringArray(addresses))
+	}
+
+	events := []walletEvent{}
+	if err := q.Order("id").Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]model.WalletEvent, 0, len(events))
+	for _, event := range events {
+		data, err := event.ToModel()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *data)
+	}
+
+	return res, nil
+}
+
+func (w *WalletEventRepo) Bounds(ctx context.Context) (uint64, uint64, error) {
+	var bounds struct {
+		First int64
+		Last  int64
+	}
+	err := w.db.WithContext(ctx).Model(&walletEvent{}).
+		Select("COALESCE(MIN(id), 0) AS first, COALESCE(MAX(id), 0) AS last").
+		Scan(&bounds).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return uint64(bounds.First), uint64(bounds.Last), nil
+}
+
+func (w *WalletEventRepo) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	res := w.db.WithContext(ctx).Where("created_at < ?", before).Delete(&walletEvent{})
+
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,196 @@
+//go:build integration
+// +build integration
+
+package gormrepo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/helper/test"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository/gormrepo"
+	"github.com/aalexanderkevin/crypto-wallet/service/chain"
+	"github.com/aalexanderkevin/crypto-wallet/service/confirmation"
+	"github.com/aalexanderkevin/crypto-wallet/service/eventbus"
+	"github.com/aalexanderkevin/crypto-wallet/service/mocks"
+	"github.com/aalexanderkevin/crypto-wallet/storage"
+	"github.com/aalexanderkevin/crypto-wallet/usecase"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresBus(t *testing.T) {
+	t.Run("ShouldDeliverToAnotherProcess", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		// each bus has its own connections, like the worker and the grpc server
+		publisher := eventbus.NewPostgresBus(gormrepo.NewWalletEventRepository(storage.PostgresDbConn(&dbName)),
+			storage.NewPostgresNotifier(&dbName), 10, time.Minute)
+		defer publisher.Close()
+		receiver := eventbus.NewPostgresBus(gormrepo.NewWalletEventRepository(storage.PostgresDbConn(&dbName)),
+			storage.NewPostgresNotifier(&dbName), 10, time.Minute)
+		defer receiver.Close()
+
+		events, unsubscribe, err := receiver.Subscribe(context.TODO(), []string{"address"}, nil)
+		require.NoError(t, err)
+		defer unsubscribe()
+		// give the receiver the time to listen, the poll interval is too long to be the one delivering
+		time.Sleep(500 * time.Millisecond)
+
+		//-- code under test
+		publisher.Publish(context.TODO(), model.WalletEvent{
+			Type:      helper.Pointer(model.EventDepositDetected),
+			Addresses: []string{"other-address"},
+		})
+		publisher.Publish(context.TODO(), model.WalletEvent{
+			Type:          helper.Pointer(model.EventSendConfirmed),
+			TransactionId: helper.Pointer("tx-id"),
+			Addresses:     []string{"address"},
+			Amount:        helper.Pointer(int64(100)),
+		})
+
+		//-- assert
+		select {
+		case event := <-events:
+			require.Equal(t, model.EventSendConfirmed, *event.Type)
+			require.Equal(t, "tx-id", *event.TransactionId)
+			require.Equal(t, int64(100), *event.Amount)
+			require.NotNil(t, event.ResumeToken)
+		case <-time.After(5 * time.Second):
+			t.Fatal("event not received")
+		}
+	})
+
+	t.Run("ShouldReplayAfterTheResumeToken", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		repo := gormrepo.NewWalletEventRepository(db)
+		first, err := repo.Add(context.TODO(), &model.WalletEvent{Type: helper.Pointer(model.EventSendConfirmed), Addresses: []string{"address"}})
+		require.NoError(t, err)
+		second, err := repo.Add(context.TODO(), &model.WalletEvent{Type: helper.Pointer(model.EventSendFailed), Addresses: []string{"address"}})
+		require.NoError(t, err)
+
+		bus := eventbus.NewPostgresBus(gormrepo.NewWalletEventRepository(db), storage.NewPostgresNotifier(&dbName), 10, time.Minute)
+		defer bus.Close()
+
+		//-- code under test
+		events, unsubscribe, err := bus.Subscribe(context.TODO(), []string{"address"}, first.ResumeToken)
+
+		//-- assert
+		require.NoError(t, err)
+		defer unsubscribe()
+		event := <-events
+		require.Equal(t, second.Sequence, event.Sequence)
+		require.Equal(t, model.EventSendFailed, *event.Type)
+	})
+
+	t.Run("ShouldFail_WhenTheResumeTokenIsNoLongerRetained", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		repo := gormrepo.NewWalletEventRepository(db)
+		first, err := repo.Add(context.TODO(), &model.WalletEvent{Type: helper.Pointer(model.EventSendConfirmed), Addresses: []string{"address"}})
+		require.NoError(t, err)
+		_, err = repo.Add(context.TODO(), &model.WalletEvent{Type: helper.Pointer(model.EventSendConfirmed), Addresses: []string{"address"}})
+		require.NoError(t, err)
+		_, err = repo.Add(context.TODO(), &model.WalletEvent{Type: helper.Pointer(model.EventSendFailed), Addresses: []string{"address"}})
+		require.NoError(t, err)
+		require.NoError(t, db.Exec("DELETE FROM wallet_events WHERE id <= ?", first.Sequence+1).Error)
+
+		bus := eventbus.NewPostgresBus(repo, storage.NewPostgresNotifier(&dbName), 10, time.Minute)
+		defer bus.Close()
+
+		//-- code under test
+		_, _, err = bus.Subscribe(context.TODO(), []string{"address"}, first.ResumeToken)
+
+		//-- assert
+		require.Error(t, err)
+		require.Equal(t, model.NewResumeTokenExpiredError(), err)
+	})
+}
+
+func TestWalletEvents_TrackedByTheWorker(t *testing.T) {
+	t.Run("ShouldReachTheStreamOfTheGrpcServer", func(t *testing.T) {
+		//-- init
+		cfg := config.Instance()
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		wallet := test.FakeWalletCreate(t, db, nil)
+		confirmationPolicy, err := confirmation.NewPolicy(cfg.Confirmation)
+		require.NoError(t, err)
+
+		adapter := &mocks.ChainAdapter{}
+		adapter.On("Chain").Return("eth")
+		adapter.On("Address", mock.Anything).Return(wallet.EthAddress)
+		adapter.On("GetTx", mock.Anything, "tx-hash").Return(&model.Transaction{
+			Block:        helper.Pointer(int64(10)),
+			Confirmation: helper.Pointer(int64(1000)),
+			Status:       helper.Pointer(model.TransactionStatusSuccess),
+		}, nil)
+		chains := chain.NewRegistry()
+		chains.Register(adapter)
+
+		// the worker and the grpc server are built like two processes, sharing only the database
+		workerDb := storage.PostgresDbConn(&dbName)
+		workerBus := eventbus.NewPostgresBus(gormrepo.NewWalletEventRepository(workerDb), storage.NewPostgresNotifier(&dbName), 10, time.Minute)
+		defer workerBus.Close()
+		worker := container.NewContainer()
+		worker.SetConfig(cfg)
+		worker.SetEventBus(workerBus)
+		worker.SetChainRegistry(chains)
+		worker.SetConfirmationPolicy(confirmationPolicy)
+		worker.SetJobRepo(gormrepo.NewJobRepository(workerDb))
+		worker.SetTransactionEthRepo(gormrepo.NewEthTransactionRepository(workerDb))
+
+		grpcDb := storage.PostgresDbConn(&dbName)
+		grpcBus := eventbus.NewPostgresBus(gormrepo.NewWalletEventRepository(grpcDb), storage.NewPostgresNotifier(&dbName), 10, time.Minute)
+		defer grpcBus.Close()
+		grpc := container.NewContainer()
+		grpc.SetConfig(cfg)
+		grpc.SetEventBus(grpcBus)
+		grpc.SetChainRegistry(chains)
+		grpc.SetWalletRepo(gormrepo.NewWalletRepository(grpcDb))
+
+		events, unsubscribe, err := usecase.NewEvent(grpc).SubscribeWalletEvents(context.TODO(), wallet.UserId, nil)
+		require.NoError(t, err)
+		defer unsubscribe()
+		// give the grpc bus the time to listen, the poll interval is too long to be the one delivering
+		time.Sleep(500 * time.Millisecond)
+
+		err = usecase.NewTransaction(worker).TrackTransaction(context.TODO(), "eth", &model.Transaction{
+			Id:              helper.Pointer("tx-hash"),
+			SenderAddress:   []string{*wallet.EthAddress},
+			ReceiverAddress: []string{"receiver"},
+			Amount:          helper.Pointer(int64(100)),
+			Status:          helper.Pointer(model.TransactionStatusPending),
+		})
+		require.NoError(t, err)
+
+		//-- code under test
+		ran, err := usecase.NewJobRunner(worker).RunDue(context.TODO())
+		require.NoError(t, err)
+		require.Equal(t, 1, ran)
+
+		//-- assert
+		select {
+		case event := <-events:
+			require.Equal(t, model.EventSendConfirmed, *event.Type)
+			require.Equal(t, "tx-hash", *event.TransactionId)
+			require.Equal(t, []string{*wallet.EthAddress}, event.Addresses)
+		case <-time.After(5 * time.Second):
+			t.Fatal("event not received")
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type webhookDelivery struct {
+	Id             *string
+	EndpointId     *string
+	EventType      *string
+	Payload        *string
+	Status         *string
+	Attempts       *int
+	NextAttemptAt  *time.Time
+	LastStatusCode *int
+	LastError      *string
+	DeliveredAt    *time.Time
+	CreatedAt      *time.Time
+	UpdatedAt      *time.Time
+}
+
+func (w webhookDelivery) FromModel(data model.WebhookDelivery) *webhookDelivery {
+	return &webhookDelivery{
+		Id:             data.Id,
+		EndpointId:     data.EndpointId,
+		EventType:      data.EventType,
+		Payload:        data.Payload,
+		Status:         data.Status,
+		Attempts:       data.Attempts,
+		NextAttemptAt:  data.NextAttemptAt,
+		LastStatusCode: data.LastStatusCode,
+		LastError:      data.LastError,
+		DeliveredAt:    data.DeliveredAt,
+		CreatedAt:      data.CreatedAt,
+		UpdatedAt:      data.UpdatedAt,
+	}
+}
+
+func (w webhookDelivery) ToModel() *model.WebhookDelivery {
+	return &model.WebhookDelivery{
+		Id:             w.Id,
+		EndpointId:     w.EndpointId,
+		EventType:      w.EventType,
+		Payload:        w.Payload,
+		Status:         w.Status,
+		Attempts:       w.Attempts,
+		NextAttemptAt:  w.NextAttemptAt,
+		LastStatusCode: w.LastStatusCode,
+		LastError:      w.LastError,
+		DeliveredAt:    w.DeliveredAt,
+		CreatedAt:      w.CreatedAt,
+		UpdatedAt:      w.UpdatedAt,
+	}
+}
+
+func (w webhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+func (w *webhookDelivery) BeforeCreate(db *gorm.DB) error {
+	if w.Id == nil {
+		w.Id = helper.Pointer(ksuid.New().String())
+	}
+
+	return nil
+}
+
+type WebhookDeliveryRepo struct {
+	db *gorm.DB
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) repository.WebhookDelivery {
+	return &WebhookDeliveryRepo{
+		db: db,
+	}
+}
+
+func (w *WebhookDeliveryRepo) Add(ctx context.Context, delivery *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	gormModel := webhookDelivery{}.FromModel(*delivery)
+
+	if err := w.db.WithContext(ctx).Create(&gormModel).Error; err != nil {
+		return nil, err
+	}
+
+	return gormModel.ToModel(), nil
+}
+
+func (w *WebhookDeliveryRepo) Get(ctx context.Context, filter *repository.WebhookDeliveryGetFilter) (*model.WebhookDelivery, error) {
+	delivery := webhookDelivery{}
+
+	err := w.query(ctx, filter).First(&delivery).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.NewNotFoundError()
+		}
+		return nil, err
+	}
+
+	return delivery.ToModel(), nil
+}
+
+func (w *WebhookDeliveryRepo) List(ctx context.Context, filter *repository.WebhookDeliveryGetFilter) ([]model.WebhookDelivery, error) {
+	deliveries := []webhookDelivery{}
+
+	err := w.query(ctx, filter).Order("created_at DESC").Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		res = append(res, *delivery.ToModel())
+	}
+
+	return res, nil
+}
+
+func (w *WebhookDeliveryRepo) Update(ctx context.Context, id string, delivery *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	_, err := w.Get(ctx, &repository.WebhookDeliveryGetFilter{Id: &id})
+	if err != nil {
+		return nil, err
+	}
+
+	gormModel := webhookDelivery{}.FromModel(*delivery)
+	gormModel.UpdatedAt = helper.Pointer(time.Now())
+
+	err = w.db.WithContext(ctx).Model(&webhookDelivery{Id: &id}).Updates(&gormModel).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return w.Get(ctx, &repository.WebhookDeliveryGetFilter{Id: &id})
+}
+
+func (w *WebhookDeliveryRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error) {
+	deliveries := []webhookDelivery{}
+	now := time.Now()
+
+	err := w.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.WebhookDeliveryPending, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]string, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, *delivery.Id)
+		}
+
+		return tx.Model(&webhookDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		res = append(res, *delivery.ToModel())
+	}
+
+	return res, nil
+}
+
+func (w *WebhookDeliveryRepo) query(ctx context.Context, filter *repository.WebhookDeliveryGetFilter) *gorm.DB {
+	q := w.db.WithContext(ctx)
+	if filter.Id != nil {
+		q = q.Where("id = ?", filter.Id)
+	}
+
+	if filter.EndpointId != nil {
+		q = q.Where("endpoint_id = ?", filter.EndpointId)
+	}
+
+	if filter.UserId != nil {
+		q = q.Where("endpoint_id IN (?)", w.db.Model(&webhookEndpoint{}).Select("id").Where("user_id = ?", filter.UserId))
+	}
+
+	if filter.Status != nil {
+		q = q.Where("status = ?", filter.Status)
+	}
+
+	return q
+}
@@ -0,0 +1,91 @@
+//go:build integration
+// +build integration
+
+package gormrepo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/helper/test"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/repository/gormrepo"
+	"github.com/aalexanderkevin/crypto-wallet/storage"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDeliveryRepository_ClaimDue(t *testing.T) {
+	t.Run("ShouldOnlyClaimDuePendingDeliveries", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		due := test.FakeWebhookDeliveryCreate(t, db, nil)
+		test.FakeWebhookDeliveryCreate(t, db, func(delivery model.WebhookDelivery) model.WebhookDelivery {
+			delivery.NextAttemptAt = helper.Pointer(time.Now().Add(time.Hour))
+			return delivery
+		})
+		test.FakeWebhookDeliveryCreate(t, db, func(delivery model.WebhookDelivery) model.WebhookDelivery {
+			delivery.Status = helper.Pointer(model.WebhookDeliveryDead)
+			return delivery
+		})
+
+		//-- code under test
+		deliveryRepo := gormrepo.NewWebhookDeliveryRepository(db)
+		claimed, err := deliveryRepo.ClaimDue(context.TODO(), 10, time.Minute)
+
+		//-- assert
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+		require.Equal(t, *due.Id, *claimed[0].Id)
+	})
+
+	t.Run("ShouldNotClaimTwice_WhenLeaseIsActive", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		test.FakeWebhookDeliveryCreate(t, db, nil)
+		deliveryRepo := gormrepo.NewWebhookDeliveryRepository(db)
+		claimed, err := deliveryRepo.ClaimDue(context.TODO(), 10, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+
+		//-- code under test
+		claimed, err = deliveryRepo.ClaimDue(context.TODO(), 10, time.Minute)
+
+		//-- assert
+		require.NoError(t, err)
+		require.Len(t, claimed, 0)
+	})
+}
+
+func TestWebhookDeliveryRepository_List(t *testing.T) {
+	t.Run("ShouldFilterByEndpointOwner", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		endpoint := test.FakeWebhookEndpointCreate(t, db, nil)
+		owned := test.FakeWebhookDeliveryCreate(t, db, func(delivery model.WebhookDelivery) model.WebhookDelivery {
+			delivery.EndpointId = endpoint.Id
+			return delivery
+		})
+		test.FakeWebhookDeliveryCreate(t, db, nil)
+
+		//-- code under test
+		deliveryRepo := gormrepo.NewWebhookDeliveryRepository(db)
+		deliveries, err := deliveryRepo.List(context.TODO(), &repository.WebhookDeliveryGetFilter{
+			UserId: endpoint.UserId,
+		})
+
+		//-- assert
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, *owned.Id, *deliveries[0].Id)
+	})
+}
@@ -0,0 +1,147 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+)
+
+type webhookEndpoint struct {
+	Id        *string
+	UserId    *string
+	Url       *string
+	Secret    *string
+	Active    *bool
+	CreatedAt *time.Time
+	UpdatedAt *time.Time
+}
+
+func (w webhookEndpoint) FromModel(data model.WebhookEndpoint) *webhookEndpoint {
+	return &webhookEndpoint{
+		Id:        data.Id,
+		UserId:    data.UserId,
+		Url:       data.Url,
+		Secret:    data.Secret,
+		Active:    data.Active,
+		CreatedAt: data.CreatedAt,
+		UpdatedAt: data.UpdatedAt,
+	}
+}
+
+func (w webhookEndpoint) ToModel() *model.WebhookEndpoint {
+	return &model.WebhookEndpoint{
+		Id:        w.Id,
+		UserId:    w.UserId,
+		Url:       w.Url,
+		Secret:    w.Secret,
+		Active:    w.Active,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+func (w webhookEndpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+func (w *webhookEndpoint) BeforeCreate(db *gorm.DB) error {
+	if w.Id == nil {
+		w.Id = helper.Pointer(ksuid.New().String())
+	}
+
+	return nil
+}
+
+type WebhookEndpointRepo struct {
+	db *gorm.DB
+}
+
+func NewWebhookEndpointRepository(db *gorm.DB) repository.WebhookEndpoint {
+	return &WebhookEndpointRepo{
+		db: db,
+	}
+}
+
+func (w *WebhookEndpointRepo) Add(ctx context.Context, endpoint *model.WebhookEndpoint) (*model.WebhookEndpoint, error) {
+	gormModel := webhookEndpoint{}.FromModel(*endpoint)
+
+	if err := w.db.WithContext(ctx).Create(&gormModel).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return nil, model.NewDuplicateError()
+		}
+		return nil, err
+	}
+
+	return gormModel.ToModel(), nil
+}
+
+func (w *WebhookEndpointRepo) Get(ctx context.Context, filter *repository.WebhookEndpointGetFilter) (*model.WebhookEndpoint, error) {
+	endpoint := webhookEndpoint{}
+
+	err := w.query(ctx, filter).First(&endpoint).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.NewNotFoundError()
+		}
+		return nil, err
+	}
+
+	return endpoint.ToModel(), nil
+}
+
+func (w *WebhookEndpointRepo) List(ctx context.Context, filter *repository.WebhookEndpointGetFilter) ([]model.WebhookEndpoint, error) {
+	endpoints := []webhookEndpoint{}
+
+	err := w.query(ctx, filter).Order("created_at").Find(&endpoints).Error
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.WebhookEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		res = append(res, *endpoint.ToModel())
+	}
+
+	return res, nil
+}
+
+func (w *WebhookEndpointRepo) Delete(ctx context.Context, id string) error {
+	return w.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("id = ?", id).Delete(&webhookEndpoint{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return model.NewNotFoundError()
+		}
+
+		return tx.Where("endpoint_id = ?", id).Delete(&webhookDelivery{}).Error
+	})
+}
+
+func (w *WebhookEndpointRepo) query(ctx context.Context, filter *repository.WebhookEndpointGetFilter) *gorm.DB {
+	q := w.db.WithContext(ctx)
+	if filter.Id != nil {
+		q = q.Where("id = ?", filter.Id)
+	}
+
+	if filter.UserId != nil {
+		q = q.Where("user_id = ?", filter.UserId)
+	}
+
+	if filter.Active != nil {
+		q = q.Where("active = ?", filter.Active)
+	}
+
+	return q
+}
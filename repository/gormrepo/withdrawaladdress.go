@@ -0,0 +1,142 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+)
+
+type withdrawalAddress struct {
+	Id        *string
+	UserId    *string
+	Chain     *string
+	Address   *string
+	Label     *string
+	UsableAt  *time.Time
+	CreatedAt *time.Time
+}
+
+func (w withdrawalAddress) FromModel(data model.WithdrawalAddress) *withdrawalAddress {
+	return &withdrawalAddress{
+		Id:        data.Id,
+		UserId:    data.UserId,
+		Chain:     data.Chain,
+		Address:   data.Address,
+		Label:     data.Label,
+		UsableAt:  data.UsableAt,
+		CreatedAt: data.CreatedAt,
+	}
+}
+
+func (w withdrawalAddress) ToModel() *model.WithdrawalAddress {
+	return &model.WithdrawalAddress{
+		Id:        w.Id,
+		UserId:    w.UserId,
+		Chain:     w.Chain,
+		Address:   w.Address,
+		Label:     w.Label,
+		UsableAt:  w.UsableAt,
+		CreatedAt: w.CreatedAt,
+	}
+}
+
+func (w withdrawalAddress) TableName() string {
+	return "withdrawal_addresses"
+}
+
+func (w *withdrawalAddress) BeforeCreate(db *gorm.DB) error {
+	if w.Id == nil {
+		w.Id = helper.Pointer(ksuid.New().String())
+	}
+
+	return nil
+}
+
+type WithdrawalAddressRepo struct {
+	db *gorm.DB
+}
+
+func NewWithdrawalAddressRepository(db *gorm.DB) repository.WithdrawalAddress {
+	return &WithdrawalAddressRepo{
+		db: db,
+	}
+}
+
+func (w *WithdrawalAddressRepo) Add(ctx context.Context, data *model.WithdrawalAddress) (*model.WithdrawalAddress, error) {
+	gormModel := withdrawalAddress{}.FromModel(*data)
+
+	if err := w.db.WithContext(ctx).Create(&gormModel).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return nil, model.NewDuplicateError()
+		}
+		return nil, err
+	}
+
+	return gormModel.ToModel(), nil
+}
+
+func (w *WithdrawalAddressRepo) Get(ctx context.Context, filter *repository.WithdrawalAddressFilter) (*model.WithdrawalAddress, error) {
+	var gormModel withdrawalAddress
+	if err := w.filter(ctx, filter).First(&gormModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.NewNotFoundError()
+		}
+		return nil, err
+	}
+
+	return gormModel.ToModel(), nil
+}
+
+func (w *WithdrawalAddressRepo) List(ctx context.Context, filter *repository.WithdrawalAddressFilter) ([]model.WithdrawalAddress, error) {
+	addresses := []withdrawalAddress{}
+	if err := w.filter(ctx, filter).Order("created_at").Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]model.WithdrawalAddress, 0, len(addresses))
+	for _, address := range addresses {
+		res = append(res, *address.ToModel())
+	}
+
+	return res, nil
+}
+
+func (w *WithdrawalAddressRepo) Delete(ctx context.Context, userId string, id string) error {
+	res := w.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userId).Delete(&withdrawalAddress{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return model.NewNotFoundError()
+	}
+
+	return nil
+}
+
+func (w *WithdrawalAddressRepo) filter(ctx context.Context, filter *repository.WithdrawalAddressFilter) *gorm.DB {
+	q := w.db.WithContext(ctx)
+	if filter.Id != nil {
+		q = q.Where("id = ?", filter.Id)
+	}
+	if filter.UserId != nil {
+		q = q.Where("user_id = ?", filter.UserId)
+	}
+	if filter.Chain != nil {
+		q = q.Where("chain = ?", filter.Chain)
+	}
+	if filter.Address != nil {
+		q = q.Where("address = ?", filter.Address)
+	}
+
+	return q
+}
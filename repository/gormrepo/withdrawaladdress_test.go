@@ -0,0 +1,70 @@
+//go:build integration
+// +build integration
+
+package gormrepo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/helper/test"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/repository/gormrepo"
+	"github.com/aalexanderkevin/crypto-wallet/storage"
+
+	"github.com/stretchr/testify/require"
+)
+
+func fakeWithdrawalAddress(userId *string) *model.WithdrawalAddress {
+	return &model.WithdrawalAddress{
+		UserId:   userId,
+		Chain:    helper.Pointer("eth"),
+		Address:  helper.Pointer("0x00000000219ab540356cbb839cbe05303d7705fa"),
+		UsableAt: helper.Pointer(time.Now().Add(time.Hour)),
+	}
+}
+
+func TestWithdrawalAddressRepository_Add(t *testing.T) {
+	t.Run("ShouldReturnDuplicateError_WhenTheAddressIsAlreadyAllowlisted", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		user := test.FakeUserCreate(t, db, nil)
+		withdrawalAddressRepo := gormrepo.NewWithdrawalAddressRepository(db)
+		_, err := withdrawalAddressRepo.Add(context.TODO(), fakeWithdrawalAddress(user.Id))
+		require.NoError(t, err)
+
+		//-- code under test
+		_, err = withdrawalAddressRepo.Add(context.TODO(), fakeWithdrawalAddress(user.Id))
+
+		//-- assert
+		require.EqualError(t, err, model.NewDuplicateError().Error())
+	})
+}
+
+func TestWithdrawalAddressRepository_Delete(t *testing.T) {
+	t.Run("ShouldReturnNotFoundError_WhenTheAddressIsOfAnotherUser", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		owner := test.FakeUserCreate(t, db, nil)
+		other := test.FakeUserCreate(t, db, nil)
+		withdrawalAddressRepo := gormrepo.NewWithdrawalAddressRepository(db)
+		address, err := withdrawalAddressRepo.Add(context.TODO(), fakeWithdrawalAddress(owner.Id))
+		require.NoError(t, err)
+
+		//-- code under test
+		err = withdrawalAddressRepo.Delete(context.TODO(), *other.Id, *address.Id)
+
+		//-- assert
+		require.EqualError(t, err, model.NewNotFoundError().Error())
+		addresses, err := withdrawalAddressRepo.List(context.TODO(), &repository.WithdrawalAddressFilter{UserId: owner.Id})
+		require.NoError(t, err)
+		require.Len(t, addresses, 1)
+	})
+}
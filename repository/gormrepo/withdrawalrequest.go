@@ -0,0 +1,422 @@
+package gormrepo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"github.com/segmentio/ksuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type approvalPolicy struct {
+	Id                *string
+	WalletId          *string
+	Chain             *string
+	Threshold         *int64
+	RequiredApprovals *int
+	ApproverIds       pq.StringArray `gorm:"type:text[]"`
+	CreatedAt         *time.Time
+	UpdatedAt         *time.Time
+}
+
+func (a approvalPolicy) FromModel(data model.ApprovalPolicy) *approvalPolicy {
+	return &approvalPolicy{
+		Id:                data.Id,
+		WalletId:          data.WalletId,
+		Chain:             data.Chain,
+		Threshold:         data.Threshold,
+		RequiredApprovals: data.RequiredApprovals,
+		ApproverIds:       data.ApproverIds,
+		CreatedAt:         data.CreatedAt,
+		UpdatedAt:         data.UpdatedAt,
+	}
+}
+
+func (a approvalPolicy) ToModel() *model.ApprovalPolicy {
+	return &model.ApprovalPolicy{
+		Id:                a.Id,
+		WalletId:          a.WalletId,
+		Chain:             a.Chain,
+		Threshold:         a.Threshold,
+		RequiredApprovals: a.RequiredApprovals,
+		ApproverIds:       a.ApproverIds,
+		CreatedAt:         a.CreatedAt,
+		UpdatedAt:         a.UpdatedAt,
+	}
+}
+
+func (a approvalPolicy) TableName() string {
+	return "approval_policies"
+}
+
+func (a *approvalPolicy) BeforeCreate(db *gorm.DB) error {
+	if a.Id == nil {
+		a.Id = helper.Pointer(ksuid.New().String())
+	}
+
+	return nil
+}
+
+type ApprovalPolicyRepo struct {
+	db *gorm.DB
+}
+
+func NewApprovalPolicyRepository(db *gorm.DB) repository.ApprovalPolicy {
+	return &ApprovalPolicyRepo{
+		db: db,
+	}
+}
+
+func (a *ApprovalPolicyRepo) Upsert(ctx context.Context, data *model.ApprovalPolicy) (*model.ApprovalPolicy, error) {
+	gormModel := approvalPolicy{}.FromModel(*data)
+	now := time.Now()
+	gormModel.Id = nil
+	gormModel.CreatedAt = &now
+	gormModel.UpdatedAt = &now
+
+	err := a.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "wallet_id"}, {Name: "chain"}},
+		DoUpdates: clause.AssignmentColumns([]string{"threshold", "required_approvals", "approver_ids", "updated_at"}),
+	}).Create(gormModel).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return a.Get(ctx, *data.WalletId, *data.Chain)
+}
+
+func (a *ApprovalPolicyRepo) Get(ctx context.Context, walletId string, chain string) (*model.ApprovalPolicy, error) {
+	var gormModel approvalPolicy
+	if err := a.db.WithContext(ctx).Where("wallet_id = ? AND chain = ?", walletId, chain).First(&gormModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.NewNotFoundError()
+		}
+		return nil, err
+	}
+
+	return gormModel.ToModel(), nil
+}
+
+func (a *ApprovalPolicyRepo) List(ctx context.Context, walletId *string) ([]model.ApprovalPolicy, error) {
+	q := a.db.WithContext(ctx)
+	if walletId != nil {
+		q = q.Where("wallet_id = ?", walletId)
+	}
+
+	policies := []approvalPolicy{}
+	if err := q.Order("wallet_id, chain").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]model.ApprovalPolicy, 0, len(policies))
+	for _, policy := range policies {
+		res = append(res, *policy.ToModel())
+	}
+
+	return res, nil
+}
+
+type withdrawalRequest struct {
+	Id                *string
+	WalletId          *string
+	UserId            *string
+	Chain             *string
+	ReceiverAddress   *string
+	Amount            *int64
+	Status            *string
+	RequiredApprovals *int
+	ApproverIds       pq.StringArray `gorm:"type:text[]"`
+	TransactionId     *string
+	FailureReason     *string
+	ExpiresAt         *time.Time
+	CreatedAt         *time.Time
+	UpdatedAt         *time.Time
+}
+
+func (w withdrawalRequest) FromModel(data model.WithdrawalRequest) *withdrawalRequest {
+	return &withdrawalRequest{
+		Id:                data.Id,
+		WalletId:          data.WalletId,
+		UserId:            data.UserId,
+		Chain:             data.Chain,
+		ReceiverAddress:   data.ReceiverAddress,
+		Amount:            data.Amount,
+		Status:            data.Status,
+		RequiredApprovals: data.RequiredApprovals,
+		ApproverIds:       data.ApproverIds,
+		TransactionId:     data.TransactionId,
+		FailureReason:     data.FailureReason,
+		ExpiresAt:         data.ExpiresAt,
+		CreatedAt:         data.CreatedAt,
+		UpdatedAt:         data.UpdatedAt,
+	}
+}
+
+func (w withdrawalRequest) ToModel() *model.WithdrawalRequest {
+	return &model.WithdrawalRequest{
+		Id:                w.Id,
+		WalletId:          w.WalletId,
+		UserId:            w.UserId,
+		Chain:             w.Chain,
+		ReceiverAddress:   w.ReceiverAddress,
+		Amount:            w.Amount,
+		Status:            w.Status,
+		RequiredApprovals: w.RequiredApprovals,
+		ApproverIds:       w.ApproverIds,
+		TransactionId:     w.TransactionId,
+		FailureReason:     w.FailureReason,
+		ExpiresAt:         w.ExpiresAt,
+		CreatedAt:         w.CreatedAt,
+		UpdatedAt:         w.UpdatedAt,
+	}
+}
+
+func (w withdrawalRequest) TableName() string {
+	return "withdrawal_requests"
+}
+
+func (w *withdrawalRequest) BeforeCreate(db *gorm.DB) error {
+	if w.Id == nil {
+		w.Id = helper.Pointer(ksuid.New().String())
+	}
+
+	return nil
+}
+
+type withdrawalApproval struct {
+	Id                  *string
+	WithdrawalRequestId *string
+	ApproverId          *string
+	Decision            *string
+	Reason              *string
+	CreatedAt           *time.Time
+}
+
+func (w withdrawalApproval) FromModel(data model.WithdrawalApproval) *withdrawalApproval {
+	return &withdrawalApproval{
+		Id:                  data.Id,
+		WithdrawalRequestId: data.WithdrawalRequestId,
+		ApproverId:          data.ApproverId,
+		Decision:            data.Decision,
+		Reason:              data.Reason,
+		CreatedAt:           data.CreatedAt,
+	}
+}
+
+func (w withdrawalApproval) ToModel() *model.WithdrawalApproval {
+	return &model.WithdrawalApproval{
+		Id:                  w.Id,
+		WithdrawalRequestId: w.WithdrawalRequestId,
+		ApproverId:          w.ApproverId,
+		Decision:            w.Decision,
+		Reason:              w.Reason,
+		CreatedAt:           w.CreatedAt,
+	}
+}
+
+func (w withdrawalApproval) TableName() string {
+	return "withdrawal_approvals"
+}
+
+func (w *withdrawalApproval) BeforeCreate(db *gorm.DB) error {
+	if w.Id == nil {
+		w.Id = helper.Pointer(ksuid.New().String())
+	}
+
+	return nil
+}
+
+type WithdrawalRequestRepo struct {
+	db *gorm.DB
+}
+
+func NewWithdrawalRequestRepository(db *gorm.DB) repository.WithdrawalRequest {
+	return &WithdrawalRequestRepo{
+		db: db,
+	}
+}
+
+func (w *WithdrawalRequestRepo) Add(ctx context.Context, data *model.WithdrawalRequest) (*model.WithdrawalRequest, error) {
+	gormModel := withdrawalRequest{}.FromModel(*data)
+
+	if err := w.db.WithContext(ctx).Create(&gormModel).Error; err != nil {
+		return nil, err
+	}
+
+	return gormModel.ToModel(), nil
+}
+
+func (w *WithdrawalRequestRepo) Get(ctx context.Context, id string) (*model.WithdrawalRequest, error) {
+	return w.get(w.db.WithContext(ctx), id)
+}
+
+func (w *WithdrawalRequestRepo) get(q *gorm.DB, id string) (*model.WithdrawalRequest, error) {
+	var gormModel withdrawalRequest
+	if err := q.Where("id = ?", id).First(&gormModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, model.NewNotFoundError()
+		}
+		return nil, err
+	}
+
+	approvals := []withdrawalApproval{}
+	if err := q.Session(&gorm.Session{NewDB: true}).Where("withdrawal_request_id = ?", id).Order("created_at").Find(&approvals).Error; err != nil {
+		return nil, err
+	}
+
+	res := gormModel.ToModel()
+	for _, approval := range approvals {
+		res.Approvals = append(res.Approvals, *approval.ToModel())
+	}
+
+	return res, nil
+}
+
+func (w *WithdrawalRequestRepo) ListPending(ctx context.Context, approverId string, now time.Time) ([]model.WithdrawalRequest, error) {
+	requests := []withdrawalRequest{}
+	err := w.db.WithContext(ctx).
+		Where("status = ? AND expires_at > ? AND ? = ANY(approver_ids) AND user_id <> ?", model.WithdrawalRequested, now, approverId, approverId).
+		Where("NOT EXISTS (SELECT 1 FROM withdrawal_approvals WHERE withdrawal_request_id = withdrawal_requests.id AND approver_id = ?)", approverId).
+		Order("created_at").
+		Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.WithdrawalRequest, 0, len(requests))
+	for _, request := range requests {
+		res = append(res, *request.ToModel())
+	}
+
+	return res, nil
+}
+
+func (w *WithdrawalRequestRepo) ListApproved(ctx context.Context) ([]model.WithdrawalRequest, error) {
+	requests := []withdrawalRequest{}
+	err := w.db.WithContext(ctx).
+		Where("status = ? AND transaction_id IS NULL", model.WithdrawalApproved).
+		Order("created_at").
+		Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.WithdrawalRequest, 0, len(requests))
+	for _, request := range requests {
+		res = append(res, *request.ToModel())
+	}
+
+	return res, nil
+}
+
+func (w *WithdrawalRequestRepo) ListStaleSigning(ctx context.Context, before time.Time) ([]model.WithdrawalRequest, error) {
+	requests := []withdrawalRequest{}
+	err := w.db.WithContext(ctx).
+		Where("status = ? AND updated_at < ?", model.WithdrawalSigning, before).
+		Order("created_at").
+		Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]model.WithdrawalRequest, 0, len(requests))
+	for _, request := range requests {
+		res = append(res, *request.ToModel())
+	}
+
+	return res, nil
+}
+
+func (w *WithdrawalRequestRepo) Decide(ctx context.Context, id string, decide repository.WithdrawalDecider) (*model.WithdrawalRequest, error) {
+	var res *model.WithdrawalRequest
+
+	err := w.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		request, err := w.get(tx.Clauses(clause.Locking{Strength: "UPDATE"}), id)
+		if err != nil {
+			return err
+		}
+
+		approval, status, err := decide(request)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if approval != nil {
+			approval.WithdrawalRequestId = request.Id
+			approval.CreatedAt = &now
+			gormApproval := withdrawalApproval{}.FromModel(*approval)
+			if err := tx.Create(gormApproval).Error; err != nil {
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+					return model.NewDuplicateError()
+				}
+				return err
+			}
+			request.Approvals = append(request.Approvals, *gormApproval.ToModel())
+		}
+
+		if status != "" && status != *request.Status {
+			err := tx.Model(&withdrawalRequest{}).Where("id = ?", id).Updates(map[string]interface{}{
+				"status":     status,
+				"updated_at": now,
+			}).Error
+			if err != nil {
+				return err
+			}
+			request.Status = &status
+			request.UpdatedAt = &now
+		}
+
+		res = request
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (w *WithdrawalRequestRepo) Transition(ctx context.Context, id string, from string, to string, update *model.WithdrawalRequest) (bool, error) {
+	updates := map[string]interface{}{
+		"status":     to,
+		"updated_at": time.Now(),
+	}
+	if update != nil {
+		if update.TransactionId != nil {
+			updates["transaction_id"] = update.TransactionId
+		}
+		if update.FailureReason != nil {
+			updates["failure_reason"] = update.FailureReason
+		}
+	}
+
+	// the status condition makes a single caller win the transition
+	res := w.db.WithContext(ctx).Model(&withdrawalRequest{}).Where("id = ? AND status = ?", id, from).Updates(updates)
+	if res.Error != nil {
+		return false, res.Error
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (w *WithdrawalRequestRepo) ExpireStale(ctx context.Context, now time.Time) (int64, error) {
+	res := w.db.WithContext(ctx).Model(&withdrawalRequest{}).
+		Where("status = ? AND expires_at <= ?", model.WithdrawalRequested, now).
+		Updates(map[string]interface{}{
+			"status":     model.WithdrawalExpired,
+			"updated_at": now,
+		})
+
+	return res.RowsAffected, res.Error
+}
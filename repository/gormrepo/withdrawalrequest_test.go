@@ -0,0 +1,242 @@
+//go:build integration
+// +build integration
+
+package gormrepo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/helper/test"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository/gormrepo"
+	"github.com/aalexanderkevin/crypto-wallet/storage"
+
+	"github.com/stretchr/testify/require"
+)
+
+func fakeWithdrawalRequest(userId *string, approverIds []string, expiresAt time.Time) *model.WithdrawalRequest {
+	return &model.WithdrawalRequest{
+		WalletId:          helper.Pointer("wallet-id"),
+		UserId:            userId,
+		Chain:             helper.Pointer("eth"),
+		ReceiverAddress:   helper.Pointer("0x00000000219ab540356cbb839cbe05303d7705fa"),
+		Amount:            helper.Pointer(int64(1000)),
+		Status:            helper.Pointer(model.WithdrawalRequested),
+		RequiredApprovals: helper.Pointer(2),
+		ApproverIds:       approverIds,
+		ExpiresAt:         &expiresAt,
+	}
+}
+
+func approve(approverId string) func(request *model.WithdrawalRequest) (*model.WithdrawalApproval, string, error) {
+	return func(request *model.WithdrawalRequest) (*model.WithdrawalApproval, string, error) {
+		status := ""
+		if request.ApprovalCount()+1 >= *request.RequiredApprovals {
+			status = model.WithdrawalApproved
+		}
+		return &model.WithdrawalApproval{
+			ApproverId: &approverId,
+			Decision:   helper.Pointer(model.DecisionApproved),
+		}, status, nil
+	}
+}
+
+func TestWithdrawalRequestRepository_Decide(t *testing.T) {
+	t.Run("ShouldApprove_WhenTheQuorumIsMet", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		requester := test.FakeUserCreate(t, db, nil)
+		first := test.FakeUserCreate(t, db, nil)
+		second := test.FakeUserCreate(t, db, nil)
+		withdrawalRequestRepo := gormrepo.NewWithdrawalRequestRepository(db)
+		request, err := withdrawalRequestRepo.Add(context.TODO(), fakeWithdrawalRequest(requester.Id, []string{*first.Id, *second.Id}, time.Now().Add(time.Hour)))
+		require.NoError(t, err)
+
+		//-- code under test
+		afterFirst, err := withdrawalRequestRepo.Decide(context.TODO(), *request.Id, approve(*first.Id))
+		require.NoError(t, err)
+		afterSecond, err := withdrawalRequestRepo.Decide(context.TODO(), *request.Id, approve(*second.Id))
+		require.NoError(t, err)
+
+		//-- assert
+		require.Equal(t, model.WithdrawalRequested, *afterFirst.Status)
+		require.Equal(t, model.WithdrawalApproved, *afterSecond.Status)
+		require.Equal(t, 2, afterSecond.ApprovalCount())
+
+		stored, err := withdrawalRequestRepo.Get(context.TODO(), *request.Id)
+		require.NoError(t, err)
+		require.Equal(t, model.WithdrawalApproved, *stored.Status)
+		require.Len(t, stored.Approvals, 2)
+	})
+
+	t.Run("ShouldReturnDuplicateError_WhenTheApproverAlreadyDecided", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		requester := test.FakeUserCreate(t, db, nil)
+		approver := test.FakeUserCreate(t, db, nil)
+		withdrawalRequestRepo := gormrepo.NewWithdrawalRequestRepository(db)
+		request, err := withdrawalRequestRepo.Add(context.TODO(), fakeWithdrawalRequest(requester.Id, []string{*approver.Id, "other"}, time.Now().Add(time.Hour)))
+		require.NoError(t, err)
+		_, err = withdrawalRequestRepo.Decide(context.TODO(), *request.Id, approve(*approver.Id))
+		require.NoError(t, err)
+
+		//-- code under test
+		_, err = withdrawalRequestRepo.Decide(context.TODO(), *request.Id, approve(*approver.Id))
+
+		//-- assert
+		require.EqualError(t, err, model.NewDuplicateError().Error())
+		stored, err := withdrawalRequestRepo.Get(context.TODO(), *request.Id)
+		require.NoError(t, err)
+		require.Equal(t, model.WithdrawalRequested, *stored.Status)
+		require.Len(t, stored.Approvals, 1)
+	})
+}
+
+func TestWithdrawalRequestRepository_Transition(t *testing.T) {
+	t.Run("ShouldLetASingleCallerWin_WhenTheRequestIsMovedTwice", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		requester := test.FakeUserCreate(t, db, nil)
+		withdrawalRequestRepo := gormrepo.NewWithdrawalRequestRepository(db)
+		data := fakeWithdrawalRequest(requester.Id, []string{"approver"}, time.Now().Add(time.Hour))
+		data.Status = helper.Pointer(model.WithdrawalApproved)
+		request, err := withdrawalRequestRepo.Add(context.TODO(), data)
+		require.NoError(t, err)
+
+		//-- code under test
+		first, err := withdrawalRequestRepo.Transition(context.TODO(), *request.Id, model.WithdrawalApproved, model.WithdrawalSigning, nil)
+		require.NoError(t, err)
+		second, err := withdrawalRequestRepo.Transition(context.TODO(), *request.Id, model.WithdrawalApproved, model.WithdrawalSigning, nil)
+		require.NoError(t, err)
+
+		//-- assert
+		require.True(t, first)
+		require.False(t, second)
+	})
+}
+
+func TestWithdrawalRequestRepository_ExpireStale(t *testing.T) {
+	t.Run("ShouldExpireOnlyTheRequestedRequestsPastTheirExpiry", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		requester := test.FakeUserCreate(t, db, nil)
+		withdrawalRequestRepo := gormrepo.NewWithdrawalRequestRepository(db)
+		stale, err := withdrawalRequestRepo.Add(context.TODO(), fakeWithdrawalRequest(requester.Id, []string{"approver"}, time.Now().Add(-time.Minute)))
+		require.NoError(t, err)
+		fresh, err := withdrawalRequestRepo.Add(context.TODO(), fakeWithdrawalRequest(requester.Id, []string{"approver"}, time.Now().Add(time.Hour)))
+		require.NoError(t, err)
+
+		//-- code under test
+		expired, err := withdrawalRequestRepo.ExpireStale(context.TODO(), time.Now())
+
+		//-- assert
+		require.NoError(t, err)
+		require.EqualValues(t, 1, expired)
+		stored, err := withdrawalRequestRepo.Get(context.TODO(), *stale.Id)
+		require.NoError(t, err)
+		require.Equal(t, model.WithdrawalExpired, *stored.Status)
+		stored, err = withdrawalRequestRepo.Get(context.TODO(), *fresh.Id)
+		require.NoError(t, err)
+		require.Equal(t, model.WithdrawalRequested, *stored.Status)
+	})
+}
+
+func TestWithdrawalRequestRepository_ListApproved(t *testing.T) {
+	t.Run("ShouldListOnlyTheApprovedRequestsNotSentYet", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		requester := test.FakeUserCreate(t, db, nil)
+		withdrawalRequestRepo := gormrepo.NewWithdrawalRequestRepository(db)
+		approved, err := withdrawalRequestRepo.Add(context.TODO(), fakeWithdrawalRequest(requester.Id, []string{"approver"}, time.Now().Add(time.Hour)))
+		require.NoError(t, err)
+		_, err = withdrawalRequestRepo.Transition(context.TODO(), *approved.Id, model.WithdrawalRequested, model.WithdrawalApproved, nil)
+		require.NoError(t, err)
+		sent, err := withdrawalRequestRepo.Add(context.TODO(), fakeWithdrawalRequest(requester.Id, []string{"approver"}, time.Now().Add(time.Hour)))
+		require.NoError(t, err)
+		_, err = withdrawalRequestRepo.Transition(context.TODO(), *sent.Id, model.WithdrawalRequested, model.WithdrawalBroadcast, &model.WithdrawalRequest{TransactionId: helper.Pointer("tx-id")})
+		require.NoError(t, err)
+		_, err = withdrawalRequestRepo.Add(context.TODO(), fakeWithdrawalRequest(requester.Id, []string{"approver"}, time.Now().Add(time.Hour)))
+		require.NoError(t, err)
+
+		//-- code under test
+		requests, err := withdrawalRequestRepo.ListApproved(context.TODO())
+
+		//-- assert
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		require.Equal(t, *approved.Id, *requests[0].Id)
+	})
+}
+
+func TestWithdrawalRequestRepository_ListPending(t *testing.T) {
+	t.Run("ShouldSkipTheRequestsTheApproverDecidedOn", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		requester := test.FakeUserCreate(t, db, nil)
+		approver := test.FakeUserCreate(t, db, nil)
+		withdrawalRequestRepo := gormrepo.NewWithdrawalRequestRepository(db)
+		decided, err := withdrawalRequestRepo.Add(context.TODO(), fakeWithdrawalRequest(requester.Id, []string{*approver.Id, "other"}, time.Now().Add(time.Hour)))
+		require.NoError(t, err)
+		pending, err := withdrawalRequestRepo.Add(context.TODO(), fakeWithdrawalRequest(requester.Id, []string{*approver.Id, "other"}, time.Now().Add(time.Hour)))
+		require.NoError(t, err)
+		_, err = withdrawalRequestRepo.Add(context.TODO(), fakeWithdrawalRequest(requester.Id, []string{"other"}, time.Now().Add(time.Hour)))
+		require.NoError(t, err)
+		_, err = withdrawalRequestRepo.Decide(context.TODO(), *decided.Id, approve(*approver.Id))
+		require.NoError(t, err)
+
+		//-- code under test
+		requests, err := withdrawalRequestRepo.ListPending(context.TODO(), *approver.Id, time.Now())
+
+		//-- assert
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		require.Equal(t, *pending.Id, *requests[0].Id)
+	})
+}
+
+func TestWithdrawalRequestRepository_ListStaleSigning(t *testing.T) {
+	t.Run("ShouldListOnlyTheRequestsInSigningSinceBeforeTheTime", func(t *testing.T) {
+		//-- init
+		db := storage.PostgresDbConn(&dbName)
+		defer cleanDB(t, db)
+
+		requester := test.FakeUserCreate(t, db, nil)
+		withdrawalRequestRepo := gormrepo.NewWithdrawalRequestRepository(db)
+		stale, err := withdrawalRequestRepo.Add(context.TODO(), fakeWithdrawalRequest(requester.Id, []string{"approver"}, time.Now().Add(time.Hour)))
+		require.NoError(t, err)
+		_, err = withdrawalRequestRepo.Transition(context.TODO(), *stale.Id, model.WithdrawalRequested, model.WithdrawalSigning, nil)
+		require.NoError(t, err)
+		before := time.Now()
+		recent, err := withdrawalRequestRepo.Add(context.TODO(), fakeWithdrawalRequest(requester.Id, []string{"approver"}, time.Now().Add(time.Hour)))
+		require.NoError(t, err)
+		_, err = withdrawalRequestRepo.Transition(context.TODO(), *recent.Id, model.WithdrawalRequested, model.WithdrawalSigning, nil)
+		require.NoError(t, err)
+		approved, err := withdrawalRequestRepo.Add(context.TODO(), fakeWithdrawalRequest(requester.Id, []string{"approver"}, time.Now().Add(time.Hour)))
+		require.NoError(t, err)
+		_, err = withdrawalRequestRepo.Transition(context.TODO(), *approved.Id, model.WithdrawalRequested, model.WithdrawalApproved, nil)
+		require.NoError(t, err)
+
+		//-- code under test
+		requests, err := withdrawalRequestRepo.ListStaleSigning(context.TODO(), before)
+
+		//-- assert
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		require.Equal(t, *stale.Id, *requests[0].Id)
+	})
+}
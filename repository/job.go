@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/model"
+)
+
+type Job interface {
+	Add(ctx context.Context, job *model.Job) (*model.Job, error)
+	Get(ctx context.Context, filter *JobGetFilter) (*model.Job, error)
+	Update(ctx context.Context, id string, job *model.Job) (*model.Job, error)
+	// ClaimDue locks up to limit queued jobs that are due and pushes their run time by lease, a job whose worker
+	// dies is picked up again once the lease expires
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.Job, error)
+}
+
+type JobGetFilter struct {
+	Id     *string
+	Type   *string
+	Status *string
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aalexanderkevin/crypto-wallet/model"
+)
+
+type Outbox interface {
+	// PublishPending hands the oldest unpublished events to publish in order, it stops at the first failure
+	// so the events of an aggregate are never published out of order
+	PublishPending(ctx context.Context, limit int, publish func(ctx context.Context, event model.OutboxEvent) error) (int, error)
+}
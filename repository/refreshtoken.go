@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aalexanderkevin/crypto-wallet/model"
+)
+
+type RefreshToken interface {
+	Add(ctx context.Context, refreshToken *model.RefreshToken) (*model.RefreshToken, error)
+	Get(ctx context.Context, id string) (*model.RefreshToken, error)
+	// MarkUsed uses the token up, it returns not found when the token was already used or revoked
+	MarkUsed(ctx context.Context, id string) error
+	RevokeFamily(ctx context.Context, familyId string) error
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aalexanderkevin/crypto-wallet/model"
+)
+
+type SpendingLimit interface {
+	// Upsert sets the limit of the user on the chain, or the global one when the user id is nil
+	Upsert(ctx context.Context, limit *model.SpendingLimit) (*model.SpendingLimit, error)
+	List(ctx context.Context, filter *SpendingLimitFilter) ([]model.SpendingLimit, error)
+	Delete(ctx context.Context, id string) error
+	// Reserve records the transfer when check accepts it against the limit of the user with the usage of the user, and
+	// against the global limit with the usage of all the users. The reservations on a chain are serialized so
+	// concurrent transfers see each other
+	Reserve(ctx context.Context, record *model.SpendingRecord, check func(limit model.SpendingLimit, usage model.SpendingUsage) error) (*model.SpendingRecord, error)
+	// Release drops the reservation of a transfer that was not sent
+	Release(ctx context.Context, id string) error
+	SetTransactionId(ctx context.Context, id string, transactionId string) error
+	// GetByWithdrawalRequest returns the reservation of the withdrawal request, it is a not found error when the
+	// request was never reserved or its reservation was released
+	GetByWithdrawalRequest(ctx context.Context, withdrawalRequestId string) (*model.SpendingRecord, error)
+}
+
+type SpendingLimitFilter struct {
+	UserId *string
+	// Global only lists the limits without a user
+	Global bool
+	Chain  *string
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/aalexanderkevin/crypto-wallet/model"
 )
@@ -11,11 +12,15 @@ type Transaction interface {
 	Update(ctx context.Context, id string, trx *model.Transaction) (*model.Transaction, error)
 	Upsert(ctx context.Context, transaction *model.Transaction) (*model.Transaction, error)
 	Get(ctx context.Context, filter *TransactionGetFilter) (*model.Transaction, error)
+	List(ctx context.Context, filter *TransactionGetFilter) ([]model.Transaction, error)
 }
 
 type TransactionGetFilter struct {
 	Id              *string
 	SenderAddress   *string
 	ReceiverAddress *string
-	Status          *string
+	// Address matches either the sender or the receiver address
+	Address       *string
+	Status        *string
+	CreatedBefore *time.Time
 }
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/model"
+)
+
+type User interface {
+	Add(ctx context.Context, user *model.User) (*model.User, error)
+	Get(ctx context.Context, filter *UserGetFilter) (*model.User, error)
+	// UpdatePassword stores a new password hash and drops the legacy salt
+	UpdatePassword(ctx context.Context, id string, password string) error
+	// SetTotpSecret stores the encrypted secret of a new enrollment, it fails with a not found error once TOTP is enabled
+	SetTotpSecret(ctx context.Context, id string, secret []byte) error
+	// EnableTotp enables the enrolled secret, step is the time step of the code that verified it
+	EnableTotp(ctx context.Context, id string, step int64) error
+	// UseTotpStep records the step of an accepted code, it returns false when a code of the step or a later one was
+	// already accepted
+	UseTotpStep(ctx context.Context, id string, step int64) (bool, error)
+	// SetAllowlist stores when the withdrawal allowlist was switched on and when switching it off takes effect
+	SetAllowlist(ctx context.Context, id string, enabledAt *time.Time, disabledAt *time.Time) error
+}
+
+type UserGetFilter struct {
+	Id       *string
+	Username *string
+	Email    *string
+}
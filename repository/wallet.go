@@ -10,9 +10,15 @@ type Wallet interface {
 	Add(ctx context.Context, wallet *model.Wallet, encryptionKey *string) (*model.Wallet, error)
 	Get(ctx context.Context, filter *WalletGetFilter, encryptionKey *string) (*model.Wallet, error)
 	Update(ctx context.Context, id string, wallet *model.Wallet) (*model.Wallet, error)
+	// ListMissingAddresses returns the wallets after the id without a litecoin or a dogecoin address, ordered by id,
+	// their seed phrases stay encrypted
+	ListMissingAddresses(ctx context.Context, afterId *string, limit int) ([]model.Wallet, error)
 }
 
 type WalletGetFilter struct {
-	Id    *string
-	Email *string
+	Id     *string
+	UserId *string
+	Email  *string
+	// Address matches any of the wallet chain addresses
+	Address *string
 }
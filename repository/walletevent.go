@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/model"
+)
+
+type WalletEvent interface {
+	// Add stores the event and notifies the listeners of model.WalletEventsChannel. The events are added one at a time
+	// so their ids are committed in increasing order and a reader going by id does not skip one
+	Add(ctx context.Context, event *model.WalletEvent) (*model.WalletEvent, error)
+	// ListAfter returns up to limit events after the id ordered by id, only the events of the addresses when they are
+	// set. The id of an event is its Sequence
+	ListAfter(ctx context.Context, afterId uint64, addresses []string, limit int) ([]model.WalletEvent, error)
+	// Bounds returns the ids of the oldest and the newest stored events, zeros when there is none
+	Bounds(ctx context.Context) (first uint64, last uint64, err error)
+	// DeleteBefore removes the events created before the time and returns how many were removed
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/model"
+)
+
+type WebhookEndpoint interface {
+	Add(ctx context.Context, endpoint *model.WebhookEndpoint) (*model.WebhookEndpoint, error)
+	Get(ctx context.Context, filter *WebhookEndpointGetFilter) (*model.WebhookEndpoint, error)
+	List(ctx context.Context, filter *WebhookEndpointGetFilter) ([]model.WebhookEndpoint, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type WebhookEndpointGetFilter struct {
+	Id     *string
+	UserId *string
+	Active *bool
+}
+
+type WebhookDelivery interface {
+	Add(ctx context.Context, delivery *model.WebhookDelivery) (*model.WebhookDelivery, error)
+	Get(ctx context.Context, filter *WebhookDeliveryGetFilter) (*model.WebhookDelivery, error)
+	List(ctx context.Context, filter *WebhookDeliveryGetFilter) ([]model.WebhookDelivery, error)
+	Update(ctx context.Context, id string, delivery *model.WebhookDelivery) (*model.WebhookDelivery, error)
+	// ClaimDue locks up to limit pending deliveries that are due and pushes their next attempt by lease,
+	// so concurrent workers do not deliver the same row twice
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookDelivery, error)
+}
+
+type WebhookDeliveryGetFilter struct {
+	Id         *string
+	EndpointId *string
+	UserId     *string
+	Status     *string
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/aalexanderkevin/crypto-wallet/model"
+)
+
+type WithdrawalAddress interface {
+	Add(ctx context.Context, address *model.WithdrawalAddress) (*model.WithdrawalAddress, error)
+	Get(ctx context.Context, filter *WithdrawalAddressFilter) (*model.WithdrawalAddress, error)
+	List(ctx context.Context, filter *WithdrawalAddressFilter) ([]model.WithdrawalAddress, error)
+	// Delete removes the address of the user, it returns not found when the user has no address with the id
+	Delete(ctx context.Context, userId string, id string) error
+}
+
+type WithdrawalAddressFilter struct {
+	Id      *string
+	UserId  *string
+	Chain   *string
+	Address *string
+}
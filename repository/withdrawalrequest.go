@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/model"
+)
+
+type ApprovalPolicy interface {
+	// Upsert sets the policy of the wallet on the chain
+	Upsert(ctx context.Context, policy *model.ApprovalPolicy) (*model.ApprovalPolicy, error)
+	Get(ctx context.Context, walletId string, chain string) (*model.ApprovalPolicy, error)
+	List(ctx context.Context, walletId *string) ([]model.ApprovalPolicy, error)
+}
+
+// WithdrawalDecider gets the locked request with its approvals and returns the approval to record, when there is one,
+// and the status the request moves to, an empty status keeps it
+type WithdrawalDecider func(request *model.WithdrawalRequest) (approval *model.WithdrawalApproval, status string, err error)
+
+type WithdrawalRequest interface {
+	Add(ctx context.Context, request *model.WithdrawalRequest) (*model.WithdrawalRequest, error)
+	// Get returns the request with its approvals
+	Get(ctx context.Context, id string) (*model.WithdrawalRequest, error)
+	// ListPending returns the requested and unexpired requests the approver did not decide on yet
+	ListPending(ctx context.Context, approverId string, now time.Time) ([]model.WithdrawalRequest, error)
+	// Decide locks the request and applies the decision of decide to it, the decisions on a request are serialized
+	// so concurrent approvals see each other. A second decision of the same approver is a duplicate error
+	Decide(ctx context.Context, id string, decide WithdrawalDecider) (*model.WithdrawalRequest, error)
+	// Transition moves the request from the status to the next one with the fields of update, it returns false when
+	// the request is not in the status anymore
+	Transition(ctx context.Context, id string, from string, to string, update *model.WithdrawalRequest) (bool, error)
+	// ListApproved returns the approved requests that were not sent yet
+	ListApproved(ctx context.Context) ([]model.WithdrawalRequest, error)
+	// ListStaleSigning returns the requests left in signing since before the time
+	ListStaleSigning(ctx context.Context, before time.Time) ([]model.WithdrawalRequest, error)
+	// ExpireStale expires the requested requests whose expiry passed and returns how many were expired
+	ExpireStale(ctx context.Context, now time.Time) (int64, error)
+}
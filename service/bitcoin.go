@@ -9,12 +9,16 @@ import (
 	"github.com/blockcypher/gobcy/v2"
 )
 
+// Bitcoin is a UTXO coin, bitcoin itself or one of the coins sharing its transaction format like litecoin and dogecoin
 type Bitcoin interface {
+	// Coin is the chain id of the coin, for example btc, ltc or doge
+	Coin() string
 	CheckAddress(address *string) bool
 	GetWallet(ctx context.Context, seedPhrase *string) (*model.BtcHdWallet, error)
 	GetBalance(ctx context.Context, address string) (*big.Int, error)
 	SendTx(ctx context.Context, wallet *model.BtcHdWallet, txOpts *model.TxOpts) (*model.Transaction, error)
 	GetTx(ctx context.Context, txhash string) (*gobcy.TX, error)
-	CreateWebhookConfirmedTx(ctx context.Context, address *string) (*gobcy.Hook, error)
+	GetCurrentBlock(ctx context.Context) (*int64, error)
+	CreateWebhookConfirmedTx(ctx context.Context, address *string, confirmations int) (*gobcy.Hook, error)
 	DeleteWebhook(ctx context.Context, id *string) error
 }
@@ -12,96 +12,128 @@ import (
 	"net/url"
 	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/aalexanderkevin/crypto-wallet/config"
 	"github.com/aalexanderkevin/crypto-wallet/helper"
 	"github.com/aalexanderkevin/crypto-wallet/model"
 	"github.com/aalexanderkevin/crypto-wallet/service"
+	"github.com/aalexanderkevin/crypto-wallet/service/ratelimit"
 
 	"github.com/blockcypher/gobcy/v2"
-	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcutil"
 	"github.com/btcsuite/btcutil/hdkeychain"
 	"github.com/tyler-smith/go-bip39"
 )
 
+// BitcoinImpl serves a UTXO coin through BlockCypher, bitcoin and the coins sharing its transaction format
 type BitcoinImpl struct {
-	client *gobcy.API
-	config config.Bitcoin
+	client     *gobcy.API
+	httpClient *http.Client
+	limiter    *ratelimit.TokenBucket
+	rateLimit  config.RateLimit
+	config     config.Bitcoin
+	coin       Coin
 }
 
 func NewBitcoinImpl(config config.Config) service.Bitcoin {
+	return NewUtxoImpl(BitcoinCoin(config.Bitcoin.Chain), config.Bitcoin, config.RateLimit)
+}
+
+func NewLitecoinImpl(config config.Config) service.Bitcoin {
+	return NewUtxoImpl(LitecoinCoin(config.Litecoin.Chain), config.Bitcoin, config.RateLimit)
+}
+
+func NewDogecoinImpl(config config.Config) service.Bitcoin {
+	return NewUtxoImpl(DogecoinCoin(config.Dogecoin.Chain), config.Bitcoin, config.RateLimit)
+}
+
+// NewUtxoImpl serves the coin with the BlockCypher token and webhook settings of config, every coin shares the
+// rate limit of the token
+func NewUtxoImpl(coin Coin, config config.Bitcoin, rateLimit config.RateLimit) service.Bitcoin {
 	//explicitly
 	client := &gobcy.API{
-		Token: config.Bitcoin.Token,
-		Coin:  "btc",                //options: "btc","bcy","ltc","doge","eth"
-		Chain: config.Bitcoin.Chain, //depending on coin: "main","test3","test"
+		Token: config.Token,
+		Coin:  coin.BlockCypherCoin,  //options: "btc","bcy","ltc","doge","eth"
+		Chain: coin.BlockCypherChain, //depending on coin: "main","test3","test"
 	}
 
 	return &BitcoinImpl{
 		client: client,
-		config: config.Bitcoin,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second},
+		limiter:   ratelimit.Provider("blockcypher", rateLimit.BlockCypherPerSecond, rateLimit.BlockCypherBurst),
+		rateLimit: rateLimit,
+		config:    config,
+		coin:      coin,
 	}
 }
 
-func (b *BitcoinImpl) CheckAddress(address *string) bool {
-	// Regular expression for a valid Bitcoin address
-	pattern := "^(tb1|[mn2])[a-km-zA-HJ-NP-Z0-9]{25,39}$"
-	chainConfig := &chaincfg.TestNet3Params
-	if b.client.Chain == "main" {
-		chainConfig = &chaincfg.MainNetParams
-		pattern = "^bc1[ac-hj-np-z02-9]{25,39}$"
-	}
+func (b *BitcoinImpl) Coin() string {
+	return b.coin.Chain
+}
 
-	matched, _ := regexp.MatchString(pattern, *address)
-	if !matched {
-		return false
+func (b *BitcoinImpl) CheckAddress(address *string) bool {
+	if b.coin.AddressPattern != "" {
+		matched, _ := regexp.MatchString(b.coin.AddressPattern, *address)
+		if !matched {
+			return false
+		}
 	}
 
 	// Decode the address and perform checksum verification
-	decoded, err := btcutil.DecodeAddress(*address, chainConfig)
+	decoded, err := btcutil.DecodeAddress(*address, b.coin.Params)
 	if err != nil {
 		return false
 	}
 
-	return decoded.IsForNet(chainConfig)
+	return decoded.IsForNet(b.coin.Params)
 }
 
 func (b *BitcoinImpl) GetWallet(ctx context.Context, seedPhrase *string) (*model.BtcHdWallet, error) {
-	logger := helper.GetLogger(ctx).WithField("method", "Service.Bitcoin.GetWallet")
+	logger := helper.GetLogger(ctx).WithField("method", "Service.Bitcoin.GetWallet").WithField("coin", b.coin.Chain)
 
 	// Generate a seed from the mnemonic
 	seed := bip39.NewSeed(*seedPhrase, "")
 
 	// Create a master extended key from the seed
-	masterKey, err := hdkeychain.NewMaster(seed, &chaincfg.TestNet3Params)
+	key, err := hdkeychain.NewMaster(seed, b.coin.Params)
 	if err != nil {
 		logger.WithError(err).Warn("Failed create masker key")
 		return nil, err
 	}
 
-	// Get the public key and Bitcoin address from the child key
-	publicKey, err := masterKey.ECPubKey()
+	for _, index := range b.coin.DerivationPath {
+		key, err = key.Derive(index)
+		if err != nil {
+			logger.WithError(err).Warn("Failed derive key")
+			return nil, err
+		}
+	}
+
+	// Get the public key and address from the child key
+	publicKey, err := key.ECPubKey()
 	if err != nil {
 		logger.WithError(err).Warn("Failed get public key")
 		return nil, err
 	}
 
 	// Get the private key in Wallet Import Format (WIF)
-	privateKey, err := masterKey.ECPrivKey()
+	privateKey, err := key.ECPrivKey()
 	if err != nil {
 		logger.WithError(err).Warn("Failed get private key")
 		return nil, err
 	}
 
 	// Convert the private key to WIF using the btcutil library
-	wif, err := btcutil.NewWIF(privateKey, &chaincfg.TestNet3Params, true)
+	wif, err := btcutil.NewWIF(privateKey, b.coin.Params, true)
 	if err != nil {
 		logger.WithError(err).Warn("Failed convert private key")
 		return nil, err
 	}
 
-	address, err := btcutil.NewAddressPubKey(publicKey.SerializeCompressed(), &chaincfg.TestNet3Params)
+	address, err := btcutil.NewAddressPubKey(publicKey.SerializeCompressed(), b.coin.Params)
 	if err != nil {
 		logger.WithError(err).Warn("Failed generate new address")
 		return nil, err
@@ -119,7 +151,8 @@ func (b *BitcoinImpl) GetWallet(ctx context.Context, seedPhrase *string) (*model
 func (b *BitcoinImpl) GetBalance(ctx context.Context, address string) (*big.Int, error) {
 	logger := helper.GetLogger(ctx).WithField("method", "Service.Bitcoin.GetWallet")
 
-	addr, err := b.client.GetAddr(address, nil)
+	var addr gobcy.Addr
+	err := b.get(ctx, "/addrs/"+address, nil, &addr)
 	if err != nil {
 		logger.WithError(err).Warn("Failed GetAddrBal")
 		return nil, err
@@ -133,7 +166,8 @@ func (b *BitcoinImpl) SendTx(ctx context.Context, wallet *model.BtcHdWallet, txO
 
 	tx := gobcy.TempNewTX(wallet.Address.EncodeAddress(), *txOpts.To, *txOpts.Amount)
 	tx.Preference = "low"
-	skels, err := b.client.NewTX(tx, false)
+	var skels gobcy.TXSkel
+	err := b.post(ctx, "/txs/new", map[string]string{"includeToSignTx": "false"}, &tx, &skels)
 	if err != nil {
 		logger.WithError(err).Warn("Failed create tx")
 		return nil, err
@@ -152,20 +186,29 @@ func (b *BitcoinImpl) SendTx(ctx context.Context, wallet *model.BtcHdWallet, txO
 	}
 
 	// Send TXSkeleton
-	skels, err = b.client.SendTX(skels)
+	var sent gobcy.TXSkel
+	err = b.post(ctx, "/txs/send", nil, &skels, &sent)
 	if err != nil {
 		logger.WithError(err).Warn("Failed send tx")
+		// only a client error is a refusal of the transaction, on any other failure it may have been pushed
+		if !strings.HasPrefix(err.Error(), "HTTP 4") || strings.HasPrefix(err.Error(), "HTTP 408") {
+			return nil, model.NewBroadcastUnknownError(err)
+		}
 		return nil, err
 	}
 
-	return model.Transaction{}.FromModel(skels.Trans), nil
+	return model.Transaction{}.FromModel(sent.Trans), nil
 }
 
 func (b *BitcoinImpl) GetTx(ctx context.Context, txhash string) (*gobcy.TX, error) {
 	logger := helper.GetLogger(ctx).WithField("method", "Service.Bitcoin.GetTx")
 
-	tx, err := b.client.GetTX(txhash, nil)
+	var tx gobcy.TX
+	err := b.get(ctx, "/txs/"+txhash, nil, &tx)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "HTTP 404") {
+			return nil, model.NewNotFoundError()
+		}
 		logger.WithError(err).Warn("Failed get tx")
 		return nil, err
 	}
@@ -173,21 +216,31 @@ func (b *BitcoinImpl) GetTx(ctx context.Context, txhash string) (*gobcy.TX, erro
 	return &tx, nil
 }
 
-func (b *BitcoinImpl) CreateWebhookConfirmedTx(ctx context.Context, address *string) (*gobcy.Hook, error) {
-	logger := helper.GetLogger(ctx).WithField("method", "Service.Bitcoin.CreateWebhookConfirmedTx")
-	hooks, err := b.client.ListHooks()
-	for _, h := range hooks {
-		err = b.client.DeleteHook(h.ID)
-		fmt.Println(err)
+func (b *BitcoinImpl) GetCurrentBlock(ctx context.Context) (*int64, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Service.Bitcoin.GetCurrentBlock")
+
+	var chain gobcy.Blockchain
+	err := b.get(ctx, "", nil, &chain)
+	if err != nil {
+		logger.WithError(err).Warn("Failed get chain")
+		return nil, err
 	}
 
-	hook, err := b.client.CreateHook(gobcy.Hook{
+	return helper.Pointer(int64(chain.Height)), nil
+}
+
+// CreateWebhookConfirmedTx notifies every new confirmation of the address transactions up to confirmations
+func (b *BitcoinImpl) CreateWebhookConfirmedTx(ctx context.Context, address *string, confirmations int) (*gobcy.Hook, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Service.Bitcoin.CreateWebhookConfirmedTx")
+
+	var hook gobcy.Hook
+	err := b.post(ctx, "/hooks", nil, &gobcy.Hook{
 		Event: "tx-confirmation",
 		// SignKey:       "preset",
 		Address:       *address,
-		URL:           b.config.WebhookURL + "/webhook/transaction",
-		Confirmations: b.config.MinimalConfirmation,
-	})
+		URL:           b.webhookURL(),
+		Confirmations: confirmations,
+	}, &hook)
 	if err != nil {
 		logger.WithError(err).Warn("Failed create hook")
 		return nil, err
@@ -196,10 +249,25 @@ func (b *BitcoinImpl) CreateWebhookConfirmedTx(ctx context.Context, address *str
 	return &hook, nil
 }
 
+// webhookURL is the callback url registered on BlockCypher, it carries the shared token when one is configured and
+// the coin when it is not bitcoin
+func (b *BitcoinImpl) webhookURL() string {
+	target := b.config.WebhookURL + "/webhook/transaction"
+	if b.config.WebhookToken != "" {
+		target += "/" + url.PathEscape(b.config.WebhookToken)
+	}
+
+	if b.coin.Chain != "btc" {
+		target += "?coin=" + url.QueryEscape(b.coin.Chain)
+	}
+
+	return target
+}
+
 func (b *BitcoinImpl) DeleteWebhook(ctx context.Context, id *string) error {
 	logger := helper.GetLogger(ctx).WithField("method", "Service.Bitcoin.CreateWebhookConfirmedTx")
 
-	if err := b.client.DeleteHook(*id); err != nil {
+	if err := b.delete(ctx, "/hooks/"+*id); err != nil {
 		logger.WithError(err).Warn("Failed delete hook")
 		return err
 	}
@@ -221,13 +289,64 @@ type Hook struct {
 	CallbackErrs  int     `json:"callback_errors,omitempty"`
 }
 
-func (b *BitcoinImpl) createHook(hook Hook) (result gobcy.Hook, err error) {
-	u, err := b.buildURL("/hooks", nil)
+func (b *BitcoinImpl) createHook(ctx context.Context, hook Hook) (result gobcy.Hook, err error) {
+	err = b.post(ctx, "/hooks", nil, &hook, &result)
+	return
+}
+
+func (b *BitcoinImpl) get(ctx context.Context, path string, params map[string]string, decTarget interface{}) error {
+	return b.do(ctx, http.MethodGet, path, params, nil, decTarget)
+}
+
+func (b *BitcoinImpl) post(ctx context.Context, path string, params map[string]string, encTarget interface{}, decTarget interface{}) error {
+	return b.do(ctx, http.MethodPost, path, params, encTarget, decTarget)
+}
+
+func (b *BitcoinImpl) delete(ctx context.Context, path string) error {
+	return b.do(ctx, http.MethodDelete, path, nil, nil, nil)
+}
+
+// do calls the BlockCypher API once the rate limit of the token lets it through, the calls BlockCypher throttled
+// are retried after the Retry-After it gave
+func (b *BitcoinImpl) do(ctx context.Context, method string, path string, params map[string]string, encTarget interface{}, decTarget interface{}) error {
+	target, err := b.buildURL(path, params)
 	if err != nil {
-		return
+		return err
 	}
-	err = postResponse(u, &hook, &result)
-	return
+
+	return ratelimit.Do(ctx, b.limiter, b.rateLimit, func() error {
+		var body io.Reader
+		if encTarget != nil {
+			var data bytes.Buffer
+			if err := json.NewEncoder(&data).Encode(encTarget); err != nil {
+				return err
+			}
+			body = &data
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+		if err != nil {
+			return err
+		}
+		if encTarget != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := b.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+			return respErrorMaker(resp)
+		}
+
+		if decTarget == nil {
+			return nil
+		}
+		return json.NewDecoder(resp.Body).Decode(decTarget)
+	})
 }
 
 func (b *BitcoinImpl) buildURL(u string, params map[string]string) (target *url.URL, err error) {
@@ -248,30 +367,11 @@ func (b *BitcoinImpl) buildURL(u string, params map[string]string) (target *url.
 	return
 }
 
-func postResponse(target *url.URL, encTarget interface{}, decTarget interface{}) (err error) {
-	var data bytes.Buffer
-	enc := json.NewEncoder(&data)
-	if err = enc.Encode(encTarget); err != nil {
-		return
-	}
-	resp, err := http.Post(target.String(), "application/json", &data)
-	if err != nil {
-		return
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		err = respErrorMaker(resp.StatusCode, resp.Body)
-		return
-	}
-	dec := json.NewDecoder(resp.Body)
-	err = dec.Decode(decTarget)
-	return
-}
-
-func respErrorMaker(statusCode int, body io.Reader) (err error) {
-	status := "HTTP " + strconv.Itoa(statusCode) + " " + http.StatusText(statusCode)
-	if statusCode == 429 {
-		err = errors.New(status)
+// respErrorMaker keeps the Retry-After of a throttled call so it can be retried
+func respErrorMaker(resp *http.Response) (err error) {
+	status := "HTTP " + strconv.Itoa(resp.StatusCode) + " " + http.StatusText(resp.StatusCode)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		err = ratelimit.NewTooManyRequestsError(resp)
 		return
 	}
 	type errorJSON struct {
@@ -281,10 +381,10 @@ func respErrorMaker(statusCode int, body io.Reader) (err error) {
 		} `json:"errors"`
 	}
 	var msg errorJSON
-	dec := json.NewDecoder(body)
+	dec := json.NewDecoder(resp.Body)
 	err = dec.Decode(&msg)
 	if err != nil {
-		return err
+		return errors.New(status)
 	}
 	var errtxt string
 	errtxt += msg.Err
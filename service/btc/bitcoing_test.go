@@ -146,7 +146,7 @@ func TestServiceBtc_CreateWebhook(t *testing.T) {
 		ctx := context.TODO()
 
 		// CODE UNDER TEST
-		webhook, err := btcSvc.CreateWebhookConfirmedTx(ctx, helper.Pointer("myzJWXp5ywnjJiZRH6qoc6vioV9WQB9gJg"))
+		webhook, err := btcSvc.CreateWebhookConfirmedTx(ctx, helper.Pointer("myzJWXp5ywnjJiZRH6qoc6vioV9WQB9gJg"), 6)
 
 		// EXPECTATION
 		require.NoError(t, err)
@@ -158,3 +158,53 @@ func TestServiceBtc_CreateWebhook(t *testing.T) {
 	})
 
 }
+
+func TestServiceBtc_GetWalletUtxoCoins(t *testing.T) {
+	seedPhrase := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	t.Run("ShouldDeriveTheLitecoinAddressOnItsCoinType", func(t *testing.T) {
+		// INIT
+		ltcSvc := btc.NewUtxoImpl(btc.LitecoinCoin("main"), config.Bitcoin{}, config.RateLimit{})
+
+		// CODE UNDER TEST
+		wallet, err := ltcSvc.GetWallet(context.TODO(), &seedPhrase)
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.Equal(t, "LUWPbpM43E2p7ZSh8cyTBEkvpHmr3cB8Ez", wallet.Address.EncodeAddress())
+		require.True(t, ltcSvc.CheckAddress(helper.Pointer(wallet.Address.EncodeAddress())))
+	})
+
+	t.Run("ShouldDeriveTheDogecoinAddressOnItsCoinType", func(t *testing.T) {
+		// INIT
+		dogeSvc := btc.NewUtxoImpl(btc.DogecoinCoin("main"), config.Bitcoin{}, config.RateLimit{})
+
+		// CODE UNDER TEST
+		wallet, err := dogeSvc.GetWallet(context.TODO(), &seedPhrase)
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.Equal(t, "DBus3bamQjgJULBJtYXpEzDWQRwF5iwxgC", wallet.Address.EncodeAddress())
+		require.True(t, dogeSvc.CheckAddress(helper.Pointer(wallet.Address.EncodeAddress())))
+		require.False(t, dogeSvc.CheckAddress(helper.Pointer("LUWPbpM43E2p7ZSh8cyTBEkvpHmr3cB8Ez")))
+	})
+	t.Run("ShouldDeriveATestnetAddress_WhenTheChainIsNotMain", func(t *testing.T) {
+		// INIT
+		ltcSvc := btc.NewUtxoImpl(btc.LitecoinCoin("test"), config.Bitcoin{}, config.RateLimit{})
+		dogeSvc := btc.NewUtxoImpl(btc.DogecoinCoin("test"), config.Bitcoin{}, config.RateLimit{})
+
+		// CODE UNDER TEST
+		ltcWallet, err := ltcSvc.GetWallet(context.TODO(), &seedPhrase)
+		require.NoError(t, err)
+		dogeWallet, err := dogeSvc.GetWallet(context.TODO(), &seedPhrase)
+		require.NoError(t, err)
+
+		// EXPECTATION
+		require.Equal(t, "mkpZhYtJu2r87Js3pDiWJDmPte2NRZ8bJV", ltcWallet.Address.EncodeAddress())
+		require.Equal(t, "nZVmfmUtKPmskB9Ds4P9GUJy9eYFqPKHqH", dogeWallet.Address.EncodeAddress())
+		require.True(t, ltcSvc.CheckAddress(helper.Pointer(ltcWallet.Address.EncodeAddress())))
+		require.False(t, ltcSvc.CheckAddress(helper.Pointer("LUWPbpM43E2p7ZSh8cyTBEkvpHmr3cB8Ez")))
+		require.True(t, dogeSvc.CheckAddress(helper.Pointer(dogeWallet.Address.EncodeAddress())))
+		require.False(t, dogeSvc.CheckAddress(helper.Pointer("DBus3bamQjgJULBJtYXpEzDWQRwF5iwxgC")))
+	})
+}
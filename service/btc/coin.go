@@ -0,0 +1,144 @@
+package btc
+
+import (
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil/hdkeychain"
+)
+
+// Coin is a UTXO coin served through BlockCypher, Chain is the id it is registered under
+type Coin struct {
+	Chain  string
+	Symbol string
+	// BlockCypherCoin and BlockCypherChain select the coin on the BlockCypher API, for example ltc and main
+	BlockCypherCoin  string
+	BlockCypherChain string
+	Params           *chaincfg.Params
+	// AddressPattern is an optional extra check on the addresses the coin accepts
+	AddressPattern string
+	// DerivationPath is the BIP-44 path of the wallet key, the master key is used when it is empty
+	DerivationPath []uint32
+}
+
+// BitcoinCoin keeps the master key of the seed as the bitcoin key, as every wallet created so far uses it
+func BitcoinCoin(chain string) Coin {
+	if chain == "main" {
+		return Coin{
+			Chain:            "btc",
+			Symbol:           "BTC",
+			BlockCypherCoin:  "btc",
+			BlockCypherChain: chain,
+			Params:           &chaincfg.MainNetParams,
+			AddressPattern:   "^bc1[ac-hj-np-z02-9]{25,39}$",
+		}
+	}
+
+	return Coin{
+		Chain:            "btc",
+		Symbol:           "BTC",
+		BlockCypherCoin:  "btc",
+		BlockCypherChain: chain,
+		Params:           &chaincfg.TestNet3Params,
+		AddressPattern:   "^(tb1|[mn2])[a-km-zA-HJ-NP-Z0-9]{25,39}$",
+	}
+}
+
+// LitecoinCoin derives the litecoin key on the SLIP-44 coin type 2, or on the coin type 1 of the testnets
+func LitecoinCoin(chain string) Coin {
+	params := &litecoinTestNetParams
+	if chain == "main" {
+		params = &litecoinMainNetParams
+	}
+
+	return Coin{
+		Chain:            "ltc",
+		Symbol:           "LTC",
+		BlockCypherCoin:  "ltc",
+		BlockCypherChain: chain,
+		Params:           params,
+		DerivationPath:   bip44Path(params.HDCoinType),
+	}
+}
+
+// DogecoinCoin derives the dogecoin key on the SLIP-44 coin type 3, or on the coin type 1 of the testnets
+func DogecoinCoin(chain string) Coin {
+	params := &dogecoinTestNetParams
+	if chain == "main" {
+		params = &dogecoinMainNetParams
+	}
+
+	return Coin{
+		Chain:            "doge",
+		Symbol:           "DOGE",
+		BlockCypherCoin:  "doge",
+		BlockCypherChain: chain,
+		Params:           params,
+		DerivationPath:   bip44Path(params.HDCoinType),
+	}
+}
+
+// bip44Path is m/44'/coinType'/0'/0/0, the first receiving address of the first account
+func bip44Path(coinType uint32) []uint32 {
+	return []uint32{
+		hdkeychain.HardenedKeyStart + 44,
+		hdkeychain.HardenedKeyStart + coinType,
+		hdkeychain.HardenedKeyStart + 0,
+		0,
+		0,
+	}
+}
+
+var litecoinMainNetParams = chaincfg.Params{
+	Name:             "litecoin",
+	Net:              wire.BitcoinNet(0xdbb6c0fb),
+	PubKeyHashAddrID: 0x30,
+	ScriptHashAddrID: 0x32,
+	PrivateKeyID:     0xb0,
+	Bech32HRPSegwit:  "ltc",
+	HDPrivateKeyID:   [4]byte{0x04, 0x88, 0xad, 0xe4},
+	HDPublicKeyID:    [4]byte{0x04, 0x88, 0xb2, 0x1e},
+	HDCoinType:       2,
+}
+
+var dogecoinMainNetParams = chaincfg.Params{
+	Name:             "dogecoin",
+	Net:              wire.BitcoinNet(0xc0c0c0c0),
+	PubKeyHashAddrID: 0x1e,
+	ScriptHashAddrID: 0x16,
+	PrivateKeyID:     0x9e,
+	HDPrivateKeyID:   [4]byte{0x02, 0xfa, 0xc3, 0x98},
+	HDPublicKeyID:    [4]byte{0x02, 0xfa, 0xca, 0xfd},
+	HDCoinType:       3,
+}
+
+var litecoinTestNetParams = chaincfg.Params{
+	Name:             "litecoin-testnet4",
+	Net:              wire.BitcoinNet(0xf1c8d2fd),
+	PubKeyHashAddrID: 0x6f,
+	ScriptHashAddrID: 0x3a,
+	PrivateKeyID:     0xef,
+	Bech32HRPSegwit:  "tltc",
+	HDPrivateKeyID:   [4]byte{0x04, 0x35, 0x83, 0x94},
+	HDPublicKeyID:    [4]byte{0x04, 0x35, 0x87, 0xcf},
+	HDCoinType:       1,
+}
+
+var dogecoinTestNetParams = chaincfg.Params{
+	Name:             "dogecoin-testnet",
+	Net:              wire.BitcoinNet(0xdcb7c1fc),
+	PubKeyHashAddrID: 0x71,
+	ScriptHashAddrID: 0xc4,
+	PrivateKeyID:     0xf1,
+	HDPrivateKeyID:   [4]byte{0x04, 0x35, 0x83, 0x94},
+	HDPublicKeyID:    [4]byte{0x04, 0x35, 0x87, 0xcf},
+	HDCoinType:       1,
+}
+
+func init() {
+	// registering makes the segwit prefix of litecoin known to the address decoder
+	for _, params := range []*chaincfg.Params{&litecoinMainNetParams, &dogecoinMainNetParams, &litecoinTestNetParams, &dogecoinTestNetParams} {
+		if err := chaincfg.Register(params); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -2,13 +2,19 @@ package service
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrCacheMiss is returned by Get when the key is not in the cache
+var ErrCacheMiss = errors.New("cache miss")
+
 type Cache interface {
 	Close()
 	Get(ctx context.Context, key string) (string, error)
 	Put(context.Context, string, string, time.Duration) error
+	// PutIfAbsent stores the key only when it is not in the cache yet, it reports whether it was stored
+	PutIfAbsent(ctx context.Context, key string, data string, ttl time.Duration) (bool, error)
 	Delete(context.Context, string) error
 	SetList(ctx context.Context, key string, data string, ttl *time.Duration) (int64, error)
 	GetList(ctx context.Context, key string) ([]string, error)
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/aalexanderkevin/crypto-wallet/model"
+)
+
+// ChainAdapter is one blockchain behind a common interface, amounts are in the smallest unit of the chain
+type ChainAdapter interface {
+	// Chain is the id the adapter is registered under, for example btc, eth or trx
+	Chain() string
+	// Symbol is the symbol of the native coin of the chain
+	Symbol() string
+	// ExplorerUrl is the page of the transaction on a block explorer, empty when no explorer is configured
+	ExplorerUrl(txHash string) string
+	// Address is the address the wallet holds on the chain
+	Address(wallet *model.Wallet) *string
+	DeriveAddress(ctx context.Context, seedPhrase *string) (*string, error)
+	ValidateAddress(address string) error
+	GetBalance(ctx context.Context, address string) (*big.Int, error)
+	// Send builds, signs and broadcasts a transfer from the wallet derived from the seed phrase
+	Send(ctx context.Context, seedPhrase *string, txOpts *model.TxOpts) (*model.Transaction, error)
+	// GetTx returns what the chain knows about the transaction, a not found error when it does not know it
+	GetTx(ctx context.Context, txHash string) (*model.Transaction, error)
+	// IsReplaced tells whether another transaction took the place of the one not mined yet, like a confirmed double
+	// spend of its inputs or a mined transaction reusing its nonce
+	IsReplaced(ctx context.Context, transaction model.Transaction) (bool, error)
+	GetCurrentHeight(ctx context.Context) (*int64, error)
+	// WatchDeposits reports the deposits to the address to onDeposit until the context is done. A chain whose deposits
+	// arrive by callback registers the address for as long as the watch lasts instead
+	WatchDeposits(ctx context.Context, address string, onDeposit func(deposit *model.Transaction)) error
+}
+
+// ChainRegistry resolves a chain id or one of its aliases to its adapter
+type ChainRegistry interface {
+	Register(adapter ChainAdapter, aliases ...string)
+	Get(chain string) (ChainAdapter, error)
+	// Chains are the ids of the registered chains
+	Chains() []string
+}
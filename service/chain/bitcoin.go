@@ -0,0 +1,143 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+)
+
+// BitcoinAdapter serves a UTXO coin, address picks the address of the coin out of a wallet
+type BitcoinAdapter struct {
+	service.Bitcoin
+	symbol  string
+	address func(wallet *model.Wallet) *string
+}
+
+func NewBitcoinAdapter(bitcoin service.Bitcoin) service.ChainAdapter {
+	return newUtxoAdapter(bitcoin, "BTC", func(wallet *model.Wallet) *string { return wallet.BtcAddress })
+}
+
+func NewLitecoinAdapter(litecoin service.Bitcoin) service.ChainAdapter {
+	return newUtxoAdapter(litecoin, "LTC", func(wallet *model.Wallet) *string { return wallet.LtcAddress })
+}
+
+func NewDogecoinAdapter(dogecoin service.Bitcoin) service.ChainAdapter {
+	return newUtxoAdapter(dogecoin, "DOGE", func(wallet *model.Wallet) *string { return wallet.DogeAddress })
+}
+
+func newUtxoAdapter(bitcoin service.Bitcoin, symbol string, address func(wallet *model.Wallet) *string) service.ChainAdapter {
+	return &BitcoinAdapter{
+		Bitcoin: bitcoin,
+		symbol:  symbol,
+		address: address,
+	}
+}
+
+func (b *BitcoinAdapter) Chain() string {
+	return b.Bitcoin.Coin()
+}
+
+func (b *BitcoinAdapter) Symbol() string {
+	return b.symbol
+}
+
+func (b *BitcoinAdapter) ExplorerUrl(txHash string) string {
+	return ""
+}
+
+func (b *BitcoinAdapter) Address(wallet *model.Wallet) *string {
+	return b.address(wallet)
+}
+
+func (b *BitcoinAdapter) DeriveAddress(ctx context.Context, seedPhrase *string) (*string, error) {
+	wallet, err := b.Bitcoin.GetWallet(ctx, seedPhrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return helper.Pointer(wallet.Address.EncodeAddress()), nil
+}
+
+func (b *BitcoinAdapter) ValidateAddress(address string) error {
+	if !b.Bitcoin.CheckAddress(&address) {
+		return errors.New("invalid address")
+	}
+
+	return nil
+}
+
+func (b *BitcoinAdapter) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	return b.Bitcoin.GetBalance(ctx, address)
+}
+
+func (b *BitcoinAdapter) Send(ctx context.Context, seedPhrase *string, txOpts *model.TxOpts) (*model.Transaction, error) {
+	wallet, err := b.Bitcoin.GetWallet(ctx, seedPhrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.Bitcoin.SendTx(ctx, wallet, txOpts)
+}
+
+func (b *BitcoinAdapter) GetTx(ctx context.Context, txHash string) (*model.Transaction, error) {
+	tx, err := b.Bitcoin.GetTx(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := model.Transaction{}.FromModel(*tx)
+	if tx.BlockHeight > 0 {
+		transaction.Block = helper.Pointer(int64(tx.BlockHeight))
+	}
+
+	return transaction, nil
+}
+
+// IsReplaced is true once a transaction spending the same inputs confirmed
+func (b *BitcoinAdapter) IsReplaced(ctx context.Context, transaction model.Transaction) (bool, error) {
+	tx, err := b.Bitcoin.GetTx(ctx, *transaction.Id)
+	if model.IsNotFoundError(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if !tx.DoubleSpend || tx.DoubleOf == "" || tx.Confirmations > 0 {
+		return false, nil
+	}
+
+	other, err := b.Bitcoin.GetTx(ctx, tx.DoubleOf)
+	if model.IsNotFoundError(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return other.Confirmations > 0, nil
+}
+
+func (b *BitcoinAdapter) GetCurrentHeight(ctx context.Context) (*int64, error) {
+	return b.Bitcoin.GetCurrentBlock(ctx)
+}
+
+// utxoWatchConfirmations is how many confirmations of a deposit BlockCypher reports while the address is watched, the
+// reconciler follows the deposit after that
+const utxoWatchConfirmations = 6
+
+// WatchDeposits registers a BlockCypher hook on the address, its deposits arrive on the webhook callback and not on
+// onDeposit. The hook is removed once the watch is over
+func (b *BitcoinAdapter) WatchDeposits(ctx context.Context, address string, onDeposit func(deposit *model.Transaction)) error {
+	hook, err := b.Bitcoin.CreateWebhookConfirmedTx(ctx, &address, utxoWatchConfirmations)
+	if err != nil {
+		return err
+	}
+
+	<-ctx.Done()
+
+	// the context of the watch is done, the hook is removed without it
+	return b.Bitcoin.DeleteWebhook(context.Background(), &hook.ID)
+}
@@ -0,0 +1,118 @@
+package chain
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+)
+
+var (
+	// cacheHits and cacheMisses count the cached reads per chain and kind, for example eth.balance
+	cacheHits   = expvar.NewMap("chain_cache_hits")
+	cacheMisses = expvar.NewMap("chain_cache_misses")
+)
+
+// CachedAdapter keeps the balances and the height of a chain in the cache for a few seconds, the third-party APIs
+// serving the chains have tight quotas
+type CachedAdapter struct {
+	service.ChainAdapter
+	cache  service.Cache
+	config config.ChainCache
+}
+
+func NewCachedAdapter(adapter service.ChainAdapter, cache service.Cache, config config.ChainCache) service.ChainAdapter {
+	return &CachedAdapter{
+		ChainAdapter: adapter,
+		cache:        cache,
+		config:       config,
+	}
+}
+
+func (c *CachedAdapter) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	key := c.balanceKey(address)
+	if cached, ok := c.get(ctx, "balance", key, c.config.BalanceTtlSeconds); ok {
+		if balance, ok := new(big.Int).SetString(cached, 10); ok {
+			return balance, nil
+		}
+	}
+
+	balance, err := c.ChainAdapter.GetBalance(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	c.put(ctx, key, balance.String(), c.config.BalanceTtlSeconds)
+	return balance, nil
+}
+
+func (c *CachedAdapter) GetCurrentHeight(ctx context.Context) (*int64, error) {
+	key := fmt.Sprintf("chain:%s:height", c.Chain())
+	if cached, ok := c.get(ctx, "height", key, c.config.HeightTtlSeconds); ok {
+		if height, err := strconv.ParseInt(cached, 10, 64); err == nil {
+			return &height, nil
+		}
+	}
+
+	height, err := c.ChainAdapter.GetCurrentHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.put(ctx, key, strconv.FormatInt(*height, 10), c.config.HeightTtlSeconds)
+	return height, nil
+}
+
+// Send forgets the balance of the sender, it changed with the transfer
+func (c *CachedAdapter) Send(ctx context.Context, seedPhrase *string, txOpts *model.TxOpts) (*model.Transaction, error) {
+	transaction, err := c.ChainAdapter.Send(ctx, seedPhrase, txOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, address := range transaction.SenderAddress {
+		if err := c.cache.Delete(ctx, c.balanceKey(address)); err != nil {
+			helper.GetLogger(ctx).WithField("method", "Service.Chain.CachedAdapter.Send").WithError(err).Warn("Failed delete cached balance")
+		}
+	}
+
+	return transaction, nil
+}
+
+func (c *CachedAdapter) balanceKey(address string) string {
+	return fmt.Sprintf("chain:%s:balance:%s", c.Chain(), address)
+}
+
+// get is a miss when caching is disabled or the cache fails, the chain is asked instead
+func (c *CachedAdapter) get(ctx context.Context, kind string, key string, ttlSeconds int) (string, bool) {
+	if ttlSeconds <= 0 {
+		return "", false
+	}
+
+	metric := c.Chain() + "." + kind
+	cached, err := c.cache.Get(ctx, key)
+	if err != nil || cached == "" {
+		cacheMisses.Add(metric, 1)
+		return "", false
+	}
+
+	cacheHits.Add(metric, 1)
+	return cached, true
+}
+
+func (c *CachedAdapter) put(ctx context.Context, key string, value string, ttlSeconds int) {
+	if ttlSeconds <= 0 {
+		return
+	}
+
+	if err := c.cache.Put(ctx, key, value, time.Duration(ttlSeconds)*time.Second); err != nil {
+		helper.GetLogger(ctx).WithField("method", "Service.Chain.CachedAdapter.put").WithError(err).Warn("Failed cache chain response")
+	}
+}
@@ -0,0 +1,88 @@
+package chain_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/service/chain"
+	"github.com/aalexanderkevin/crypto-wallet/service/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceChain_CachedAdapter(t *testing.T) {
+	cfg := config.ChainCache{BalanceTtlSeconds: 15, HeightTtlSeconds: 5}
+
+	t.Run("ShouldReturnTheCachedBalance_WhenItIsCached", func(t *testing.T) {
+		// INIT
+		adapter := &mocks.ChainAdapter{}
+		adapter.On("Chain").Return("btc")
+		cache := &mocks.Cache{}
+		cache.On("Get", mock.Anything, "chain:btc:balance:addr").Return("1500", nil).Once()
+
+		// CODE UNDER TEST
+		balance, err := chain.NewCachedAdapter(adapter, cache, cfg).GetBalance(context.TODO(), "addr")
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(1500), balance)
+		adapter.AssertNotCalled(t, "GetBalance", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ShouldCacheTheBalance_WhenItIsNotCached", func(t *testing.T) {
+		// INIT
+		adapter := &mocks.ChainAdapter{}
+		adapter.On("Chain").Return("btc")
+		adapter.On("GetBalance", mock.Anything, "addr").Return(big.NewInt(42), nil).Once()
+		cache := &mocks.Cache{}
+		cache.On("Get", mock.Anything, "chain:btc:balance:addr").Return("", nil).Once()
+		cache.On("Put", mock.Anything, "chain:btc:balance:addr", "42", 15*time.Second).Return(nil).Once()
+
+		// CODE UNDER TEST
+		balance, err := chain.NewCachedAdapter(adapter, cache, cfg).GetBalance(context.TODO(), "addr")
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(42), balance)
+		cache.AssertExpectations(t)
+	})
+
+	t.Run("ShouldAskTheChain_WhenCachingIsDisabled", func(t *testing.T) {
+		// INIT
+		adapter := &mocks.ChainAdapter{}
+		adapter.On("Chain").Return("eth")
+		adapter.On("GetCurrentHeight", mock.Anything).Return(helper.Pointer(int64(100)), nil).Once()
+		cache := &mocks.Cache{}
+
+		// CODE UNDER TEST
+		height, err := chain.NewCachedAdapter(adapter, cache, config.ChainCache{}).GetCurrentHeight(context.TODO())
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.Equal(t, int64(100), *height)
+		cache.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ShouldForgetTheSenderBalance_WhenItSends", func(t *testing.T) {
+		// INIT
+		txOpts := &model.TxOpts{}
+		adapter := &mocks.ChainAdapter{}
+		adapter.On("Chain").Return("eth")
+		adapter.On("Send", mock.Anything, mock.Anything, txOpts).Return(&model.Transaction{SenderAddress: []string{"from"}}, nil).Once()
+		cache := &mocks.Cache{}
+		cache.On("Delete", mock.Anything, "chain:eth:balance:from").Return(nil).Once()
+
+		// CODE UNDER TEST
+		_, err := chain.NewCachedAdapter(adapter, cache, cfg).Send(context.TODO(), helper.Pointer("seed"), txOpts)
+
+		// EXPECTATION
+		require.NoError(t, err)
+		cache.AssertExpectations(t)
+	})
+}
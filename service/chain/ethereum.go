@@ -0,0 +1,169 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// EthereumAdapter serves ethereum and every other EVM network, they all share the address derived on the ethereum path
+type EthereumAdapter struct {
+	service.Ethereum
+	network config.EvmNetwork
+}
+
+func NewEthereumAdapter(ethereum service.Ethereum, network config.EvmNetwork) service.ChainAdapter {
+	return &EthereumAdapter{
+		Ethereum: ethereum,
+		network:  network,
+	}
+}
+
+func (e *EthereumAdapter) Chain() string {
+	return e.network.Chain
+}
+
+func (e *EthereumAdapter) Symbol() string {
+	return e.network.Symbol
+}
+
+func (e *EthereumAdapter) ExplorerUrl(txHash string) string {
+	if e.network.ExplorerUrl == "" {
+		return ""
+	}
+
+	return strings.TrimSuffix(e.network.ExplorerUrl, "/") + "/tx/" + txHash
+}
+
+func (e *EthereumAdapter) Address(wallet *model.Wallet) *string {
+	return wallet.EthAddress
+}
+
+func (e *EthereumAdapter) DeriveAddress(ctx context.Context, seedPhrase *string) (*string, error) {
+	wallet, err := e.Ethereum.GetWallet(ctx, seedPhrase)
+	if err != nil {
+		return nil, err
+	}
+
+	return helper.Pointer(wallet.Account.Address.Hex()), nil
+}
+
+func (e *EthereumAdapter) ValidateAddress(address string) error {
+	return e.Ethereum.CheckAddress(address)
+}
+
+func (e *EthereumAdapter) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	return e.Ethereum.GetBalance(ctx, common.HexToAddress(address))
+}
+
+func (e *EthereumAdapter) Send(ctx context.Context, seedPhrase *string, txOpts *model.TxOpts) (*model.Transaction, error) {
+	wallet, err := e.Ethereum.GetWallet(ctx, seedPhrase)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := e.Ethereum.SendTx(ctx, txOpts, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Transaction{
+		Id:              helper.Pointer(tx.Hash().Hex()),
+		SenderAddress:   []string{wallet.Account.Address.Hex()},
+		ReceiverAddress: []string{*txOpts.To},
+		Amount:          helper.Pointer(txOpts.Amount.Int64()),
+		Confirmation:    helper.Pointer[int64](0),
+		Fee:             helper.Pointer(tx.GasPrice().Int64() * int64(tx.Gas())),
+		Status:          helper.Pointer(model.TransactionStatusPending),
+		Nonce:           helper.Pointer(int64(tx.Nonce())),
+	}, nil
+}
+
+func (e *EthereumAdapter) GetTx(ctx context.Context, txHash string) (*model.Transaction, error) {
+	return e.Ethereum.GetTx(ctx, helper.Pointer(common.HexToHash(txHash)))
+}
+
+// IsReplaced is true once the sender has a mined transaction with the nonce of the transaction while the transaction
+// itself is not mined
+func (e *EthereumAdapter) IsReplaced(ctx context.Context, transaction model.Transaction) (bool, error) {
+	if transaction.Nonce == nil || len(transaction.SenderAddress) == 0 {
+		return false, nil
+	}
+
+	nonce, err := e.Ethereum.GetNonce(ctx, common.HexToAddress(transaction.SenderAddress[0]))
+	if err != nil {
+		return false, err
+	}
+	if nonce <= uint64(*transaction.Nonce) {
+		return false, nil
+	}
+
+	// the nonce moved past as well when the transaction itself was mined since it was looked up
+	mined, err := e.Ethereum.GetTx(ctx, helper.Pointer(common.HexToHash(*transaction.Id)))
+	if model.IsNotFoundError(err) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return mined.Block == nil, nil
+}
+
+func (e *EthereumAdapter) GetCurrentHeight(ctx context.Context) (*int64, error) {
+	return e.Ethereum.GetCurrentBlock(ctx)
+}
+
+// WatchDeposits follows the pending transactions of the network and reports the ones sent to the address, a dropped
+// subscription is made again
+func (e *EthereumAdapter) WatchDeposits(ctx context.Context, address string, onDeposit func(deposit *model.Transaction)) error {
+	for {
+		subs, txch, err := e.Ethereum.SubscribePendingTransactions(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = e.watchPending(ctx, subs, txch, address, onDeposit)
+		subs.Unsubscribe()
+		if err == nil {
+			return nil
+		}
+		helper.GetLogger(ctx).WithField("method", "Chain.Ethereum.WatchDeposits").WithError(err).Warn("subscribe client connection is closed unexpectedly")
+	}
+}
+
+// watchPending reports the deposits of the subscription until the context is done or the subscription fails
+func (e *EthereumAdapter) watchPending(ctx context.Context, subs *rpc.ClientSubscription, txch chan *types.Transaction, address string, onDeposit func(deposit *model.Transaction)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-subs.Err():
+			if err == nil {
+				err = errors.New("subscription closed")
+			}
+			return err
+		case tx := <-txch:
+			if tx.To() == nil || !strings.EqualFold(tx.To().Hex(), address) {
+				continue
+			}
+
+			onDeposit(&model.Transaction{
+				Id:              helper.Pointer(tx.Hash().Hex()),
+				ReceiverAddress: []string{tx.To().Hex()},
+				Amount:          helper.Pointer(tx.Value().Int64()),
+				Confirmation:    helper.Pointer[int64](0),
+				Status:          helper.Pointer(model.TransactionStatusPending),
+			})
+		}
+	}
+}
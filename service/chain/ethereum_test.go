@@ -0,0 +1,67 @@
+package chain_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/service/chain"
+	"github.com/aalexanderkevin/crypto-wallet/service/mocks"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceChain_EthereumAdapter_IsReplaced(t *testing.T) {
+	sender := "0x00000000000000000000000000000000000000aa"
+	transaction := model.Transaction{
+		Id:            helper.Pointer("0x01"),
+		SenderAddress: []string{sender},
+		Nonce:         helper.Pointer(int64(7)),
+	}
+
+	t.Run("ShouldBeReplaced_WhenTheNonceWasUsedByAnotherTransaction", func(t *testing.T) {
+		// INIT
+		ethereum := &mocks.Ethereum{}
+		ethereum.On("GetNonce", mock.Anything, common.HexToAddress(sender)).Return(uint64(8), nil)
+		ethereum.On("GetTx", mock.Anything, mock.Anything).Return(nil, model.NewNotFoundError())
+
+		// CODE UNDER TEST
+		replaced, err := chain.NewEthereumAdapter(ethereum, config.EvmNetwork{Chain: "eth"}).IsReplaced(context.TODO(), transaction)
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.True(t, replaced)
+	})
+
+	t.Run("ShouldNotBeReplaced_WhenTheNonceIsNotUsedYet", func(t *testing.T) {
+		// INIT
+		ethereum := &mocks.Ethereum{}
+		ethereum.On("GetNonce", mock.Anything, common.HexToAddress(sender)).Return(uint64(7), nil)
+
+		// CODE UNDER TEST
+		replaced, err := chain.NewEthereumAdapter(ethereum, config.EvmNetwork{Chain: "eth"}).IsReplaced(context.TODO(), transaction)
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.False(t, replaced)
+		ethereum.AssertNotCalled(t, "GetTx", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ShouldNotBeReplaced_WhenTheTransactionItselfWasMined", func(t *testing.T) {
+		// INIT
+		ethereum := &mocks.Ethereum{}
+		ethereum.On("GetNonce", mock.Anything, common.HexToAddress(sender)).Return(uint64(8), nil)
+		ethereum.On("GetTx", mock.Anything, mock.Anything).Return(&model.Transaction{Block: helper.Pointer(int64(100))}, nil)
+
+		// CODE UNDER TEST
+		replaced, err := chain.NewEthereumAdapter(ethereum, config.EvmNetwork{Chain: "eth"}).IsReplaced(context.TODO(), transaction)
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.False(t, replaced)
+	})
+}
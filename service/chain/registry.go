@@ -0,0 +1,60 @@
+package chain
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+)
+
+type Registry struct {
+	mu       sync.RWMutex
+	chains   []string
+	adapters map[string]service.ChainAdapter
+}
+
+func NewRegistry() service.ChainRegistry {
+	return &Registry{
+		adapters: map[string]service.ChainAdapter{},
+	}
+}
+
+// Register adds the adapter under its chain id and the aliases, registering a chain id again replaces its adapter
+func (r *Registry) Register(adapter service.ChainAdapter, aliases ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.adapters[adapter.Chain()]; !ok {
+		r.chains = append(r.chains, adapter.Chain())
+	}
+
+	r.adapters[adapter.Chain()] = adapter
+	for _, alias := range aliases {
+		if alias == "" {
+			continue
+		}
+		r.adapters[strings.ToLower(alias)] = adapter
+	}
+}
+
+func (r *Registry) Get(chain string) (service.ChainAdapter, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	adapter, ok := r.adapters[strings.ToLower(chain)]
+	if !ok {
+		return nil, model.NewParameterError(helper.Pointer(fmt.Sprintf("unsupported chain %s", chain)))
+	}
+
+	return adapter, nil
+}
+
+func (r *Registry) Chains() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]string{}, r.chains...)
+}
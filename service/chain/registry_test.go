@@ -0,0 +1,45 @@
+package chain_test
+
+import (
+	"testing"
+
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/service/chain"
+	"github.com/aalexanderkevin/crypto-wallet/service/mocks"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceChain_Registry(t *testing.T) {
+	t.Run("ShouldResolveTheChainAndItsAliases", func(t *testing.T) {
+		// INIT
+		adapter := &mocks.ChainAdapter{}
+		adapter.On("Chain").Return("eth")
+
+		registry := chain.NewRegistry()
+		registry.Register(adapter, "ethereum")
+
+		// CODE UNDER TEST
+		byChain, errChain := registry.Get("eth")
+		byAlias, errAlias := registry.Get("Ethereum")
+
+		// EXPECTATION
+		require.NoError(t, errChain)
+		require.NoError(t, errAlias)
+		require.Equal(t, adapter, byChain)
+		require.Equal(t, adapter, byAlias)
+		require.Equal(t, []string{"eth"}, registry.Chains())
+	})
+
+	t.Run("ShouldReturnParameterError_WhenChainIsNotRegistered", func(t *testing.T) {
+		// INIT
+		registry := chain.NewRegistry()
+
+		// CODE UNDER TEST
+		adapter, err := registry.Get("doge")
+
+		// EXPECTATION
+		require.Nil(t, adapter)
+		require.True(t, model.IsParameterError(err))
+	})
+}
@@ -0,0 +1,212 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+)
+
+type TronAdapter struct {
+	service.Tron
+}
+
+func NewTronAdapter(tron service.Tron) service.ChainAdapter {
+	return &TronAdapter{
+		Tron: tron,
+	}
+}
+
+func (t *TronAdapter) Chain() string {
+	return "trx"
+}
+
+func (t *TronAdapter) Symbol() string {
+	return "TRX"
+}
+
+func (t *TronAdapter) ExplorerUrl(txHash string) string {
+	return ""
+}
+
+func (t *TronAdapter) Address(wallet *model.Wallet) *string {
+	return wallet.TrxAddress
+}
+
+func (t *TronAdapter) DeriveAddress(ctx context.Context, seedPhrase *string) (*string, error) {
+	return t.Tron.GetWallet(ctx, seedPhrase).Address, nil
+}
+
+func (t *TronAdapter) ValidateAddress(address string) error {
+	return t.Tron.CheckAddress(address)
+}
+
+func (t *TronAdapter) GetBalance(ctx context.Context, address string) (*big.Int, error) {
+	balance, err := t.Tron.GetBalance(ctx, &address)
+	if err != nil {
+		return nil, err
+	}
+
+	return big.NewInt(*balance), nil
+}
+
+// Send refuses a transfer the wallet can not pay the burned bandwidth of, instead of letting it fail on chain
+func (t *TronAdapter) Send(ctx context.Context, seedPhrase *string, txOpts *model.TxOpts) (*model.Transaction, error) {
+	wallet := t.Tron.GetWallet(ctx, seedPhrase)
+
+	estimate, err := t.Tron.EstimateTransferFee(ctx, txOpts, wallet.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := t.Tron.GetBalance(ctx, wallet.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	if *balance < txOpts.Amount.Int64()+*estimate.Fee {
+		return nil, model.NewFailedPreconditionError(helper.Pointer(fmt.Sprintf("insufficient balance, the transfer needs %d sun of which %d sun fee", txOpts.Amount.Int64()+*estimate.Fee, *estimate.Fee)))
+	}
+
+	tx, err := t.Tron.SendTx(ctx, txOpts, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Transaction{
+		Id:              helper.Pointer(common.BytesToHash(tx.Txid).Hex()[2:]),
+		SenderAddress:   []string{*wallet.Address},
+		ReceiverAddress: []string{*txOpts.To},
+		Amount:          helper.Pointer(txOpts.Amount.Int64()),
+		Fee:             helper.Pointer[int64](0),
+		Confirmation:    helper.Pointer[int64](0),
+		Status:          helper.Pointer(model.TransactionStatusPending),
+	}, nil
+}
+
+// GetTx only knows the block, fee and result of the transaction, the addresses and amount stay with the caller
+func (t *TronAdapter) GetTx(ctx context.Context, txHash string) (*model.Transaction, error) {
+	txInfo, err := t.Tron.GetTx(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	currentBlock, err := t.Tron.GetCurrentBlock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := &model.Transaction{
+		Id:         &txHash,
+		Fee:        &txInfo.Fee,
+		Block:      &txInfo.BlockNumber,
+		ReceivedAt: helper.Pointer(time.UnixMilli(txInfo.BlockTimeStamp)),
+		Status:     helper.Pointer(model.TransactionStatusPending),
+	}
+	if currentBlock != nil {
+		transaction.Confirmation = helper.Pointer(*currentBlock - txInfo.BlockNumber)
+	}
+
+	if txInfo.GetResult() == core.TransactionInfo_FAILED {
+		transaction.Status = helper.Pointer(model.TransactionStatusFailed)
+		transaction.FailureReason = helper.Pointer(string(txInfo.GetResMessage()))
+	}
+
+	return transaction, nil
+}
+
+// IsReplaced is always false, a tron transaction can not be replaced, it expires
+func (t *TronAdapter) IsReplaced(ctx context.Context, transaction model.Transaction) (bool, error) {
+	return false, nil
+}
+
+func (t *TronAdapter) GetCurrentHeight(ctx context.Context) (*int64, error) {
+	return t.Tron.GetCurrentBlock(ctx)
+}
+
+// tronWatchInterval is how often the confirmed transactions to a watched address are listed
+const tronWatchInterval = time.Minute
+
+// WatchDeposits lists the confirmed transactions to the address made since the watch started on every interval
+func (t *TronAdapter) WatchDeposits(ctx context.Context, address string, onDeposit func(deposit *model.Transaction)) error {
+	logger := helper.GetLogger(ctx).WithField("method", "Chain.Tron.WatchDeposits")
+	since := time.Now().UnixMilli()
+
+	for {
+		next, err := t.pollDeposits(ctx, address, since, onDeposit)
+		if err != nil {
+			logger.WithError(err).Warn("failed list deposits")
+		}
+		since = next
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(tronWatchInterval):
+		}
+	}
+}
+
+// pollDeposits reports every page of the confirmed transactions to the address made since the timestamp, it returns
+// the timestamp after the last reported one
+func (t *TronAdapter) pollDeposits(ctx context.Context, address string, since int64, onDeposit func(deposit *model.Transaction)) (int64, error) {
+	currentBlock, err := t.Tron.GetCurrentBlock(ctx)
+	if err != nil {
+		return since, err
+	}
+
+	next := since
+	var fingerprint *string
+	for {
+		res, err := t.Tron.GetTxByAccountAddress(ctx, &address, &service.GetTxByAccountAddressFilter{
+			OnlyConfirmed:  helper.Pointer(true),
+			OnlyTo:         helper.Pointer(true),
+			OrderBy:        helper.Pointer("block_timestamp,asc"),
+			MinTimestampMs: helper.Pointer(since),
+			Fingerprint:    fingerprint,
+		})
+		if err != nil {
+			return next, err
+		}
+
+		for _, data := range res.Data {
+			if data.RawData == nil || len(data.RawData.Contract) == 0 || data.BlockTimestamp == nil {
+				continue
+			}
+			value := data.RawData.Contract[0].Parameter.Value
+			if value.ToAddress == nil || value.OwnerAddress == nil {
+				continue
+			}
+
+			deposit := &model.Transaction{
+				Id:              data.TxID,
+				SenderAddress:   []string{*helper.ToTrxAddress(*value.OwnerAddress)},
+				ReceiverAddress: []string{*helper.ToTrxAddress(*value.ToAddress)},
+				Amount:          value.Amount,
+				ReceivedAt:      helper.Pointer(time.UnixMilli(*data.BlockTimestamp)),
+				Fee:             data.NetFee,
+				Block:           data.BlockNumber,
+			}
+			if currentBlock != nil && deposit.Block != nil {
+				deposit.Confirmation = helper.Pointer(*currentBlock - *deposit.Block)
+			}
+			onDeposit(deposit)
+
+			if *data.BlockTimestamp >= next {
+				next = *data.BlockTimestamp + 1
+			}
+		}
+
+		if res.Meta == nil || res.Meta.Fingerprint == nil {
+			return next, nil
+		}
+		fingerprint = res.Meta.Fingerprint
+	}
+}
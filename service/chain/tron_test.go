@@ -0,0 +1,55 @@
+package chain_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+	"github.com/aalexanderkevin/crypto-wallet/service/chain"
+	"github.com/aalexanderkevin/crypto-wallet/service/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceChain_TronAdapter_WatchDeposits(t *testing.T) {
+	t.Run("ShouldReportTheConfirmedTransfersToTheAddress", func(t *testing.T) {
+		// INIT
+		owner := "41" + "00000000000000000000000000000000000000aa"
+		receiver := "41" + "00000000000000000000000000000000000000bb"
+		tron := &mocks.Tron{}
+		tron.On("GetCurrentBlock", mock.Anything).Return(helper.Pointer(int64(120)), nil)
+		tron.On("GetTxByAccountAddress", mock.Anything, mock.Anything, mock.Anything).Return(&service.GetTransactionResponse{
+			Data: []service.TransactionData{{
+				TxID:           helper.Pointer("tx-id"),
+				BlockNumber:    helper.Pointer(int64(100)),
+				BlockTimestamp: helper.Pointer(int64(1700000000000)),
+				RawData: &service.RawDataTransaction{Contract: []service.Contract{{
+					Parameter: struct {
+						Value   service.Value `json:"value"`
+						TypeURL *string       `json:"type_url"`
+					}{Value: service.Value{Amount: helper.Pointer(int64(5000)), OwnerAddress: &owner, ToAddress: &receiver}},
+				}}},
+			}},
+		}, nil)
+		ctx, cancel := context.WithCancel(context.TODO())
+		deposits := []*model.Transaction{}
+
+		// CODE UNDER TEST
+		err := chain.NewTronAdapter(tron).WatchDeposits(ctx, *helper.ToTrxAddress(receiver), func(deposit *model.Transaction) {
+			deposits = append(deposits, deposit)
+			cancel()
+		})
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.Len(t, deposits, 1)
+		require.Equal(t, "tx-id", *deposits[0].Id)
+		require.Equal(t, []string{*helper.ToTrxAddress(receiver)}, deposits[0].ReceiverAddress)
+		require.Equal(t, []string{*helper.ToTrxAddress(owner)}, deposits[0].SenderAddress)
+		require.Equal(t, int64(5000), *deposits[0].Amount)
+		require.Equal(t, int64(20), *deposits[0].Confirmation)
+	})
+}
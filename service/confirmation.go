@@ -0,0 +1,12 @@
+package service
+
+import "github.com/aalexanderkevin/crypto-wallet/model"
+
+type ConfirmationPolicy interface {
+	// RequiredConfirmations returns how many confirmations a transfer of amount needs on the chain of token
+	RequiredConfirmations(token string, amount *int64) int64
+	// MaxConfirmations returns the highest requirement of the chain, whatever the amount
+	MaxConfirmations(token string) int64
+	// Apply derives the status of a transaction from its confirmations, failed transactions are left untouched
+	Apply(token string, transaction *model.Transaction)
+}
@@ -0,0 +1,137 @@
+package confirmation
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+)
+
+// Tier raises the confirmations required for transfers of at least MinAmount, in the smallest unit of the chain
+type Tier struct {
+	MinAmount     int64
+	Confirmations int64
+}
+
+type chainPolicy struct {
+	confirmations int64
+	// tiers are sorted by descending MinAmount
+	tiers []Tier
+}
+
+type Policy struct {
+	chains map[string]chainPolicy
+}
+
+// NewPolicy builds the policy of btc, eth, trx, ltc and doge from cfg and of the EVM networks from their own confirmations
+func NewPolicy(cfg config.Confirmation, networks ...config.EvmNetwork) (service.ConfirmationPolicy, error) {
+	policy := &Policy{chains: map[string]chainPolicy{}}
+
+	type chainConfirmation struct {
+		confirmations int
+		tiers         string
+	}
+	chains := map[string]chainConfirmation{
+		"btc":  {cfg.Btc, cfg.BtcTiers},
+		"eth":  {cfg.Eth, cfg.EthTiers},
+		"trx":  {cfg.Trx, cfg.TrxTiers},
+		"ltc":  {cfg.Ltc, cfg.LtcTiers},
+		"doge": {cfg.Doge, cfg.DogeTiers},
+	}
+	for _, network := range networks {
+		chains[network.Chain] = chainConfirmation{network.Confirmations, network.ConfirmationTiers}
+	}
+
+	for token, chain := range chains {
+		tiers, err := ParseTiers(chain.tiers)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s confirmation tiers: %w", token, err)
+		}
+		policy.chains[token] = chainPolicy{confirmations: int64(chain.confirmations), tiers: tiers}
+	}
+
+	return policy, nil
+}
+
+// ParseTiers reads tiers written as "<min amount>:<confirmations>" separated by commas
+func ParseTiers(value string) ([]Tier, error) {
+	tiers := []Tier{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		amount, confirmations, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("tier %q is not <min amount>:<confirmations>", part)
+		}
+
+		tier := Tier{}
+		var err error
+		if tier.MinAmount, err = strconv.ParseInt(strings.TrimSpace(amount), 10, 64); err != nil {
+			return nil, fmt.Errorf("tier %q: %w", part, err)
+		}
+		if tier.Confirmations, err = strconv.ParseInt(strings.TrimSpace(confirmations), 10, 64); err != nil {
+			return nil, fmt.Errorf("tier %q: %w", part, err)
+		}
+		tiers = append(tiers, tier)
+	}
+
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].MinAmount > tiers[j].MinAmount
+	})
+
+	return tiers, nil
+}
+
+func (p *Policy) RequiredConfirmations(token string, amount *int64) int64 {
+	chain := p.chains[token]
+
+	required := chain.confirmations
+	for _, tier := range chain.tiers {
+		if helper.Val(amount) >= tier.MinAmount {
+			if tier.Confirmations > required {
+				required = tier.Confirmations
+			}
+			break
+		}
+	}
+
+	return required
+}
+
+func (p *Policy) MaxConfirmations(token string) int64 {
+	chain := p.chains[token]
+
+	max := chain.confirmations
+	for _, tier := range chain.tiers {
+		if tier.Confirmations > max {
+			max = tier.Confirmations
+		}
+	}
+
+	return max
+}
+
+func (p *Policy) Apply(token string, transaction *model.Transaction) {
+	if helper.Val(transaction.Status) == model.TransactionStatusFailed {
+		return
+	}
+
+	if helper.Val(transaction.Confirmation) < p.RequiredConfirmations(token, transaction.Amount) {
+		transaction.Status = helper.Pointer(model.TransactionStatusPending)
+		return
+	}
+
+	transaction.Status = helper.Pointer(model.TransactionStatusSuccess)
+	if transaction.CompletedAt == nil {
+		transaction.CompletedAt = helper.Pointer(time.Now())
+	}
+}
@@ -0,0 +1,99 @@
+package confirmation_test
+
+import (
+	"testing"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/service/confirmation"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceConfirmation_Policy(t *testing.T) {
+	policy, err := confirmation.NewPolicy(config.Confirmation{
+		Btc:      6,
+		BtcTiers: "100000000:12, 1000000000:30",
+		Eth:      12,
+		Trx:      19,
+	})
+	require.NoError(t, err)
+
+	t.Run("ShouldUseTheTierOfTheAmount", func(t *testing.T) {
+		// CODE UNDER TEST & EXPECTATION
+		require.Equal(t, int64(6), policy.RequiredConfirmations("btc", helper.Pointer(int64(99999999))))
+		require.Equal(t, int64(12), policy.RequiredConfirmations("btc", helper.Pointer(int64(100000000))))
+		require.Equal(t, int64(30), policy.RequiredConfirmations("btc", helper.Pointer(int64(5000000000))))
+		require.Equal(t, int64(30), policy.MaxConfirmations("btc"))
+		require.Equal(t, int64(12), policy.RequiredConfirmations("eth", nil))
+	})
+
+	t.Run("ShouldBeSuccess_WhenConfirmationsExceedTheThreshold", func(t *testing.T) {
+		// INIT
+		transaction := &model.Transaction{
+			Amount:       helper.Pointer(int64(1000)),
+			Confirmation: helper.Pointer(int64(7)),
+			Status:       helper.Pointer(model.TransactionStatusPending),
+		}
+
+		// CODE UNDER TEST
+		policy.Apply("btc", transaction)
+
+		// EXPECTATION
+		require.Equal(t, model.TransactionStatusSuccess, *transaction.Status)
+		require.NotNil(t, transaction.CompletedAt)
+	})
+
+	t.Run("ShouldStayPending_WhenTheTierNeedsMoreConfirmations", func(t *testing.T) {
+		// INIT
+		transaction := &model.Transaction{
+			Amount:       helper.Pointer(int64(200000000)),
+			Confirmation: helper.Pointer(int64(7)),
+		}
+
+		// CODE UNDER TEST
+		policy.Apply("btc", transaction)
+
+		// EXPECTATION
+		require.Equal(t, model.TransactionStatusPending, *transaction.Status)
+		require.Nil(t, transaction.CompletedAt)
+	})
+
+	t.Run("ShouldKeepFailed", func(t *testing.T) {
+		// INIT
+		transaction := &model.Transaction{
+			Confirmation: helper.Pointer(int64(40)),
+			Status:       helper.Pointer(model.TransactionStatusFailed),
+		}
+
+		// CODE UNDER TEST
+		policy.Apply("trx", transaction)
+
+		// EXPECTATION
+		require.Equal(t, model.TransactionStatusFailed, *transaction.Status)
+	})
+
+	t.Run("ShouldReturnError_WhenTierIsMalformed", func(t *testing.T) {
+		// CODE UNDER TEST
+		_, err := confirmation.NewPolicy(config.Confirmation{EthTiers: "1000"})
+
+		// EXPECTATION
+		require.Error(t, err)
+	})
+
+	t.Run("ShouldUseTheConfirmationsOfTheEvmNetwork", func(t *testing.T) {
+		// INIT
+		policy, err := confirmation.NewPolicy(config.Confirmation{Eth: 12}, config.EvmNetwork{
+			Chain:             "matic",
+			Confirmations:     128,
+			ConfirmationTiers: "1000000000000000000:256",
+		})
+		require.NoError(t, err)
+
+		// CODE UNDER TEST & EXPECTATION
+		require.Equal(t, int64(12), policy.RequiredConfirmations("eth", nil))
+		require.Equal(t, int64(128), policy.RequiredConfirmations("matic", helper.Pointer(int64(1000))))
+		require.Equal(t, int64(256), policy.MaxConfirmations("matic"))
+	})
+}
@@ -4,17 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"math/big"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/aalexanderkevin/crypto-wallet/config"
 	"github.com/aalexanderkevin/crypto-wallet/helper"
 	"github.com/aalexanderkevin/crypto-wallet/model"
 	"github.com/aalexanderkevin/crypto-wallet/service"
+	"github.com/aalexanderkevin/crypto-wallet/service/failover"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -24,33 +26,66 @@ import (
 )
 
 type EthereumImpl struct {
-	client *ethclient.Client
-	ws     *ethclient.Client
-	config config.Ethereum
+	client  *failover.Pool[*ethclient.Client]
+	ws      *failover.Pool[*ethclient.Client]
+	network config.EvmNetwork
 }
 
-func NewEthereumImpl(config config.Config) service.Ethereum {
-	client, err := ethclient.Dial(config.Ethereum.NetUrl)
-
+func NewEthereumImpl(config config.Config) (service.Ethereum, error) {
+	network, err := config.Ethereum.Network()
 	if err != nil {
-		panic(fmt.Sprintf("error connect to eth client: %s, with error %v", config.Ethereum.NetUrl, err))
+		return nil, err
 	}
 
-	ws, err := ethclient.Dial("wss://sepolia.infura.io/ws/v3/282be59eb719440b89fe8168d85003fb")
-	if err != nil {
-		panic(fmt.Sprintf("error connect to eth client: %s, with error %v", config.Ethereum.NetUrl, err))
+	return NewEvmImpl(network, config.Failover), nil
+}
+
+// NewEvmImpl serves an EVM compatible network through its endpoints, the websocket is only needed to watch pending
+// transactions
+func NewEvmImpl(network config.EvmNetwork, failoverConfig config.Failover) service.Ethereum {
+	e := &EthereumImpl{
+		client:  failover.NewPool(network.Chain, network.RpcEndpoints(), failoverConfig, evmClient),
+		network: network,
 	}
 
-	return &EthereumImpl{
-		client: client,
-		config: config.Ethereum,
-		ws:     ws,
+	if wsEndpoints := network.WebsocketEndpoints(); len(wsEndpoints) > 0 {
+		e.ws = failover.NewPool(network.Chain+"-ws", wsEndpoints, failoverConfig, evmClient)
 	}
+
+	return e
+}
+
+var evmClient = failover.Client[*ethclient.Client]{
+	Dial: ethclient.DialContext,
+	Height: func(ctx context.Context, client *ethclient.Client) (int64, error) {
+		height, err := client.BlockNumber(ctx)
+		return int64(height), err
+	},
+	IsFailure: isEndpointFailure,
+	Close: func(client *ethclient.Client) {
+		client.Close()
+	},
+}
+
+// isEndpointFailure tells the errors of the node from the ones of the request, a JSON-RPC error or a missing
+// transaction would be the same on any other endpoint
+func isEndpointFailure(err error) bool {
+	var rpcErr rpc.Error
+	switch {
+	case errors.Is(err, ethereum.NotFound), errors.Is(err, context.Canceled):
+		return false
+	case errors.As(err, &rpcErr):
+		return false
+	}
+
+	return true
 }
 
 func (e *EthereumImpl) Close() {
 	e.client.Close()
-	e.ws.Close()
+	if e.ws != nil {
+		e.ws.Close()
+	}
 }
 
 func (e *EthereumImpl) GetWallet(ctx context.Context, seedPhrase *string) (*model.EthHdWallet, error) {
@@ -90,10 +125,15 @@ func (e *EthereumImpl) SendTx(ctx context.Context, txOpts *model.TxOpts, wallet
 		return nil, err
 	}
 
-	err = e.client.SendTransaction(ctx, tx)
+	err = failover.Do(ctx, e.client, func(client *ethclient.Client) error {
+		return client.SendTransaction(ctx, tx)
+	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed sendTransaction ethereum")
-		return
+		if !rejected(err) {
+			return nil, model.NewBroadcastUnknownError(err)
+		}
+		return nil, err
 	}
 
 	return
@@ -102,7 +142,9 @@ func (e *EthereumImpl) SendTx(ctx context.Context, txOpts *model.TxOpts, wallet
 func (e *EthereumImpl) GetBalance(ctx context.Context, fromAddress common.Address) (balance *big.Int, err error) {
 	logger := helper.GetLogger(ctx).WithField("method", "Service.Ethereum.GetBalance")
 
-	balance, err = e.client.BalanceAt(ctx, fromAddress, nil)
+	balance, err = failover.Call(ctx, e.client, func(client *ethclient.Client) (*big.Int, error) {
+		return client.BalanceAt(ctx, fromAddress, nil)
+	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed balanceAt Ethereum")
 		return nil, err
@@ -112,21 +154,33 @@ func (e *EthereumImpl) GetBalance(ctx context.Context, fromAddress common.Addres
 }
 
 func (e *EthereumImpl) getNonce(ctx context.Context, fromAddress common.Address) (nonce uint64, err error) {
-	return e.client.PendingNonceAt(ctx, fromAddress)
+	return failover.Call(ctx, e.client, func(client *ethclient.Client) (uint64, error) {
+		return client.PendingNonceAt(ctx, fromAddress)
+	})
+}
+
+func (e *EthereumImpl) GetNonce(ctx context.Context, address common.Address) (uint64, error) {
+	return failover.Call(ctx, e.client, func(client *ethclient.Client) (uint64, error) {
+		return client.NonceAt(ctx, address, nil)
+	})
 }
 
 func (e *EthereumImpl) getGasLimit(ctx context.Context, fromAddress common.Address, txOpts model.TxOpts) (gasLimit uint64, err error) {
 	toAddress := common.HexToAddress(*txOpts.To)
 
-	return e.client.EstimateGas(ctx, ethereum.CallMsg{
-		From:  fromAddress,
-		To:    helper.Pointer(toAddress),
-		Value: txOpts.Amount,
+	return failover.Call(ctx, e.client, func(client *ethclient.Client) (uint64, error) {
+		return client.EstimateGas(ctx, ethereum.CallMsg{
+			From:  fromAddress,
+			To:    helper.Pointer(toAddress),
+			Value: txOpts.Amount,
+		})
 	})
 }
 
 func (e *EthereumImpl) getGasPrice(ctx context.Context) (gasPrice *big.Int, err error) {
-	return e.client.SuggestGasPrice(ctx)
+	return failover.Call(ctx, e.client, func(client *ethclient.Client) (*big.Int, error) {
+		return client.SuggestGasPrice(ctx)
+	})
 }
 
 func (e *EthereumImpl) createTx(ctx context.Context, txOpts *model.TxOpts, wallet *model.EthHdWallet) (*types.Transaction, error) {
@@ -166,21 +220,18 @@ func (e *EthereumImpl) createTx(ctx context.Context, txOpts *model.TxOpts, walle
 		Data:      nil,
 	}
 
-	chainID, err := e.client.NetworkID(ctx)
+	chainID, err := e.chainID(ctx)
 	if err != nil {
-		log.Fatal(err)
 		return nil, err
 	}
 
 	privateKey, err := wallet.Wallet.PrivateKey(*wallet.Account)
 	if err != nil {
-		log.Fatal(err)
 		return nil, err
 	}
 
 	signedTx, err := types.SignNewTx(privateKey, types.LatestSignerForChainID(chainID), baseTx)
 	if err != nil {
-		log.Fatal(err)
 		return nil, err
 	}
 
@@ -188,6 +239,17 @@ func (e *EthereumImpl) createTx(ctx context.Context, txOpts *model.TxOpts, walle
 
 }
 
+// chainID is the configured chain id of the network, or the one of the node when none is configured
+func (e *EthereumImpl) chainID(ctx context.Context) (*big.Int, error) {
+	if e.network.ChainId != 0 {
+		return big.NewInt(e.network.ChainId), nil
+	}
+
+	return failover.Call(ctx, e.client, func(client *ethclient.Client) (*big.Int, error) {
+		return client.ChainID(ctx)
+	})
+}
+
 func checkValueEnough(value *big.Int, gasPrice *big.Int, gasLimit uint64, balance *big.Int) bool {
 	tvalue := big.NewInt(0).Set(value)
 	tgasPrice := big.NewInt(0).Set(gasPrice)
@@ -218,6 +280,12 @@ func (e *EthereumImpl) GetTx(ctx context.Context, txHash *common.Hash) (*model.T
 	res.ReceivedAt = blockInformation.ReceivedAt
 	res.Confirmation = blockInformation.Confirmation
 	res.Status = blockInformation.Status
+	res.GasUsed = blockInformation.GasUsed
+	res.EffectiveGasPrice = blockInformation.EffectiveGasPrice
+	res.FailureReason = blockInformation.FailureReason
+	if blockInformation.Fee != nil {
+		res.Fee = blockInformation.Fee
+	}
 
 	return res, nil
 }
@@ -236,7 +304,9 @@ type EtherscanTransaction struct {
 func (e *EthereumImpl) GetCurrentBlock(ctx context.Context) (*int64, error) {
 	logger := helper.GetLogger(ctx).WithField("method", "Service.Ethereum.GetCurrentBlock")
 
-	blockNum, err := e.client.BlockNumber(ctx)
+	blockNum, err := failover.Call(ctx, e.client, func(client *ethclient.Client) (uint64, error) {
+		return client.BlockNumber(ctx)
+	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed get BlockNumber")
 		return nil, err
@@ -258,13 +328,17 @@ func (e *EthereumImpl) GetBlockInformation(ctx context.Context, txHash *common.H
 	logger := helper.GetLogger(ctx).WithField("method", "Service.Ethereum.GetTx")
 	res := &model.Transaction{}
 
-	receipt, err := e.client.TransactionReceipt(context.Background(), *txHash)
+	receipt, err := failover.Call(ctx, e.client, func(client *ethclient.Client) (*types.Receipt, error) {
+		return client.TransactionReceipt(ctx, *txHash)
+	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed get TransactionReceipt")
 		return nil, err
 	}
 
-	blck, err := e.client.BlockByHash(ctx, receipt.BlockHash)
+	blck, err := failover.Call(ctx, e.client, func(client *ethclient.Client) (*types.Block, error) {
+		return client.BlockByHash(ctx, receipt.BlockHash)
+	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed get BlockByHash")
 		return nil, err
@@ -279,23 +353,89 @@ func (e *EthereumImpl) GetBlockInformation(ctx context.Context, txHash *common.H
 	res.Block = helper.Pointer(receipt.BlockNumber.Int64())
 	res.ReceivedAt = helper.Pointer(time.Unix(int64(blck.Time()), 0))
 	res.Confirmation = helper.Pointer(int64(*currentBlock) - *res.Block)
+	res.GasUsed = helper.Pointer(int64(receipt.GasUsed))
+	if receipt.EffectiveGasPrice != nil {
+		res.EffectiveGasPrice = helper.Pointer(receipt.EffectiveGasPrice.Int64())
+		res.Fee = helper.Pointer(*res.GasUsed * *res.EffectiveGasPrice)
+	}
 
-	if *res.Confirmation > 12 {
-		res.Status = helper.Pointer("success")
+	// a mined transaction is only final once the confirmation policy says so, unless it reverted
+	res.Status = helper.Pointer(model.TransactionStatusPending)
+	if receipt.Status == types.ReceiptStatusFailed {
+		res.Status = helper.Pointer(model.TransactionStatusFailed)
+		res.FailureReason = helper.Pointer(e.revertReason(ctx, txHash, receipt))
 	}
 
 	return res, nil
 }
 
+// revertReason replays a reverted transaction with eth_call on the state of its parent block to get the revert
+// reason, the generic reason is returned when the node does not give one
+func (e *EthereumImpl) revertReason(ctx context.Context, txHash *common.Hash, receipt *types.Receipt) string {
+	logger := helper.GetLogger(ctx).WithField("method", "Service.Ethereum.revertReason")
+	reason := "execution reverted"
+
+	tx, _, err := e.transactionByHash(ctx, txHash)
+	if err != nil {
+		logger.WithError(err).Warn("Failed get TransactionByHash")
+		return reason
+	}
+
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		logger.WithError(err).Warn("Failed get transaction sender")
+		return reason
+	}
+
+	msg := ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	if tx.Type() == types.LegacyTxType || tx.Type() == types.AccessListTxType {
+		msg.GasPrice = tx.GasPrice()
+	} else {
+		msg.GasFeeCap = tx.GasFeeCap()
+		msg.GasTipCap = tx.GasTipCap()
+	}
+
+	_, err = failover.Call(ctx, e.client, func(client *ethclient.Client) ([]byte, error) {
+		return client.CallContract(ctx, msg, new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1)))
+	})
+	if err == nil {
+		// the replay did not revert on the parent state, most likely it depended on an earlier transaction of its block
+		if receipt.GasUsed == tx.Gas() {
+			return "out of gas"
+		}
+		return reason
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if unpacked, err := abi.UnpackRevert(common.FromHex(data)); err == nil {
+				return unpacked
+			}
+		}
+	}
+
+	return err.Error()
+}
+
 func (e *EthereumImpl) GetTransactionPending(ctx context.Context, txHash *common.Hash) (*model.Transaction, *bool, error) {
 	logger := helper.GetLogger(ctx).WithField("method", "Service.Ethereum.GetTransactionPending")
 
 	res := &model.Transaction{Id: helper.Pointer(txHash.Hex())}
-	res.Status = helper.Pointer("pending")
+	res.Status = helper.Pointer(model.TransactionStatusPending)
 	res.Confirmation = helper.Pointer[int64](0)
 
-	tx, isPending, err := e.client.TransactionByHash(ctx, *txHash)
+	tx, isPending, err := e.transactionByHash(ctx, txHash)
 	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			return nil, nil, model.NewNotFoundError()
+		}
 		logger.WithError(err).Warn("Failed get TransactionByHash")
 		return nil, nil, err
 	}
@@ -303,8 +443,9 @@ func (e *EthereumImpl) GetTransactionPending(ctx context.Context, txHash *common
 	res.ReceiverAddress = []string{tx.To().Hex()}
 	res.Amount = helper.Pointer(tx.Value().Int64())
 	res.Fee = helper.Pointer(tx.GasPrice().Int64() * int64(tx.Gas()))
+	res.Nonce = helper.Pointer(int64(tx.Nonce()))
 
-	chainId, err := e.client.ChainID(ctx)
+	chainId, err := e.chainID(ctx)
 	if err != nil {
 		logger.WithError(err).Warn("Failed get ChainID")
 		return nil, nil, err
@@ -327,13 +468,38 @@ func (e *EthereumImpl) GetTransactionPending(ctx context.Context, txHash *common
 
 func (e *EthereumImpl) SubscribePendingTransactions(ctx context.Context) (subs *rpc.ClientSubscription, txch chan *types.Transaction, err error) {
 	logger := helper.GetLogger(ctx).WithField("method", "Service.Ethereum.RunningWatcher")
-	gcli := gethclient.New(e.ws.Client())
+	if e.ws == nil {
+		return nil, nil, fmt.Errorf("no websocket url configured for %s", e.network.Chain)
+	}
 
 	txch = make(chan *types.Transaction, 100)
-	subs, err = gcli.SubscribeFullPendingTransactions(context.Background(), txch)
+	subs, err = failover.Call(ctx, e.ws, func(client *ethclient.Client) (*rpc.ClientSubscription, error) {
+		return gethclient.New(client.Client()).SubscribeFullPendingTransactions(context.Background(), txch)
+	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed to SubscribeFullPendingTransactions")
 	}
 
 	return
 }
+
+func (e *EthereumImpl) transactionByHash(ctx context.Context, txHash *common.Hash) (*types.Transaction, bool, error) {
+	type result struct {
+		tx        *types.Transaction
+		isPending bool
+	}
+
+	res, err := failover.Call(ctx, e.client, func(client *ethclient.Client) (result, error) {
+		tx, isPending, err := client.TransactionByHash(ctx, *txHash)
+		return result{tx: tx, isPending: isPending}, err
+	})
+
+	return res.tx, res.isPending, err
+}
+
+// rejected tells if the node refused the transaction, on any other failure of the broadcast the transaction may have
+// reached the node. A transaction the node already knows was broadcast before
+func rejected(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) && !strings.Contains(err.Error(), "already known")
+}
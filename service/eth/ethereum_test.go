@@ -20,7 +20,8 @@ import (
 func TestServiceEth_GetWallet(t *testing.T) {
 	t.Run("ShouldGetEthWallet", func(t *testing.T) {
 		// INIT
-		ethSvc := eth.NewEthereumImpl(config.Instance())
+		ethSvc, err := eth.NewEthereumImpl(config.Instance())
+		require.NoError(t, err)
 		defer ethSvc.Close()
 
 		seedPhrase := "yellow dolphin robot express road develop repair neutral rate tide economy section"
@@ -40,7 +41,8 @@ func TestServiceEth_GetWallet(t *testing.T) {
 func TestServiceEth_GetBalance(t *testing.T) {
 	t.Run("ShouldGetBalance", func(t *testing.T) {
 		// INIT
-		ethSvc := eth.NewEthereumImpl(config.Instance())
+		ethSvc, err := eth.NewEthereumImpl(config.Instance())
+		require.NoError(t, err)
 		defer ethSvc.Close()
 
 		seedPhrase := "yellow dolphin robot express road develop repair neutral rate tide economy section"
@@ -65,7 +67,8 @@ func TestServiceEth_GetBalance(t *testing.T) {
 func TestServiceEth_SendTx(t *testing.T) {
 	t.Run("ShouldSendTx", func(t *testing.T) {
 		// INIT
-		ethSvc := eth.NewEthereumImpl(config.Instance())
+		ethSvc, err := eth.NewEthereumImpl(config.Instance())
+		require.NoError(t, err)
 		defer ethSvc.Close()
 
 		seedPhrase := "yellow dolphin robot express road develop repair neutral rate tide economy section"
@@ -106,7 +109,8 @@ func TestServiceEth_SendTx(t *testing.T) {
 func TestServiceEth_GetTx(t *testing.T) {
 	t.Run("ShouldGetTx", func(t *testing.T) {
 		// INIT
-		ethSvc := eth.NewEthereumImpl(config.Instance())
+		ethSvc, err := eth.NewEthereumImpl(config.Instance())
+		require.NoError(t, err)
 		defer ethSvc.Close()
 
 		txHash := common.HexToHash("0x47379d2fef6de3fbfad1fc023391c8b35ec15639dd1bcb97f2709392505bb3ba")
@@ -21,5 +21,7 @@ type Ethereum interface {
 	CheckAddress(address string) error
 	GetBlockInformation(ctx context.Context, txHash *common.Hash) (*model.Transaction, error)
 	GetTransactionPending(ctx context.Context, txHash *common.Hash) (*model.Transaction, *bool, error)
+	// GetNonce is the nonce of the next transaction of the address, counting only the mined ones
+	GetNonce(ctx context.Context, address common.Address) (uint64, error)
 	SubscribePendingTransactions(ctx context.Context) (subs *rpc.ClientSubscription, txch chan *types.Transaction, err error)
 }
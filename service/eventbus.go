@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aalexanderkevin/crypto-wallet/model"
+)
+
+type EventBus interface {
+	Publish(ctx context.Context, event model.WalletEvent)
+	// Subscribe returns the events of the given addresses, starting after resumeToken when it is provided.
+	// The channel is closed when the subscriber falls too far behind, the caller should resubscribe with the last resume token.
+	Subscribe(ctx context.Context, addresses []string, resumeToken *string) (<-chan model.WalletEvent, func(), error)
+}
@@ -0,0 +1,165 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+
+	"github.com/segmentio/ksuid"
+	"github.com/sirupsen/logrus"
+)
+
+// MemoryBus is an in-process event bus, it keeps the last bufferSize events so a subscriber can resume
+// after reconnecting. Events are not shared between processes and are lost on restart, it is meant for tests and
+// single process setups, see PostgresBus otherwise.
+type MemoryBus struct {
+	mu          sync.Mutex
+	epoch       string
+	sequence    uint64
+	bufferSize  int
+	buffer      []model.WalletEvent
+	subscribers map[int]*subscriber
+	nextId      int
+}
+
+type subscriber struct {
+	addresses map[string]struct{}
+	ch        chan model.WalletEvent
+}
+
+func NewMemoryBus(bufferSize int) service.EventBus {
+	return &MemoryBus{
+		epoch:       ksuid.New().String(),
+		bufferSize:  bufferSize,
+		subscribers: map[int]*subscriber{},
+	}
+}
+
+func (m *MemoryBus) Publish(ctx context.Context, event model.WalletEvent) {
+	logger := helper.GetLogger(ctx).WithField("method", "Service.EventBus.Publish")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sequence++
+	event.Sequence = m.sequence
+	event.ResumeToken = helper.Pointer(fmt.Sprintf("%s-%d", m.epoch, m.sequence))
+	if event.CreatedAt == nil {
+		event.CreatedAt = helper.Pointer(time.Now())
+	}
+
+	m.buffer = append(m.buffer, event)
+	if len(m.buffer) > m.bufferSize {
+		m.buffer = m.buffer[len(m.buffer)-m.bufferSize:]
+	}
+
+	fanOut(logger, m.subscribers, event)
+}
+
+func (m *MemoryBus) Subscribe(ctx context.Context, addresses []string, resumeToken *string) (<-chan model.WalletEvent, func(), error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sub := newSubscriber(addresses, m.bufferSize+64)
+
+	if resumeToken != nil && *resumeToken != "" {
+		after, err := m.parseResumeToken(*resumeToken)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, event := range m.buffer {
+			if event.Sequence > after && sub.match(event) {
+				sub.ch <- event
+			}
+		}
+	}
+
+	id := m.nextId
+	m.nextId++
+	m.subscribers[id] = sub
+
+	unsubscribe := func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		if _, ok := m.subscribers[id]; ok {
+			close(sub.ch)
+			delete(m.subscribers, id)
+		}
+	}
+
+	return sub.ch, unsubscribe, nil
+}
+
+// parseResumeToken returns the sequence of the token, it fails when the token was issued by another process
+// or the events after it are no longer buffered.
+func (m *MemoryBus) parseResumeToken(token string) (uint64, error) {
+	parts := strings.SplitN(token, "-", 2)
+	if len(parts) != 2 {
+		return 0, model.NewBadRequestError(helper.Pointer("invalid resume token"))
+	}
+
+	sequence, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, model.NewBadRequestError(helper.Pointer("invalid resume token"))
+	}
+
+	if parts[0] != m.epoch || sequence > m.sequence {
+		return 0, model.NewResumeTokenExpiredError()
+	}
+
+	if len(m.buffer) > 0 && sequence+1 < m.buffer[0].Sequence {
+		return 0, model.NewResumeTokenExpiredError()
+	}
+
+	return sequence, nil
+}
+
+// fanOut sends the event to the matching subscribers, a subscriber whose channel is full is closed and removed so it
+// does not hold the others back
+func fanOut(logger *logrus.Entry, subscribers map[int]*subscriber, event model.WalletEvent) {
+	for id, sub := range subscribers {
+		if !sub.match(event) {
+			continue
+		}
+
+		select {
+		case sub.ch <- event:
+		default:
+			// drop the slow subscriber, it can resume from its last received token
+			logger.WithField("subscriber", id).Warn("subscriber is too slow, closing subscription")
+			close(sub.ch)
+			delete(subscribers, id)
+		}
+	}
+}
+
+func newSubscriber(addresses []string, size int) *subscriber {
+	sub := &subscriber{
+		addresses: map[string]struct{}{},
+		ch:        make(chan model.WalletEvent, size),
+	}
+	for _, address := range addresses {
+		sub.addresses[address] = struct{}{}
+	}
+
+	return sub
+}
+
+func (s *subscriber) match(event model.WalletEvent) bool {
+	for _, address := range event.Addresses {
+		if _, ok := s.addresses[address]; ok {
+			return true
+		}
+	}
+
+	return false
+}
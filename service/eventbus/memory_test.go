@@ -0,0 +1,96 @@
+package eventbus_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/service/eventbus"
+
+	"github.com/stretchr/testify/require"
+)
+
+func fakeEvent(txId string, address string) model.WalletEvent {
+	return model.WalletEvent{
+		Type:          helper.Pointer(model.EventDepositDetected),
+		Token:         helper.Pointer("eth"),
+		TransactionId: helper.Pointer(txId),
+		Addresses:     []string{address},
+	}
+}
+
+func TestMemoryBus_Subscribe(t *testing.T) {
+	t.Run("ShouldOnlyReceiveEventsOfTheSubscribedAddresses", func(t *testing.T) {
+		// INIT
+		bus := eventbus.NewMemoryBus(10)
+
+		// CODE UNDER TEST
+		events, unsubscribe, err := bus.Subscribe(context.TODO(), []string{"address-1"}, nil)
+		require.NoError(t, err)
+		defer unsubscribe()
+
+		bus.Publish(context.TODO(), fakeEvent("tx-1", "address-2"))
+		bus.Publish(context.TODO(), fakeEvent("tx-2", "address-1"))
+
+		// EXPECTATION
+		event := <-events
+		require.Equal(t, "tx-2", *event.TransactionId)
+		require.NotNil(t, event.ResumeToken)
+		require.Len(t, events, 0)
+	})
+
+	t.Run("ShouldReplayMissedEvents_WhenResumeTokenProvided", func(t *testing.T) {
+		// INIT
+		bus := eventbus.NewMemoryBus(10)
+		events, unsubscribe, err := bus.Subscribe(context.TODO(), []string{"address-1"}, nil)
+		require.NoError(t, err)
+
+		bus.Publish(context.TODO(), fakeEvent("tx-1", "address-1"))
+		first := <-events
+		unsubscribe()
+
+		bus.Publish(context.TODO(), fakeEvent("tx-2", "address-1"))
+		bus.Publish(context.TODO(), fakeEvent("tx-3", "address-1"))
+
+		// CODE UNDER TEST
+		events, unsubscribe, err = bus.Subscribe(context.TODO(), []string{"address-1"}, first.ResumeToken)
+		require.NoError(t, err)
+		defer unsubscribe()
+
+		// EXPECTATION
+		require.Equal(t, "tx-2", *(<-events).TransactionId)
+		require.Equal(t, "tx-3", *(<-events).TransactionId)
+	})
+
+	t.Run("ShouldReturnError_WhenResumeTokenIsNoLongerBuffered", func(t *testing.T) {
+		// INIT
+		bus := eventbus.NewMemoryBus(1)
+		events, unsubscribe, err := bus.Subscribe(context.TODO(), []string{"address-1"}, nil)
+		require.NoError(t, err)
+
+		bus.Publish(context.TODO(), fakeEvent("tx-1", "address-1"))
+		first := <-events
+		unsubscribe()
+
+		bus.Publish(context.TODO(), fakeEvent("tx-2", "address-1"))
+		bus.Publish(context.TODO(), fakeEvent("tx-3", "address-1"))
+
+		// CODE UNDER TEST
+		_, _, err = bus.Subscribe(context.TODO(), []string{"address-1"}, first.ResumeToken)
+
+		// EXPECTATION
+		require.EqualError(t, err, model.NewResumeTokenExpiredError().Error())
+	})
+
+	t.Run("ShouldReturnError_WhenResumeTokenIsFromAnotherProcess", func(t *testing.T) {
+		// INIT
+		bus := eventbus.NewMemoryBus(10)
+
+		// CODE UNDER TEST
+		_, _, err := bus.Subscribe(context.TODO(), []string{"address-1"}, helper.Pointer("2WxbJpbGNsE6aUoU9Gtn1NjqW0P-1"))
+
+		// EXPECTATION
+		require.EqualError(t, err, model.NewResumeTokenExpiredError().Error())
+	})
+}
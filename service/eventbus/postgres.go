@@ -0,0 +1,223 @@
+package eventbus
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+)
+
+// postgresPollBatchSize is the number of events read at once when new events are picked up
+const postgresPollBatchSize = 500
+
+// PostgresBus shares the events between the processes through the wallet_events table, a published event is stored
+// and announced with NOTIFY, every process listens and hands the new events to its own subscribers.
+//
+// The resume token of an event is its id in the table. The ids are committed in increasing order, so a subscriber
+// resuming from a token receives every event of its addresses published after it, in order and without gaps, as long
+// as the events are still retained. A token older than the retained events, or one that does not exist yet, is
+// answered with a ResumeTokenExpired error and the subscriber has to start again without a token.
+type PostgresBus struct {
+	repo         repository.WalletEvent
+	notifier     service.Notifier
+	bufferSize   int
+	pollInterval time.Duration
+
+	mu          sync.Mutex
+	cancel      context.CancelFunc
+	last        uint64
+	subscribers map[int]*subscriber
+	nextId      int
+}
+
+// NewPostgresBus returns a bus sharing the events through Postgres, pollInterval is how often the table is read
+// when no notification arrives, it covers the notifications lost while reconnecting
+func NewPostgresBus(repo repository.WalletEvent, notifier service.Notifier, bufferSize int, pollInterval time.Duration) *PostgresBus {
+	return &PostgresBus{
+		repo:         repo,
+		notifier:     notifier,
+		bufferSize:   bufferSize,
+		pollInterval: pollInterval,
+		subscribers:  map[int]*subscriber{},
+	}
+}
+
+func (p *PostgresBus) Publish(ctx context.Context, event model.WalletEvent) {
+	if event.CreatedAt == nil {
+		event.CreatedAt = helper.Pointer(time.Now())
+	}
+
+	if _, err := p.repo.Add(ctx, &event); err != nil {
+		helper.GetLogger(ctx).WithField("method", "Service.EventBus.Publish").WithError(err).Warn("failed add wallet event")
+	}
+}
+
+func (p *PostgresBus) Subscribe(ctx context.Context, addresses []string, resumeToken *string) (<-chan model.WalletEvent, func(), error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.start(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	sub := newSubscriber(addresses, p.bufferSize+64)
+	if resumeToken != nil && *resumeToken != "" {
+		if err := p.replay(ctx, sub, addresses, *resumeToken); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	id := p.nextId
+	p.nextId++
+	p.subscribers[id] = sub
+
+	unsubscribe := func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		if _, ok := p.subscribers[id]; ok {
+			close(sub.ch)
+			delete(p.subscribers, id)
+		}
+	}
+
+	return sub.ch, unsubscribe, nil
+}
+
+// Close stops listening for new events, the subscriptions stay open but do not receive anything anymore
+func (p *PostgresBus) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancel != nil {
+		p.cancel()
+	}
+}
+
+// start begins listening on the first subscription, a process only publishing does not hold a connection for it.
+// It must be called with the lock held
+func (p *PostgresBus) start(ctx context.Context) error {
+	if p.cancel != nil {
+		return nil
+	}
+
+	_, last, err := p.repo.Bounds(ctx)
+	if err != nil {
+		helper.GetLogger(ctx).WithField("method", "Service.EventBus.Subscribe").WithError(err).Warn("failed get wallet event bounds")
+		return err
+	}
+	p.last = last
+
+	listenCtx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	go p.listen(listenCtx)
+
+	return nil
+}
+
+// replay sends the events after the token that were already handed to the subscribers, the listener does not
+// deliver while the lock is held so the subscriber continues from p.last without missing or repeating one.
+// It must be called with the lock held
+func (p *PostgresBus) replay(ctx context.Context, sub *subscriber, addresses []string, token string) error {
+	after, err := strconv.ParseUint(token, 10, 64)
+	if err != nil {
+		return model.NewBadRequestError(helper.Pointer("invalid resume token"))
+	}
+	if after > p.last {
+		return model.NewResumeTokenExpiredError()
+	}
+	if after == p.last {
+		return nil
+	}
+
+	first, _, err := p.repo.Bounds(ctx)
+	if err != nil {
+		return err
+	}
+	// the events right after the token were removed by the retention
+	if first == 0 || after+1 < first {
+		return model.NewResumeTokenExpiredError()
+	}
+
+	events, err := p.repo.ListAfter(ctx, after, addresses, cap(sub.ch))
+	if err != nil {
+		return err
+	}
+	if len(events) == cap(sub.ch) && events[len(events)-1].Sequence < p.last {
+		// too far behind to be replayed at once
+		return model.NewResumeTokenExpiredError()
+	}
+
+	for _, event := range events {
+		if event.Sequence > p.last {
+			break
+		}
+		sub.ch <- event
+	}
+
+	return nil
+}
+
+func (p *PostgresBus) listen(ctx context.Context) {
+	logger := helper.GetLogger(ctx).WithField("method", "Service.EventBus.listen")
+
+	attempts := 0
+	for {
+		notifications, err := p.notifier.Listen(ctx, model.WalletEventsChannel)
+		if err != nil {
+			attempts++
+			logger.WithError(err).Warn("failed listen wallet events")
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(helper.Backoff(attempts, time.Second, time.Minute)):
+			}
+			continue
+		}
+		attempts = 0
+
+		// catch up on what was published while not listening
+		p.poll(ctx)
+		for open := true; open; {
+			select {
+			case <-ctx.Done():
+				return
+			case _, open = <-notifications:
+			case <-time.After(p.pollInterval):
+			}
+			p.poll(ctx)
+		}
+	}
+}
+
+// poll hands the events after p.last to the subscribers, the listener is the only one moving p.last forward
+func (p *PostgresBus) poll(ctx context.Context) {
+	logger := helper.GetLogger(ctx).WithField("method", "Service.EventBus.poll")
+
+	for {
+		events, err := p.repo.ListAfter(ctx, p.last, nil, postgresPollBatchSize)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.WithError(err).Warn("failed list wallet events")
+			}
+			return
+		}
+
+		p.mu.Lock()
+		for _, event := range events {
+			fanOut(logger, p.subscribers, event)
+			p.last = event.Sequence
+		}
+		p.mu.Unlock()
+
+		if len(events) < postgresPollBatchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,637 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+)
+
+// errReconnecting is the error of an endpoint waiting for its next reconnection attempt
+var errReconnecting = errors.New("endpoint is reconnecting")
+
+// endpointStatus publishes the status of the endpoints of every pool under chain_endpoints on /debug/vars
+var endpointStatus = expvar.NewMap("chain_endpoints")
+
+// Client tells a pool how to reach the nodes of a chain
+type Client[T any] struct {
+	// Dial connects to an endpoint, it is called on the first use of the endpoint
+	Dial func(ctx context.Context, url string) (T, error)
+	// Height is the current block of the node, it is used by the health check
+	Height func(ctx context.Context, client T) (int64, error)
+	// IsFailure tells an endpoint failure from an error of the request itself, only failures move the call to the
+	// next endpoint and count in the error rate
+	IsFailure func(err error) bool
+	Close     func(client T)
+}
+
+// Status is the health of an endpoint as last seen by its pool, the url is redacted as providers carry the api key
+// in it
+type Status struct {
+	Url           string  `json:"url"`
+	Priority      int     `json:"priority"`
+	Active        bool    `json:"active"`
+	Healthy       bool    `json:"healthy"`
+	Height        int64   `json:"height"`
+	Lag           int64   `json:"lag"`
+	LatencyMillis int64   `json:"latency_ms"`
+	ErrorRate     float64 `json:"error_rate"`
+	Reconnecting  bool    `json:"reconnecting"`
+	LastError     string  `json:"last_error,omitempty"`
+}
+
+// PoolStatus is the status of a pool, a pool is degraded while none of its endpoints is connected
+type PoolStatus struct {
+	Degraded  bool     `json:"degraded"`
+	Endpoints []Status `json:"endpoints"`
+}
+
+// Pool spreads the calls of a chain over its endpoints. The healthy endpoint with the lowest priority serves the
+// calls, a call failing on it moves to the next one. A background health check marks endpoints unhealthy when
+// they lag behind the highest block seen, answer too slowly or fail too often. An endpoint that failed is closed
+// and dialed again in the background with an exponential backoff, while none is connected the pool is degraded
+// and its calls fail right away with an unavailable error.
+type Pool[T any] struct {
+	name   string
+	client Client[T]
+	config config.Failover
+
+	mu        sync.Mutex
+	endpoints []*endpoint[T]
+	active    *endpoint[T]
+	best      int64
+	degraded  bool
+
+	stop       chan struct{}
+	done       chan struct{}
+	reconnects sync.WaitGroup
+}
+
+type endpoint[T any] struct {
+	url      string
+	priority int
+	// redactedUrl is the url without its credentials, the one logged and published
+	redactedUrl string
+
+	client       T
+	dialed       bool
+	reconnecting bool
+	healthy      bool
+	height       int64
+	latency      time.Duration
+	lastErr      error
+
+	// results holds whether the last calls failed, next is the slot the next result is written to
+	results []bool
+	next    int
+	count   int
+}
+
+// NewPool starts the health check of the endpoints, name identifies the pool in logs and metrics. Endpoints are
+// healthy until the first check says otherwise, nothing is dialed before it is used.
+func NewPool[T any](name string, endpoints []config.Endpoint, cfg config.Failover, client Client[T]) *Pool[T] {
+	p := &Pool[T]{
+		name:   name,
+		client: client,
+		config: cfg,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	window := cfg.ErrorWindow
+	if window <= 0 {
+		window = 1
+	}
+	for _, e := range endpoints {
+		p.endpoints = append(p.endpoints, &endpoint[T]{
+			url:         e.Url,
+			priority:    e.Priority,
+			redactedUrl: redactUrl(e.Url),
+			healthy:     true,
+			results:     make([]bool, window),
+		})
+	}
+	sort.SliceStable(p.endpoints, func(i, j int) bool {
+		return p.endpoints[i].priority < p.endpoints[j].priority
+	})
+
+	p.degraded = len(p.endpoints) == 0
+	endpointStatus.Set(name, expvar.Func(func() interface{} {
+		return PoolStatus{Degraded: p.Degraded(), Endpoints: p.Status()}
+	}))
+
+	if client.Height != nil && cfg.HealthCheckIntervalSeconds > 0 && len(p.endpoints) > 0 {
+		go p.run()
+	} else {
+		close(p.done)
+	}
+
+	return p
+}
+
+// Close stops the health check and the reconnections and closes the dialed endpoints
+func (p *Pool[T]) Close() {
+	select {
+	case <-p.stop:
+		return
+	default:
+		close(p.stop)
+	}
+	<-p.done
+	p.reconnects.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, e := range p.endpoints {
+		if e.dialed && p.client.Close != nil {
+			p.client.Close(e.client)
+		}
+		e.dialed = false
+	}
+}
+
+// Degraded tells whether every endpoint of the pool is waiting to reconnect
+func (p *Pool[T]) Degraded() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.degraded
+}
+
+// Status is the health of every endpoint by priority
+func (p *Pool[T]) Status() []Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	statuses := make([]Status, 0, len(p.endpoints))
+	for _, e := range p.endpoints {
+		status := Status{
+			Url:           e.redactedUrl,
+			Priority:      e.priority,
+			Active:        e == p.active,
+			Healthy:       e.healthy,
+			Reconnecting:  e.reconnecting,
+			Height:        e.height,
+			LatencyMillis: e.latency.Milliseconds(),
+			ErrorRate:     e.errorRate(),
+		}
+		if e.height > 0 && p.best > e.height {
+			status.Lag = p.best - e.height
+		}
+		if e.lastErr != nil {
+			status.LastError = e.redactError(e.lastErr)
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// redactError is the message of the error with the url of the endpoint redacted, the errors of the http client quote
+// the url
+func (e *endpoint[T]) redactError(err error) string {
+	return strings.ReplaceAll(err.Error(), e.url, e.redactedUrl)
+}
+
+// redactUrl removes the credentials and the query of the url, an url that cannot be parsed is redacted entirely
+func redactUrl(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return "redacted"
+	}
+
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	return u.String()
+}
+
+// Call runs fn on the preferred endpoint and on the next ones while it fails on them. An unavailable error is
+// returned when it fails on every endpoint, or right away when the pool is degraded.
+func Call[T any, R any](ctx context.Context, p *Pool[T], fn func(client T) (R, error)) (R, error) {
+	var zero R
+
+	candidates := p.candidates()
+	if len(candidates) == 0 {
+		return zero, model.NewUnavailableError(helper.Pointer(fmt.Sprintf("%s is unavailable, no endpoint is connected", p.name)))
+	}
+
+	var lastErr error
+	for _, e := range candidates {
+		if ctx.Err() != nil {
+			return zero, ctx.Err()
+		}
+
+		client, err := p.dial(ctx, e)
+		if err != nil {
+			p.record(ctx, e, 0, err, true)
+			lastErr = err
+			continue
+		}
+
+		start := time.Now()
+		res, err := fn(client)
+		failed := err != nil && p.isFailure(err)
+		p.record(ctx, e, time.Since(start), err, failed)
+		if failed {
+			lastErr = err
+			continue
+		}
+
+		return res, err
+	}
+
+	return zero, model.NewUnavailableError(helper.Pointer(fmt.Sprintf("%s is unavailable: %s", p.name, lastErr)))
+}
+
+// Do is Call for the calls only returning an error
+func Do[T any](ctx context.Context, p *Pool[T], fn func(client T) error) error {
+	_, err := Call(ctx, p, func(client T) (struct{}, error) {
+		return struct{}{}, fn(client)
+	})
+	return err
+}
+
+// candidates are the healthy endpoints by priority followed by the unhealthy ones, an unhealthy endpoint is
+// still better than failing the call. The endpoints waiting to reconnect are left out.
+func (p *Pool[T]) candidates() []*endpoint[T] {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	candidates := make([]*endpoint[T], 0, len(p.endpoints))
+	for _, e := range p.endpoints {
+		if e.healthy && !e.reconnecting {
+			candidates = append(candidates, e)
+		}
+	}
+	for _, e := range p.endpoints {
+		if !e.healthy && !e.reconnecting {
+			candidates = append(candidates, e)
+		}
+	}
+
+	return candidates
+}
+
+// dial connects the endpoint on its first use, an endpoint failing to connect is left to the reconnection
+func (p *Pool[T]) dial(ctx context.Context, e *endpoint[T]) (T, error) {
+	var zero T
+
+	p.mu.Lock()
+	if e.dialed {
+		defer p.mu.Unlock()
+		return e.client, nil
+	}
+	if e.reconnecting {
+		p.mu.Unlock()
+		return zero, errReconnecting
+	}
+	p.mu.Unlock()
+
+	client, err := p.client.Dial(ctx, e.url)
+	if err != nil {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.drop(ctx, e, err)
+		return zero, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if e.dialed {
+		// another call dialed it first
+		if p.client.Close != nil {
+			p.client.Close(client)
+		}
+		return e.client, nil
+	}
+	e.client = client
+	e.dialed = true
+
+	return client, nil
+}
+
+// record adds the outcome of a call to the error rate of the endpoint, an endpoint going over the maximum error
+// rate is unhealthy right away instead of on the next check. The endpoint that answered becomes the active one.
+func (p *Pool[T]) record(ctx context.Context, e *endpoint[T], latency time.Duration, err error, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e.push(failed)
+	if latency > 0 {
+		e.latency = latency
+	}
+
+	if !failed {
+		p.activate(ctx, e)
+		return
+	}
+
+	if e.healthy && e.errorRate() > p.config.MaxErrorRate {
+		p.setHealthy(ctx, e, false, "error rate")
+	}
+	p.drop(ctx, e, err)
+}
+
+// drop closes an endpoint that failed and starts its reconnection, the caller holds the lock
+func (p *Pool[T]) drop(ctx context.Context, e *endpoint[T], err error) {
+	e.lastErr = err
+	if e.dialed {
+		if p.client.Close != nil {
+			p.client.Close(e.client)
+		}
+		var zero T
+		e.client = zero
+		e.dialed = false
+	}
+
+	if e.reconnecting {
+		return
+	}
+	select {
+	case <-p.stop:
+		return
+	default:
+	}
+
+	e.reconnecting = true
+	p.reconnects.Add(1)
+	go p.reconnect(e)
+	p.updateDegraded(ctx)
+}
+
+// reconnect dials the endpoint until it answers again, waiting longer after every failed attempt
+func (p *Pool[T]) reconnect(e *endpoint[T]) {
+	defer p.reconnects.Done()
+
+	ctx := context.Background()
+	logger := helper.GetLogger(ctx).
+		WithField("method", "Service.Failover.reconnect").
+		WithField("pool", p.name).
+		WithField("endpoint", e.redactedUrl)
+	base := time.Duration(p.config.ReconnectBaseMillis) * time.Millisecond
+	max := time.Duration(p.config.ReconnectMaxSeconds) * time.Second
+
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-p.stop:
+			return
+		case <-time.After(helper.Backoff(attempt, base, max)):
+		}
+
+		client, err := p.connect(ctx, e)
+		if err != nil {
+			logger.WithField("error", e.redactError(err)).WithField("attempt", attempt).Warn("Failed reconnect endpoint")
+			p.mu.Lock()
+			e.lastErr = err
+			p.mu.Unlock()
+			continue
+		}
+
+		p.mu.Lock()
+		e.client = client
+		e.dialed = true
+		e.reconnecting = false
+		p.updateDegraded(ctx)
+		p.mu.Unlock()
+
+		logger.WithField("attempt", attempt).Info("endpoint reconnected")
+		return
+	}
+}
+
+// connect dials the endpoint and makes sure it answers, dialing alone does not reach the node on every transport
+func (p *Pool[T]) connect(ctx context.Context, e *endpoint[T]) (T, error) {
+	var zero T
+
+	if p.config.HealthCheckTimeoutSeconds > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(p.config.HealthCheckTimeoutSeconds)*time.Second)
+		defer cancel()
+	}
+
+	client, err := p.client.Dial(ctx, e.url)
+	if err != nil {
+		return zero, err
+	}
+
+	if p.client.Height != nil {
+		if _, err := p.client.Height(ctx, client); err != nil {
+			if p.client.Close != nil {
+				p.client.Close(client)
+			}
+			return zero, err
+		}
+	}
+
+	return client, nil
+}
+
+// updateDegraded logs the pool losing or getting back its last connected endpoint, the caller holds the lock
+func (p *Pool[T]) updateDegraded(ctx context.Context) {
+	degraded := true
+	for _, e := range p.endpoints {
+		if !e.reconnecting {
+			degraded = false
+			break
+		}
+	}
+
+	if degraded == p.degraded {
+		return
+	}
+	p.degraded = degraded
+
+	logger := helper.GetLogger(ctx).WithField("method", "Service.Failover.updateDegraded").WithField("pool", p.name)
+	if degraded {
+		logger.Error("every endpoint is down, the chain is degraded")
+		return
+	}
+	logger.Info("an endpoint is connected again, the chain is no longer degraded")
+}
+
+func (p *Pool[T]) isFailure(err error) bool {
+	if p.client.IsFailure == nil {
+		return true
+	}
+	return p.client.IsFailure(err)
+}
+
+func (p *Pool[T]) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(time.Duration(p.config.HealthCheckIntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		p.Check(context.Background())
+
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check asks every endpoint for its height and updates their health, it runs on every health check interval
+func (p *Pool[T]) Check(ctx context.Context) {
+	type result struct {
+		height  int64
+		latency time.Duration
+		err     error
+	}
+
+	results := make([]result, len(p.endpoints))
+	var wg sync.WaitGroup
+	for i, e := range p.endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint[T]) {
+			defer wg.Done()
+
+			checkCtx := ctx
+			if p.config.HealthCheckTimeoutSeconds > 0 {
+				var cancel context.CancelFunc
+				checkCtx, cancel = context.WithTimeout(ctx, time.Duration(p.config.HealthCheckTimeoutSeconds)*time.Second)
+				defer cancel()
+			}
+
+			client, err := p.dial(checkCtx, e)
+			if err != nil {
+				results[i] = result{err: err}
+				return
+			}
+
+			start := time.Now()
+			height, err := p.client.Height(checkCtx, client)
+			results[i] = result{height: height, latency: time.Since(start), err: err}
+		}(i, e)
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, e := range p.endpoints {
+		if errors.Is(results[i].err, errReconnecting) {
+			continue
+		}
+		e.push(results[i].err != nil)
+		if results[i].err != nil {
+			if p.isFailure(results[i].err) {
+				p.drop(ctx, e, results[i].err)
+			}
+			e.lastErr = results[i].err
+			continue
+		}
+		e.height = results[i].height
+		e.latency = results[i].latency
+		if e.height > p.best {
+			p.best = e.height
+		}
+	}
+
+	for i, e := range p.endpoints {
+		healthy, reason := true, ""
+		switch {
+		case results[i].err != nil:
+			healthy, reason = false, "unreachable"
+		case p.config.MaxBlockLag > 0 && p.best-e.height > p.config.MaxBlockLag:
+			healthy, reason = false, "block lag"
+		case p.config.MaxLatencyMillis > 0 && e.latency.Milliseconds() > p.config.MaxLatencyMillis:
+			healthy, reason = false, "latency"
+		case e.errorRate() > p.config.MaxErrorRate:
+			healthy, reason = false, "error rate"
+		}
+		p.setHealthy(ctx, e, healthy, reason)
+	}
+
+	for _, e := range p.endpoints {
+		if e.healthy {
+			p.activate(ctx, e)
+			break
+		}
+	}
+}
+
+// setHealthy logs the endpoints changing health, the caller holds the lock
+func (p *Pool[T]) setHealthy(ctx context.Context, e *endpoint[T], healthy bool, reason string) {
+	if e.healthy == healthy {
+		return
+	}
+	e.healthy = healthy
+
+	logger := helper.GetLogger(ctx).
+		WithField("method", "Service.Failover.setHealthy").
+		WithField("pool", p.name).
+		WithField("endpoint", e.redactedUrl).
+		WithField("priority", e.priority).
+		WithField("height", e.height).
+		WithField("best_height", p.best).
+		WithField("latency_ms", e.latency.Milliseconds()).
+		WithField("error_rate", e.errorRate())
+	if healthy {
+		logger.Info("endpoint is healthy again")
+		return
+	}
+	if e.lastErr != nil {
+		logger = logger.WithField("error", e.redactError(e.lastErr))
+	}
+	logger.WithField("reason", reason).Warn("endpoint is unhealthy")
+}
+
+// activate logs the pool moving to another endpoint, the caller holds the lock
+func (p *Pool[T]) activate(ctx context.Context, e *endpoint[T]) {
+	if p.active == e {
+		return
+	}
+
+	logger := helper.GetLogger(ctx).
+		WithField("method", "Service.Failover.activate").
+		WithField("pool", p.name).
+		WithField("endpoint", e.redactedUrl).
+		WithField("priority", e.priority)
+	if p.active != nil {
+		logger = logger.WithField("previous_endpoint", p.active.redactedUrl)
+	}
+	if !e.healthy {
+		logger.Warn("no healthy endpoint left, using an unhealthy one")
+	} else {
+		logger.Info("switched active endpoint")
+	}
+
+	p.active = e
+}
+
+func (e *endpoint[T]) push(failed bool) {
+	e.results[e.next] = failed
+	e.next = (e.next + 1) % len(e.results)
+	if e.count < len(e.results) {
+		e.count++
+	}
+}
+
+func (e *endpoint[T]) errorRate() float64 {
+	if e.count == 0 {
+		return 0
+	}
+
+	failures := 0
+	for i := 0; i < e.count; i++ {
+		if e.results[i] {
+			failures++
+		}
+	}
+
+	return float64(failures) / float64(e.count)
+}
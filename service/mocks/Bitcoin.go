@@ -32,25 +32,39 @@ func (_m *Bitcoin) CheckAddress(address *string) bool {
 	return r0
 }
 
-// CreateWebhookConfirmedTx provides a mock function with given fields: ctx, address
-func (_m *Bitcoin) CreateWebhookConfirmedTx(ctx context.Context, address *string) (*gobcy.Hook, error) {
-	ret := _m.Called(ctx, address)
+// Coin provides a mock function with given fields:
+func (_m *Bitcoin) Coin() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// CreateWebhookConfirmedTx provides a mock function with given fields: ctx, address, confirmations
+func (_m *Bitcoin) CreateWebhookConfirmedTx(ctx context.Context, address *string, confirmations int) (*gobcy.Hook, error) {
+	ret := _m.Called(ctx, address, confirmations)
 
 	var r0 *gobcy.Hook
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *string) (*gobcy.Hook, error)); ok {
-		return rf(ctx, address)
+	if rf, ok := ret.Get(0).(func(context.Context, *string, int) (*gobcy.Hook, error)); ok {
+		return rf(ctx, address, confirmations)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *string) *gobcy.Hook); ok {
-		r0 = rf(ctx, address)
+	if rf, ok := ret.Get(0).(func(context.Context, *string, int) *gobcy.Hook); ok {
+		r0 = rf(ctx, address, confirmations)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*gobcy.Hook)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *string) error); ok {
-		r1 = rf(ctx, address)
+	if rf, ok := ret.Get(1).(func(context.Context, *string, int) error); ok {
+		r1 = rf(ctx, address, confirmations)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -98,6 +112,32 @@ func (_m *Bitcoin) GetBalance(ctx context.Context, address string) (*big.Int, er
 	return r0, r1
 }
 
+// GetCurrentBlock provides a mock function with given fields: ctx
+func (_m *Bitcoin) GetCurrentBlock(ctx context.Context) (*int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *int64); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTx provides a mock function with given fields: ctx, txhash
 func (_m *Bitcoin) GetTx(ctx context.Context, txhash string) (*gobcy.TX, error) {
 	ret := _m.Called(ctx, txhash)
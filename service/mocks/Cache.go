@@ -98,6 +98,30 @@ func (_m *Cache) Put(_a0 context.Context, _a1 string, _a2 string, _a3 time.Durat
 	return r0
 }
 
+// PutIfAbsent provides a mock function with given fields: ctx, key, data, ttl
+func (_m *Cache) PutIfAbsent(ctx context.Context, key string, data string, ttl time.Duration) (bool, error) {
+	ret := _m.Called(ctx, key, data, ttl)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) (bool, error)); ok {
+		return rf(ctx, key, data, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) bool); ok {
+		r0 = rf(ctx, key, data, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Duration) error); ok {
+		r1 = rf(ctx, key, data, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetList provides a mock function with given fields: ctx, key, data, ttl
 func (_m *Cache) SetList(ctx context.Context, key string, data string, ttl *time.Duration) (int64, error) {
 	ret := _m.Called(ctx, key, data, ttl)
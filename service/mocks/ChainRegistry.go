@@ -0,0 +1,81 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	service "github.com/aalexanderkevin/crypto-wallet/service"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ChainRegistry is an autogenerated mock type for the ChainRegistry type
+type ChainRegistry struct {
+	mock.Mock
+}
+
+// Chains provides a mock function with given fields:
+func (_m *ChainRegistry) Chains() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: chain
+func (_m *ChainRegistry) Get(chain string) (service.ChainAdapter, error) {
+	ret := _m.Called(chain)
+
+	var r0 service.ChainAdapter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (service.ChainAdapter, error)); ok {
+		return rf(chain)
+	}
+	if rf, ok := ret.Get(0).(func(string) service.ChainAdapter); ok {
+		r0 = rf(chain)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(service.ChainAdapter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(chain)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Register provides a mock function with given fields: adapter, aliases
+func (_m *ChainRegistry) Register(adapter service.ChainAdapter, aliases ...string) {
+	_va := make([]interface{}, len(aliases))
+	for _i := range aliases {
+		_va[_i] = aliases[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, adapter)
+	_ca = append(_ca, _va...)
+	_m.Called(_ca...)
+}
+
+// NewChainRegistry creates a new instance of ChainRegistry. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewChainRegistry(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ChainRegistry {
+	mock := &ChainRegistry{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,60 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "github.com/aalexanderkevin/crypto-wallet/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ConfirmationPolicy is an autogenerated mock type for the ConfirmationPolicy type
+type ConfirmationPolicy struct {
+	mock.Mock
+}
+
+// Apply provides a mock function with given fields: token, transaction
+func (_m *ConfirmationPolicy) Apply(token string, transaction *model.Transaction) {
+	_m.Called(token, transaction)
+}
+
+// MaxConfirmations provides a mock function with given fields: token
+func (_m *ConfirmationPolicy) MaxConfirmations(token string) int64 {
+	ret := _m.Called(token)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(string) int64); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	return r0
+}
+
+// RequiredConfirmations provides a mock function with given fields: token, amount
+func (_m *ConfirmationPolicy) RequiredConfirmations(token string, amount *int64) int64 {
+	ret := _m.Called(token, amount)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(string, *int64) int64); ok {
+		r0 = rf(token, amount)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	return r0
+}
+
+// NewConfirmationPolicy creates a new instance of ConfirmationPolicy. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewConfirmationPolicy(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ConfirmationPolicy {
+	mock := &ConfirmationPolicy{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
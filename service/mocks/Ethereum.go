@@ -119,6 +119,30 @@ func (_m *Ethereum) GetCurrentBlock(ctx context.Context) (*int64, error) {
 	return r0, r1
 }
 
+// GetNonce provides a mock function with given fields: ctx, address
+func (_m *Ethereum) GetNonce(ctx context.Context, address common.Address) (uint64, error) {
+	ret := _m.Called(ctx, address)
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address) (uint64, error)); ok {
+		return rf(ctx, address)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address) uint64); ok {
+		r0 = rf(ctx, address)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address) error); ok {
+		r1 = rf(ctx, address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTransactionPending provides a mock function with given fields: ctx, txHash
 func (_m *Ethereum) GetTransactionPending(ctx context.Context, txHash *common.Hash) (*model.Transaction, *bool, error) {
 	ret := _m.Called(ctx, txHash)
@@ -0,0 +1,69 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/aalexanderkevin/crypto-wallet/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EventBus is an autogenerated mock type for the EventBus type
+type EventBus struct {
+	mock.Mock
+}
+
+// Publish provides a mock function with given fields: ctx, event
+func (_m *EventBus) Publish(ctx context.Context, event model.WalletEvent) {
+	_m.Called(ctx, event)
+}
+
+// Subscribe provides a mock function with given fields: ctx, addresses, resumeToken
+func (_m *EventBus) Subscribe(ctx context.Context, addresses []string, resumeToken *string) (<-chan model.WalletEvent, func(), error) {
+	ret := _m.Called(ctx, addresses, resumeToken)
+
+	var r0 <-chan model.WalletEvent
+	var r1 func()
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, *string) (<-chan model.WalletEvent, func(), error)); ok {
+		return rf(ctx, addresses, resumeToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, *string) <-chan model.WalletEvent); ok {
+		r0 = rf(ctx, addresses, resumeToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan model.WalletEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, *string) func()); ok {
+		r1 = rf(ctx, addresses, resumeToken)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(func())
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, []string, *string) error); ok {
+		r2 = rf(ctx, addresses, resumeToken)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewEventBus creates a new instance of EventBus. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEventBus(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EventBus {
+	mock := &EventBus{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
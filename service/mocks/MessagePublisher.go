@@ -0,0 +1,47 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MessagePublisher is an autogenerated mock type for the MessagePublisher type
+type MessagePublisher struct {
+	mock.Mock
+}
+
+// Close provides a mock function with given fields:
+func (_m *MessagePublisher) Close() {
+	_m.Called()
+}
+
+// Publish provides a mock function with given fields: ctx, topic, key, value
+func (_m *MessagePublisher) Publish(ctx context.Context, topic string, key []byte, value []byte) error {
+	ret := _m.Called(ctx, topic, key, value)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte, []byte) error); ok {
+		r0 = rf(ctx, topic, key, value)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMessagePublisher creates a new instance of MessagePublisher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMessagePublisher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MessagePublisher {
+	mock := &MessagePublisher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
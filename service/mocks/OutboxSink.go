@@ -0,0 +1,48 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/aalexanderkevin/crypto-wallet/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OutboxSink is an autogenerated mock type for the OutboxSink type
+type OutboxSink struct {
+	mock.Mock
+}
+
+// Close provides a mock function with given fields:
+func (_m *OutboxSink) Close() {
+	_m.Called()
+}
+
+// Publish provides a mock function with given fields: ctx, event
+func (_m *OutboxSink) Publish(ctx context.Context, event model.OutboxEvent) error {
+	ret := _m.Called(ctx, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.OutboxEvent) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewOutboxSink creates a new instance of OutboxSink. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOutboxSink(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OutboxSink {
+	mock := &OutboxSink{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -40,6 +40,110 @@ func (_m *Tron) Close() {
 	_m.Called()
 }
 
+// DelegateResource provides a mock function with given fields: ctx, resource, amount, receiverAddress, wallet
+func (_m *Tron) DelegateResource(ctx context.Context, resource string, amount int64, receiverAddress string, wallet *model.TrxHdWallet) (*api.TransactionExtention, error) {
+	ret := _m.Called(ctx, resource, amount, receiverAddress, wallet)
+
+	var r0 *api.TransactionExtention
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, *model.TrxHdWallet) (*api.TransactionExtention, error)); ok {
+		return rf(ctx, resource, amount, receiverAddress, wallet)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, *model.TrxHdWallet) *api.TransactionExtention); ok {
+		r0 = rf(ctx, resource, amount, receiverAddress, wallet)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.TransactionExtention)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, string, *model.TrxHdWallet) error); ok {
+		r1 = rf(ctx, resource, amount, receiverAddress, wallet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EstimateTransferFee provides a mock function with given fields: ctx, txOpts, fromAddress
+func (_m *Tron) EstimateTransferFee(ctx context.Context, txOpts *model.TxOpts, fromAddress *string) (*model.TronFeeEstimate, error) {
+	ret := _m.Called(ctx, txOpts, fromAddress)
+
+	var r0 *model.TronFeeEstimate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.TxOpts, *string) (*model.TronFeeEstimate, error)); ok {
+		return rf(ctx, txOpts, fromAddress)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.TxOpts, *string) *model.TronFeeEstimate); ok {
+		r0 = rf(ctx, txOpts, fromAddress)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TronFeeEstimate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.TxOpts, *string) error); ok {
+		r1 = rf(ctx, txOpts, fromAddress)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FreezeBalance provides a mock function with given fields: ctx, resource, amount, wallet
+func (_m *Tron) FreezeBalance(ctx context.Context, resource string, amount int64, wallet *model.TrxHdWallet) (*api.TransactionExtention, error) {
+	ret := _m.Called(ctx, resource, amount, wallet)
+
+	var r0 *api.TransactionExtention
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, *model.TrxHdWallet) (*api.TransactionExtention, error)); ok {
+		return rf(ctx, resource, amount, wallet)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, *model.TrxHdWallet) *api.TransactionExtention); ok {
+		r0 = rf(ctx, resource, amount, wallet)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.TransactionExtention)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, *model.TrxHdWallet) error); ok {
+		r1 = rf(ctx, resource, amount, wallet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountResources provides a mock function with given fields: ctx, address
+func (_m *Tron) GetAccountResources(ctx context.Context, address *string) (*model.TronAccountResources, error) {
+	ret := _m.Called(ctx, address)
+
+	var r0 *model.TronAccountResources
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string) (*model.TronAccountResources, error)); ok {
+		return rf(ctx, address)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *string) *model.TronAccountResources); ok {
+		r0 = rf(ctx, address)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TronAccountResources)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *string) error); ok {
+		r1 = rf(ctx, address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBalance provides a mock function with given fields: ctx, address
 func (_m *Tron) GetBalance(ctx context.Context, address *string) (*int64, error) {
 	ret := _m.Called(ctx, address)
@@ -92,6 +196,32 @@ func (_m *Tron) GetConfirmedTxAddress(ctx context.Context, address *string) (*se
 	return r0, r1
 }
 
+// GetCurrentBlock provides a mock function with given fields: ctx
+func (_m *Tron) GetCurrentBlock(ctx context.Context) (*int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *int64); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTx provides a mock function with given fields: ctx, txhash
 func (_m *Tron) GetTx(ctx context.Context, txhash string) (*core.TransactionInfo, error) {
 	ret := _m.Called(ctx, txhash)
@@ -212,6 +342,58 @@ func (_m *Tron) SendTx(ctx context.Context, txOpts *model.TxOpts, wallet *model.
 	return r0, r1
 }
 
+// UndelegateResource provides a mock function with given fields: ctx, resource, amount, receiverAddress, wallet
+func (_m *Tron) UndelegateResource(ctx context.Context, resource string, amount int64, receiverAddress string, wallet *model.TrxHdWallet) (*api.TransactionExtention, error) {
+	ret := _m.Called(ctx, resource, amount, receiverAddress, wallet)
+
+	var r0 *api.TransactionExtention
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, *model.TrxHdWallet) (*api.TransactionExtention, error)); ok {
+		return rf(ctx, resource, amount, receiverAddress, wallet)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string, *model.TrxHdWallet) *api.TransactionExtention); ok {
+		r0 = rf(ctx, resource, amount, receiverAddress, wallet)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.TransactionExtention)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, string, *model.TrxHdWallet) error); ok {
+		r1 = rf(ctx, resource, amount, receiverAddress, wallet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnfreezeBalance provides a mock function with given fields: ctx, resource, amount, wallet
+func (_m *Tron) UnfreezeBalance(ctx context.Context, resource string, amount int64, wallet *model.TrxHdWallet) (*api.TransactionExtention, error) {
+	ret := _m.Called(ctx, resource, amount, wallet)
+
+	var r0 *api.TransactionExtention
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, *model.TrxHdWallet) (*api.TransactionExtention, error)); ok {
+		return rf(ctx, resource, amount, wallet)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, *model.TrxHdWallet) *api.TransactionExtention); ok {
+		r0 = rf(ctx, resource, amount, wallet)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.TransactionExtention)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, *model.TrxHdWallet) error); ok {
+		r1 = rf(ctx, resource, amount, wallet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewTron creates a new instance of Tron. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTron(t interface {
@@ -0,0 +1,11 @@
+package service
+
+import "context"
+
+// Notifier delivers the notifications of a channel shared between processes, such as Postgres LISTEN/NOTIFY
+type Notifier interface {
+	// Listen returns a channel that receives a value when a notification arrives, notifications coming in before the
+	// previous one was read are merged into one. The channel is closed when the connection is lost, the caller should
+	// listen again and look for what it may have missed
+	Listen(ctx context.Context, channel string) (<-chan struct{}, error)
+}
@@ -0,0 +1,40 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+)
+
+// BrokerSink publishes outbox events on "<topic>.<event type>", keyed by the aggregate id so that brokers
+// which partition by key keep the events of one transaction in order
+type BrokerSink struct {
+	topic     string
+	publisher service.MessagePublisher
+}
+
+func NewBrokerSink(config config.Config, publisher service.MessagePublisher) service.OutboxSink {
+	return &BrokerSink{
+		topic:     config.Outbox.Topic,
+		publisher: publisher,
+	}
+}
+
+func (b *BrokerSink) Publish(ctx context.Context, event model.OutboxEvent) error {
+	topic := b.topic + "." + helper.Val(event.EventType)
+
+	err := b.publisher.Publish(ctx, topic, []byte(helper.Val(event.AggregateId)), []byte(helper.Val(event.Payload)))
+	if err != nil {
+		helper.GetLogger(ctx).WithField("method", "Service.Outbox.BrokerSink.Publish").WithError(err).Warn("Failed publish outbox event")
+		return err
+	}
+
+	return nil
+}
+
+func (b *BrokerSink) Close() {
+	b.publisher.Close()
+}
@@ -0,0 +1,69 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+)
+
+const (
+	HeaderEventId       = "X-Outbox-Event-Id"
+	HeaderEventType     = "X-Outbox-Event-Type"
+	HeaderAggregateType = "X-Outbox-Aggregate-Type"
+	HeaderAggregateId   = "X-Outbox-Aggregate-Id"
+)
+
+type HttpSink struct {
+	url        string
+	httpClient *http.Client
+}
+
+func NewHttpSink(config config.Config) service.OutboxSink {
+	return &HttpSink{
+		url: config.Outbox.HttpUrl,
+		httpClient: &http.Client{
+			Timeout: time.Duration(config.Outbox.HttpTimeoutSeconds) * time.Second,
+		},
+	}
+}
+
+func (h *HttpSink) Publish(ctx context.Context, event model.OutboxEvent) error {
+	logger := helper.GetLogger(ctx).WithField("method", "Service.Outbox.HttpSink.Publish")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader([]byte(helper.Val(event.Payload))))
+	if err != nil {
+		logger.WithError(err).Warn("Failed create request")
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventId, strconv.FormatInt(helper.Val(event.Id), 10))
+	req.Header.Set(HeaderEventType, helper.Val(event.EventType))
+	req.Header.Set(HeaderAggregateType, helper.Val(event.AggregateType))
+	req.Header.Set(HeaderAggregateId, helper.Val(event.AggregateId))
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		logger.WithError(err).Warn("Failed post outbox event")
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return model.NewStatusNotOKError(resp.StatusCode, b)
+	}
+
+	return nil
+}
+
+func (h *HttpSink) Close() {
+}
@@ -0,0 +1,66 @@
+package outbox_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/service/outbox"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceOutbox_HttpSink(t *testing.T) {
+	event := model.OutboxEvent{
+		Id:            helper.Pointer(int64(7)),
+		AggregateType: helper.Pointer("eth_transactions"),
+		AggregateId:   helper.Pointer("0xabc"),
+		EventType:     helper.Pointer(model.OutboxTransactionStatusChanged),
+		Payload:       helper.Pointer(`{"token":"eth"}`),
+	}
+
+	t.Run("ShouldPostThePayload", func(t *testing.T) {
+		// INIT
+		var headers http.Header
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			headers = r.Header
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		sink := outbox.NewHttpSink(config.Config{Outbox: config.Outbox{HttpUrl: server.URL, HttpTimeoutSeconds: 5}})
+
+		// CODE UNDER TEST
+		err := sink.Publish(context.TODO(), event)
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.Equal(t, *event.Payload, string(body))
+		require.Equal(t, "7", headers.Get(outbox.HeaderEventId))
+		require.Equal(t, model.OutboxTransactionStatusChanged, headers.Get(outbox.HeaderEventType))
+		require.Equal(t, "0xabc", headers.Get(outbox.HeaderAggregateId))
+	})
+
+	t.Run("ShouldReturnError_WhenStatusIsNotSuccess", func(t *testing.T) {
+		// INIT
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		sink := outbox.NewHttpSink(config.Config{Outbox: config.Outbox{HttpUrl: server.URL, HttpTimeoutSeconds: 5}})
+
+		// CODE UNDER TEST
+		err := sink.Publish(context.TODO(), event)
+
+		// EXPECTATION
+		require.Error(t, err)
+	})
+}
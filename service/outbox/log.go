@@ -0,0 +1,31 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+)
+
+type LogSink struct {
+}
+
+func NewLogSink() service.OutboxSink {
+	return &LogSink{}
+}
+
+func (l *LogSink) Publish(ctx context.Context, event model.OutboxEvent) error {
+	helper.GetLogger(ctx).WithField("method", "Service.Outbox.LogSink.Publish").
+		WithField("outboxId", helper.Val(event.Id)).
+		WithField("aggregateType", helper.Val(event.AggregateType)).
+		WithField("aggregateId", helper.Val(event.AggregateId)).
+		WithField("eventType", helper.Val(event.EventType)).
+		WithField("payload", helper.Val(event.Payload)).
+		Info("outbox event")
+
+	return nil
+}
+
+func (l *LogSink) Close() {
+}
@@ -0,0 +1,41 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+
+	"github.com/nats-io/nats.go"
+)
+
+type NatsPublisher struct {
+	conn *nats.Conn
+}
+
+func NewNatsPublisher(config config.Config) (service.MessagePublisher, error) {
+	conn, err := nats.Connect(config.Outbox.BrokerUrl, nats.Name(config.Service.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	return &NatsPublisher{conn: conn}, nil
+}
+
+// Publish sends the message and waits for the server to acknowledge the connection is still healthy,
+// the key is carried as a header since NATS subjects are not partitioned
+func (n *NatsPublisher) Publish(ctx context.Context, topic string, key []byte, value []byte) error {
+	msg := nats.NewMsg(topic)
+	msg.Header.Set("Key", string(key))
+	msg.Data = value
+
+	if err := n.conn.PublishMsg(msg); err != nil {
+		return err
+	}
+
+	return n.conn.Flush()
+}
+
+func (n *NatsPublisher) Close() {
+	n.conn.Close()
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aalexanderkevin/crypto-wallet/model"
+)
+
+type OutboxSink interface {
+	// Publish delivers one outbox event, an error keeps the event in the outbox to be retried
+	Publish(ctx context.Context, event model.OutboxEvent) error
+	Close()
+}
+
+// MessagePublisher is the producer side of a message broker such as NATS or Kafka
+type MessagePublisher interface {
+	// Publish sends value to topic, key is used by brokers that partition messages
+	Publish(ctx context.Context, topic string, key []byte, value []byte) error
+	Close()
+}
@@ -0,0 +1,162 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+
+	argon2idPrefix = "$argon2id$"
+	bcryptPrefix   = "$2"
+)
+
+var ErrMalformedHash = errors.New("malformed password hash")
+
+// Hasher hashes passwords with the configured algorithm, the parameters are encoded in the hash so a hash stays
+// verifiable after the configuration changes
+type Hasher struct {
+	config config.Password
+}
+
+func NewHasher(cfg config.Password) (*Hasher, error) {
+	switch cfg.Algorithm {
+	case AlgorithmArgon2id:
+		if cfg.Argon2Time < 1 || cfg.Argon2MemoryKiB < 1 || cfg.Argon2Threads < 1 || cfg.Argon2KeyLength < 16 || cfg.SaltLength < 16 {
+			return nil, errors.New("invalid argon2id parameters")
+		}
+	case AlgorithmBcrypt:
+		if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	default:
+		return nil, fmt.Errorf("unknown password algorithm %q", cfg.Algorithm)
+	}
+
+	return &Hasher{config: cfg}, nil
+}
+
+// Hash returns the encoded hash of the password, argon2id hashes use the PHC string format
+// $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>
+func (h *Hasher) Hash(password string) (string, error) {
+	if h.config.Algorithm == AlgorithmBcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.config.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hash), nil
+	}
+
+	salt := make([]byte, h.config.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	params := h.argon2Params()
+	key := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, params.keyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, params.memory, params.time, params.threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify checks the password against the encoded hash, the algorithm is picked from the prefix of the hash. A hash
+// without a known prefix is a legacy salted SHA-1 hash and is checked with legacySalt. rehash tells the caller to
+// store a new hash, the hash is legacy or was made with other parameters than the configured ones
+func (h *Hasher) Verify(encoded string, password string, legacySalt *string) (match bool, rehash bool, err error) {
+	switch {
+	case strings.HasPrefix(encoded, argon2idPrefix):
+		params, salt, key, err := decodeArgon2id(encoded)
+		if err != nil {
+			return false, false, err
+		}
+
+		actual := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(key)))
+		if subtle.ConstantTimeCompare(actual, key) != 1 {
+			return false, false, nil
+		}
+
+		return true, h.config.Algorithm != AlgorithmArgon2id || params != h.argon2Params() || len(salt) != h.config.SaltLength, nil
+	case strings.HasPrefix(encoded, bcryptPrefix):
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, false, nil
+		}
+		if err != nil {
+			return false, false, err
+		}
+
+		cost, err := bcrypt.Cost([]byte(encoded))
+		if err != nil {
+			return false, false, err
+		}
+
+		return true, h.config.Algorithm != AlgorithmBcrypt || cost != h.config.BcryptCost, nil
+	default:
+		legacy := helper.Hash(helper.Val(legacySalt), password)
+		if subtle.ConstantTimeCompare([]byte(legacy), []byte(encoded)) != 1 {
+			return false, false, nil
+		}
+
+		return true, true, nil
+	}
+}
+
+type argon2Params struct {
+	memory    uint32
+	time      uint32
+	threads   uint8
+	keyLength uint32
+}
+
+func (h *Hasher) argon2Params() argon2Params {
+	return argon2Params{
+		memory:    h.config.Argon2MemoryKiB,
+		time:      h.config.Argon2Time,
+		threads:   h.config.Argon2Threads,
+		keyLength: h.config.Argon2KeyLength,
+	}
+}
+
+func decodeArgon2id(encoded string) (params argon2Params, salt []byte, key []byte, err error) {
+	// "", "argon2id", "v=19", "m=65536,t=3,p=2", salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, ErrMalformedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, ErrMalformedHash
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return params, nil, nil, ErrMalformedHash
+	}
+
+	salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrMalformedHash
+	}
+	key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrMalformedHash
+	}
+	params.keyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,126 @@
+package password_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/service/password"
+
+	"github.com/stretchr/testify/require"
+)
+
+func argon2idConfig() config.Password {
+	return config.Password{
+		Algorithm:       password.AlgorithmArgon2id,
+		Argon2Time:      1,
+		Argon2MemoryKiB: 1024,
+		Argon2Threads:   1,
+		Argon2KeyLength: 32,
+		SaltLength:      16,
+		BcryptCost:      4,
+	}
+}
+
+func TestHasher_Verify(t *testing.T) {
+	t.Run("ShouldMatchTheArgon2idHash", func(t *testing.T) {
+		// INIT
+		hasher, err := password.NewHasher(argon2idConfig())
+		require.NoError(t, err)
+		hash, err := hasher.Hash("password")
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"))
+
+		// CODE UNDER TEST
+		match, rehash, err := hasher.Verify(hash, "password", nil)
+		mismatch, _, mismatchErr := hasher.Verify(hash, "wrong-password", nil)
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.True(t, match)
+		require.False(t, rehash)
+		require.NoError(t, mismatchErr)
+		require.False(t, mismatch)
+	})
+
+	t.Run("ShouldAskToRehash_WhenTheParametersChanged", func(t *testing.T) {
+		// INIT
+		old, err := password.NewHasher(argon2idConfig())
+		require.NoError(t, err)
+		hash, err := old.Hash("password")
+		require.NoError(t, err)
+
+		cfg := argon2idConfig()
+		cfg.Argon2Time = 2
+		hasher, err := password.NewHasher(cfg)
+		require.NoError(t, err)
+
+		// CODE UNDER TEST
+		match, rehash, err := hasher.Verify(hash, "password", nil)
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.True(t, match)
+		require.True(t, rehash)
+	})
+
+	t.Run("ShouldVerifyTheBcryptHash_WhenArgon2idIsConfigured", func(t *testing.T) {
+		// INIT
+		cfg := argon2idConfig()
+		cfg.Algorithm = password.AlgorithmBcrypt
+		bcryptHasher, err := password.NewHasher(cfg)
+		require.NoError(t, err)
+		hash, err := bcryptHasher.Hash("password")
+		require.NoError(t, err)
+
+		hasher, err := password.NewHasher(argon2idConfig())
+		require.NoError(t, err)
+
+		// CODE UNDER TEST
+		match, rehash, err := hasher.Verify(hash, "password", nil)
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.True(t, match)
+		require.True(t, rehash)
+	})
+
+	t.Run("ShouldVerifyTheLegacyHashAndAskToRehash", func(t *testing.T) {
+		// INIT
+		hasher, err := password.NewHasher(argon2idConfig())
+		require.NoError(t, err)
+		hash := helper.Hash("salt", "password")
+
+		// CODE UNDER TEST
+		match, rehash, err := hasher.Verify(hash, "password", helper.Pointer("salt"))
+		mismatch, _, _ := hasher.Verify(hash, "password", helper.Pointer("other-salt"))
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.True(t, match)
+		require.True(t, rehash)
+		require.False(t, mismatch)
+	})
+
+	t.Run("ShouldReturnError_WhenTheHashIsMalformed", func(t *testing.T) {
+		// INIT
+		hasher, err := password.NewHasher(argon2idConfig())
+		require.NoError(t, err)
+
+		// CODE UNDER TEST
+		match, _, err := hasher.Verify("$argon2id$v=19$m=1024", "password", nil)
+
+		// EXPECTATION
+		require.ErrorIs(t, err, password.ErrMalformedHash)
+		require.False(t, match)
+	})
+}
+
+func TestNewHasher(t *testing.T) {
+	cfg := argon2idConfig()
+	cfg.Algorithm = "md5"
+
+	_, err := password.NewHasher(cfg)
+	require.Error(t, err)
+}
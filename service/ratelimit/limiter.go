@@ -0,0 +1,216 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+)
+
+var (
+	// waits counts the calls that waited for a token and throttled the responses asking to slow down, per provider
+	waits     = expvar.NewMap("rate_limit_waits")
+	throttled = expvar.NewMap("rate_limit_throttled")
+
+	providersMu sync.Mutex
+	providers   = map[string]*TokenBucket{}
+)
+
+// TokenBucket lets ratePerSecond calls through on average and up to burst at once
+type TokenBucket struct {
+	name          string
+	mu            sync.Mutex
+	ratePerSecond float64
+	burst         float64
+	tokens        float64
+	last          time.Time
+	// blockedUntil holds every call back after the provider asked to retry later
+	blockedUntil time.Time
+}
+
+// NewTokenBucket starts full, a rate of zero or less only holds the calls back after a Retry-After
+func NewTokenBucket(name string, ratePerSecond float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &TokenBucket{
+		name:          name,
+		ratePerSecond: ratePerSecond,
+		burst:         float64(burst),
+		tokens:        float64(burst),
+		last:          time.Now(),
+	}
+}
+
+// Provider is the bucket shared by every client of the provider, the first call creates it
+func Provider(name string, ratePerSecond float64, burst int) *TokenBucket {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	if bucket, ok := providers[name]; ok {
+		return bucket
+	}
+
+	bucket := NewTokenBucket(name, ratePerSecond, burst)
+	providers[name] = bucket
+	return bucket
+}
+
+// Wait blocks until a token is available or ctx is done
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	waited := false
+	for {
+		delay := b.reserve()
+		if delay <= 0 {
+			return nil
+		}
+
+		if !waited {
+			waits.Add(b.name, 1)
+			waited = true
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// BlockFor holds every call back for d, it is used when the provider answers with a Retry-After
+func (b *TokenBucket) BlockFor(d time.Duration) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+}
+
+// reserve takes a token when there is one, otherwise it tells how long to wait for the next one
+func (b *TokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(b.blockedUntil) {
+		return b.blockedUntil.Sub(now)
+	}
+	if b.ratePerSecond <= 0 {
+		return 0
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * b.ratePerSecond
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) / b.ratePerSecond * float64(time.Second))
+}
+
+// TooManyRequestsError is a provider asking to slow down, RetryAfter is zero when it did not say for how long
+type TooManyRequestsError struct {
+	Status     string
+	RetryAfter time.Duration
+}
+
+func (e *TooManyRequestsError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s, retry after %s", e.Status, e.RetryAfter)
+	}
+	return e.Status
+}
+
+// NewTooManyRequestsError reads the Retry-After of a 429 response
+func NewTooManyRequestsError(resp *http.Response) *TooManyRequestsError {
+	return &TooManyRequestsError{
+		Status:     "HTTP " + strconv.Itoa(resp.StatusCode) + " " + http.StatusText(resp.StatusCode),
+		RetryAfter: ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// ParseRetryAfter reads a Retry-After header given either in seconds or as an HTTP date
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
+
+// Do calls fn once a token is available, a call the provider throttled is retried after the Retry-After it gave,
+// or after a growing backoff when it gave none, up to the configured retries
+func Do(ctx context.Context, bucket *TokenBucket, cfg config.RateLimit, fn func() error) error {
+	logger := helper.GetLogger(ctx).WithField("method", "Service.RateLimit.Do")
+
+	for attempt := 1; ; attempt++ {
+		if err := bucket.Wait(ctx); err != nil {
+			return err
+		}
+
+		err := fn()
+		var tooManyRequests *TooManyRequestsError
+		if !errors.As(err, &tooManyRequests) {
+			return err
+		}
+
+		name := ""
+		if bucket != nil {
+			name = bucket.name
+		}
+		throttled.Add(name, 1)
+
+		if attempt > cfg.MaxRetries {
+			return err
+		}
+
+		delay := tooManyRequests.RetryAfter
+		if delay <= 0 {
+			delay = helper.Backoff(attempt, time.Duration(cfg.RetryBaseMillis)*time.Millisecond, time.Duration(cfg.RetryMaxSeconds)*time.Second)
+		}
+		if max := time.Duration(cfg.RetryMaxSeconds) * time.Second; delay > max {
+			// waiting longer than the caller would is pointless, the error is returned instead
+			return err
+		}
+		bucket.BlockFor(delay)
+
+		logger.WithField("provider", name).WithField("attempt", attempt).WithField("retry_after", delay.String()).
+			Warn("provider throttled the call, retrying")
+	}
+}
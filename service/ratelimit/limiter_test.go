@@ -0,0 +1,111 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/service/ratelimit"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket_Wait(t *testing.T) {
+	t.Run("ShouldWaitForTheNextToken_WhenTheBurstIsUsed", func(t *testing.T) {
+		// INIT
+		bucket := ratelimit.NewTokenBucket("test", 20, 2)
+		require.NoError(t, bucket.Wait(context.TODO()))
+		require.NoError(t, bucket.Wait(context.TODO()))
+
+		// CODE UNDER TEST
+		start := time.Now()
+		err := bucket.Wait(context.TODO())
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	})
+
+	t.Run("ShouldReturnTheContextError_WhenItIsDoneFirst", func(t *testing.T) {
+		// INIT
+		bucket := ratelimit.NewTokenBucket("test", 0.1, 1)
+		require.NoError(t, bucket.Wait(context.TODO()))
+		ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+		defer cancel()
+
+		// CODE UNDER TEST
+		err := bucket.Wait(ctx)
+
+		// EXPECTATION
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 10, 28, 10, 0, 0, 0, time.UTC)
+
+	require.Equal(t, 2*time.Second, ratelimit.ParseRetryAfter("2", now))
+	require.Equal(t, 30*time.Second, ratelimit.ParseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now))
+	require.Equal(t, time.Duration(0), ratelimit.ParseRetryAfter("soon", now))
+	require.Equal(t, time.Duration(0), ratelimit.ParseRetryAfter("", now))
+}
+
+func TestDo(t *testing.T) {
+	cfg := config.RateLimit{MaxRetries: 2, RetryBaseMillis: 1, RetryMaxSeconds: 1}
+
+	t.Run("ShouldRetryAfterTheDelayOfTheProvider_WhenTheCallIsThrottled", func(t *testing.T) {
+		// INIT
+		bucket := ratelimit.NewTokenBucket("test", 0, 1)
+		calls := 0
+
+		// CODE UNDER TEST
+		start := time.Now()
+		err := ratelimit.Do(context.TODO(), bucket, cfg, func() error {
+			calls++
+			if calls == 1 {
+				return &ratelimit.TooManyRequestsError{Status: "HTTP 429 Too Many Requests", RetryAfter: 50 * time.Millisecond}
+			}
+			return nil
+		})
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.Equal(t, 2, calls)
+		require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("ShouldReturnTheError_WhenTheRetriesAreUsed", func(t *testing.T) {
+		// INIT
+		calls := 0
+
+		// CODE UNDER TEST
+		err := ratelimit.Do(context.TODO(), ratelimit.NewTokenBucket("test", 10, 10), cfg, func() error {
+			calls++
+			return &ratelimit.TooManyRequestsError{Status: "HTTP 429 Too Many Requests"}
+		})
+
+		// EXPECTATION
+		var tooManyRequests *ratelimit.TooManyRequestsError
+		require.ErrorAs(t, err, &tooManyRequests)
+		require.Equal(t, 3, calls)
+	})
+
+	t.Run("ShouldNotRetry_WhenTheCallFailsOtherwise", func(t *testing.T) {
+		// INIT
+		errFailed := errors.New("failed")
+		calls := 0
+
+		// CODE UNDER TEST
+		err := ratelimit.Do(context.TODO(), nil, cfg, func() error {
+			calls++
+			return errFailed
+		})
+
+		// EXPECTATION
+		require.ErrorIs(t, err, errFailed)
+		require.Equal(t, 1, calls)
+	})
+}
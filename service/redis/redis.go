@@ -31,7 +31,7 @@ func (r *RedisClient) Close() {
 func (r *RedisClient) Get(ctx context.Context, key string) (string, error) {
 	res, err := r.Client.Get(ctx, key).Result()
 	if err != nil && err.Error() == "redis: nil" {
-		return "", fmt.Errorf("redis nil")
+		return "", service.ErrCacheMiss
 	} else if err != nil {
 		return "", err
 	}
@@ -43,6 +43,10 @@ func (r *RedisClient) Put(ctx context.Context, key string, data string, ttl time
 	return r.Client.Set(ctx, key, data, ttl).Err()
 }
 
+func (r *RedisClient) PutIfAbsent(ctx context.Context, key string, data string, ttl time.Duration) (bool, error) {
+	return r.Client.SetNX(ctx, key, data, ttl).Result()
+}
+
 func (r *RedisClient) SetList(ctx context.Context, key string, data string, ttl *time.Duration) (int64, error) {
 	res, err := r.Client.RPush(ctx, key, data).Result()
 	if err != nil {
@@ -0,0 +1,83 @@
+package totp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/service/totp"
+
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the base32 of the "12345678901234567890" secret of the RFC 6238 test vectors
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	t.Run("ShouldMatchTheRfcTestVectors", func(t *testing.T) {
+		// CODE UNDER TEST
+		codes := map[int64]string{}
+		for _, unix := range []int64{59, 1111111109, 1234567890} {
+			code, err := totp.Code(rfcSecret, totp.Step(time.Unix(unix, 0)))
+			require.NoError(t, err)
+			codes[unix] = code
+		}
+
+		// EXPECTATION
+		require.Equal(t, "287082", codes[59])
+		require.Equal(t, "081804", codes[1111111109])
+		require.Equal(t, "005924", codes[1234567890])
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("ShouldAcceptTheCodeOfTheNextStep_WhenItIsWithinTheSkew", func(t *testing.T) {
+		// INIT
+		now := time.Unix(1234567890, 0)
+		code, err := totp.Code(rfcSecret, totp.Step(now)+1)
+		require.NoError(t, err)
+
+		// CODE UNDER TEST
+		step, ok, err := totp.Validate(rfcSecret, code, now, 1)
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, totp.Step(now)+1, step)
+	})
+
+	t.Run("ShouldRefuseTheCode_WhenItIsOutsideTheSkew", func(t *testing.T) {
+		// INIT
+		now := time.Unix(1234567890, 0)
+		code, err := totp.Code(rfcSecret, totp.Step(now)-2)
+		require.NoError(t, err)
+
+		// CODE UNDER TEST
+		_, ok, err := totp.Validate(rfcSecret, code, now, 1)
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("ShouldRefuseTheCode_WhenItHasTheWrongLength", func(t *testing.T) {
+		// CODE UNDER TEST
+		_, ok, err := totp.Validate(rfcSecret, "12345", time.Now(), 1)
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func TestGenerateSecret(t *testing.T) {
+	t.Run("ShouldReturnAUsableSecret", func(t *testing.T) {
+		// CODE UNDER TEST
+		secret, err := totp.GenerateSecret()
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.Len(t, secret, 32)
+		_, err = totp.Code(secret, 1)
+		require.NoError(t, err)
+	})
+}
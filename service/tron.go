@@ -20,6 +20,12 @@ type Tron interface {
 	CheckAddress(address string) error
 	GetCurrentBlock(ctx context.Context) (*int64, error)
 	GetTxByAccountAddress(ctx context.Context, address *string, filter *GetTxByAccountAddressFilter) (*GetTransactionResponse, error)
+	GetAccountResources(ctx context.Context, address *string) (*model.TronAccountResources, error)
+	EstimateTransferFee(ctx context.Context, txOpts *model.TxOpts, fromAddress *string) (*model.TronFeeEstimate, error)
+	FreezeBalance(ctx context.Context, resource string, amount int64, wallet *model.TrxHdWallet) (*api.TransactionExtention, error)
+	UnfreezeBalance(ctx context.Context, resource string, amount int64, wallet *model.TrxHdWallet) (*api.TransactionExtention, error)
+	DelegateResource(ctx context.Context, resource string, amount int64, receiverAddress string, wallet *model.TrxHdWallet) (*api.TransactionExtention, error)
+	UndelegateResource(ctx context.Context, resource string, amount int64, receiverAddress string, wallet *model.TrxHdWallet) (*api.TransactionExtention, error)
 }
 
 type GetTransactionResponse struct {
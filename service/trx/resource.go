@@ -0,0 +1,227 @@
+package trx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+
+	"github.com/fbsobreira/gotron-sdk/pkg/client"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/api"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// every transaction result takes this many bytes of bandwidth on top of the signed transaction
+	transactionResultSize = 64
+	signatureSize         = 65
+)
+
+// chainFees are the chain parameters that price bandwidth and account activation, all in sun
+type chainFees struct {
+	bandwidthPrice          int64
+	createAccountFee        int64
+	createNewAccountFeeInSc int64
+}
+
+func (t *TronImpl) GetAccountResources(ctx context.Context, address *string) (*model.TronAccountResources, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Service.Tron.GetAccountResources")
+
+	resource, err := call(ctx, t, func(conn *client.GrpcClient) (*api.AccountResourceMessage, error) {
+		return conn.GetAccountResource(*address)
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Failed get account resource")
+		return nil, err
+	}
+
+	return &model.TronAccountResources{
+		Address:            address,
+		FreeBandwidthLimit: helper.Pointer(resource.FreeNetLimit),
+		FreeBandwidthUsed:  helper.Pointer(resource.FreeNetUsed),
+		BandwidthLimit:     helper.Pointer(resource.NetLimit),
+		BandwidthUsed:      helper.Pointer(resource.NetUsed),
+		EnergyLimit:        helper.Pointer(resource.EnergyLimit),
+		EnergyUsed:         helper.Pointer(resource.EnergyUsed),
+	}, nil
+}
+
+// EstimateTransferFee estimates the TRX a transfer burns, the bandwidth it takes is measured on the transaction the
+// node builds for it and is only paid for when the sender does not have enough bandwidth left
+func (t *TronImpl) EstimateTransferFee(ctx context.Context, txOpts *model.TxOpts, fromAddress *string) (*model.TronFeeEstimate, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Service.Tron.EstimateTransferFee")
+
+	tx, err := call(ctx, t, func(conn *client.GrpcClient) (*api.TransactionExtention, error) {
+		return conn.Transfer(*fromAddress, *txOpts.To, txOpts.Amount.Int64())
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Failed build transfer")
+		return nil, err
+	}
+	bandwidth := int64(proto.Size(tx.Transaction) + signatureSize + transactionResultSize)
+
+	resources, err := t.GetAccountResources(ctx, fromAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	fees, err := t.getChainFees(ctx)
+	if err != nil {
+		logger.WithError(err).Warn("Failed get chain fees")
+		return nil, err
+	}
+
+	// a receiver the chain does not know yet is activated by the transfer
+	activated := true
+	_, err = call(ctx, t, func(conn *client.GrpcClient) (*core.Account, error) {
+		return conn.GetAccount(*txOpts.To)
+	})
+	if err != nil {
+		if err.Error() != "account not found" {
+			logger.WithError(err).Warn("Failed get receiver account")
+			return nil, err
+		}
+		activated = false
+	}
+
+	return estimateTransferFee(*resources, bandwidth, activated, *fees), nil
+}
+
+func estimateTransferFee(resources model.TronAccountResources, bandwidth int64, activated bool, fees chainFees) *model.TronFeeEstimate {
+	res := &model.TronFeeEstimate{
+		Bandwidth:     &bandwidth,
+		BandwidthFee:  helper.Pointer[int64](0),
+		ActivationFee: helper.Pointer[int64](0),
+	}
+
+	if activated {
+		if resources.AvailableFreeBandwidth() < bandwidth && resources.AvailableBandwidth() < bandwidth {
+			res.BandwidthFee = helper.Pointer(bandwidth * fees.bandwidthPrice)
+		}
+	} else {
+		// activating an account can not use the free bandwidth, without staked bandwidth a flat fee is burned instead
+		res.ActivationFee = &fees.createNewAccountFeeInSc
+		if resources.AvailableBandwidth() < bandwidth {
+			res.BandwidthFee = &fees.createAccountFee
+		}
+	}
+
+	res.Fee = helper.Pointer(*res.BandwidthFee + *res.ActivationFee)
+
+	return res
+}
+
+func (t *TronImpl) getChainFees(ctx context.Context) (*chainFees, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, "TRON-PRO-API-KEY", t.config.ApiKey)
+
+	params, err := call(ctx, t, func(conn *client.GrpcClient) (*core.ChainParameters, error) {
+		return conn.Client.GetChainParameters(ctx, new(api.EmptyMessage))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &chainFees{}
+	for _, param := range params.GetChainParameter() {
+		switch param.GetKey() {
+		case "getTransactionFee":
+			res.bandwidthPrice = param.GetValue()
+		case "getCreateAccountFee":
+			res.createAccountFee = param.GetValue()
+		case "getCreateNewAccountFeeInSystemContract":
+			res.createNewAccountFeeInSc = param.GetValue()
+		}
+	}
+
+	return res, nil
+}
+
+func (t *TronImpl) FreezeBalance(ctx context.Context, resource string, amount int64, wallet *model.TrxHdWallet) (*api.TransactionExtention, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Service.Tron.FreezeBalance")
+
+	resourceCode, err := toResourceCode(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := call(ctx, t, func(conn *client.GrpcClient) (*api.TransactionExtention, error) {
+		return conn.FreezeBalanceV2(*wallet.Address, resourceCode, amount)
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Failed to freeze balance")
+		return nil, err
+	}
+
+	return t.signAndBroadcast(ctx, tx, wallet)
+}
+
+func (t *TronImpl) UnfreezeBalance(ctx context.Context, resource string, amount int64, wallet *model.TrxHdWallet) (*api.TransactionExtention, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Service.Tron.UnfreezeBalance")
+
+	resourceCode, err := toResourceCode(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := call(ctx, t, func(conn *client.GrpcClient) (*api.TransactionExtention, error) {
+		return conn.UnfreezeBalanceV2(*wallet.Address, resourceCode, amount)
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Failed to unfreeze balance")
+		return nil, err
+	}
+
+	return t.signAndBroadcast(ctx, tx, wallet)
+}
+
+func (t *TronImpl) DelegateResource(ctx context.Context, resource string, amount int64, receiverAddress string, wallet *model.TrxHdWallet) (*api.TransactionExtention, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Service.Tron.DelegateResource")
+
+	resourceCode, err := toResourceCode(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := call(ctx, t, func(conn *client.GrpcClient) (*api.TransactionExtention, error) {
+		return conn.DelegateResource(*wallet.Address, receiverAddress, resourceCode, amount, false, 0)
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Failed to delegate resource")
+		return nil, err
+	}
+
+	return t.signAndBroadcast(ctx, tx, wallet)
+}
+
+func (t *TronImpl) UndelegateResource(ctx context.Context, resource string, amount int64, receiverAddress string, wallet *model.TrxHdWallet) (*api.TransactionExtention, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Service.Tron.UndelegateResource")
+
+	resourceCode, err := toResourceCode(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := call(ctx, t, func(conn *client.GrpcClient) (*api.TransactionExtention, error) {
+		return conn.UnDelegateResource(*wallet.Address, receiverAddress, resourceCode, amount, false)
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Failed to undelegate resource")
+		return nil, err
+	}
+
+	return t.signAndBroadcast(ctx, tx, wallet)
+}
+
+func toResourceCode(resource string) (core.ResourceCode, error) {
+	switch resource {
+	case model.TronResourceBandwidth:
+		return core.ResourceCode_BANDWIDTH, nil
+	case model.TronResourceEnergy:
+		return core.ResourceCode_ENERGY, nil
+	default:
+		return 0, model.NewParameterError(helper.Pointer(fmt.Sprintf("invalid tron resource %s", resource)))
+	}
+}
@@ -15,45 +15,104 @@ import (
 	"github.com/aalexanderkevin/crypto-wallet/helper"
 	"github.com/aalexanderkevin/crypto-wallet/model"
 	"github.com/aalexanderkevin/crypto-wallet/service"
+	"github.com/aalexanderkevin/crypto-wallet/service/failover"
+	"github.com/aalexanderkevin/crypto-wallet/service/ratelimit"
 
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fbsobreira/gotron-sdk/pkg/account"
 	"github.com/fbsobreira/gotron-sdk/pkg/client"
 	"github.com/fbsobreira/gotron-sdk/pkg/common"
 	"github.com/fbsobreira/gotron-sdk/pkg/keys"
 	"github.com/fbsobreira/gotron-sdk/pkg/proto/api"
 	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
 
 type TronImpl struct {
-	grpcClient *client.GrpcClient
+	grpcClient *failover.Pool[*client.GrpcClient]
 	httpClient *http.Client
+	limiter    *ratelimit.TokenBucket
+	rateLimit  config.RateLimit
 	config     config.Tron
 }
 
-func NewTronImpl(config config.Config) service.Tron {
-	opts := make([]grpc.DialOption, 0)
-	opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-
-	conn := client.NewGrpcClient(config.Tron.NetgRPCUrl)
-	if err := conn.Start(opts...); err != nil {
-		_ = fmt.Errorf("error connecting GRPC Client: %v", err)
+func NewTronImpl(config config.Config) (service.Tron, error) {
+	endpoints, err := config.Tron.GetGrpcEndpoints()
+	if err != nil {
+		return nil, err
 	}
 
-	conn.SetAPIKey(config.Tron.ApiKey)
-
 	return &TronImpl{
-		grpcClient: conn,
+		grpcClient: failover.NewPool("trx", endpoints, config.Failover, tronClient(config.Tron.ApiKey)),
 		httpClient: &http.Client{
 			Timeout: 5 * time.Second},
-		config: config.Tron,
+		limiter:   ratelimit.Provider("trongrid", config.RateLimit.TronGridPerSecond, config.RateLimit.TronGridBurst),
+		rateLimit: config.RateLimit,
+		config:    config.Tron,
+	}, nil
+}
+
+func tronClient(apiKey string) failover.Client[*client.GrpcClient] {
+	return failover.Client[*client.GrpcClient]{
+		Dial: func(ctx context.Context, url string) (*client.GrpcClient, error) {
+			conn := client.NewGrpcClient(url)
+			if err := conn.Start(grpc.WithTransportCredentials(insecure.NewCredentials())); err != nil {
+				return nil, fmt.Errorf("error connecting GRPC Client: %w", err)
+			}
+
+			if err := conn.SetAPIKey(apiKey); err != nil {
+				conn.Stop()
+				return nil, err
+			}
+
+			return conn, nil
+		},
+		Height: func(ctx context.Context, conn *client.GrpcClient) (int64, error) {
+			block, err := conn.GetNowBlock()
+			if err != nil {
+				return 0, err
+			}
+			return block.BlockHeader.RawData.Number, nil
+		},
+		IsFailure: isEndpointFailure,
+		Close: func(conn *client.GrpcClient) {
+			conn.Stop()
+		},
+	}
+}
+
+// isEndpointFailure tells the errors of the node from the ones of the request, only the gRPC codes of an
+// unreachable or overloaded node move the call to another endpoint
+func isEndpointFailure(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+
+	switch s.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Internal:
+		return true
+	}
+
+	return false
+}
+
+// call runs fn on the node once the TronGrid rate limit lets it through, the gRPC and HTTP APIs share the limit
+func call[R any](ctx context.Context, t *TronImpl, fn func(conn *client.GrpcClient) (R, error)) (R, error) {
+	if err := t.limiter.Wait(ctx); err != nil {
+		var zero R
+		return zero, err
 	}
+
+	return failover.Call(ctx, t.grpcClient, fn)
 }
 
 func (t *TronImpl) Close() {
-	t.grpcClient.Stop()
+	t.grpcClient.Close()
 }
 
 func (t *TronImpl) GetWallet(ctx context.Context, seedPhrase *string) *model.TrxHdWallet {
@@ -76,12 +135,27 @@ func (t *TronImpl) GetWallet(ctx context.Context, seedPhrase *string) *model.Trx
 func (t *TronImpl) SendTx(ctx context.Context, txOpts *model.TxOpts, wallet *model.TrxHdWallet) (transaction *api.TransactionExtention, err error) {
 	logger := helper.GetLogger(ctx).WithField("method", "Service.Tron.SendTx")
 
-	tx, err := t.grpcClient.Transfer(*wallet.Address, *txOpts.To, txOpts.Amount.Int64())
+	tx, err := call(ctx, t, func(conn *client.GrpcClient) (*api.TransactionExtention, error) {
+		return conn.Transfer(*wallet.Address, *txOpts.To, txOpts.Amount.Int64())
+	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed to tranfer")
 		return nil, err
 	}
 
+	return t.signAndBroadcast(ctx, tx, wallet)
+}
+
+// signAndBroadcast signs a transaction the node built for the wallet and broadcasts it
+func (t *TronImpl) signAndBroadcast(ctx context.Context, tx *api.TransactionExtention, wallet *model.TrxHdWallet) (*api.TransactionExtention, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Service.Tron.signAndBroadcast")
+
+	if tx.GetResult().GetCode() != api.Return_SUCCESS {
+		err := fmt.Errorf("failed to build transaction: %s", tx.GetResult().GetMessage())
+		logger.WithError(err).Warn("node did not build the transaction")
+		return nil, err
+	}
+
 	rawData, err := proto.Marshal(tx.Transaction.GetRawData())
 	if err != nil {
 		logger.WithError(err).Warn("Failed to parse raw data")
@@ -100,15 +174,21 @@ func (t *TronImpl) SendTx(ctx context.Context, txOpts *model.TxOpts, wallet *mod
 
 	tx.Transaction.Signature = append(tx.Transaction.Signature, signature)
 
-	result, err := t.grpcClient.Broadcast(tx.Transaction)
+	result, err := call(ctx, t, func(conn *client.GrpcClient) (*api.Return, error) {
+		return conn.Broadcast(tx.Transaction)
+	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed to broadcast message")
-		return nil, err
+		return nil, model.NewBroadcastUnknownError(err)
 	}
 
 	if result.Code != api.Return_SUCCESS {
 		err := errors.New(result.String())
 		logger.WithError(err).Warn("broadcast transaction return not success")
+		// a duplicate was broadcast before
+		if result.Code == api.Return_DUP_TRANSACTION_ERROR {
+			return nil, model.NewBroadcastUnknownError(err)
+		}
 		return nil, err
 	}
 
@@ -118,7 +198,9 @@ func (t *TronImpl) SendTx(ctx context.Context, txOpts *model.TxOpts, wallet *mod
 func (t *TronImpl) GetBalance(ctx context.Context, address *string) (balance *int64, err error) {
 	logger := helper.GetLogger(ctx).WithField("method", "Service.Tron.GetBalance")
 
-	accDetailed, err := t.grpcClient.GetAccountDetailed(*address)
+	accDetailed, err := call(ctx, t, func(conn *client.GrpcClient) (*account.Account, error) {
+		return conn.GetAccountDetailed(*address)
+	})
 	if err != nil {
 		logger.WithError(err).Warn("error get account detailed")
 		return nil, err
@@ -130,7 +212,9 @@ func (t *TronImpl) GetBalance(ctx context.Context, address *string) (balance *in
 func (t *TronImpl) GetCurrentBlock(ctx context.Context) (*int64, error) {
 	logger := helper.GetLogger(ctx).WithField("method", "Service.Tron.GetCurrentBlock")
 
-	block, err := t.grpcClient.GetNowBlock()
+	block, err := call(ctx, t, func(conn *client.GrpcClient) (*api.BlockExtention, error) {
+		return conn.GetNowBlock()
+	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed GetNowBlock")
 		return nil, err
@@ -142,8 +226,13 @@ func (t *TronImpl) GetCurrentBlock(ctx context.Context) (*int64, error) {
 func (t *TronImpl) GetTx(ctx context.Context, txhash string) (*core.TransactionInfo, error) {
 	logger := helper.GetLogger(ctx).WithField("method", "Service.Tron.GetTx")
 
-	tx, err := t.grpcClient.GetTransactionInfoByID(txhash)
+	tx, err := call(ctx, t, func(conn *client.GrpcClient) (*core.TransactionInfo, error) {
+		return conn.GetTransactionInfoByID(txhash)
+	})
 	if err != nil {
+		if err.Error() == "transaction info not found" {
+			return nil, model.NewNotFoundError()
+		}
 		logger.WithError(err).Warn("Failed get tx")
 		return nil, err
 	}
@@ -152,74 +241,54 @@ func (t *TronImpl) GetTx(ctx context.Context, txhash string) (*core.TransactionI
 }
 
 func (t *TronImpl) GetUnconfirmedTxAddress(ctx context.Context, address *string) (*service.GetTransactionResponse, error) {
-	logger := helper.GetLogger(ctx).WithField("method", "Service.Tron.GetUnconfirmedTx")
-
 	URL := fmt.Sprintf("%s/v1/accounts/%s/transactions?only_confirmed=false&only_unconfirmed=true", t.config.NetUrl, *address)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
-	if err != nil {
-		logger.WithError(err).Warn("Failed create request")
-		return nil, err
-	}
-
-	req.Header.Add("TRON-PRO-API-KEY", t.config.ApiKey)
-	req.Header.Add("Content-Type", "application/json")
+	return t.getTransactions(ctx, URL)
+}
 
-	resp, err := t.httpClient.Do(req)
-	if err != nil {
-		logger.Error(err)
-		return nil, err
-	}
+func (t *TronImpl) GetConfirmedTxAddress(ctx context.Context, address *string) (*service.GetTransactionResponse, error) {
+	URL := fmt.Sprintf("%s/v1/accounts/%s/transactions?only_confirmed=true&only_unconfirmed=false", t.config.NetUrl, *address)
+	return t.getTransactions(ctx, URL)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		b, _ := io.ReadAll(resp.Body)
-		logger.
-			WithField("header", resp.Header).
-			WithField("body", string(b)).
-			WithField("status", resp.Status).
-			Error("Status is not OK", resp.Status)
-		return nil, fmt.Errorf("status not OK: %s, body: %s", resp.Status, b)
-	}
+// getTransactions reads a page of account transactions from TronGrid once its rate limit lets the call through,
+// a call TronGrid throttled is retried after the Retry-After it gave
+func (t *TronImpl) getTransactions(ctx context.Context, URL string) (*service.GetTransactionResponse, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Service.Tron.getTransactions")
 
 	var result service.GetTransactionResponse
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
-		return nil, err
-	}
-
-	return &result, nil
-}
+	err := ratelimit.Do(ctx, t.limiter, t.rateLimit, func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
+		if err != nil {
+			logger.WithError(err).Warn("Failed create request")
+			return err
+		}
 
-func (t *TronImpl) GetConfirmedTxAddress(ctx context.Context, address *string) (*service.GetTransactionResponse, error) {
-	logger := helper.GetLogger(ctx).WithField("method", "Service.Tron.GetUnconfirmedTx")
+		req.Header.Add("TRON-PRO-API-KEY", t.config.ApiKey)
+		req.Header.Add("Content-Type", "application/json")
 
-	URL := fmt.Sprintf("%s/v1/accounts/%s/transactions?only_confirmed=true&only_unconfirmed=false", t.config.NetUrl, *address)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
-	if err != nil {
-		logger.WithError(err).Warn("Failed create request")
-		return nil, err
-	}
-
-	req.Header.Add("TRON-PRO-API-KEY", t.config.ApiKey)
-	req.Header.Add("Content-Type", "application/json")
+		resp, err := t.httpClient.Do(req)
+		if err != nil {
+			logger.Error(err)
+			return err
+		}
+		defer resp.Body.Close()
 
-	resp, err := t.httpClient.Do(req)
-	if err != nil {
-		logger.Error(err)
-		return nil, err
-	}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return ratelimit.NewTooManyRequestsError(resp)
+		}
 
-	if resp.StatusCode != http.StatusOK {
-		b, _ := io.ReadAll(resp.Body)
-		logger.
-			WithField("header", resp.Header).
-			WithField("body", string(b)).
-			WithField("status", resp.Status).
-			Error("Status is not OK", resp.Status)
-		return nil, fmt.Errorf("status not OK: %s, body: %s", resp.Status, b)
-	}
+		if resp.StatusCode != http.StatusOK {
+			b, _ := io.ReadAll(resp.Body)
+			logger.
+				WithField("header", resp.Header).
+				WithField("body", string(b)).
+				WithField("status", resp.Status).
+				Error("Status is not OK", resp.Status)
+			return fmt.Errorf("status not OK: %s, body: %s", resp.Status, b)
+		}
 
-	var result service.GetTransactionResponse
-	err = json.NewDecoder(resp.Body).Decode(&result)
+		return json.NewDecoder(resp.Body).Decode(&result)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -235,8 +304,6 @@ func (t *TronImpl) CheckAddress(address string) error {
 }
 
 func (t *TronImpl) GetTxByAccountAddress(ctx context.Context, address *string, filter *service.GetTxByAccountAddressFilter) (*service.GetTransactionResponse, error) {
-	logger := helper.GetLogger(ctx).WithField("method", "Service.Tron.GetUnconfirmedTx")
-
 	URL := fmt.Sprintf("%s/v1/accounts/%s/transactions", t.config.NetUrl, *address)
 
 	if filter != nil {
@@ -282,36 +349,5 @@ func (t *TronImpl) GetTxByAccountAddress(ctx context.Context, address *string, f
 		}
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
-	if err != nil {
-		logger.WithError(err).Warn("Failed create request")
-		return nil, err
-	}
-
-	req.Header.Add("TRON-PRO-API-KEY", t.config.ApiKey)
-	req.Header.Add("Content-Type", "application/json")
-
-	resp, err := t.httpClient.Do(req)
-	if err != nil {
-		logger.Error(err)
-		return nil, err
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		b, _ := io.ReadAll(resp.Body)
-		logger.
-			WithField("header", resp.Header).
-			WithField("body", string(b)).
-			WithField("status", resp.Status).
-			Error("Status is not OK", resp.Status)
-		return nil, fmt.Errorf("status not OK: %s, body: %s", resp.Status, b)
-	}
-
-	var result service.GetTransactionResponse
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
-		return nil, err
-	}
-
-	return &result, nil
+	return t.getTransactions(ctx, URL)
 }
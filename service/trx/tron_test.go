@@ -42,7 +42,8 @@ func TestServiceTron_GetWallet(t *testing.T) {
 		aa := helper.Pointer(base58.Encode([]byte(addb)))
 		fmt.Println(aa)
 		// INIT
-		tronSvc := trx.NewTronImpl(config.Instance())
+		tronSvc, err := trx.NewTronImpl(config.Instance())
+		require.NoError(t, err)
 		defer tronSvc.Close()
 
 		seedPhrase := "yellow dolphin robot express road develop repair neutral rate tide economy section"
@@ -62,7 +63,8 @@ func TestServiceTron_GetWallet(t *testing.T) {
 func TestServiceTrx_GetBalance(t *testing.T) {
 	t.Run("ShouldGetBalance", func(t *testing.T) {
 		// INIT
-		trxSvc := trx.NewTronImpl(config.Instance())
+		trxSvc, err := trx.NewTronImpl(config.Instance())
+		require.NoError(t, err)
 		defer trxSvc.Close()
 
 		// CODE UNDER TEST
@@ -77,7 +79,8 @@ func TestServiceTrx_GetBalance(t *testing.T) {
 func TestServiceTrx_SendTx(t *testing.T) {
 	t.Run("ShouldSendTx", func(t *testing.T) {
 		// INIT
-		tronSvc := trx.NewTronImpl(config.Instance())
+		tronSvc, err := trx.NewTronImpl(config.Instance())
+		require.NoError(t, err)
 		defer tronSvc.Close()
 
 		seedPhrase := "yellow dolphin robot express road develop repair neutral rate tide economy section"
@@ -117,7 +120,8 @@ func TestServiceTrx_SendTx(t *testing.T) {
 func TestServiceTrx_GetUnconfirmedTx(t *testing.T) {
 	t.Run("ShouldGetTx", func(t *testing.T) {
 		// INIT
-		trxSvc := trx.NewTronImpl(config.Instance())
+		trxSvc, err := trx.NewTronImpl(config.Instance())
+		require.NoError(t, err)
 		defer trxSvc.Close()
 
 		// CODE UNDER TEST
@@ -132,7 +136,8 @@ func TestServiceTrx_GetUnconfirmedTx(t *testing.T) {
 func TestServiceTrx_GetTx(t *testing.T) {
 	t.Run("ShouldGetTx", func(t *testing.T) {
 		// INIT
-		trxSvc := trx.NewTronImpl(config.Instance())
+		trxSvc, err := trx.NewTronImpl(config.Instance())
+		require.NoError(t, err)
 		defer trxSvc.Close()
 
 		// CODE UNDER TEST
@@ -147,11 +152,12 @@ func TestServiceTrx_GetTx(t *testing.T) {
 func TestServiceTrx_CheckAddress(t *testing.T) {
 	t.Run("ShouldReturnErrorNil_WhenAddressValid", func(t *testing.T) {
 		// INIT
-		trxSvc := trx.NewTronImpl(config.Instance())
+		trxSvc, err := trx.NewTronImpl(config.Instance())
+		require.NoError(t, err)
 		defer trxSvc.Close()
 
 		// CODE UNDER TEST
-		err := trxSvc.CheckAddress("TNjq63hm9JfqQYRRwVAtS84PRy1Ty6CU5U")
+		err = trxSvc.CheckAddress("TNjq63hm9JfqQYRRwVAtS84PRy1Ty6CU5U")
 
 		// EXPECTATION
 		require.NoError(t, err)
@@ -159,11 +165,12 @@ func TestServiceTrx_CheckAddress(t *testing.T) {
 
 	t.Run("ShouldReturnError_WhenAddressInvalid", func(t *testing.T) {
 		// INIT
-		trxSvc := trx.NewTronImpl(config.Instance())
+		trxSvc, err := trx.NewTronImpl(config.Instance())
+		require.NoError(t, err)
 		defer trxSvc.Close()
 
 		// CODE UNDER TEST
-		err := trxSvc.CheckAddress("TNjq63hm9JfqQYRRwVKtS84PRy1Ty6CU5U")
+		err = trxSvc.CheckAddress("TNjq63hm9JfqQYRRwVKtS84PRy1Ty6CU5U")
 
 		// EXPECTATION
 		require.Error(t, err)
@@ -174,7 +181,8 @@ func TestServiceTrx_CheckAddress(t *testing.T) {
 func TestServiceTrx_GetCurrentBlock(t *testing.T) {
 	t.Run("ShouldGetBlock", func(t *testing.T) {
 		// INIT
-		trxSvc := trx.NewTronImpl(config.Instance())
+		trxSvc, err := trx.NewTronImpl(config.Instance())
+		require.NoError(t, err)
 		defer trxSvc.Close()
 
 		// CODE UNDER TEST
@@ -186,3 +194,41 @@ func TestServiceTrx_GetCurrentBlock(t *testing.T) {
 		require.Greater(t, *block, int64(1))
 	})
 }
+
+func TestServiceTrx_GetAccountResources(t *testing.T) {
+	t.Run("ShouldGetTheFreeBandwidth", func(t *testing.T) {
+		// INIT
+		trxSvc, err := trx.NewTronImpl(config.Instance())
+		require.NoError(t, err)
+		defer trxSvc.Close()
+
+		// CODE UNDER TEST
+		resources, err := trxSvc.GetAccountResources(context.TODO(), helper.Pointer("TNjq63hm9JfqQYRRwVAtS84PRy1Ty6CU5U"))
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.NotNil(t, resources)
+		require.Greater(t, *resources.FreeBandwidthLimit, int64(0))
+	})
+}
+
+func TestServiceTrx_EstimateTransferFee(t *testing.T) {
+	t.Run("ShouldMeasureTheBandwidth", func(t *testing.T) {
+		// INIT
+		trxSvc, err := trx.NewTronImpl(config.Instance())
+		require.NoError(t, err)
+		defer trxSvc.Close()
+
+		// CODE UNDER TEST
+		estimate, err := trxSvc.EstimateTransferFee(context.TODO(), &model.TxOpts{
+			To:     helper.Pointer("TUoHaVjx7n5xz8LwPRDckgFrDWhMhuSHmd"),
+			Amount: big.NewInt(1),
+		}, helper.Pointer("TNjq63hm9JfqQYRRwVAtS84PRy1Ty6CU5U"))
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.NotNil(t, estimate)
+		require.Greater(t, *estimate.Bandwidth, int64(0))
+		require.Equal(t, *estimate.Fee, *estimate.BandwidthFee+*estimate.ActivationFee)
+	})
+}
@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+)
+
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+)
+
+// errForbiddenAddress is the error of an endpoint resolving to an address of the internal network
+var errForbiddenAddress = errors.New("webhook endpoint resolves to a forbidden address")
+
+// forbiddenNetworks are the ranges not covered by the netip.Addr helpers that still reach internal hosts
+var forbiddenNetworks = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	// NAT64 reaches the IPv4 address embedded in the last 32 bits, which can be an internal one
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+type HttpSender struct {
+	httpClient *http.Client
+}
+
+// NewHttpSender returns a sender that only reaches public addresses, the endpoints are given by the users. The
+// address is checked once resolved, right before connecting, so a name resolving to another address on the next
+// lookup is caught as well. Redirects are not followed
+func NewHttpSender(config config.Config) service.WebhookSender {
+	dialer := &net.Dialer{
+		Timeout: time.Duration(config.OutboundWebhook.TimeoutSeconds) * time.Second,
+	}
+	if !config.OutboundWebhook.AllowPrivateNetworks {
+		dialer.Control = checkAddress
+	}
+
+	return &HttpSender{
+		httpClient: &http.Client{
+			Timeout: time.Duration(config.OutboundWebhook.TimeoutSeconds) * time.Second,
+			// no proxy from the environment, it would be the one dialed and checked
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: 10 * time.Second,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (h *HttpSender) Send(ctx context.Context, url string, secret string, eventType string, deliveryId string, payload []byte) (int, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Service.Webhook.Send")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		logger.WithError(err).Warn("Failed create request")
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, eventType)
+	req.Header.Set(HeaderDelivery, deliveryId)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, "sha256="+Sign(secret, timestamp, payload))
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		logger.WithError(err).Warn("Failed post webhook")
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// the body is not kept, the delivery is shown to the user and the response is the one of the endpoint
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, model.NewStatusNotOKError(resp.StatusCode, nil)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// checkAddress refuses to connect to the loopback, private, link-local and other internal addresses, an IPv4-mapped
+// IPv6 address is checked as the IPv4 address it carries
+func checkAddress(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return errForbiddenAddress
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return errForbiddenAddress
+	}
+	for _, forbidden := range forbiddenNetworks {
+		if forbidden.Contains(ip) {
+			return errForbiddenAddress
+		}
+	}
+
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<payload>", receivers should compute the same value
+// and reject requests whose timestamp is too old
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(fmt.Sprintf("%d.", timestamp)))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,143 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/service/webhook"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceWebhook_Send(t *testing.T) {
+	t.Run("ShouldSignThePayload", func(t *testing.T) {
+		// INIT
+		var headers http.Header
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			headers = r.Header
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		cfg := config.Config{OutboundWebhook: config.OutboundWebhook{TimeoutSeconds: 5, AllowPrivateNetworks: true}}
+		sender := webhook.NewHttpSender(cfg)
+		payload := []byte(`{"type":"send_confirmed"}`)
+
+		// CODE UNDER TEST
+		statusCode, err := sender.Send(context.TODO(), server.URL, "whsec_secret", "send_confirmed", "delivery-id", payload)
+
+		// EXPECTATION
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNoContent, statusCode)
+		require.Equal(t, payload, body)
+		require.Equal(t, "send_confirmed", headers.Get(webhook.HeaderEvent))
+		require.Equal(t, "delivery-id", headers.Get(webhook.HeaderDelivery))
+
+		timestamp, err := strconv.ParseInt(headers.Get(webhook.HeaderTimestamp), 10, 64)
+		require.NoError(t, err)
+		require.Equal(t, "sha256="+webhook.Sign("whsec_secret", timestamp, payload), headers.Get(webhook.HeaderSignature))
+	})
+
+	t.Run("ShouldReturnError_WhenStatusIsNotSuccess", func(t *testing.T) {
+		// INIT
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		cfg := config.Config{OutboundWebhook: config.OutboundWebhook{TimeoutSeconds: 5, AllowPrivateNetworks: true}}
+		sender := webhook.NewHttpSender(cfg)
+
+		// CODE UNDER TEST
+		statusCode, err := sender.Send(context.TODO(), server.URL, "whsec_secret", "send_confirmed", "delivery-id", []byte(`{}`))
+
+		// EXPECTATION
+		require.Error(t, err)
+		require.Equal(t, http.StatusBadGateway, statusCode)
+	})
+
+	t.Run("ShouldNotKeepTheBody_WhenStatusIsNotSuccess", func(t *testing.T) {
+		// INIT
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("internal secret"))
+		}))
+		defer server.Close()
+
+		cfg := config.Config{OutboundWebhook: config.OutboundWebhook{TimeoutSeconds: 5, AllowPrivateNetworks: true}}
+		sender := webhook.NewHttpSender(cfg)
+
+		// CODE UNDER TEST
+		_, err := sender.Send(context.TODO(), server.URL, "whsec_secret", "send_confirmed", "delivery-id", []byte(`{}`))
+
+		// EXPECTATION
+		require.Error(t, err)
+		require.NotContains(t, err.Error(), "internal secret")
+	})
+
+	t.Run("ShouldNotFollowRedirects", func(t *testing.T) {
+		// INIT
+		redirected := false
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			redirected = true
+		}))
+		defer target.Close()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+		}))
+		defer server.Close()
+
+		cfg := config.Config{OutboundWebhook: config.OutboundWebhook{TimeoutSeconds: 5, AllowPrivateNetworks: true}}
+		sender := webhook.NewHttpSender(cfg)
+
+		// CODE UNDER TEST
+		statusCode, err := sender.Send(context.TODO(), server.URL, "whsec_secret", "send_confirmed", "delivery-id", []byte(`{}`))
+
+		// EXPECTATION
+		require.Error(t, err)
+		require.Equal(t, http.StatusTemporaryRedirect, statusCode)
+		require.False(t, redirected)
+	})
+
+	t.Run("ShouldRefuse_WhenTheEndpointResolvesToAPrivateAddress", func(t *testing.T) {
+		// INIT
+		called := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer server.Close()
+
+		cfg := config.Config{OutboundWebhook: config.OutboundWebhook{TimeoutSeconds: 5}}
+		sender := webhook.NewHttpSender(cfg)
+
+		// CODE UNDER TEST
+		statusCode, err := sender.Send(context.TODO(), server.URL, "whsec_secret", "send_confirmed", "delivery-id", []byte(`{}`))
+
+		// EXPECTATION
+		require.Error(t, err)
+		require.Equal(t, 0, statusCode)
+		require.False(t, called)
+	})
+
+	t.Run("ShouldRefuse_WhenTheAddressEmbedsAnInternalIPv4Address", func(t *testing.T) {
+		// INIT
+		cfg := config.Config{OutboundWebhook: config.OutboundWebhook{TimeoutSeconds: 5}}
+		sender := webhook.NewHttpSender(cfg)
+
+		for _, url := range []string{"http://[::ffff:127.0.0.1]:80", "http://[::ffff:10.0.0.1]:80", "http://[64:ff9b::a00:1]:80", "http://[64:ff9b:1::a00:1]:80"} {
+			// CODE UNDER TEST
+			statusCode, err := sender.Send(context.TODO(), url, "whsec_secret", "send_confirmed", "delivery-id", []byte(`{}`))
+
+			// EXPECTATION
+			require.ErrorContains(t, err, "forbidden address", url)
+			require.Equal(t, 0, statusCode)
+		}
+	})
+}
@@ -0,0 +1,8 @@
+package service
+
+import "context"
+
+type WebhookSender interface {
+	// Send posts the signed payload to url and returns the response status code
+	Send(ctx context.Context, url string, secret string, eventType string, deliveryId string, payload []byte) (int, error)
+}
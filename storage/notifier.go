@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// PostgresNotifier listens to Postgres notifications on a dedicated connection, the pooled gorm connections cannot
+// be used as LISTEN is bound to a session
+type PostgresNotifier struct {
+	dbName *string
+}
+
+func NewPostgresNotifier(dbName *string) *PostgresNotifier {
+	return &PostgresNotifier{
+		dbName: dbName,
+	}
+}
+
+func (p *PostgresNotifier) Listen(ctx context.Context, channel string) (<-chan struct{}, error) {
+	conn, err := pgx.Connect(ctx, getPostgresUrl(p.dbName))
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		conn.Close(context.Background())
+		return nil, err
+	}
+
+	notifications := make(chan struct{}, 1)
+	go func() {
+		defer close(notifications)
+		defer conn.Close(context.Background())
+
+		for {
+			if _, err := conn.WaitForNotification(ctx); err != nil {
+				if ctx.Err() == nil {
+					helper.GetLogger(ctx).WithField("method", "Storage.PostgresNotifier.Listen").WithField("channel", channel).WithError(err).Warn("lost notification connection")
+				}
+				return
+			}
+
+			select {
+			case notifications <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return notifications, nil
+}
@@ -122,6 +122,18 @@ func TruncateNonRefTables(db *gorm.DB) error {
 		gormrepo.EthTransactionRepo{},
 		gormrepo.TrxTransactionRepo{},
 		gormrepo.WalletRepo{},
+		gormrepo.WebhookEndpointRepo{},
+		gormrepo.WebhookDeliveryRepo{},
+		gormrepo.OutboxRepo{},
+		gormrepo.JobRepo{},
+		gormrepo.UserRepo{},
+		gormrepo.RefreshTokenRepo{},
+		gormrepo.SpendingLimitRepo{},
+		gormrepo.WithdrawalAddressRepo{},
+		gormrepo.ApprovalPolicyRepo{},
+		gormrepo.WithdrawalRequestRepo{},
+		gormrepo.AuditEventRepo{},
+		gormrepo.WalletEventRepo{},
 	}
 	for _, v := range models {
 		err := db.Statement.Parse(v)
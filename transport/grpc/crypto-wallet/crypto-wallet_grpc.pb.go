@@ -20,18 +20,105 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	CryptoWallet_CreateWallet_FullMethodName   = "/crypto_wallet.CryptoWallet/CreateWallet"
-	CryptoWallet_SendToken_FullMethodName      = "/crypto_wallet.CryptoWallet/SendToken"
-	CryptoWallet_TriggerWatcher_FullMethodName = "/crypto_wallet.CryptoWallet/TriggerWatcher"
+	CryptoWallet_Register_FullMethodName                  = "/crypto_wallet.CryptoWallet/Register"
+	CryptoWallet_Login_FullMethodName                     = "/crypto_wallet.CryptoWallet/Login"
+	CryptoWallet_RefreshToken_FullMethodName              = "/crypto_wallet.CryptoWallet/RefreshToken"
+	CryptoWallet_Logout_FullMethodName                    = "/crypto_wallet.CryptoWallet/Logout"
+	CryptoWallet_EnrollTOTP_FullMethodName                = "/crypto_wallet.CryptoWallet/EnrollTOTP"
+	CryptoWallet_VerifyTOTP_FullMethodName                = "/crypto_wallet.CryptoWallet/VerifyTOTP"
+	CryptoWallet_CreateWallet_FullMethodName              = "/crypto_wallet.CryptoWallet/CreateWallet"
+	CryptoWallet_SendToken_FullMethodName                 = "/crypto_wallet.CryptoWallet/SendToken"
+	CryptoWallet_GetTransaction_FullMethodName            = "/crypto_wallet.CryptoWallet/GetTransaction"
+	CryptoWallet_ListTransactions_FullMethodName          = "/crypto_wallet.CryptoWallet/ListTransactions"
+	CryptoWallet_EstimateFee_FullMethodName               = "/crypto_wallet.CryptoWallet/EstimateFee"
+	CryptoWallet_GetBalance_FullMethodName                = "/crypto_wallet.CryptoWallet/GetBalance"
+	CryptoWallet_GetTronResources_FullMethodName          = "/crypto_wallet.CryptoWallet/GetTronResources"
+	CryptoWallet_FreezeTron_FullMethodName                = "/crypto_wallet.CryptoWallet/FreezeTron"
+	CryptoWallet_UnfreezeTron_FullMethodName              = "/crypto_wallet.CryptoWallet/UnfreezeTron"
+	CryptoWallet_DelegateTronResource_FullMethodName      = "/crypto_wallet.CryptoWallet/DelegateTronResource"
+	CryptoWallet_UndelegateTronResource_FullMethodName    = "/crypto_wallet.CryptoWallet/UndelegateTronResource"
+	CryptoWallet_AddWithdrawalAddress_FullMethodName      = "/crypto_wallet.CryptoWallet/AddWithdrawalAddress"
+	CryptoWallet_RemoveWithdrawalAddress_FullMethodName   = "/crypto_wallet.CryptoWallet/RemoveWithdrawalAddress"
+	CryptoWallet_ListWithdrawalAddresses_FullMethodName   = "/crypto_wallet.CryptoWallet/ListWithdrawalAddresses"
+	CryptoWallet_SetWithdrawalAllowlist_FullMethodName    = "/crypto_wallet.CryptoWallet/SetWithdrawalAllowlist"
+	CryptoWallet_RequestWithdrawal_FullMethodName         = "/crypto_wallet.CryptoWallet/RequestWithdrawal"
+	CryptoWallet_GetWithdrawal_FullMethodName             = "/crypto_wallet.CryptoWallet/GetWithdrawal"
+	CryptoWallet_ApproveWithdrawal_FullMethodName         = "/crypto_wallet.CryptoWallet/ApproveWithdrawal"
+	CryptoWallet_RejectWithdrawal_FullMethodName          = "/crypto_wallet.CryptoWallet/RejectWithdrawal"
+	CryptoWallet_ListPendingApprovals_FullMethodName      = "/crypto_wallet.CryptoWallet/ListPendingApprovals"
+	CryptoWallet_TriggerWatcher_FullMethodName            = "/crypto_wallet.CryptoWallet/TriggerWatcher"
+	CryptoWallet_SubscribeWalletEvents_FullMethodName     = "/crypto_wallet.CryptoWallet/SubscribeWalletEvents"
+	CryptoWallet_RegisterWebhookEndpoint_FullMethodName   = "/crypto_wallet.CryptoWallet/RegisterWebhookEndpoint"
+	CryptoWallet_ListWebhookEndpoints_FullMethodName      = "/crypto_wallet.CryptoWallet/ListWebhookEndpoints"
+	CryptoWallet_DeleteWebhookEndpoint_FullMethodName     = "/crypto_wallet.CryptoWallet/DeleteWebhookEndpoint"
+	CryptoWallet_ListWebhookDeliveries_FullMethodName     = "/crypto_wallet.CryptoWallet/ListWebhookDeliveries"
+	CryptoWallet_RedeliverWebhook_FullMethodName          = "/crypto_wallet.CryptoWallet/RedeliverWebhook"
+	CryptoWallet_AdminGetWallet_FullMethodName            = "/crypto_wallet.CryptoWallet/AdminGetWallet"
+	CryptoWallet_AdminGetBalance_FullMethodName           = "/crypto_wallet.CryptoWallet/AdminGetBalance"
+	CryptoWallet_AdminListTransactions_FullMethodName     = "/crypto_wallet.CryptoWallet/AdminListTransactions"
+	CryptoWallet_AdminSetSpendingLimit_FullMethodName     = "/crypto_wallet.CryptoWallet/AdminSetSpendingLimit"
+	CryptoWallet_AdminListSpendingLimits_FullMethodName   = "/crypto_wallet.CryptoWallet/AdminListSpendingLimits"
+	CryptoWallet_AdminDeleteSpendingLimit_FullMethodName  = "/crypto_wallet.CryptoWallet/AdminDeleteSpendingLimit"
+	CryptoWallet_AdminSetApprovalPolicy_FullMethodName    = "/crypto_wallet.CryptoWallet/AdminSetApprovalPolicy"
+	CryptoWallet_AdminListApprovalPolicies_FullMethodName = "/crypto_wallet.CryptoWallet/AdminListApprovalPolicies"
+	CryptoWallet_AdminListAuditEvents_FullMethodName      = "/crypto_wallet.CryptoWallet/AdminListAuditEvents"
 )
 
 // CryptoWalletClient is the client API for CryptoWallet service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type CryptoWalletClient interface {
+	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*User, error)
+	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	Logout(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// EnrollTOTP starts the enrollment of an authenticator app, VerifyTOTP enables it with a first valid code. Once
+	// enabled every SendToken needs a code
+	EnrollTOTP(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*EnrollTOTPResponse, error)
+	VerifyTOTP(ctx context.Context, in *VerifyTOTPRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	CreateWallet(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CreteWalletResponse, error)
 	SendToken(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error)
+	GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*Transaction, error)
+	ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error)
+	EstimateFee(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*FeeEstimate, error)
+	GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*Balance, error)
+	GetTronResources(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*TronResources, error)
+	FreezeTron(ctx context.Context, in *TronStakeRequest, opts ...grpc.CallOption) (*SendResponse, error)
+	UnfreezeTron(ctx context.Context, in *TronStakeRequest, opts ...grpc.CallOption) (*SendResponse, error)
+	DelegateTronResource(ctx context.Context, in *TronStakeRequest, opts ...grpc.CallOption) (*SendResponse, error)
+	UndelegateTronResource(ctx context.Context, in *TronStakeRequest, opts ...grpc.CallOption) (*SendResponse, error)
+	// the withdrawal addresses are usable once their cooling-off period is over, SendToken only accepts them while the
+	// allowlist is on. Switching the allowlist off takes effect after the cooling-off period
+	AddWithdrawalAddress(ctx context.Context, in *AddWithdrawalAddressRequest, opts ...grpc.CallOption) (*WithdrawalAddress, error)
+	RemoveWithdrawalAddress(ctx context.Context, in *RemoveWithdrawalAddressRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListWithdrawalAddresses(ctx context.Context, in *ListWithdrawalAddressesRequest, opts ...grpc.CallOption) (*ListWithdrawalAddressesResponse, error)
+	SetWithdrawalAllowlist(ctx context.Context, in *SetWithdrawalAllowlistRequest, opts ...grpc.CallOption) (*WithdrawalAllowlist, error)
+	// the transfers above the threshold of the approval policy of the wallet are requested with RequestWithdrawal
+	// instead of SendToken, they are signed once enough approvers approved them. A single rejection rejects them
+	RequestWithdrawal(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*WithdrawalRequest, error)
+	GetWithdrawal(ctx context.Context, in *GetWithdrawalRequest, opts ...grpc.CallOption) (*WithdrawalRequest, error)
+	ApproveWithdrawal(ctx context.Context, in *ApproveWithdrawalRequest, opts ...grpc.CallOption) (*WithdrawalRequest, error)
+	RejectWithdrawal(ctx context.Context, in *RejectWithdrawalRequest, opts ...grpc.CallOption) (*WithdrawalRequest, error)
+	ListPendingApprovals(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListWithdrawalRequestsResponse, error)
 	TriggerWatcher(ctx context.Context, in *TriggerWatcherRequest, opts ...grpc.CallOption) (*TriggerWatcherResponse, error)
+	SubscribeWalletEvents(ctx context.Context, in *SubscribeWalletEventsRequest, opts ...grpc.CallOption) (CryptoWallet_SubscribeWalletEventsClient, error)
+	RegisterWebhookEndpoint(ctx context.Context, in *RegisterWebhookEndpointRequest, opts ...grpc.CallOption) (*WebhookEndpoint, error)
+	ListWebhookEndpoints(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListWebhookEndpointsResponse, error)
+	DeleteWebhookEndpoint(ctx context.Context, in *DeleteWebhookEndpointRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+	RedeliverWebhook(ctx context.Context, in *RedeliverWebhookRequest, opts ...grpc.CallOption) (*WebhookDelivery, error)
+	// the admin methods act on the wallet of the user with the id, they need the admin scope
+	AdminGetWallet(ctx context.Context, in *AdminGetWalletRequest, opts ...grpc.CallOption) (*CreteWalletResponse, error)
+	AdminGetBalance(ctx context.Context, in *AdminGetBalanceRequest, opts ...grpc.CallOption) (*Balance, error)
+	AdminListTransactions(ctx context.Context, in *AdminListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error)
+	// AdminSetSpendingLimit sets the limit of the user on the chain, or the global limit of the chain without a user id
+	AdminSetSpendingLimit(ctx context.Context, in *SpendingLimit, opts ...grpc.CallOption) (*SpendingLimit, error)
+	AdminListSpendingLimits(ctx context.Context, in *AdminListSpendingLimitsRequest, opts ...grpc.CallOption) (*ListSpendingLimitsResponse, error)
+	AdminDeleteSpendingLimit(ctx context.Context, in *AdminDeleteSpendingLimitRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	AdminSetApprovalPolicy(ctx context.Context, in *ApprovalPolicy, opts ...grpc.CallOption) (*ApprovalPolicy, error)
+	AdminListApprovalPolicies(ctx context.Context, in *AdminListApprovalPoliciesRequest, opts ...grpc.CallOption) (*ListApprovalPoliciesResponse, error)
+	// AdminListAuditEvents pages through the audit log in the order it was written
+	AdminListAuditEvents(ctx context.Context, in *AdminListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error)
 }
 
 type cryptoWalletClient struct {
@@ -42,119 +129,1363 @@ func NewCryptoWalletClient(cc grpc.ClientConnInterface) CryptoWalletClient {
 	return &cryptoWalletClient{cc}
 }
 
+func (c *cryptoWalletClient) Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, CryptoWallet_Register_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_Login_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_RefreshToken_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) Logout(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, CryptoWallet_Logout_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) EnrollTOTP(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*EnrollTOTPResponse, error) {
+	out := new(EnrollTOTPResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_EnrollTOTP_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) VerifyTOTP(ctx context.Context, in *VerifyTOTPRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, CryptoWallet_VerifyTOTP_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cryptoWalletClient) CreateWallet(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*CreteWalletResponse, error) {
 	out := new(CreteWalletResponse)
 	err := c.cc.Invoke(ctx, CryptoWallet_CreateWallet_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	return out, nil
+}
+
+func (c *cryptoWalletClient) SendToken(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error) {
+	out := new(SendResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_SendToken_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*Transaction, error) {
+	out := new(Transaction)
+	err := c.cc.Invoke(ctx, CryptoWallet_GetTransaction_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error) {
+	out := new(ListTransactionsResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_ListTransactions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) EstimateFee(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*FeeEstimate, error) {
+	out := new(FeeEstimate)
+	err := c.cc.Invoke(ctx, CryptoWallet_EstimateFee_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*Balance, error) {
+	out := new(Balance)
+	err := c.cc.Invoke(ctx, CryptoWallet_GetBalance_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) GetTronResources(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*TronResources, error) {
+	out := new(TronResources)
+	err := c.cc.Invoke(ctx, CryptoWallet_GetTronResources_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) FreezeTron(ctx context.Context, in *TronStakeRequest, opts ...grpc.CallOption) (*SendResponse, error) {
+	out := new(SendResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_FreezeTron_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) UnfreezeTron(ctx context.Context, in *TronStakeRequest, opts ...grpc.CallOption) (*SendResponse, error) {
+	out := new(SendResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_UnfreezeTron_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) DelegateTronResource(ctx context.Context, in *TronStakeRequest, opts ...grpc.CallOption) (*SendResponse, error) {
+	out := new(SendResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_DelegateTronResource_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) UndelegateTronResource(ctx context.Context, in *TronStakeRequest, opts ...grpc.CallOption) (*SendResponse, error) {
+	out := new(SendResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_UndelegateTronResource_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) AddWithdrawalAddress(ctx context.Context, in *AddWithdrawalAddressRequest, opts ...grpc.CallOption) (*WithdrawalAddress, error) {
+	out := new(WithdrawalAddress)
+	err := c.cc.Invoke(ctx, CryptoWallet_AddWithdrawalAddress_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) RemoveWithdrawalAddress(ctx context.Context, in *RemoveWithdrawalAddressRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, CryptoWallet_RemoveWithdrawalAddress_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) ListWithdrawalAddresses(ctx context.Context, in *ListWithdrawalAddressesRequest, opts ...grpc.CallOption) (*ListWithdrawalAddressesResponse, error) {
+	out := new(ListWithdrawalAddressesResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_ListWithdrawalAddresses_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) SetWithdrawalAllowlist(ctx context.Context, in *SetWithdrawalAllowlistRequest, opts ...grpc.CallOption) (*WithdrawalAllowlist, error) {
+	out := new(WithdrawalAllowlist)
+	err := c.cc.Invoke(ctx, CryptoWallet_SetWithdrawalAllowlist_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) RequestWithdrawal(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*WithdrawalRequest, error) {
+	out := new(WithdrawalRequest)
+	err := c.cc.Invoke(ctx, CryptoWallet_RequestWithdrawal_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) GetWithdrawal(ctx context.Context, in *GetWithdrawalRequest, opts ...grpc.CallOption) (*WithdrawalRequest, error) {
+	out := new(WithdrawalRequest)
+	err := c.cc.Invoke(ctx, CryptoWallet_GetWithdrawal_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) ApproveWithdrawal(ctx context.Context, in *ApproveWithdrawalRequest, opts ...grpc.CallOption) (*WithdrawalRequest, error) {
+	out := new(WithdrawalRequest)
+	err := c.cc.Invoke(ctx, CryptoWallet_ApproveWithdrawal_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) RejectWithdrawal(ctx context.Context, in *RejectWithdrawalRequest, opts ...grpc.CallOption) (*WithdrawalRequest, error) {
+	out := new(WithdrawalRequest)
+	err := c.cc.Invoke(ctx, CryptoWallet_RejectWithdrawal_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) ListPendingApprovals(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListWithdrawalRequestsResponse, error) {
+	out := new(ListWithdrawalRequestsResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_ListPendingApprovals_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) TriggerWatcher(ctx context.Context, in *TriggerWatcherRequest, opts ...grpc.CallOption) (*TriggerWatcherResponse, error) {
+	out := new(TriggerWatcherResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_TriggerWatcher_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) SubscribeWalletEvents(ctx context.Context, in *SubscribeWalletEventsRequest, opts ...grpc.CallOption) (CryptoWallet_SubscribeWalletEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &CryptoWallet_ServiceDesc.Streams[0], CryptoWallet_SubscribeWalletEvents_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cryptoWalletSubscribeWalletEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type CryptoWallet_SubscribeWalletEventsClient interface {
+	Recv() (*WalletEvent, error)
+	grpc.ClientStream
+}
+
+type cryptoWalletSubscribeWalletEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *cryptoWalletSubscribeWalletEventsClient) Recv() (*WalletEvent, error) {
+	m := new(WalletEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *cryptoWalletClient) RegisterWebhookEndpoint(ctx context.Context, in *RegisterWebhookEndpointRequest, opts ...grpc.CallOption) (*WebhookEndpoint, error) {
+	out := new(WebhookEndpoint)
+	err := c.cc.Invoke(ctx, CryptoWallet_RegisterWebhookEndpoint_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) ListWebhookEndpoints(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListWebhookEndpointsResponse, error) {
+	out := new(ListWebhookEndpointsResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_ListWebhookEndpoints_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) DeleteWebhookEndpoint(ctx context.Context, in *DeleteWebhookEndpointRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, CryptoWallet_DeleteWebhookEndpoint_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_ListWebhookDeliveries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) RedeliverWebhook(ctx context.Context, in *RedeliverWebhookRequest, opts ...grpc.CallOption) (*WebhookDelivery, error) {
+	out := new(WebhookDelivery)
+	err := c.cc.Invoke(ctx, CryptoWallet_RedeliverWebhook_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) AdminGetWallet(ctx context.Context, in *AdminGetWalletRequest, opts ...grpc.CallOption) (*CreteWalletResponse, error) {
+	out := new(CreteWalletResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_AdminGetWallet_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) AdminGetBalance(ctx context.Context, in *AdminGetBalanceRequest, opts ...grpc.CallOption) (*Balance, error) {
+	out := new(Balance)
+	err := c.cc.Invoke(ctx, CryptoWallet_AdminGetBalance_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) AdminListTransactions(ctx context.Context, in *AdminListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error) {
+	out := new(ListTransactionsResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_AdminListTransactions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) AdminSetSpendingLimit(ctx context.Context, in *SpendingLimit, opts ...grpc.CallOption) (*SpendingLimit, error) {
+	out := new(SpendingLimit)
+	err := c.cc.Invoke(ctx, CryptoWallet_AdminSetSpendingLimit_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) AdminListSpendingLimits(ctx context.Context, in *AdminListSpendingLimitsRequest, opts ...grpc.CallOption) (*ListSpendingLimitsResponse, error) {
+	out := new(ListSpendingLimitsResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_AdminListSpendingLimits_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) AdminDeleteSpendingLimit(ctx context.Context, in *AdminDeleteSpendingLimitRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, CryptoWallet_AdminDeleteSpendingLimit_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) AdminSetApprovalPolicy(ctx context.Context, in *ApprovalPolicy, opts ...grpc.CallOption) (*ApprovalPolicy, error) {
+	out := new(ApprovalPolicy)
+	err := c.cc.Invoke(ctx, CryptoWallet_AdminSetApprovalPolicy_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) AdminListApprovalPolicies(ctx context.Context, in *AdminListApprovalPoliciesRequest, opts ...grpc.CallOption) (*ListApprovalPoliciesResponse, error) {
+	out := new(ListApprovalPoliciesResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_AdminListApprovalPolicies_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoWalletClient) AdminListAuditEvents(ctx context.Context, in *AdminListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error) {
+	out := new(ListAuditEventsResponse)
+	err := c.cc.Invoke(ctx, CryptoWallet_AdminListAuditEvents_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CryptoWalletServer is the server API for CryptoWallet service.
+// All implementations must embed UnimplementedCryptoWalletServer
+// for forward compatibility
+type CryptoWalletServer interface {
+	Register(context.Context, *RegisterRequest) (*User, error)
+	Login(context.Context, *LoginRequest) (*LoginResponse, error)
+	RefreshToken(context.Context, *RefreshTokenRequest) (*LoginResponse, error)
+	Logout(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	// EnrollTOTP starts the enrollment of an authenticator app, VerifyTOTP enables it with a first valid code. Once
+	// enabled every SendToken needs a code
+	EnrollTOTP(context.Context, *emptypb.Empty) (*EnrollTOTPResponse, error)
+	VerifyTOTP(context.Context, *VerifyTOTPRequest) (*emptypb.Empty, error)
+	CreateWallet(context.Context, *emptypb.Empty) (*CreteWalletResponse, error)
+	SendToken(context.Context, *SendRequest) (*SendResponse, error)
+	GetTransaction(context.Context, *GetTransactionRequest) (*Transaction, error)
+	ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsResponse, error)
+	EstimateFee(context.Context, *SendRequest) (*FeeEstimate, error)
+	GetBalance(context.Context, *GetBalanceRequest) (*Balance, error)
+	GetTronResources(context.Context, *emptypb.Empty) (*TronResources, error)
+	FreezeTron(context.Context, *TronStakeRequest) (*SendResponse, error)
+	UnfreezeTron(context.Context, *TronStakeRequest) (*SendResponse, error)
+	DelegateTronResource(context.Context, *TronStakeRequest) (*SendResponse, error)
+	UndelegateTronResource(context.Context, *TronStakeRequest) (*SendResponse, error)
+	// the withdrawal addresses are usable once their cooling-off period is over, SendToken only accepts them while the
+	// allowlist is on. Switching the allowlist off takes effect after the cooling-off period
+	AddWithdrawalAddress(context.Context, *AddWithdrawalAddressRequest) (*WithdrawalAddress, error)
+	RemoveWithdrawalAddress(context.Context, *RemoveWithdrawalAddressRequest) (*emptypb.Empty, error)
+	ListWithdrawalAddresses(context.Context, *ListWithdrawalAddressesRequest) (*ListWithdrawalAddressesResponse, error)
+	SetWithdrawalAllowlist(context.Context, *SetWithdrawalAllowlistRequest) (*WithdrawalAllowlist, error)
+	// the transfers above the threshold of the approval policy of the wallet are requested with RequestWithdrawal
+	// instead of SendToken, they are signed once enough approvers approved them. A single rejection rejects them
+	RequestWithdrawal(context.Context, *SendRequest) (*WithdrawalRequest, error)
+	GetWithdrawal(context.Context, *GetWithdrawalRequest) (*WithdrawalRequest, error)
+	ApproveWithdrawal(context.Context, *ApproveWithdrawalRequest) (*WithdrawalRequest, error)
+	RejectWithdrawal(context.Context, *RejectWithdrawalRequest) (*WithdrawalRequest, error)
+	ListPendingApprovals(context.Context, *emptypb.Empty) (*ListWithdrawalRequestsResponse, error)
+	TriggerWatcher(context.Context, *TriggerWatcherRequest) (*TriggerWatcherResponse, error)
+	SubscribeWalletEvents(*SubscribeWalletEventsRequest, CryptoWallet_SubscribeWalletEventsServer) error
+	RegisterWebhookEndpoint(context.Context, *RegisterWebhookEndpointRequest) (*WebhookEndpoint, error)
+	ListWebhookEndpoints(context.Context, *emptypb.Empty) (*ListWebhookEndpointsResponse, error)
+	DeleteWebhookEndpoint(context.Context, *DeleteWebhookEndpointRequest) (*emptypb.Empty, error)
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+	RedeliverWebhook(context.Context, *RedeliverWebhookRequest) (*WebhookDelivery, error)
+	// the admin methods act on the wallet of the user with the id, they need the admin scope
+	AdminGetWallet(context.Context, *AdminGetWalletRequest) (*CreteWalletResponse, error)
+	AdminGetBalance(context.Context, *AdminGetBalanceRequest) (*Balance, error)
+	AdminListTransactions(context.Context, *AdminListTransactionsRequest) (*ListTransactionsResponse, error)
+	// AdminSetSpendingLimit sets the limit of the user on the chain, or the global limit of the chain without a user id
+	AdminSetSpendingLimit(context.Context, *SpendingLimit) (*SpendingLimit, error)
+	AdminListSpendingLimits(context.Context, *AdminListSpendingLimitsRequest) (*ListSpendingLimitsResponse, error)
+	AdminDeleteSpendingLimit(context.Context, *AdminDeleteSpendingLimitRequest) (*emptypb.Empty, error)
+	AdminSetApprovalPolicy(context.Context, *ApprovalPolicy) (*ApprovalPolicy, error)
+	AdminListApprovalPolicies(context.Context, *AdminListApprovalPoliciesRequest) (*ListApprovalPoliciesResponse, error)
+	// AdminListAuditEvents pages through the audit log in the order it was written
+	AdminListAuditEvents(context.Context, *AdminListAuditEventsRequest) (*ListAuditEventsResponse, error)
+	mustEmbedUnimplementedCryptoWalletServer()
+}
+
+// UnimplementedCryptoWalletServer must be embedded to have forward compatible implementations.
+type UnimplementedCryptoWalletServer struct {
+}
+
+func (UnimplementedCryptoWalletServer) Register(context.Context, *RegisterRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (UnimplementedCryptoWalletServer) Login(context.Context, *LoginRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
+}
+func (UnimplementedCryptoWalletServer) RefreshToken(context.Context, *RefreshTokenRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshToken not implemented")
+}
+func (UnimplementedCryptoWalletServer) Logout(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (UnimplementedCryptoWalletServer) EnrollTOTP(context.Context, *emptypb.Empty) (*EnrollTOTPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollTOTP not implemented")
+}
+func (UnimplementedCryptoWalletServer) VerifyTOTP(context.Context, *VerifyTOTPRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyTOTP not implemented")
+}
+func (UnimplementedCryptoWalletServer) CreateWallet(context.Context, *emptypb.Empty) (*CreteWalletResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWallet not implemented")
+}
+func (UnimplementedCryptoWalletServer) SendToken(context.Context, *SendRequest) (*SendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendToken not implemented")
+}
+func (UnimplementedCryptoWalletServer) GetTransaction(context.Context, *GetTransactionRequest) (*Transaction, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransaction not implemented")
+}
+func (UnimplementedCryptoWalletServer) ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTransactions not implemented")
+}
+func (UnimplementedCryptoWalletServer) EstimateFee(context.Context, *SendRequest) (*FeeEstimate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EstimateFee not implemented")
+}
+func (UnimplementedCryptoWalletServer) GetBalance(context.Context, *GetBalanceRequest) (*Balance, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
+}
+func (UnimplementedCryptoWalletServer) GetTronResources(context.Context, *emptypb.Empty) (*TronResources, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTronResources not implemented")
+}
+func (UnimplementedCryptoWalletServer) FreezeTron(context.Context, *TronStakeRequest) (*SendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FreezeTron not implemented")
+}
+func (UnimplementedCryptoWalletServer) UnfreezeTron(context.Context, *TronStakeRequest) (*SendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnfreezeTron not implemented")
+}
+func (UnimplementedCryptoWalletServer) DelegateTronResource(context.Context, *TronStakeRequest) (*SendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DelegateTronResource not implemented")
+}
+func (UnimplementedCryptoWalletServer) UndelegateTronResource(context.Context, *TronStakeRequest) (*SendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UndelegateTronResource not implemented")
+}
+func (UnimplementedCryptoWalletServer) AddWithdrawalAddress(context.Context, *AddWithdrawalAddressRequest) (*WithdrawalAddress, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddWithdrawalAddress not implemented")
+}
+func (UnimplementedCryptoWalletServer) RemoveWithdrawalAddress(context.Context, *RemoveWithdrawalAddressRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveWithdrawalAddress not implemented")
+}
+func (UnimplementedCryptoWalletServer) ListWithdrawalAddresses(context.Context, *ListWithdrawalAddressesRequest) (*ListWithdrawalAddressesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWithdrawalAddresses not implemented")
+}
+func (UnimplementedCryptoWalletServer) SetWithdrawalAllowlist(context.Context, *SetWithdrawalAllowlistRequest) (*WithdrawalAllowlist, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetWithdrawalAllowlist not implemented")
+}
+func (UnimplementedCryptoWalletServer) RequestWithdrawal(context.Context, *SendRequest) (*WithdrawalRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestWithdrawal not implemented")
+}
+func (UnimplementedCryptoWalletServer) GetWithdrawal(context.Context, *GetWithdrawalRequest) (*WithdrawalRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWithdrawal not implemented")
+}
+func (UnimplementedCryptoWalletServer) ApproveWithdrawal(context.Context, *ApproveWithdrawalRequest) (*WithdrawalRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveWithdrawal not implemented")
+}
+func (UnimplementedCryptoWalletServer) RejectWithdrawal(context.Context, *RejectWithdrawalRequest) (*WithdrawalRequest, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectWithdrawal not implemented")
+}
+func (UnimplementedCryptoWalletServer) ListPendingApprovals(context.Context, *emptypb.Empty) (*ListWithdrawalRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPendingApprovals not implemented")
+}
+func (UnimplementedCryptoWalletServer) TriggerWatcher(context.Context, *TriggerWatcherRequest) (*TriggerWatcherResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TriggerWatcher not implemented")
+}
+func (UnimplementedCryptoWalletServer) SubscribeWalletEvents(*SubscribeWalletEventsRequest, CryptoWallet_SubscribeWalletEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeWalletEvents not implemented")
+}
+func (UnimplementedCryptoWalletServer) RegisterWebhookEndpoint(context.Context, *RegisterWebhookEndpointRequest) (*WebhookEndpoint, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterWebhookEndpoint not implemented")
+}
+func (UnimplementedCryptoWalletServer) ListWebhookEndpoints(context.Context, *emptypb.Empty) (*ListWebhookEndpointsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookEndpoints not implemented")
+}
+func (UnimplementedCryptoWalletServer) DeleteWebhookEndpoint(context.Context, *DeleteWebhookEndpointRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhookEndpoint not implemented")
+}
+func (UnimplementedCryptoWalletServer) ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
+func (UnimplementedCryptoWalletServer) RedeliverWebhook(context.Context, *RedeliverWebhookRequest) (*WebhookDelivery, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RedeliverWebhook not implemented")
+}
+func (UnimplementedCryptoWalletServer) AdminGetWallet(context.Context, *AdminGetWalletRequest) (*CreteWalletResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminGetWallet not implemented")
+}
+func (UnimplementedCryptoWalletServer) AdminGetBalance(context.Context, *AdminGetBalanceRequest) (*Balance, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminGetBalance not implemented")
+}
+func (UnimplementedCryptoWalletServer) AdminListTransactions(context.Context, *AdminListTransactionsRequest) (*ListTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminListTransactions not implemented")
+}
+func (UnimplementedCryptoWalletServer) AdminSetSpendingLimit(context.Context, *SpendingLimit) (*SpendingLimit, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminSetSpendingLimit not implemented")
+}
+func (UnimplementedCryptoWalletServer) AdminListSpendingLimits(context.Context, *AdminListSpendingLimitsRequest) (*ListSpendingLimitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminListSpendingLimits not implemented")
+}
+func (UnimplementedCryptoWalletServer) AdminDeleteSpendingLimit(context.Context, *AdminDeleteSpendingLimitRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminDeleteSpendingLimit not implemented")
+}
+func (UnimplementedCryptoWalletServer) AdminSetApprovalPolicy(context.Context, *ApprovalPolicy) (*ApprovalPolicy, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminSetApprovalPolicy not implemented")
+}
+func (UnimplementedCryptoWalletServer) AdminListApprovalPolicies(context.Context, *AdminListApprovalPoliciesRequest) (*ListApprovalPoliciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminListApprovalPolicies not implemented")
+}
+func (UnimplementedCryptoWalletServer) AdminListAuditEvents(context.Context, *AdminListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminListAuditEvents not implemented")
+}
+func (UnimplementedCryptoWalletServer) mustEmbedUnimplementedCryptoWalletServer() {}
+
+// UnsafeCryptoWalletServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CryptoWalletServer will
+// result in compilation errors.
+type UnsafeCryptoWalletServer interface {
+	mustEmbedUnimplementedCryptoWalletServer()
+}
+
+func RegisterCryptoWalletServer(s grpc.ServiceRegistrar, srv CryptoWalletServer) {
+	s.RegisterService(&CryptoWallet_ServiceDesc, srv)
+}
+
+func _CryptoWallet_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_Register_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).Register(ctx, req.(*RegisterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_Login_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_RefreshToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).RefreshToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_RefreshToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).RefreshToken(ctx, req.(*RefreshTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_Logout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).Logout(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_EnrollTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).EnrollTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_EnrollTOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).EnrollTOTP(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_VerifyTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyTOTPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).VerifyTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_VerifyTOTP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).VerifyTOTP(ctx, req.(*VerifyTOTPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_CreateWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).CreateWallet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_CreateWallet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).CreateWallet(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_SendToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).SendToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_SendToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).SendToken(ctx, req.(*SendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).GetTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_GetTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).GetTransaction(ctx, req.(*GetTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_ListTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).ListTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_ListTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).ListTransactions(ctx, req.(*ListTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_EstimateFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).EstimateFee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_EstimateFee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).EstimateFee(ctx, req.(*SendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).GetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_GetBalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).GetBalance(ctx, req.(*GetBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_GetTronResources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).GetTronResources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_GetTronResources_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).GetTronResources(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_FreezeTron_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TronStakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).FreezeTron(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_FreezeTron_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).FreezeTron(ctx, req.(*TronStakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_UnfreezeTron_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TronStakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).UnfreezeTron(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_UnfreezeTron_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).UnfreezeTron(ctx, req.(*TronStakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_DelegateTronResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TronStakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).DelegateTronResource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_DelegateTronResource_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).DelegateTronResource(ctx, req.(*TronStakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_UndelegateTronResource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TronStakeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).UndelegateTronResource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_UndelegateTronResource_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).UndelegateTronResource(ctx, req.(*TronStakeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_AddWithdrawalAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddWithdrawalAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).AddWithdrawalAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_AddWithdrawalAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).AddWithdrawalAddress(ctx, req.(*AddWithdrawalAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_RemoveWithdrawalAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveWithdrawalAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).RemoveWithdrawalAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_RemoveWithdrawalAddress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).RemoveWithdrawalAddress(ctx, req.(*RemoveWithdrawalAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_ListWithdrawalAddresses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWithdrawalAddressesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).ListWithdrawalAddresses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_ListWithdrawalAddresses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).ListWithdrawalAddresses(ctx, req.(*ListWithdrawalAddressesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_SetWithdrawalAllowlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetWithdrawalAllowlistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).SetWithdrawalAllowlist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_SetWithdrawalAllowlist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).SetWithdrawalAllowlist(ctx, req.(*SetWithdrawalAllowlistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_RequestWithdrawal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).RequestWithdrawal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_RequestWithdrawal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).RequestWithdrawal(ctx, req.(*SendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_GetWithdrawal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWithdrawalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).GetWithdrawal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_GetWithdrawal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).GetWithdrawal(ctx, req.(*GetWithdrawalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_ApproveWithdrawal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveWithdrawalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).ApproveWithdrawal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_ApproveWithdrawal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).ApproveWithdrawal(ctx, req.(*ApproveWithdrawalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_RejectWithdrawal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectWithdrawalRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).RejectWithdrawal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_RejectWithdrawal_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).RejectWithdrawal(ctx, req.(*RejectWithdrawalRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_ListPendingApprovals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).ListPendingApprovals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_ListPendingApprovals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).ListPendingApprovals(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_TriggerWatcher_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerWatcherRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).TriggerWatcher(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_TriggerWatcher_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).TriggerWatcher(ctx, req.(*TriggerWatcherRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_SubscribeWalletEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeWalletEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CryptoWalletServer).SubscribeWalletEvents(m, &cryptoWalletSubscribeWalletEventsServer{stream})
+}
+
+type CryptoWallet_SubscribeWalletEventsServer interface {
+	Send(*WalletEvent) error
+	grpc.ServerStream
+}
+
+type cryptoWalletSubscribeWalletEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *cryptoWalletSubscribeWalletEventsServer) Send(m *WalletEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _CryptoWallet_RegisterWebhookEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterWebhookEndpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).RegisterWebhookEndpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_RegisterWebhookEndpoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).RegisterWebhookEndpoint(ctx, req.(*RegisterWebhookEndpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *cryptoWalletClient) SendToken(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error) {
-	out := new(SendResponse)
-	err := c.cc.Invoke(ctx, CryptoWallet_SendToken_FullMethodName, in, out, opts...)
-	if err != nil {
+func _CryptoWallet_ListWebhookEndpoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).ListWebhookEndpoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_ListWebhookEndpoints_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).ListWebhookEndpoints(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *cryptoWalletClient) TriggerWatcher(ctx context.Context, in *TriggerWatcherRequest, opts ...grpc.CallOption) (*TriggerWatcherResponse, error) {
-	out := new(TriggerWatcherResponse)
-	err := c.cc.Invoke(ctx, CryptoWallet_TriggerWatcher_FullMethodName, in, out, opts...)
-	if err != nil {
+func _CryptoWallet_DeleteWebhookEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookEndpointRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).DeleteWebhookEndpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_DeleteWebhookEndpoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).DeleteWebhookEndpoint(ctx, req.(*DeleteWebhookEndpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// CryptoWalletServer is the server API for CryptoWallet service.
-// All implementations must embed UnimplementedCryptoWalletServer
-// for forward compatibility
-type CryptoWalletServer interface {
-	CreateWallet(context.Context, *emptypb.Empty) (*CreteWalletResponse, error)
-	SendToken(context.Context, *SendRequest) (*SendResponse, error)
-	TriggerWatcher(context.Context, *TriggerWatcherRequest) (*TriggerWatcherResponse, error)
-	mustEmbedUnimplementedCryptoWalletServer()
+func _CryptoWallet_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_ListWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// UnimplementedCryptoWalletServer must be embedded to have forward compatible implementations.
-type UnimplementedCryptoWalletServer struct {
+func _CryptoWallet_RedeliverWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedeliverWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).RedeliverWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_RedeliverWebhook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).RedeliverWebhook(ctx, req.(*RedeliverWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (UnimplementedCryptoWalletServer) CreateWallet(context.Context, *emptypb.Empty) (*CreteWalletResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateWallet not implemented")
+func _CryptoWallet_AdminGetWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminGetWalletRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).AdminGetWallet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_AdminGetWallet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).AdminGetWallet(ctx, req.(*AdminGetWalletRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedCryptoWalletServer) SendToken(context.Context, *SendRequest) (*SendResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SendToken not implemented")
+
+func _CryptoWallet_AdminGetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminGetBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).AdminGetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_AdminGetBalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).AdminGetBalance(ctx, req.(*AdminGetBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedCryptoWalletServer) TriggerWatcher(context.Context, *TriggerWatcherRequest) (*TriggerWatcherResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method TriggerWatcher not implemented")
+
+func _CryptoWallet_AdminListTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminListTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).AdminListTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_AdminListTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).AdminListTransactions(ctx, req.(*AdminListTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedCryptoWalletServer) mustEmbedUnimplementedCryptoWalletServer() {}
 
-// UnsafeCryptoWalletServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to CryptoWalletServer will
-// result in compilation errors.
-type UnsafeCryptoWalletServer interface {
-	mustEmbedUnimplementedCryptoWalletServer()
+func _CryptoWallet_AdminSetSpendingLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpendingLimit)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).AdminSetSpendingLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_AdminSetSpendingLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).AdminSetSpendingLimit(ctx, req.(*SpendingLimit))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterCryptoWalletServer(s grpc.ServiceRegistrar, srv CryptoWalletServer) {
-	s.RegisterService(&CryptoWallet_ServiceDesc, srv)
+func _CryptoWallet_AdminListSpendingLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminListSpendingLimitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).AdminListSpendingLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_AdminListSpendingLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).AdminListSpendingLimits(ctx, req.(*AdminListSpendingLimitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _CryptoWallet_CreateWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(emptypb.Empty)
+func _CryptoWallet_AdminDeleteSpendingLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminDeleteSpendingLimitRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(CryptoWalletServer).CreateWallet(ctx, in)
+		return srv.(CryptoWalletServer).AdminDeleteSpendingLimit(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: CryptoWallet_CreateWallet_FullMethodName,
+		FullMethod: CryptoWallet_AdminDeleteSpendingLimit_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CryptoWalletServer).CreateWallet(ctx, req.(*emptypb.Empty))
+		return srv.(CryptoWalletServer).AdminDeleteSpendingLimit(ctx, req.(*AdminDeleteSpendingLimitRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _CryptoWallet_SendToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendRequest)
+func _CryptoWallet_AdminSetApprovalPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApprovalPolicy)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(CryptoWalletServer).SendToken(ctx, in)
+		return srv.(CryptoWalletServer).AdminSetApprovalPolicy(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: CryptoWallet_SendToken_FullMethodName,
+		FullMethod: CryptoWallet_AdminSetApprovalPolicy_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CryptoWalletServer).SendToken(ctx, req.(*SendRequest))
+		return srv.(CryptoWalletServer).AdminSetApprovalPolicy(ctx, req.(*ApprovalPolicy))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _CryptoWallet_TriggerWatcher_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TriggerWatcherRequest)
+func _CryptoWallet_AdminListApprovalPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminListApprovalPoliciesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(CryptoWalletServer).TriggerWatcher(ctx, in)
+		return srv.(CryptoWalletServer).AdminListApprovalPolicies(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: CryptoWallet_TriggerWatcher_FullMethodName,
+		FullMethod: CryptoWallet_AdminListApprovalPolicies_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CryptoWalletServer).TriggerWatcher(ctx, req.(*TriggerWatcherRequest))
+		return srv.(CryptoWalletServer).AdminListApprovalPolicies(ctx, req.(*AdminListApprovalPoliciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoWallet_AdminListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminListAuditEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoWalletServer).AdminListAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoWallet_AdminListAuditEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoWalletServer).AdminListAuditEvents(ctx, req.(*AdminListAuditEventsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -166,6 +1497,30 @@ var CryptoWallet_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "crypto_wallet.CryptoWallet",
 	HandlerType: (*CryptoWalletServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Register",
+			Handler:    _CryptoWallet_Register_Handler,
+		},
+		{
+			MethodName: "Login",
+			Handler:    _CryptoWallet_Login_Handler,
+		},
+		{
+			MethodName: "RefreshToken",
+			Handler:    _CryptoWallet_RefreshToken_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _CryptoWallet_Logout_Handler,
+		},
+		{
+			MethodName: "EnrollTOTP",
+			Handler:    _CryptoWallet_EnrollTOTP_Handler,
+		},
+		{
+			MethodName: "VerifyTOTP",
+			Handler:    _CryptoWallet_VerifyTOTP_Handler,
+		},
 		{
 			MethodName: "CreateWallet",
 			Handler:    _CryptoWallet_CreateWallet_Handler,
@@ -174,11 +1529,145 @@ var CryptoWallet_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SendToken",
 			Handler:    _CryptoWallet_SendToken_Handler,
 		},
+		{
+			MethodName: "GetTransaction",
+			Handler:    _CryptoWallet_GetTransaction_Handler,
+		},
+		{
+			MethodName: "ListTransactions",
+			Handler:    _CryptoWallet_ListTransactions_Handler,
+		},
+		{
+			MethodName: "EstimateFee",
+			Handler:    _CryptoWallet_EstimateFee_Handler,
+		},
+		{
+			MethodName: "GetBalance",
+			Handler:    _CryptoWallet_GetBalance_Handler,
+		},
+		{
+			MethodName: "GetTronResources",
+			Handler:    _CryptoWallet_GetTronResources_Handler,
+		},
+		{
+			MethodName: "FreezeTron",
+			Handler:    _CryptoWallet_FreezeTron_Handler,
+		},
+		{
+			MethodName: "UnfreezeTron",
+			Handler:    _CryptoWallet_UnfreezeTron_Handler,
+		},
+		{
+			MethodName: "DelegateTronResource",
+			Handler:    _CryptoWallet_DelegateTronResource_Handler,
+		},
+		{
+			MethodName: "UndelegateTronResource",
+			Handler:    _CryptoWallet_UndelegateTronResource_Handler,
+		},
+		{
+			MethodName: "AddWithdrawalAddress",
+			Handler:    _CryptoWallet_AddWithdrawalAddress_Handler,
+		},
+		{
+			MethodName: "RemoveWithdrawalAddress",
+			Handler:    _CryptoWallet_RemoveWithdrawalAddress_Handler,
+		},
+		{
+			MethodName: "ListWithdrawalAddresses",
+			Handler:    _CryptoWallet_ListWithdrawalAddresses_Handler,
+		},
+		{
+			MethodName: "SetWithdrawalAllowlist",
+			Handler:    _CryptoWallet_SetWithdrawalAllowlist_Handler,
+		},
+		{
+			MethodName: "RequestWithdrawal",
+			Handler:    _CryptoWallet_RequestWithdrawal_Handler,
+		},
+		{
+			MethodName: "GetWithdrawal",
+			Handler:    _CryptoWallet_GetWithdrawal_Handler,
+		},
+		{
+			MethodName: "ApproveWithdrawal",
+			Handler:    _CryptoWallet_ApproveWithdrawal_Handler,
+		},
+		{
+			MethodName: "RejectWithdrawal",
+			Handler:    _CryptoWallet_RejectWithdrawal_Handler,
+		},
+		{
+			MethodName: "ListPendingApprovals",
+			Handler:    _CryptoWallet_ListPendingApprovals_Handler,
+		},
 		{
 			MethodName: "TriggerWatcher",
 			Handler:    _CryptoWallet_TriggerWatcher_Handler,
 		},
+		{
+			MethodName: "RegisterWebhookEndpoint",
+			Handler:    _CryptoWallet_RegisterWebhookEndpoint_Handler,
+		},
+		{
+			MethodName: "ListWebhookEndpoints",
+			Handler:    _CryptoWallet_ListWebhookEndpoints_Handler,
+		},
+		{
+			MethodName: "DeleteWebhookEndpoint",
+			Handler:    _CryptoWallet_DeleteWebhookEndpoint_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _CryptoWallet_ListWebhookDeliveries_Handler,
+		},
+		{
+			MethodName: "RedeliverWebhook",
+			Handler:    _CryptoWallet_RedeliverWebhook_Handler,
+		},
+		{
+			MethodName: "AdminGetWallet",
+			Handler:    _CryptoWallet_AdminGetWallet_Handler,
+		},
+		{
+			MethodName: "AdminGetBalance",
+			Handler:    _CryptoWallet_AdminGetBalance_Handler,
+		},
+		{
+			MethodName: "AdminListTransactions",
+			Handler:    _CryptoWallet_AdminListTransactions_Handler,
+		},
+		{
+			MethodName: "AdminSetSpendingLimit",
+			Handler:    _CryptoWallet_AdminSetSpendingLimit_Handler,
+		},
+		{
+			MethodName: "AdminListSpendingLimits",
+			Handler:    _CryptoWallet_AdminListSpendingLimits_Handler,
+		},
+		{
+			MethodName: "AdminDeleteSpendingLimit",
+			Handler:    _CryptoWallet_AdminDeleteSpendingLimit_Handler,
+		},
+		{
+			MethodName: "AdminSetApprovalPolicy",
+			Handler:    _CryptoWallet_AdminSetApprovalPolicy_Handler,
+		},
+		{
+			MethodName: "AdminListApprovalPolicies",
+			Handler:    _CryptoWallet_AdminListApprovalPolicies_Handler,
+		},
+		{
+			MethodName: "AdminListAuditEvents",
+			Handler:    _CryptoWallet_AdminListAuditEvents_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeWalletEvents",
+			Handler:       _CryptoWallet_SubscribeWalletEvents_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "transport/grpc/crypto-wallet/crypto-wallet.proto",
 }
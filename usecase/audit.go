@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+)
+
+// auditVerifyBatchSize is the number of events read at once when the chain is verified
+const auditVerifyBatchSize = 500
+
+// Audit writes the security relevant actions to the append-only audit log and checks the log was not tampered with
+type Audit struct {
+	repository.AuditEvent
+}
+
+func NewAudit(c *container.Container) *Audit {
+	return &Audit{
+		AuditEvent: c.AuditEventRepo(),
+	}
+}
+
+// Record appends the action on the target to the audit log, the actor and the request id are the ones of the context
+func (a Audit) Record(ctx context.Context, action string, actorId *string, targetId *string, metadata map[string]string) error {
+	_, err := a.AuditEvent.Append(ctx, &model.AuditEvent{
+		Action:   &action,
+		ActorId:  actorId,
+		TargetId: targetId,
+		Metadata: metadata,
+	})
+	if err != nil {
+		helper.GetLogger(ctx).WithField("method", "Usecase.Audit.Record").WithField("action", action).WithError(err).Warn("failed append audit event")
+		return err
+	}
+
+	return nil
+}
+
+// Verify walks the audit log from its first event and stops at the first event that does not follow the previous one
+func (a Audit) Verify(ctx context.Context) (*model.AuditVerification, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Audit.Verify")
+
+	res := &model.AuditVerification{}
+	var prev *model.AuditEvent
+	for {
+		filter := &repository.AuditEventFilter{Limit: auditVerifyBatchSize}
+		if prev != nil {
+			filter.AfterId = prev.Id
+		}
+
+		events, err := a.AuditEvent.List(ctx, filter)
+		if err != nil {
+			logger.WithError(err).Warn("failed list audit events")
+			return nil, err
+		}
+
+		for i := range events {
+			event := events[i]
+			if err := event.Follows(prev); err != nil {
+				res.BrokenAt = event.Id
+				res.Reason = helper.Pointer(err.Error())
+				return res, nil
+			}
+			res.Checked++
+			res.LastId, res.LastHash = event.Id, event.Hash
+			prev = &event
+		}
+
+		if len(events) < auditVerifyBatchSize {
+			return res, nil
+		}
+	}
+}
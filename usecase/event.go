@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+)
+
+type Event struct {
+	config config.Config
+	repository.Wallet
+
+	chains      service.ChainRegistry
+	eventBus    service.EventBus
+	walletEvent repository.WalletEvent
+}
+
+func NewEvent(c *container.Container) *Event {
+	return &Event{
+		config:      c.Config(),
+		Wallet:      c.WalletRepo(),
+		chains:      c.ChainRegistry(),
+		eventBus:    c.EventBus(),
+		walletEvent: c.WalletEventRepo(),
+	}
+}
+
+func (e Event) SubscribeWalletEvents(ctx context.Context, userId *string, resumeToken *string) (<-chan model.WalletEvent, func(), error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Event.SubscribeWalletEvents")
+
+	wallet, err := e.Wallet.Get(ctx, &repository.WalletGetFilter{
+		UserId: userId,
+	}, nil)
+	if err != nil {
+		logger.WithError(err).Warn("failed get wallet")
+		return nil, nil, err
+	}
+
+	// the addresses of every supported chain, the EVM networks share the ethereum address
+	addresses := []string{}
+	seen := map[string]struct{}{}
+	for _, chain := range e.chains.Chains() {
+		adapter, err := e.chains.Get(chain)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		address := adapter.Address(wallet)
+		if address == nil {
+			continue
+		}
+		if _, ok := seen[*address]; !ok {
+			seen[*address] = struct{}{}
+			addresses = append(addresses, *address)
+		}
+	}
+
+	events, unsubscribe, err := e.eventBus.Subscribe(ctx, addresses, resumeToken)
+	if err != nil {
+		logger.WithError(err).Warn("failed subscribe wallet events")
+		return nil, nil, err
+	}
+
+	return events, unsubscribe, nil
+}
+
+// RunRetention removes the stored events older than the retention every hour, the subscribers can no longer resume
+// from them
+func (e Event) RunRetention(ctx context.Context) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Event.RunRetention")
+	retention := time.Duration(e.config.EventBus.RetentionHours) * time.Hour
+
+	for {
+		deleted, err := e.walletEvent.DeleteBefore(ctx, time.Now().Add(-retention))
+		if err != nil {
+			logger.WithError(err).Warn("failed delete wallet events")
+		} else if deleted > 0 {
+			logger.WithField("deleted", deleted).Info("wallet events deleted")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Hour):
+		}
+	}
+}
+
+// eventPublisher fans a wallet event out to the stream subscribers and the merchant webhooks
+type eventPublisher struct {
+	eventBus        service.EventBus
+	outboundWebhook *OutboundWebhook
+}
+
+func newEventPublisher(c *container.Container) eventPublisher {
+	publisher := eventPublisher{eventBus: c.EventBus()}
+	if c.WebhookDeliveryRepo() != nil {
+		publisher.outboundWebhook = NewOutboundWebhook(c)
+	}
+
+	return publisher
+}
+
+func (e eventPublisher) publish(ctx context.Context, event model.WalletEvent) {
+	if len(event.Addresses) == 0 {
+		return
+	}
+
+	if e.eventBus != nil {
+		e.eventBus.Publish(ctx, event)
+	}
+
+	if e.outboundWebhook != nil {
+		if err := e.outboundWebhook.Enqueue(ctx, event); err != nil {
+			helper.GetLogger(ctx).WithField("method", "Usecase.eventPublisher.publish").WithError(err).Warn("failed enqueue webhook deliveries")
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+)
+
+// jobHandler runs one step of a job, it returns after how long the job should run again or zero when it is done
+type jobHandler func(ctx context.Context, job model.Job) (time.Duration, error)
+
+type JobRunner struct {
+	config config.Config
+
+	jobRepo  repository.Job
+	handlers map[string]jobHandler
+}
+
+func NewJobRunner(c *container.Container) *JobRunner {
+	transaction := NewTransaction(c)
+
+	handlers := map[string]jobHandler{}
+	for _, chain := range c.ChainRegistry().Chains() {
+		handlers[model.JobCheckTransaction(chain)] = transaction.checkTransaction(chain)
+	}
+
+	return &JobRunner{
+		config:   c.Config(),
+		jobRepo:  c.JobRepo(),
+		handlers: handlers,
+	}
+}
+
+// RunWorker runs the due jobs until the context is cancelled
+func (j JobRunner) RunWorker(ctx context.Context) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.JobRunner.RunWorker")
+	pollInterval := time.Duration(j.config.Job.PollIntervalSeconds) * time.Second
+
+	for {
+		ran, err := j.RunDue(ctx)
+		if err != nil {
+			logger.WithError(err).Warn("failed run due jobs")
+		}
+
+		// keep draining while batches are full
+		if err == nil && ran == j.config.Job.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// RunDue runs one batch of due jobs and returns how many were run
+func (j JobRunner) RunDue(ctx context.Context) (int, error) {
+	cfg := j.config.Job
+
+	jobs, err := j.jobRepo.ClaimDue(ctx, cfg.BatchSize, time.Duration(cfg.LeaseSeconds)*time.Second)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, job := range jobs {
+		if err := j.run(ctx, job); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(jobs), nil
+}
+
+func (j JobRunner) run(ctx context.Context, job model.Job) error {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.JobRunner.run").WithField("jobId", *job.Id).WithField("jobType", helper.Val(job.Type))
+
+	update := &model.Job{}
+
+	handler, ok := j.handlers[helper.Val(job.Type)]
+	if !ok {
+		update.Status = helper.Pointer(model.JobDead)
+		update.LastError = helper.Pointer("unknown job type")
+	} else {
+		runAfter, err := handler(ctx, job)
+		switch {
+		case err != nil:
+			// attempts count the consecutive failures, a step that succeeds resets them
+			update.Attempts = helper.Pointer(helper.Val(job.Attempts) + 1)
+			update.LastError = helper.Pointer(err.Error())
+			if *update.Attempts >= helper.Val(job.MaxAttempts) {
+				logger.WithError(err).Warn("job exhausted its attempts")
+				update.Status = helper.Pointer(model.JobDead)
+			} else {
+				update.RunAt = helper.Pointer(time.Now().Add(j.retryBackoff(*update.Attempts)))
+			}
+		case runAfter > 0:
+			update.Attempts = helper.Pointer(0)
+			update.RunAt = helper.Pointer(time.Now().Add(runAfter))
+		default:
+			update.Attempts = helper.Pointer(0)
+			update.Status = helper.Pointer(model.JobDone)
+			update.CompletedAt = helper.Pointer(time.Now())
+		}
+	}
+
+	if _, err := j.jobRepo.Update(ctx, *job.Id, update); err != nil {
+		logger.WithError(err).Warn("failed update job")
+		return err
+	}
+
+	return nil
+}
+
+// retryBackoff doubles the base delay on every failed attempt, capped at the configured maximum
+func (j JobRunner) retryBackoff(attempts int) time.Duration {
+	cfg := j.config.Job
+	return helper.Backoff(attempts, time.Duration(cfg.RetryBaseSeconds)*time.Second, time.Duration(cfg.RetryMaxSeconds)*time.Second)
+}
+
+// enqueueJob stores a job that the workers run as soon as possible
+func enqueueJob(ctx context.Context, jobRepo repository.Job, cfg config.Job, jobType string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = jobRepo.Add(ctx, &model.Job{
+		Type:        &jobType,
+		Payload:     helper.Pointer(string(data)),
+		Status:      helper.Pointer(model.JobQueued),
+		Attempts:    helper.Pointer(0),
+		MaxAttempts: &cfg.MaxAttempts,
+		RunAt:       helper.Pointer(time.Now()),
+	})
+
+	return err
+}
@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+
+	"github.com/segmentio/ksuid"
+)
+
+type OutboundWebhook struct {
+	config config.Config
+	repository.Wallet
+	service.WebhookSender
+
+	webhookEndpointRepo repository.WebhookEndpoint
+	webhookDeliveryRepo repository.WebhookDelivery
+}
+
+func NewOutboundWebhook(c *container.Container) *OutboundWebhook {
+	return &OutboundWebhook{
+		config:              c.Config(),
+		Wallet:              c.WalletRepo(),
+		WebhookSender:       c.WebhookSender(),
+		webhookEndpointRepo: c.WebhookEndpointRepo(),
+		webhookDeliveryRepo: c.WebhookDeliveryRepo(),
+	}
+}
+
+func (o OutboundWebhook) RegisterEndpoint(ctx context.Context, userId *string, url *string) (*model.WebhookEndpoint, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.OutboundWebhook.RegisterEndpoint")
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		logger.WithError(err).Warn("failed generate webhook secret")
+		return nil, err
+	}
+
+	endpoint := &model.WebhookEndpoint{
+		UserId: userId,
+		Url:    url,
+		Secret: helper.Pointer("whsec_" + hex.EncodeToString(secret)),
+		Active: helper.Pointer(true),
+	}
+	if err := endpoint.Validate(); err != nil {
+		return nil, model.NewParameterError(helper.Pointer(err.Error()))
+	}
+
+	endpoint, err := o.webhookEndpointRepo.Add(ctx, endpoint)
+	if err != nil {
+		logger.WithError(err).Warn("failed add webhook endpoint")
+		return nil, err
+	}
+
+	return endpoint, nil
+}
+
+func (o OutboundWebhook) ListEndpoints(ctx context.Context, userId *string) ([]model.WebhookEndpoint, error) {
+	return o.webhookEndpointRepo.List(ctx, &repository.WebhookEndpointGetFilter{UserId: userId})
+}
+
+func (o OutboundWebhook) DeleteEndpoint(ctx context.Context, userId *string, id *string) error {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.OutboundWebhook.DeleteEndpoint")
+
+	endpoint, err := o.webhookEndpointRepo.Get(ctx, &repository.WebhookEndpointGetFilter{Id: id, UserId: userId})
+	if err != nil {
+		logger.WithError(err).Warn("failed get webhook endpoint")
+		return err
+	}
+
+	return o.webhookEndpointRepo.Delete(ctx, *endpoint.Id)
+}
+
+func (o OutboundWebhook) ListDeliveries(ctx context.Context, userId *string, status *string) ([]model.WebhookDelivery, error) {
+	return o.webhookDeliveryRepo.List(ctx, &repository.WebhookDeliveryGetFilter{UserId: userId, Status: status})
+}
+
+// Redeliver moves a delivery back to the queue with a fresh attempt budget
+func (o OutboundWebhook) Redeliver(ctx context.Context, userId *string, id *string) (*model.WebhookDelivery, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.OutboundWebhook.Redeliver")
+
+	delivery, err := o.webhookDeliveryRepo.Get(ctx, &repository.WebhookDeliveryGetFilter{Id: id, UserId: userId})
+	if err != nil {
+		logger.WithError(err).Warn("failed get webhook delivery")
+		return nil, err
+	}
+
+	if *delivery.Status == model.WebhookDeliveryPending {
+		return nil, model.NewBadRequestError(helper.Pointer("delivery is still pending"))
+	}
+
+	return o.webhookDeliveryRepo.Update(ctx, *delivery.Id, &model.WebhookDelivery{
+		Status:        helper.Pointer(model.WebhookDeliveryPending),
+		Attempts:      helper.Pointer(0),
+		NextAttemptAt: helper.Pointer(time.Now()),
+	})
+}
+
+// Enqueue stores a delivery for every active endpoint of the wallets the event concerns
+func (o OutboundWebhook) Enqueue(ctx context.Context, event model.WalletEvent) error {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.OutboundWebhook.Enqueue")
+
+	enqueued := map[string]struct{}{}
+	for _, address := range event.Addresses {
+		wallet, err := o.Wallet.Get(ctx, &repository.WalletGetFilter{Address: helper.Pointer(address)}, nil)
+		if model.IsNotFoundError(err) {
+			continue
+		} else if err != nil {
+			logger.WithError(err).Warn("failed get wallet by address")
+			return err
+		}
+
+		endpoints, err := o.webhookEndpointRepo.List(ctx, &repository.WebhookEndpointGetFilter{
+			UserId: wallet.UserId,
+			Active: helper.Pointer(true),
+		})
+		if err != nil {
+			logger.WithError(err).Warn("failed list webhook endpoints")
+			return err
+		}
+
+		for _, endpoint := range endpoints {
+			if _, ok := enqueued[*endpoint.Id]; ok {
+				continue
+			}
+			enqueued[*endpoint.Id] = struct{}{}
+
+			deliveryId := ksuid.New().String()
+			payload, err := json.Marshal(model.WebhookPayload{
+				DeliveryId:    &deliveryId,
+				Type:          event.Type,
+				Token:         event.Token,
+				TransactionId: event.TransactionId,
+				Address:       helper.Pointer(address),
+				Amount:        event.Amount,
+				Confirmation:  event.Confirmation,
+				Status:        event.Status,
+				FailureReason: event.FailureReason,
+				CreatedAt:     helper.Pointer(time.Now().Unix()),
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = o.webhookDeliveryRepo.Add(ctx, &model.WebhookDelivery{
+				Id:            &deliveryId,
+				EndpointId:    endpoint.Id,
+				EventType:     event.Type,
+				Payload:       helper.Pointer(string(payload)),
+				Status:        helper.Pointer(model.WebhookDeliveryPending),
+				Attempts:      helper.Pointer(0),
+				NextAttemptAt: helper.Pointer(time.Now()),
+			})
+			if err != nil {
+				logger.WithError(err).Warn("failed add webhook delivery")
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// RunWorker delivers the queued webhooks until the context is cancelled
+func (o OutboundWebhook) RunWorker(ctx context.Context) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.OutboundWebhook.RunWorker")
+	pollInterval := time.Duration(o.config.OutboundWebhook.PollIntervalSeconds) * time.Second
+
+	for {
+		delivered, err := o.DeliverDue(ctx)
+		if err != nil {
+			logger.WithError(err).Warn("failed deliver due webhooks")
+		}
+
+		// keep draining while batches are full
+		if err == nil && delivered == o.config.OutboundWebhook.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// DeliverDue attempts one batch of due deliveries and returns how many were attempted
+func (o OutboundWebhook) DeliverDue(ctx context.Context) (int, error) {
+	cfg := o.config.OutboundWebhook
+
+	// the lease must outlive the whole batch, otherwise another worker could pick the same rows
+	lease := time.Duration(cfg.BatchSize*cfg.TimeoutSeconds+30) * time.Second
+	deliveries, err := o.webhookDeliveryRepo.ClaimDue(ctx, cfg.BatchSize, lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range deliveries {
+		if err := o.deliver(ctx, delivery); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+func (o OutboundWebhook) deliver(ctx context.Context, delivery model.WebhookDelivery) error {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.OutboundWebhook.deliver").WithField("deliveryId", *delivery.Id)
+
+	update := &model.WebhookDelivery{
+		Attempts: helper.Pointer(helper.Val(delivery.Attempts) + 1),
+	}
+
+	endpoint, err := o.webhookEndpointRepo.Get(ctx, &repository.WebhookEndpointGetFilter{Id: delivery.EndpointId})
+	if err != nil && !model.IsNotFoundError(err) {
+		return err
+	}
+
+	if endpoint == nil || !helper.Val(endpoint.Active) {
+		update.Status = helper.Pointer(model.WebhookDeliveryDead)
+		update.LastError = helper.Pointer("webhook endpoint is no longer active")
+	} else {
+		statusCode, err := o.WebhookSender.Send(ctx, *endpoint.Url, *endpoint.Secret, helper.Val(delivery.EventType), *delivery.Id, []byte(*delivery.Payload))
+		if statusCode != 0 {
+			update.LastStatusCode = &statusCode
+		}
+
+		if err == nil {
+			update.Status = helper.Pointer(model.WebhookDeliveryDelivered)
+			update.DeliveredAt = helper.Pointer(time.Now())
+		} else if *update.Attempts >= o.config.OutboundWebhook.MaxAttempts {
+			logger.WithError(err).Warn("webhook delivery exhausted its attempts")
+			update.Status = helper.Pointer(model.WebhookDeliveryDead)
+			update.LastError = helper.Pointer(err.Error())
+		} else {
+			update.LastError = helper.Pointer(err.Error())
+			update.NextAttemptAt = helper.Pointer(time.Now().Add(o.retryBackoff(*update.Attempts)))
+		}
+	}
+
+	if _, err = o.webhookDeliveryRepo.Update(ctx, *delivery.Id, update); err != nil {
+		logger.WithError(err).Warn("failed update webhook delivery")
+		return err
+	}
+
+	return nil
+}
+
+// retryBackoff doubles the base delay on every failed attempt, capped at the configured maximum
+func (o OutboundWebhook) retryBackoff(attempts int) time.Duration {
+	cfg := o.config.OutboundWebhook
+	return helper.Backoff(attempts, time.Duration(cfg.RetryBaseSeconds)*time.Second, time.Duration(cfg.RetryMaxSeconds)*time.Second)
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+)
+
+type OutboxRelay struct {
+	config config.Config
+	service.OutboxSink
+
+	outboxRepo repository.Outbox
+}
+
+func NewOutboxRelay(c *container.Container) *OutboxRelay {
+	return &OutboxRelay{
+		config:     c.Config(),
+		OutboxSink: c.OutboxSink(),
+		outboxRepo: c.OutboxRepo(),
+	}
+}
+
+// Run relays the outbox to the sink until the context is cancelled
+func (o OutboxRelay) Run(ctx context.Context) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.OutboxRelay.Run")
+	pollInterval := time.Duration(o.config.Outbox.PollIntervalSeconds) * time.Second
+
+	for {
+		published, err := o.RelayPending(ctx)
+		if err != nil {
+			logger.WithError(err).Warn("failed relay outbox events")
+		}
+
+		// keep draining while batches are full
+		if err == nil && published == o.config.Outbox.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// RelayPending publishes one batch of outbox events and returns how many were published
+func (o OutboxRelay) RelayPending(ctx context.Context) (int, error) {
+	return o.outboxRepo.PublishPending(ctx, o.config.Outbox.BatchSize, o.OutboxSink.Publish)
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+	"golang.org/x/exp/slices"
+)
+
+type Reconciler struct {
+	config config.Config
+
+	chains          service.ChainRegistry
+	transactionRepo func(chain string) repository.Transaction
+	walletRepo      repository.Wallet
+
+	events             eventPublisher
+	confirmationPolicy service.ConfirmationPolicy
+}
+
+func NewReconciler(c *container.Container) *Reconciler {
+	return &Reconciler{
+		config:             c.Config(),
+		chains:             c.ChainRegistry(),
+		transactionRepo:    c.TransactionRepo,
+		walletRepo:         c.WalletRepo(),
+		events:             newEventPublisher(c),
+		confirmationPolicy: c.ConfirmationPolicy(),
+	}
+}
+
+// Run reconciles right away and then on every interval until the context is cancelled
+func (r Reconciler) Run(ctx context.Context) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Reconciler.Run")
+	interval := time.Duration(r.config.Reconciler.IntervalMinutes) * time.Minute
+
+	for {
+		if _, err := r.Reconcile(ctx, false); err != nil {
+			logger.WithError(err).Warn("failed reconcile transactions")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Reconcile refreshes every pending transaction against its chain and returns the ones that changed,
+// with dryRun the changes are only reported
+func (r Reconciler) Reconcile(ctx context.Context, dryRun bool) ([]model.TransactionReconciliation, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Reconciler.Reconcile")
+
+	res := []model.TransactionReconciliation{}
+	for _, chain := range r.chains.Chains() {
+		adapter, err := r.chains.Get(chain)
+		if err != nil {
+			return nil, err
+		}
+		transactionRepo := r.transactionRepo(chain)
+
+		pending, err := transactionRepo.List(ctx, &repository.TransactionGetFilter{
+			Status: helper.Pointer(model.TransactionStatusPending),
+		})
+		if err != nil {
+			logger.WithError(err).Warn("failed list pending transactions")
+			return nil, err
+		}
+
+		for _, transaction := range pending {
+			// a transaction that can not be refreshed now is retried on the next run
+			refreshed, err := r.refresh(ctx, adapter, transaction)
+			if err != nil {
+				logger.WithError(err).WithField("transactionId", *transaction.Id).Warn("failed refresh transaction")
+				continue
+			}
+
+			if helper.EqualPointerValue(transaction.Status, refreshed.Status) && helper.EqualPointerValue(transaction.Confirmation, refreshed.Confirmation) {
+				continue
+			}
+
+			res = append(res, model.TransactionReconciliation{
+				Token:                helper.Pointer(chain),
+				TransactionId:        transaction.Id,
+				PreviousStatus:       transaction.Status,
+				Status:               refreshed.Status,
+				PreviousConfirmation: transaction.Confirmation,
+				Confirmation:         refreshed.Confirmation,
+			})
+			if dryRun {
+				continue
+			}
+
+			if _, err := transactionRepo.Upsert(ctx, refreshed); err != nil {
+				logger.WithError(err).WithField("transactionId", *transaction.Id).Warn("failed upsert reconciled transaction")
+				return nil, err
+			}
+			r.publish(ctx, chain, refreshed)
+		}
+	}
+
+	return res, nil
+}
+
+// publish announces the reconciled transaction, the sender learns the outcome of a send of its wallet while the
+// receivers of a deposit learn how far it got
+func (r Reconciler) publish(ctx context.Context, token string, transaction *model.Transaction) {
+	isSend, err := r.isSend(ctx, transaction)
+	if err != nil {
+		helper.GetLogger(ctx).WithField("method", "Usecase.Reconciler.publish").WithError(err).WithField("transactionId", *transaction.Id).Warn("failed get wallet of sender")
+		return
+	}
+
+	if !isSend {
+		// the outputs also hold the change of the sender, so the deposit goes only to the other outputs
+		deposits := []string{}
+		for _, address := range transaction.ReceiverAddress {
+			if !slices.Contains(transaction.SenderAddress, address) {
+				deposits = append(deposits, address)
+			}
+		}
+		r.events.publish(ctx, model.NewTransactionEvent(model.EventConfirmationChanged, token, transaction, deposits))
+		return
+	}
+
+	senders := slices.Clone(transaction.SenderAddress)
+	switch *transaction.Status {
+	case model.TransactionStatusSuccess:
+		r.events.publish(ctx, model.NewTransactionEvent(model.EventSendConfirmed, token, transaction, senders))
+	case model.TransactionStatusPending:
+		r.events.publish(ctx, model.NewTransactionEvent(model.EventConfirmationChanged, token, transaction, senders))
+	default:
+		r.events.publish(ctx, model.NewTransactionEvent(model.EventSendFailed, token, transaction, senders))
+	}
+}
+
+// isSend tells whether the transaction was sent by one of the wallets, any other stored transaction is a deposit
+func (r Reconciler) isSend(ctx context.Context, transaction *model.Transaction) (bool, error) {
+	for _, address := range transaction.SenderAddress {
+		_, err := r.walletRepo.Get(ctx, &repository.WalletGetFilter{Address: helper.Pointer(address)}, nil)
+		if err == nil {
+			return true, nil
+		} else if !model.IsNotFoundError(err) {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// refresh returns the transaction as its chain sees it now. A transaction not mined yet is replaced once another one
+// took its place, and dropped once its chain did not know it for long enough
+func (r Reconciler) refresh(ctx context.Context, adapter service.ChainAdapter, transaction model.Transaction) (*model.Transaction, error) {
+	observed, err := adapter.GetTx(ctx, *transaction.Id)
+	if err != nil && !model.IsNotFoundError(err) {
+		return nil, err
+	}
+
+	if observed == nil || observed.Block == nil {
+		replaced, err := adapter.IsReplaced(ctx, transaction)
+		if err != nil {
+			return nil, err
+		}
+		if replaced {
+			transaction.Status = helper.Pointer(model.TransactionStatusReplaced)
+			return &transaction, nil
+		}
+	}
+
+	if observed == nil {
+		return r.dropIfStale(transaction), nil
+	}
+
+	refreshed := transaction
+	mergeObservedTransaction(&refreshed, observed)
+	r.confirmationPolicy.Apply(adapter.Chain(), &refreshed)
+
+	return &refreshed, nil
+}
+
+// dropIfStale marks a transaction its chain does not know as dropped once it is older than the configured window
+func (r Reconciler) dropIfStale(transaction model.Transaction) *model.Transaction {
+	droppedAfter := time.Duration(r.config.Reconciler.DroppedAfterHours) * time.Hour
+	if transaction.CreatedAt == nil || time.Since(*transaction.CreatedAt) < droppedAfter {
+		return &transaction
+	}
+
+	transaction.Status = helper.Pointer(model.TransactionStatusDropped)
+	return &transaction
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+)
+
+// SpendingLimit manages the limits of the transfers and reserves each transfer against them before it is signed
+type SpendingLimit struct {
+	config config.Config
+	repository.SpendingLimit
+
+	userRepo repository.User
+	chains   service.ChainRegistry
+}
+
+func NewSpendingLimit(c *container.Container) *SpendingLimit {
+	return &SpendingLimit{
+		config:        c.Config(),
+		SpendingLimit: c.SpendingLimitRepo(),
+		userRepo:      c.UserRepo(),
+		chains:        c.ChainRegistry(),
+	}
+}
+
+// Set stores the limit of the user on the chain, or the global limit of the chain when the user id is nil
+func (s SpendingLimit) Set(ctx context.Context, limit *model.SpendingLimit) (*model.SpendingLimit, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.SpendingLimit.Set")
+
+	adapter, err := s.chains.Get(helper.Val(limit.Chain))
+	if err != nil {
+		return nil, err
+	}
+	limit.Chain = helper.Pointer(adapter.Chain())
+
+	for _, value := range []*int64{limit.MaxPerTransaction, limit.MaxPerDay, limit.MaxTransactionsPerHour} {
+		if value != nil && *value < 0 {
+			return nil, model.NewParameterError(helper.Pointer("limits cannot be negative"))
+		}
+	}
+
+	if limit.UserId != nil {
+		if _, err := s.userRepo.Get(ctx, &repository.UserGetFilter{Id: limit.UserId}); err != nil {
+			logger.WithError(err).Warn("failed get user")
+			return nil, err
+		}
+	}
+
+	limit, err = s.SpendingLimit.Upsert(ctx, limit)
+	if err != nil {
+		logger.WithError(err).Warn("failed upsert spending limit")
+		return nil, err
+	}
+
+	return limit, nil
+}
+
+// Reserve counts the transfer against the limits of the user on the chain, it fails with a failed precondition error
+// when the transfer breaks one of them. The reservation is released when the transfer cannot be sent, the one of a
+// withdrawal request is linked to it
+func (s SpendingLimit) Reserve(ctx context.Context, userId *string, chain string, amount int64, withdrawalRequestId *string) (*model.SpendingRecord, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.SpendingLimit.Reserve")
+
+	record, err := s.SpendingLimit.Reserve(ctx, &model.SpendingRecord{
+		UserId:              userId,
+		Chain:               &chain,
+		Amount:              &amount,
+		WithdrawalRequestId: withdrawalRequestId,
+	}, func(limit model.SpendingLimit, usage model.SpendingUsage) error {
+		return limit.Check(amount, usage)
+	})
+	if err != nil {
+		logger.WithError(err).WithField("chain", chain).Warn("failed reserve spending")
+		return nil, err
+	}
+
+	return record, nil
+}
@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+	"github.com/aalexanderkevin/crypto-wallet/service/totp"
+)
+
+// Totp enrolls the authenticator apps of the users and checks their codes before the transfers
+type Totp struct {
+	config   config.Config
+	userRepo repository.User
+	attempts service.Cache
+	audit    *Audit
+}
+
+func NewTotp(c *container.Container) *Totp {
+	return &Totp{
+		config:   c.Config(),
+		userRepo: c.UserRepo(),
+		attempts: c.Redis(),
+		audit:    NewAudit(c),
+	}
+}
+
+// Enroll stores a new secret for the user and returns it with its otpauth uri, TOTP is enabled once a code of the
+// secret is verified. An enabled TOTP cannot be enrolled again so a stolen access token cannot replace it
+func (t Totp) Enroll(ctx context.Context, userId *string) (secret string, uri string, err error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Totp.Enroll")
+
+	user, err := t.userRepo.Get(ctx, &repository.UserGetFilter{Id: userId})
+	if err != nil {
+		logger.WithError(err).Warn("failed get user")
+		return "", "", err
+	}
+	if user.TotpEnabled() {
+		return "", "", model.NewFailedPreconditionError(helper.Pointer("totp is already enabled"))
+	}
+
+	secret, err = totp.GenerateSecret()
+	if err != nil {
+		logger.WithError(err).Warn("failed generate totp secret")
+		return "", "", err
+	}
+
+	encrypted, err := helper.EncryptSeedPhrase(secret, t.encryptionKey())
+	if err != nil {
+		logger.WithError(err).Warn("failed encrypt totp secret")
+		return "", "", err
+	}
+
+	if err := t.userRepo.SetTotpSecret(ctx, *user.Id, encrypted); err != nil {
+		if model.IsNotFoundError(err) {
+			return "", "", model.NewFailedPreconditionError(helper.Pointer("totp is already enabled"))
+		}
+		logger.WithError(err).Warn("failed store totp secret")
+		return "", "", err
+	}
+
+	account := helper.Val(user.Email)
+	if user.Username != nil {
+		account = *user.Username
+	}
+
+	return secret, totp.URI(t.config.Totp.Issuer, account, secret), nil
+}
+
+// Verify enables the enrolled secret when the code matches it
+func (t Totp) Verify(ctx context.Context, userId *string, code string) error {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Totp.Verify")
+
+	user, err := t.userRepo.Get(ctx, &repository.UserGetFilter{Id: userId})
+	if err != nil {
+		logger.WithError(err).Warn("failed get user")
+		return err
+	}
+	if user.TotpEnabled() {
+		return model.NewFailedPreconditionError(helper.Pointer("totp is already enabled"))
+	}
+	if len(user.TotpSecret) == 0 {
+		return model.NewFailedPreconditionError(helper.Pointer("totp is not enrolled"))
+	}
+
+	step, err := t.check(ctx, user, code)
+	if err != nil {
+		return err
+	}
+
+	if err := t.userRepo.EnableTotp(ctx, *user.Id, step); err != nil {
+		logger.WithError(err).Warn("failed enable totp")
+		return err
+	}
+
+	return nil
+}
+
+// CheckSend checks the code of a transfer of the user. A user with TOTP enabled needs a valid code for every transfer,
+// a user without it cannot send more than the configured threshold of the chain
+func (t Totp) CheckSend(ctx context.Context, userId *string, chain string, amount int64, code *string) error {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Totp.CheckSend")
+
+	user, err := t.userRepo.Get(ctx, &repository.UserGetFilter{Id: userId})
+	if err != nil {
+		logger.WithError(err).Warn("failed get user")
+		return err
+	}
+
+	if !user.TotpEnabled() {
+		thresholds, err := t.config.Totp.GetRequiredAbove()
+		if err != nil {
+			logger.WithError(err).Warn("invalid totp thresholds")
+			return err
+		}
+		if threshold, ok := thresholds[chain]; ok && amount > threshold {
+			return model.NewFailedPreconditionError(helper.Pointer(fmt.Sprintf("totp must be enabled to send more than %d on %s", threshold, chain)))
+		}
+		return nil
+	}
+
+	if helper.Val(code) == "" {
+		return model.NewPermissionDeniedError(helper.Pointer("otp is required"))
+	}
+
+	step, err := t.check(ctx, user, *code)
+	if err != nil {
+		return err
+	}
+
+	fresh, err := t.userRepo.UseTotpStep(ctx, *user.Id, step)
+	if err != nil {
+		logger.WithError(err).Warn("failed record totp step")
+		return err
+	}
+	if !fresh {
+		t.auditFailure(ctx, user, "otp reused")
+		return model.NewPermissionDeniedError(helper.Pointer("otp already used"))
+	}
+
+	return nil
+}
+
+// check counts the attempt and validates the code against the secret of the user, it returns the matched time step
+func (t Totp) check(ctx context.Context, user *model.User, code string) (int64, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Totp.check")
+
+	if err := t.countAttempt(ctx, *user.Id); err != nil {
+		return 0, err
+	}
+
+	secret, err := helper.DecryptSeedPhrase(user.TotpSecret, t.encryptionKey())
+	if err != nil {
+		logger.WithError(err).Warn("failed decrypt totp secret")
+		return 0, err
+	}
+
+	step, ok, err := totp.Validate(string(secret), code, time.Now(), t.config.Totp.Skew)
+	if err != nil {
+		logger.WithError(err).Warn("failed validate otp")
+		return 0, err
+	}
+	if !ok {
+		t.auditFailure(ctx, user, "invalid otp")
+		return 0, model.NewPermissionDeniedError(helper.Pointer("invalid otp"))
+	}
+
+	if t.attempts != nil {
+		if err := t.attempts.Delete(ctx, model.TotpAttemptsKey(*user.Id)); err != nil {
+			logger.WithError(err).Warn("failed reset totp attempts")
+		}
+	}
+
+	return step, nil
+}
+
+// countAttempt refuses the code once the user tried more than the allowed attempts in the lockout window. The attempt
+// is counted before the code is checked so concurrent guesses cannot pass the limit
+func (t Totp) countAttempt(ctx context.Context, userId string) error {
+	if t.attempts == nil {
+		return nil
+	}
+
+	ttl := time.Duration(t.config.Totp.LockoutSeconds) * time.Second
+	attempts, err := t.attempts.SetList(ctx, model.TotpAttemptsKey(userId), "1", &ttl)
+	if err != nil {
+		helper.GetLogger(ctx).WithField("method", "Usecase.Totp.countAttempt").WithError(err).Warn("failed count totp attempt")
+		return model.NewUnavailableError(helper.Pointer("failed check otp attempts"))
+	}
+	if attempts > t.config.Totp.MaxAttempts {
+		return model.NewTooManyAttemptsError()
+	}
+
+	return nil
+}
+
+func (t Totp) auditFailure(ctx context.Context, user *model.User, reason string) {
+	helper.GetLogger(ctx).WithField("method", "Usecase.Totp").
+		WithField("audit", "totp_failed").
+		WithField("user_id", helper.Val(user.Id)).
+		Warn(reason)
+
+	_ = t.audit.Record(ctx, model.AuditAuthFailed, nil, user.Id, map[string]string{
+		"method": "totp",
+		"reason": reason,
+	})
+}
+
+func (t Totp) encryptionKey() string {
+	if t.config.Totp.EncryptionKey != "" {
+		return t.config.Totp.EncryptionKey
+	}
+
+	return t.config.Service.SeedPhraseEncryptionKey
+}
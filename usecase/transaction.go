@@ -2,8 +2,10 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/big"
+	"strconv"
 	"time"
 
 	"github.com/aalexanderkevin/crypto-wallet/config"
@@ -12,20 +14,25 @@ import (
 	"github.com/aalexanderkevin/crypto-wallet/model"
 	"github.com/aalexanderkevin/crypto-wallet/repository"
 	"github.com/aalexanderkevin/crypto-wallet/service"
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/segmentio/ksuid"
+	"golang.org/x/exp/slices"
 )
 
 type Transaction struct {
 	config config.Config
-	service.Bitcoin
-	service.Ethereum
 	service.Tron
 	repository.Wallet
 
-	btcTransactionRepo repository.Transaction
-	ethTransactionRepo repository.Transaction
-	trxTransactionRepo repository.Transaction
+	chains             service.ChainRegistry
+	transactionRepo    func(chain string) repository.Transaction
+	jobRepo            repository.Job
+	approvalPolicyRepo repository.ApprovalPolicy
+
+	events             eventPublisher
+	confirmationPolicy service.ConfirmationPolicy
+	totp               *Totp
+	withdrawalAddress  *WithdrawalAddress
+	spendingLimit      *SpendingLimit
+	audit              *Audit
 
 	sleepCheckPendingTrx      time.Duration
 	sleepCheckConfirmationTrx time.Duration
@@ -34,322 +41,389 @@ type Transaction struct {
 func NewTransaction(c *container.Container) *Transaction {
 	return &Transaction{
 		config:             c.Config(),
-		Bitcoin:            c.Bitcoin(),
-		Ethereum:           c.Ethereum(),
 		Tron:               c.Tron(),
-		btcTransactionRepo: c.TransactionBtcRepo(),
-		ethTransactionRepo: c.TransactionEthRepo(),
-		trxTransactionRepo: c.TransactionTrxRepo(),
+		chains:             c.ChainRegistry(),
+		transactionRepo:    c.TransactionRepo,
+		jobRepo:            c.JobRepo(),
+		approvalPolicyRepo: c.ApprovalPolicyRepo(),
 		Wallet:             c.WalletRepo(),
+		events:             newEventPublisher(c),
+		confirmationPolicy: c.ConfirmationPolicy(),
+		totp:               NewTotp(c),
+		withdrawalAddress:  NewWithdrawalAddress(c),
+		spendingLimit:      NewSpendingLimit(c),
+		audit:              NewAudit(c),
 
 		sleepCheckPendingTrx:      5 * time.Second,
 		sleepCheckConfirmationTrx: 1 * time.Minute,
 	}
 }
 
-func (t Transaction) SendBitcoin(ctx context.Context, reqSend *model.SendToken) (txHash *string, err error) {
-	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Transaction.SendBitcoin")
+// Send transfers the amount from the wallet of the user on the chain of the token, the transaction is recorded as
+// pending and followed by the worker until it is final
+func (t Transaction) Send(ctx context.Context, reqSend *model.SendToken) (txHash *string, err error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Transaction.Send")
 
-	// validate receiver bitcoin address
-	if valid := t.Bitcoin.CheckAddress(reqSend.ReceiverAddress); !valid {
-		err = fmt.Errorf("invalid receiver bitcoin address")
-		logger.WithError(err)
+	adapter, err := t.chains.Get(*reqSend.Token)
+	if err != nil {
 		return nil, err
 	}
+	logger = logger.WithField("chain", adapter.Chain())
 
-	// get the seedphrase of sender
-	wallet, err := t.Wallet.Get(ctx, &repository.WalletGetFilter{
-		Email: reqSend.Email,
-	}, &t.config.Service.SeedPhraseEncryptionKey)
-	if err != nil {
-		logger.WithError(err).Warn("failed get wallet")
+	if err := adapter.ValidateAddress(*reqSend.ReceiverAddress); err != nil {
+		return nil, model.NewParameterError(helper.Pointer(fmt.Sprintf("invalid receiver %s address", adapter.Chain())))
+	}
+
+	if err := t.withdrawalAddress.CheckSend(ctx, reqSend.UserId, adapter.Chain(), *reqSend.ReceiverAddress); err != nil {
 		return nil, err
 	}
 
-	// generate seedphrase to get btc wallet
-	btcWallet, err := t.Bitcoin.GetWallet(ctx, wallet.SeedPhrase)
+	wallet, err := t.Wallet.Get(ctx, &repository.WalletGetFilter{UserId: reqSend.UserId}, nil)
 	if err != nil {
-		logger.WithError(err).Warn("failed get btc wallet")
+		logger.WithError(err).Warn("failed get wallet")
 		return nil, err
 	}
 
-	// send token
-	tx, err := t.Bitcoin.SendTx(ctx, btcWallet, &model.TxOpts{
-		To:     reqSend.ReceiverAddress,
-		Amount: big.NewInt(*reqSend.Amount),
-	})
-	if err != nil {
-		logger.WithError(err).Warn("failed send btc")
+	// the transfers the approval policy of the wallet covers go through a withdrawal request, this is checked before the
+	// second factor so that the one-time code is not used up by a send that is refused
+	policy, err := t.approvalPolicyRepo.Get(ctx, *wallet.Id, adapter.Chain())
+	if err != nil && !model.IsNotFoundError(err) {
+		logger.WithError(err).Warn("failed get approval policy")
 		return nil, err
 	}
+	if policy != nil && policy.Requires(*reqSend.Amount) {
+		return nil, model.NewFailedPreconditionError(helper.Pointer(fmt.Sprintf("transfers above %d need approvals, request a withdrawal", *policy.Threshold)))
+	}
 
-	// upsert tx on database
-	if _, err = t.btcTransactionRepo.Upsert(ctx, tx); err != nil {
-		logger.WithError(err).Warn("Failed upsert")
+	// the second factor is checked before the seed phrase is decrypted
+	if err := t.totp.CheckSend(ctx, reqSend.UserId, adapter.Chain(), *reqSend.Amount, reqSend.Otp); err != nil {
+		return nil, err
 	}
 
-	return tx.Id, nil
+	return t.sign(ctx, adapter, reqSend, nil)
 }
 
-func (t Transaction) SendTron(ctx context.Context, reqSend *model.SendToken) (txHash *string, err error) {
-	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Transaction.SendTron")
+// sign decrypts the seed phrase of the wallet of the user, signs and broadcasts the transfer. It runs once the checks
+// of the transfer passed, the transfer is counted against the spending limits before the seed phrase is decrypted. The
+// reservation of a withdrawal request is linked to it
+func (t Transaction) sign(ctx context.Context, adapter service.ChainAdapter, reqSend *model.SendToken, withdrawalRequestId *string) (txHash *string, err error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Transaction.sign").WithField("chain", adapter.Chain())
 
-	// validate receiver tron address
-	if valid := t.Tron.CheckAddress(*reqSend.ReceiverAddress); valid != nil {
-		err = fmt.Errorf("invalid receiver tron address")
-		logger.WithError(err)
+	// the reservation is dropped when the transfer is surely not sent, it is kept when the broadcast may have gone
+	// through
+	spending, err := t.spendingLimit.Reserve(ctx, reqSend.UserId, adapter.Chain(), *reqSend.Amount, withdrawalRequestId)
+	if err != nil {
 		return nil, err
 	}
+	release := func() {
+		if err := t.spendingLimit.Release(ctx, *spending.Id); err != nil {
+			logger.WithError(err).Warn("failed release spending")
+		}
+	}
 
 	// get the seedphrase of sender
 	wallet, err := t.Wallet.Get(ctx, &repository.WalletGetFilter{
-		Email: reqSend.Email,
+		UserId: reqSend.UserId,
 	}, &t.config.Service.SeedPhraseEncryptionKey)
 	if err != nil {
 		logger.WithError(err).Warn("failed get wallet")
+		release()
 		return nil, err
 	}
 
-	// generate seedphrase to get trx wallet
-	trxWallet := t.Tron.GetWallet(ctx, wallet.SeedPhrase)
-
-	// send token
-	tx, err := t.Tron.SendTx(ctx, &model.TxOpts{
+	transaction, err := adapter.Send(ctx, wallet.SeedPhrase, &model.TxOpts{
 		To:     reqSend.ReceiverAddress,
 		Amount: big.NewInt(*reqSend.Amount),
-	}, trxWallet)
+	})
+	metadata := map[string]string{
+		"user_id":  helper.Val(reqSend.UserId),
+		"chain":    adapter.Chain(),
+		"receiver": *reqSend.ReceiverAddress,
+		"amount":   strconv.FormatInt(*reqSend.Amount, 10),
+	}
 	if err != nil {
-		logger.WithError(err).Warn("failed send trx")
+		logger.WithError(err).Warn("failed send")
+		if !model.IsBroadcastUnknownError(err) {
+			release()
+		}
+		metadata["error"] = err.Error()
+		_ = t.audit.Record(ctx, model.AuditTransactionFailed, nil, wallet.Id, metadata)
 		return nil, err
 	}
+	_ = t.audit.Record(ctx, model.AuditTransactionSent, nil, transaction.Id, metadata)
 
-	transactionHash := common.BytesToHash(tx.Txid)
+	if err := t.spendingLimit.SetTransactionId(ctx, *spending.Id, helper.Val(transaction.Id)); err != nil {
+		logger.WithError(err).Warn("failed link spending to transaction")
+	}
 
-	transaction := &model.Transaction{
-		Id:              helper.Pointer(transactionHash.Hex()[2:]),
-		SenderAddress:   []string{*wallet.TrxAddress},
-		ReceiverAddress: []string{*reqSend.ReceiverAddress},
-		Amount:          reqSend.Amount,
-		Status:          helper.Pointer("pending"),
+	if _, err = t.transactionRepo(adapter.Chain()).Upsert(ctx, transaction); err != nil {
+		logger.WithError(err).Warn("failed upsert transaction")
+		return nil, err
 	}
 
-	// open new thread to check transaction success
-	go t.checkTransactionTrx(ctx, transaction)
+	// the worker follows the transaction until it is final
+	if err := t.TrackTransaction(ctx, adapter.Chain(), transaction); err != nil {
+		logger.WithError(err).Warn("failed enqueue check transaction")
+	}
 
 	return transaction.Id, nil
 }
 
-func (t Transaction) checkTransactionTrx(ctx context.Context, transaction *model.Transaction) {
-	reqId := ctx.Value(helper.ContextKeyRequestId)
-	if reqId == nil {
-		reqId = ctx.Value(string(helper.ContextKeyRequestId))
-		if reqId == nil {
-			reqId = ksuid.New().String()
-		}
+// GetBalance returns the balance of the address the wallet of the user has on the chain of the token
+func (t Transaction) GetBalance(ctx context.Context, userId *string, token *string) (*model.Balance, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Transaction.GetBalance")
+
+	adapter, err := t.chains.Get(helper.Val(token))
+	if err != nil {
+		return nil, err
 	}
-	ctx = helper.ContextWithRequestId(context.Background(), reqId.(string))
-	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Wallet.checkTransaction")
 
-	for {
-		txInfo, err := t.Tron.GetTx(ctx, *transaction.Id)
-		if err != nil {
-			if err.Error() != "transaction info not found" {
-				logger.WithError(err).Warn("failed to get trx")
-				return
-			}
-		}
+	wallet, err := t.Wallet.Get(ctx, &repository.WalletGetFilter{UserId: userId}, nil)
+	if err != nil {
+		logger.WithError(err).Warn("failed get wallet")
+		return nil, err
+	}
 
-		if txInfo != nil {
-			transaction.Fee = &txInfo.Fee
-			transaction.ReceivedAt = helper.Pointer(time.UnixMilli(txInfo.BlockTimeStamp))
-			transaction.Block = &txInfo.BlockNumber
+	address := adapter.Address(wallet)
+	if address == nil {
+		return nil, model.NewNotFoundError()
+	}
 
-			// get the current block
-			currentBlock, err := t.Tron.GetCurrentBlock(ctx)
-			if err != nil {
-				logger.WithError(err).Warn("Failed get current block trx")
-				return
-			}
+	balance, err := adapter.GetBalance(ctx, *address)
+	if err != nil {
+		logger.WithError(err).WithField("chain", adapter.Chain()).Warn("failed get balance")
+		return nil, err
+	}
 
-			// calucate the confirmation block
-			if currentBlock != nil && transaction.Block != nil {
-				transaction.Confirmation = helper.Pointer(*currentBlock - *transaction.Block)
-			}
+	return &model.Balance{
+		Chain:   helper.Pointer(adapter.Chain()),
+		Symbol:  helper.Pointer(adapter.Symbol()),
+		Address: address,
+		Balance: balance,
+	}, nil
+}
 
-			// upsert tx on database
-			if _, err = t.trxTransactionRepo.Upsert(ctx, transaction); err != nil {
-				logger.WithError(err).Warn("Failed upsert transaction trx")
-				return
-			}
+// EstimateFee estimates the fee of sending the amount from the wallet of the user, only tron is supported
+func (t Transaction) EstimateFee(ctx context.Context, reqSend *model.SendToken) (*model.TronFeeEstimate, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Transaction.EstimateFee")
 
-			break
-		}
+	if *reqSend.Token != "trx" && *reqSend.Token != "tron" {
+		return nil, model.NewParameterError(helper.Pointer("fee estimates are only supported for trx"))
+	}
 
-		time.Sleep(t.sleepCheckPendingTrx)
+	if err := t.Tron.CheckAddress(*reqSend.ReceiverAddress); err != nil {
+		return nil, model.NewParameterError(helper.Pointer("invalid receiver tron address"))
 	}
 
-	for {
-		unconfirmed, err := t.Tron.GetUnconfirmedTxAddress(ctx, &transaction.SenderAddress[0])
+	wallet, err := t.Wallet.Get(ctx, &repository.WalletGetFilter{UserId: reqSend.UserId}, nil)
+	if err != nil {
+		logger.WithError(err).Warn("failed get wallet")
+		return nil, err
+	}
+
+	if wallet.TrxAddress == nil {
+		return nil, model.NewNotFoundError()
+	}
+
+	estimate, err := t.Tron.EstimateTransferFee(ctx, &model.TxOpts{
+		To:     reqSend.ReceiverAddress,
+		Amount: big.NewInt(*reqSend.Amount),
+	}, wallet.TrxAddress)
+	if err != nil {
+		logger.WithError(err).Warn("failed estimate trx fee")
+		return nil, err
+	}
+
+	return estimate, nil
+}
+
+// TrackTransaction enqueues the job that follows a transaction of the chain until it is final
+func (t Transaction) TrackTransaction(ctx context.Context, chain string, transaction *model.Transaction) error {
+	return enqueueJob(ctx, t.jobRepo, t.config.Job, model.JobCheckTransaction(chain), transaction)
+}
+
+// checkTransaction is one step of tracking a transaction of the chain, it asks to run again until the transaction is final
+func (t Transaction) checkTransaction(chain string) jobHandler {
+	return func(ctx context.Context, job model.Job) (time.Duration, error) {
+		logger := helper.GetLogger(ctx).WithField("method", "Usecase.Transaction.checkTransaction").WithField("chain", chain)
+
+		adapter, err := t.chains.Get(chain)
 		if err != nil {
-			logger.WithError(err).Warn("failed to GetUnconfirmedTxAddress")
-			return
+			return 0, err
 		}
+		transactionRepo := t.transactionRepo(chain)
 
-		for _, tx := range unconfirmed.Data {
-			if *tx.TxID == *transaction.Id {
-				time.Sleep(t.sleepCheckPendingTrx)
-				continue
-			}
+		transaction := &model.Transaction{}
+		if err := json.Unmarshal([]byte(*job.Payload), transaction); err != nil {
+			return 0, err
 		}
 
-		confirmed, err := t.Tron.GetConfirmedTxAddress(ctx, &transaction.SenderAddress[0])
-		if err != nil {
-			logger.WithError(err).Warn("failed to GetConfirmedTxAddress")
-			return
+		// the transaction of a send flow carries the sender, while a watched deposit only knows its receiver
+		isSend := len(transaction.SenderAddress) > 0
+		eventAddresses := transaction.ReceiverAddress
+		if isSend {
+			eventAddresses = transaction.SenderAddress
+		}
+
+		existing, err := transactionRepo.Get(ctx, &repository.TransactionGetFilter{Id: transaction.Id})
+		if err != nil && !model.IsNotFoundError(err) {
+			logger.WithError(err).Warn("failed get transaction")
+			return 0, err
+		}
+		if existing != nil && helper.Val(existing.Status) != model.TransactionStatusPending {
+			// settled elsewhere, for example dropped by the reconciler
+			return 0, nil
+		}
+
+		observed, err := adapter.GetTx(ctx, *transaction.Id)
+		if model.IsNotFoundError(err) {
+			// not propagated yet, or dropped which the reconciler decides
+			return t.sleepCheckPendingTrx, nil
+		} else if err != nil {
+			logger.WithError(err).Warn("failed get transaction from chain")
+			return 0, err
+		}
+
+		mergeObservedTransaction(transaction, observed)
+		t.confirmationPolicy.Apply(chain, transaction)
+
+		if existing == nil || !helper.EqualPointerValue(existing.Confirmation, transaction.Confirmation) || !helper.EqualPointerValue(existing.Status, transaction.Status) {
+			if _, err = transactionRepo.Upsert(ctx, transaction); err != nil {
+				logger.WithError(err).Warn("failed upsert transaction")
+				return 0, err
+			}
+
+			if *transaction.Status == model.TransactionStatusPending {
+				t.events.publish(ctx, model.NewTransactionEvent(model.EventConfirmationChanged, chain, transaction, eventAddresses))
+			}
 		}
 
-		for _, tx := range confirmed.Data {
-			if *tx.TxID == *transaction.Id {
-				// get the current block
-				currentBlock, err := t.Tron.GetCurrentBlock(ctx)
-				if err != nil {
-					logger.WithError(err).Warn("Failed get current block trx")
-					return
-				}
-
-				// calucate the confirmation block
-				if currentBlock != nil && transaction.Block != nil {
-					transaction.Confirmation = helper.Pointer(*currentBlock - *transaction.Block)
-				}
-
-				transaction.Status = helper.Pointer("success")
-
-				// upsert tx on database
-				if _, err = t.trxTransactionRepo.Upsert(ctx, transaction); err != nil {
-					logger.WithError(err).Warn("Failed upsert transaction trx")
-				}
-				return
+		switch *transaction.Status {
+		case model.TransactionStatusSuccess:
+			if isSend {
+				t.events.publish(ctx, model.NewTransactionEvent(model.EventSendConfirmed, chain, transaction, eventAddresses))
+			}
+			return 0, nil
+		case model.TransactionStatusFailed:
+			logger.WithField("transactionId", *transaction.Id).WithField("reason", helper.Val(transaction.FailureReason)).Warn("transaction failed")
+			if isSend {
+				t.events.publish(ctx, model.NewTransactionEvent(model.EventSendFailed, chain, transaction, eventAddresses))
 			}
+			return 0, nil
 		}
 
-		time.Sleep(t.sleepCheckConfirmationTrx)
+		// still waiting to be mined
+		if transaction.Block == nil {
+			return t.sleepCheckPendingTrx, nil
+		}
+
+		return t.sleepCheckConfirmationTrx, nil
 	}
 }
 
-func (t Transaction) SendEthereum(ctx context.Context, reqSend *model.SendToken) (txHash *string, err error) {
-	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Transaction.SendEthereum")
-
-	// validate receiver ethereum address
-	if err := t.Ethereum.CheckAddress(*reqSend.ReceiverAddress); err != nil {
-		err = fmt.Errorf("invalid receiver ethereum address")
-		logger.WithError(err)
-		return nil, err
+// mergeObservedTransaction copies what the chain reports onto the tracked transaction, the addresses and amount
+// stay as tracked unless the tracked transaction does not know them
+func mergeObservedTransaction(transaction *model.Transaction, observed *model.Transaction) {
+	if len(transaction.SenderAddress) == 0 {
+		transaction.SenderAddress = observed.SenderAddress
+	}
+	if len(transaction.ReceiverAddress) == 0 {
+		transaction.ReceiverAddress = observed.ReceiverAddress
+	}
+	if transaction.Amount == nil {
+		transaction.Amount = observed.Amount
+	}
+	if observed.Fee != nil {
+		transaction.Fee = observed.Fee
+	}
+	if observed.ReceivedAt != nil {
+		transaction.ReceivedAt = observed.ReceivedAt
 	}
 
-	// get the seedphrase of sender
-	wallet, err := t.Wallet.Get(ctx, &repository.WalletGetFilter{
-		Email: reqSend.Email,
-	}, &t.config.Service.SeedPhraseEncryptionKey)
+	transaction.Block = observed.Block
+	transaction.Confirmation = helper.Pointer(helper.Val(observed.Confirmation))
+	transaction.Status = observed.Status
+	transaction.GasUsed = observed.GasUsed
+	transaction.EffectiveGasPrice = observed.EffectiveGasPrice
+	transaction.FailureReason = observed.FailureReason
+}
+
+// GetTransaction looks up a transaction of the wallet of the user, transactions of other wallets are not found
+func (t Transaction) GetTransaction(ctx context.Context, userId *string, token *string, id *string) (*model.Transaction, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Transaction.GetTransaction")
+
+	wallet, err := t.Wallet.Get(ctx, &repository.WalletGetFilter{UserId: userId}, nil)
 	if err != nil {
 		logger.WithError(err).Warn("failed get wallet")
 		return nil, err
 	}
 
-	// generate seedphrase to get trx wallet
-	ethWallet, err := t.Ethereum.GetWallet(ctx, wallet.SeedPhrase)
+	adapter, transactionRepo, address, err := t.walletChain(wallet, helper.Val(token))
 	if err != nil {
-		logger.WithError(err).Warn("failed get eth wallet")
 		return nil, err
 	}
 
-	// send token
-	tx, err := t.Ethereum.SendTx(ctx, &model.TxOpts{
-		To:     reqSend.ReceiverAddress,
-		Amount: big.NewInt(*reqSend.Amount),
-	}, ethWallet)
+	transaction, err := transactionRepo.Get(ctx, &repository.TransactionGetFilter{Id: id})
 	if err != nil {
-		logger.WithError(err).Warn("failed send trx")
+		logger.WithError(err).Warn("failed get transaction")
 		return nil, err
 	}
 
-	transaction := &model.Transaction{
-		Id:              helper.Pointer(tx.Hash().Hex()),
-		SenderAddress:   []string{*wallet.EthAddress},
-		ReceiverAddress: []string{*reqSend.ReceiverAddress},
-		Amount:          reqSend.Amount,
-		Confirmation:    helper.Pointer[int64](0),
-		Fee:             helper.Pointer(tx.GasPrice().Int64() * int64(tx.Gas())),
-		Status:          helper.Pointer("pending"),
+	if !slices.Contains(transaction.SenderAddress, helper.Val(address)) && !slices.Contains(transaction.ReceiverAddress, helper.Val(address)) {
+		return nil, model.NewNotFoundError()
 	}
+	setExplorerUrl(adapter, transaction)
+
+	return transaction, nil
+}
 
-	// open new thread to check transaction success
-	go t.CheckTransactionEth(ctx, transaction)
+// ListTransactions returns the history of the wallet of the user on one chain, oldest first
+func (t Transaction) ListTransactions(ctx context.Context, userId *string, token *string, status *string) ([]model.Transaction, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Transaction.ListTransactions")
 
-	_, err = t.ethTransactionRepo.Upsert(ctx, transaction)
+	wallet, err := t.Wallet.Get(ctx, &repository.WalletGetFilter{UserId: userId}, nil)
 	if err != nil {
-		logger.WithError(err).Warn("failed Upsert eth transaction")
+		logger.WithError(err).Warn("failed get wallet")
 		return nil, err
 	}
 
-	return transaction.Id, nil
-}
-
-func (t Transaction) CheckTransactionEth(ctx context.Context, transaction *model.Transaction) {
-	reqId := ctx.Value(helper.ContextKeyRequestId)
-	if reqId == nil {
-		reqId = ctx.Value(string(helper.ContextKeyRequestId))
-		if reqId == nil {
-			reqId = ksuid.New().String()
-		}
+	adapter, transactionRepo, address, err := t.walletChain(wallet, helper.Val(token))
+	if err != nil {
+		return nil, err
 	}
-	ctx = helper.ContextWithRequestId(context.Background(), reqId.(string))
-	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Wallet.checkTransactionEth")
-
-	txHash := helper.Pointer(common.HexToHash(*transaction.Id))
-	tx := &model.Transaction{Id: transaction.Id}
-	for {
-
-		var isPending *bool
-		var err error
-		tx, isPending, err = t.Ethereum.GetTransactionPending(ctx, txHash)
-		if err != nil {
-			logger.WithError(err).Warn("failed to GetTransactionPending")
-			return
-		}
-
-		if isPending != nil && !*isPending {
-			break
-		}
 
-		time.Sleep(t.sleepCheckPendingTrx)
+	transactions, err := transactionRepo.List(ctx, &repository.TransactionGetFilter{
+		Address: address,
+		Status:  status,
+	})
+	if err != nil {
+		logger.WithError(err).Warn("failed list transactions")
+		return nil, err
+	}
+	for i := range transactions {
+		setExplorerUrl(adapter, &transactions[i])
 	}
 
-	for {
-		blockDetails, err := t.Ethereum.GetBlockInformation(ctx, txHash)
-		if err != nil {
-			logger.WithError(err).Warn("failed to GetUnconfirmedTxAddress")
-			return
-		}
+	return transactions, nil
+}
 
-		tx.ReceivedAt = blockDetails.ReceivedAt
-		tx.Block = blockDetails.Block
-		if blockDetails.Status != nil {
-			tx.Status = blockDetails.Status
-		}
-		if *blockDetails.Confirmation > *tx.Confirmation {
-			tx.Confirmation = blockDetails.Confirmation
+// walletChain returns the adapter and transaction repository of the token and the address the wallet has on that chain
+func (t Transaction) walletChain(wallet *model.Wallet, token string) (service.ChainAdapter, repository.Transaction, *string, error) {
+	adapter, err := t.chains.Get(token)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-			_, err = t.ethTransactionRepo.Upsert(ctx, tx)
-			if err != nil {
-				logger.WithError(err).Warn("failed Upsert eth transaction")
-				return
-			}
-		}
+	address := adapter.Address(wallet)
+	if address == nil {
+		return nil, nil, nil, model.NewNotFoundError()
+	}
 
-		if *tx.Status == "success" {
-			break
-		}
+	return adapter, t.transactionRepo(adapter.Chain()), address, nil
+}
 
-		time.Sleep(t.sleepCheckConfirmationTrx)
+func setExplorerUrl(adapter service.ChainAdapter, transaction *model.Transaction) {
+	if url := adapter.ExplorerUrl(helper.Val(transaction.Id)); url != "" {
+		transaction.ExplorerUrl = &url
 	}
 }
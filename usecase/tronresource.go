@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/api"
+)
+
+// tronChain is the chain the resources are staked on, it is what the limits and the allowlist are kept under
+const tronChain = "trx"
+
+type TronResource struct {
+	config config.Config
+	service.Tron
+	repository.Wallet
+
+	trxTransactionRepo repository.Transaction
+	jobRepo            repository.Job
+
+	totp              *Totp
+	withdrawalAddress *WithdrawalAddress
+	spendingLimit     *SpendingLimit
+	audit             *Audit
+}
+
+func NewTronResource(c *container.Container) *TronResource {
+	return &TronResource{
+		config:             c.Config(),
+		Tron:               c.Tron(),
+		Wallet:             c.WalletRepo(),
+		trxTransactionRepo: c.TransactionTrxRepo(),
+		jobRepo:            c.JobRepo(),
+		totp:               NewTotp(c),
+		withdrawalAddress:  NewWithdrawalAddress(c),
+		spendingLimit:      NewSpendingLimit(c),
+		audit:              NewAudit(c),
+	}
+}
+
+func (t TronResource) GetResources(ctx context.Context, userId *string) (*model.TronAccountResources, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.TronResource.GetResources")
+
+	wallet, err := t.Wallet.Get(ctx, &repository.WalletGetFilter{UserId: userId}, nil)
+	if err != nil {
+		logger.WithError(err).Warn("failed get wallet")
+		return nil, err
+	}
+	if wallet.TrxAddress == nil {
+		return nil, model.NewNotFoundError()
+	}
+
+	resources, err := t.Tron.GetAccountResources(ctx, wallet.TrxAddress)
+	if err != nil {
+		logger.WithError(err).Warn("failed get account resources")
+		return nil, err
+	}
+
+	return resources, nil
+}
+
+// Freeze stakes TRX of the wallet for bandwidth or energy
+func (t TronResource) Freeze(ctx context.Context, req *model.TronStake) (*string, error) {
+	return t.submit(ctx, model.TransactionTypeFreeze, req, false, func(wallet *model.TrxHdWallet) (*api.TransactionExtention, error) {
+		return t.Tron.FreezeBalance(ctx, *req.Resource, *req.Amount, wallet)
+	})
+}
+
+// Unfreeze unstakes TRX of the wallet, the TRX can be withdrawn once the unstaking period passed
+func (t TronResource) Unfreeze(ctx context.Context, req *model.TronStake) (*string, error) {
+	return t.submit(ctx, model.TransactionTypeUnfreeze, req, false, func(wallet *model.TrxHdWallet) (*api.TransactionExtention, error) {
+		return t.Tron.UnfreezeBalance(ctx, *req.Resource, *req.Amount, wallet)
+	})
+}
+
+// Delegate lends the resource of staked TRX of the wallet to the receiver address, it is checked and counted against
+// the spending limits like a transfer to the receiver
+func (t TronResource) Delegate(ctx context.Context, req *model.TronStake) (*string, error) {
+	if err := t.checkReceiver(req); err != nil {
+		return nil, err
+	}
+	if err := t.withdrawalAddress.CheckSend(ctx, req.UserId, tronChain, *req.ReceiverAddress); err != nil {
+		return nil, err
+	}
+
+	return t.submit(ctx, model.TransactionTypeDelegate, req, true, func(wallet *model.TrxHdWallet) (*api.TransactionExtention, error) {
+		return t.Tron.DelegateResource(ctx, *req.Resource, *req.Amount, *req.ReceiverAddress, wallet)
+	})
+}
+
+// Undelegate takes back a resource delegated to the receiver address
+func (t TronResource) Undelegate(ctx context.Context, req *model.TronStake) (*string, error) {
+	if err := t.checkReceiver(req); err != nil {
+		return nil, err
+	}
+
+	return t.submit(ctx, model.TransactionTypeUndelegate, req, false, func(wallet *model.TrxHdWallet) (*api.TransactionExtention, error) {
+		return t.Tron.UndelegateResource(ctx, *req.Resource, *req.Amount, *req.ReceiverAddress, wallet)
+	})
+}
+
+func (t TronResource) checkReceiver(req *model.TronStake) error {
+	if req.ReceiverAddress == nil || t.Tron.CheckAddress(*req.ReceiverAddress) != nil {
+		return model.NewParameterError(helper.Pointer("invalid receiver tron address"))
+	}
+
+	return nil
+}
+
+// submit signs the operation with the wallet of the user and records it as a pending trx transaction that the
+// worker follows like a transfer. The second factor is checked like for a transfer, and with reserve the amount is
+// counted against the spending limits
+func (t TronResource) submit(ctx context.Context, transactionType string, req *model.TronStake, reserve bool, operation func(wallet *model.TrxHdWallet) (*api.TransactionExtention, error)) (*string, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.TronResource.submit").WithField("type", transactionType)
+
+	// the second factor is checked before the seed phrase is decrypted
+	if err := t.totp.CheckSend(ctx, req.UserId, tronChain, *req.Amount, req.Otp); err != nil {
+		return nil, err
+	}
+
+	var spending *model.SpendingRecord
+	release := func() {}
+	if reserve {
+		var err error
+		spending, err = t.spendingLimit.Reserve(ctx, req.UserId, tronChain, *req.Amount, nil)
+		if err != nil {
+			return nil, err
+		}
+		release = func() {
+			if err := t.spendingLimit.Release(ctx, *spending.Id); err != nil {
+				logger.WithError(err).Warn("failed release spending")
+			}
+		}
+	}
+
+	wallet, err := t.Wallet.Get(ctx, &repository.WalletGetFilter{
+		UserId: req.UserId,
+	}, &t.config.Service.SeedPhraseEncryptionKey)
+	if err != nil {
+		logger.WithError(err).Warn("failed get wallet")
+		release()
+		return nil, err
+	}
+
+	trxWallet := t.Tron.GetWallet(ctx, wallet.SeedPhrase)
+
+	tx, err := operation(trxWallet)
+	metadata := map[string]string{
+		"user_id":  helper.Val(req.UserId),
+		"chain":    tronChain,
+		"type":     transactionType,
+		"resource": helper.Val(req.Resource),
+		"receiver": helper.Val(req.ReceiverAddress),
+		"amount":   strconv.FormatInt(*req.Amount, 10),
+	}
+	if err != nil {
+		logger.WithError(err).Warn(fmt.Sprintf("failed %s trx", transactionType))
+		// the reservation is kept when the broadcast may have gone through
+		if !model.IsBroadcastUnknownError(err) {
+			release()
+		}
+		metadata["error"] = err.Error()
+		_ = t.audit.Record(ctx, model.AuditTransactionFailed, nil, wallet.Id, metadata)
+		return nil, err
+	}
+
+	// the resource goes back to the wallet itself unless it is delegated
+	receiver := trxWallet.Address
+	if req.ReceiverAddress != nil {
+		receiver = req.ReceiverAddress
+	}
+
+	transaction := &model.Transaction{
+		Id:              helper.Pointer(common.BytesToHash(tx.Txid).Hex()[2:]),
+		SenderAddress:   []string{*trxWallet.Address},
+		ReceiverAddress: []string{*receiver},
+		Amount:          req.Amount,
+		Fee:             helper.Pointer[int64](0),
+		Confirmation:    helper.Pointer[int64](0),
+		Status:          helper.Pointer(model.TransactionStatusPending),
+		Type:            &transactionType,
+		Resource:        req.Resource,
+	}
+
+	_ = t.audit.Record(ctx, model.AuditTransactionSent, nil, transaction.Id, metadata)
+
+	if spending != nil {
+		if err := t.spendingLimit.SetTransactionId(ctx, *spending.Id, *transaction.Id); err != nil {
+			logger.WithError(err).Warn("failed link spending to transaction")
+		}
+	}
+
+	if _, err = t.trxTransactionRepo.Upsert(ctx, transaction); err != nil {
+		logger.WithError(err).Warn("failed Upsert trx transaction")
+		return nil, err
+	}
+
+	if err := enqueueJob(ctx, t.jobRepo, t.config.Job, model.JobCheckTransaction("trx"), transaction); err != nil {
+		logger.WithError(err).Warn("failed enqueue check transaction trx")
+	}
+
+	return transaction.Id, nil
+}
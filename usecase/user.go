@@ -0,0 +1,276 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+	"github.com/aalexanderkevin/crypto-wallet/service/password"
+
+	"github.com/segmentio/ksuid"
+)
+
+type User struct {
+	config config.Config
+	repository.User
+
+	refreshTokenRepo repository.RefreshToken
+	denylist         service.Cache
+	audit            *Audit
+}
+
+func NewUser(c *container.Container) *User {
+	return &User{
+		config:           c.Config(),
+		User:             c.UserRepo(),
+		refreshTokenRepo: c.RefreshTokenRepo(),
+		denylist:         c.Redis(),
+		audit:            NewAudit(c),
+	}
+}
+
+func (u User) hasher() (*password.Hasher, error) {
+	return password.NewHasher(u.config.Password)
+}
+
+// Register adds the user, the password is stored hashed with the configured algorithm
+func (u User) Register(ctx context.Context, user *model.User) (*model.User, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.User.Register")
+
+	if err := user.Validate(); err != nil {
+		return nil, model.NewParameterError(helper.Pointer(err.Error()))
+	}
+
+	hasher, err := u.hasher()
+	if err != nil {
+		logger.WithError(err).Warn("failed init password hasher")
+		return nil, err
+	}
+
+	hash, err := hasher.Hash(*user.Password)
+	if err != nil {
+		logger.WithError(err).Warn("failed hash password")
+		return nil, err
+	}
+
+	user.Id = nil
+	user.Password = &hash
+	user.PasswordSalt = nil
+	// admins are promoted in the database, registering only makes users
+	user.Roles = []string{model.RoleUser}
+
+	user, err = u.User.Add(ctx, user)
+	if err != nil {
+		logger.WithError(err).Warn("failed insert user")
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Login returns the user when the password matches, the caller issues the access token. A password hashed with a
+// legacy algorithm or outdated parameters is re-hashed with the configured ones
+func (u User) Login(ctx context.Context, login *model.User) (*model.User, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.User.Login")
+
+	if err := login.ValidateLogin(); err != nil {
+		return nil, model.NewParameterError(helper.Pointer(err.Error()))
+	}
+
+	user, err := u.User.Get(ctx, &repository.UserGetFilter{Username: login.Username})
+	if err != nil {
+		if model.IsNotFoundError(err) {
+			// an unknown username is reported like a wrong password
+			u.auditLoginFailure(ctx, nil, *login.Username, "unknown username")
+			return nil, model.NewInvalidPasswordError()
+		}
+		logger.WithError(err).Warn("failed get user")
+		return nil, err
+	}
+
+	hasher, err := u.hasher()
+	if err != nil {
+		logger.WithError(err).Warn("failed init password hasher")
+		return nil, err
+	}
+
+	match, rehash, err := hasher.Verify(helper.Val(user.Password), *login.Password, user.PasswordSalt)
+	if err != nil {
+		logger.WithError(err).Warn("failed verify password")
+		return nil, err
+	}
+	if !match {
+		u.auditLoginFailure(ctx, user.Id, *login.Username, "invalid password")
+		return nil, model.NewInvalidPasswordError()
+	}
+
+	if rehash {
+		// failing to upgrade the hash does not fail the login, it is tried again on the next one
+		hash, err := hasher.Hash(*login.Password)
+		if err == nil {
+			err = u.User.UpdatePassword(ctx, *user.Id, hash)
+		}
+		if err != nil {
+			logger.WithError(err).Warn("failed re-hash password")
+		} else {
+			user.Password = &hash
+			user.PasswordSalt = nil
+		}
+	}
+
+	return user, nil
+}
+
+func (u User) auditLoginFailure(ctx context.Context, userId *string, username string, reason string) {
+	_ = u.audit.Record(ctx, model.AuditAuthFailed, nil, userId, map[string]string{
+		"method":   "login",
+		"username": username,
+		"reason":   reason,
+	})
+}
+
+// IssueRefreshToken returns a new refresh token of the user, a nil family starts a new one. Only the hash of the
+// token is stored
+func (u User) IssueRefreshToken(ctx context.Context, userId string, familyId *string) (*string, *model.RefreshToken, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.User.IssueRefreshToken")
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		logger.WithError(err).Warn("failed generate refresh token")
+		return nil, nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	if familyId == nil {
+		familyId = helper.Pointer(ksuid.New().String())
+	}
+
+	refreshToken, err := u.refreshTokenRepo.Add(ctx, &model.RefreshToken{
+		Id:        helper.Pointer(hashRefreshToken(token)),
+		UserId:    &userId,
+		FamilyId:  familyId,
+		ExpiresAt: helper.Pointer(time.Now().Add(time.Duration(u.config.RefreshTokenTtlSeconds) * time.Second)),
+	})
+	if err != nil {
+		logger.WithError(err).Warn("failed insert refresh token")
+		return nil, nil, err
+	}
+
+	return &token, refreshToken, nil
+}
+
+// RotateRefreshToken uses the refresh token up and issues the next one of its family. A token used a second time was
+// stolen or leaked, the whole family is revoked then
+func (u User) RotateRefreshToken(ctx context.Context, token string) (*model.User, *string, *model.RefreshToken, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.User.RotateRefreshToken")
+
+	id := hashRefreshToken(token)
+	refreshToken, err := u.refreshTokenRepo.Get(ctx, id)
+	if err != nil {
+		if model.IsNotFoundError(err) {
+			return nil, nil, nil, model.NewUnauthenticatedError()
+		}
+		logger.WithError(err).Warn("failed get refresh token")
+		return nil, nil, nil, err
+	}
+	logger = logger.WithField("family_id", helper.Val(refreshToken.FamilyId))
+
+	if refreshToken.RevokedAt != nil {
+		return nil, nil, nil, model.NewUnauthenticatedError()
+	}
+	if refreshToken.UsedAt != nil {
+		logger.Warn("refresh token reused, revoking the family")
+		return nil, nil, nil, u.revokeReusedFamily(ctx, *refreshToken.FamilyId)
+	}
+	if refreshToken.ExpiresAt != nil && refreshToken.ExpiresAt.Before(time.Now()) {
+		return nil, nil, nil, model.NewUnauthenticatedError()
+	}
+
+	if err := u.refreshTokenRepo.MarkUsed(ctx, id); err != nil {
+		if model.IsNotFoundError(err) {
+			// a concurrent refresh used the token first
+			logger.Warn("refresh token reused, revoking the family")
+			return nil, nil, nil, u.revokeReusedFamily(ctx, *refreshToken.FamilyId)
+		}
+		logger.WithError(err).Warn("failed mark refresh token used")
+		return nil, nil, nil, err
+	}
+
+	user, err := u.User.Get(ctx, &repository.UserGetFilter{Id: refreshToken.UserId})
+	if err != nil {
+		logger.WithError(err).Warn("failed get user")
+		return nil, nil, nil, err
+	}
+
+	next, nextRefreshToken, err := u.IssueRefreshToken(ctx, *user.Id, refreshToken.FamilyId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return user, next, nextRefreshToken, nil
+}
+
+// Logout revokes the access token with the jti and the refresh token family it was issued for
+func (u User) Logout(ctx context.Context, jti string, expiresAt time.Time, familyId string) error {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.User.Logout")
+
+	if familyId != "" {
+		if err := u.revokeFamily(ctx, familyId); err != nil {
+			logger.WithError(err).Warn("failed revoke refresh token family")
+			return err
+		}
+	}
+
+	if err := u.deny(ctx, model.JwtDenylistKey(jti), time.Until(expiresAt)); err != nil {
+		logger.WithError(err).Warn("failed deny access token")
+		return err
+	}
+
+	return nil
+}
+
+func (u User) revokeReusedFamily(ctx context.Context, familyId string) error {
+	if err := u.revokeFamily(ctx, familyId); err != nil {
+		helper.GetLogger(ctx).WithField("method", "Usecase.User.revokeReusedFamily").WithError(err).Warn("failed revoke refresh token family")
+		return err
+	}
+
+	return model.NewUnauthenticatedError()
+}
+
+// revokeFamily revokes the refresh tokens of the family and denies the access tokens issued for it until the last
+// one expired
+func (u User) revokeFamily(ctx context.Context, familyId string) error {
+	if err := u.refreshTokenRepo.RevokeFamily(ctx, familyId); err != nil {
+		return err
+	}
+
+	return u.deny(ctx, model.TokenFamilyDenylistKey(familyId), time.Duration(u.config.JwtTtlSeconds)*time.Second)
+}
+
+func (u User) deny(ctx context.Context, key string, ttl time.Duration) error {
+	if u.denylist == nil {
+		helper.GetLogger(ctx).WithField("method", "Usecase.User.deny").Warn("no token denylist, the access token stays valid until it expires")
+		return nil
+	}
+	if ttl <= 0 {
+		// the token expired already
+		return nil
+	}
+
+	return u.denylist.Put(ctx, key, "revoked", ttl)
+}
+
+func hashRefreshToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -12,12 +12,20 @@ import (
 	"github.com/aalexanderkevin/crypto-wallet/service"
 )
 
+// backfillBatchSize is how many wallets are read at once when their missing addresses are derived
+const backfillBatchSize = 100
+
 type Wallet struct {
 	config config.Config
 	service.Bitcoin
 	service.Ethereum
 	service.Tron
 	repository.Wallet
+
+	userRepo repository.User
+	litecoin service.Bitcoin
+	dogecoin service.Bitcoin
+	audit    *Audit
 }
 
 func NewWallet(c *container.Container) *Wallet {
@@ -27,14 +35,25 @@ func NewWallet(c *container.Container) *Wallet {
 		Ethereum: c.Ethereum(),
 		Tron:     c.Tron(),
 		Wallet:   c.WalletRepo(),
+		userRepo: c.UserRepo(),
+		litecoin: c.Utxo("ltc"),
+		dogecoin: c.Utxo("doge"),
+		audit:    NewAudit(c),
 	}
 }
 
-func (w Wallet) CreateNewWallet(ctx context.Context, email *string) (wallet *model.Wallet, err error) {
+// CreateNewWallet creates the wallet of the user, a user has a single wallet
+func (w Wallet) CreateNewWallet(ctx context.Context, userId *string) (wallet *model.Wallet, err error) {
 	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Wallet.CreateNewWallet")
 
+	user, err := w.userRepo.Get(ctx, &repository.UserGetFilter{Id: userId})
+	if err != nil {
+		logger.WithError(err).Warn("failed get user")
+		return nil, err
+	}
+
 	existingWallet, err := w.Wallet.Get(ctx, &repository.WalletGetFilter{
-		Email: email,
+		UserId: userId,
 	}, nil)
 	if err == nil && existingWallet != nil {
 		err = fmt.Errorf("wallet already exist")
@@ -60,12 +79,27 @@ func (w Wallet) CreateNewWallet(ctx context.Context, email *string) (wallet *mod
 
 	trxWallet := w.Tron.GetWallet(ctx, &seedPhrase)
 
+	ltcWallet, err := w.litecoin.GetWallet(ctx, &seedPhrase)
+	if err != nil {
+		logger.WithError(err).Warn("failed get new ltc wallet by seedPhrase")
+		return nil, err
+	}
+
+	dogeWallet, err := w.dogecoin.GetWallet(ctx, &seedPhrase)
+	if err != nil {
+		logger.WithError(err).Warn("failed get new doge wallet by seedPhrase")
+		return nil, err
+	}
+
 	wallet = &model.Wallet{}
-	wallet.Email = email
+	wallet.UserId = user.Id
+	wallet.Email = user.Email
 	wallet.SeedPhrase = &seedPhrase
 	wallet.BtcAddress = helper.Pointer(btcWallet.Address.EncodeAddress())
 	wallet.EthAddress = helper.Pointer(ethWallet.Account.Address.Hex())
-	wallet.EthAddress = trxWallet.Address
+	wallet.TrxAddress = trxWallet.Address
+	wallet.LtcAddress = helper.Pointer(ltcWallet.Address.EncodeAddress())
+	wallet.DogeAddress = helper.Pointer(dogeWallet.Address.EncodeAddress())
 
 	wallet, err = w.Wallet.Add(ctx, wallet, &w.config.Service.SeedPhraseEncryptionKey)
 	if err != nil {
@@ -73,5 +107,76 @@ func (w Wallet) CreateNewWallet(ctx context.Context, email *string) (wallet *mod
 		return nil, err
 	}
 
+	// the wallet exists either way, a failed audit is only logged
+	_ = w.audit.Record(ctx, model.AuditWalletCreated, nil, wallet.Id, map[string]string{"user_id": helper.Val(user.Id)})
+
+	return wallet, nil
+}
+
+// GetWallet returns the wallet of the user without its seed phrase
+func (w Wallet) GetWallet(ctx context.Context, userId *string) (*model.Wallet, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Wallet.GetWallet")
+
+	wallet, err := w.Wallet.Get(ctx, &repository.WalletGetFilter{UserId: userId}, nil)
+	if err != nil {
+		logger.WithError(err).Warn("failed get wallet")
+		return nil, err
+	}
+
 	return wallet, nil
 }
+
+// BackfillAddresses derives the litecoin and dogecoin addresses of the wallets created before those coins were
+// supported, it returns how many wallets were updated
+func (w Wallet) BackfillAddresses(ctx context.Context) (int, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Wallet.BackfillAddresses")
+
+	updated := 0
+	var afterId *string
+	for {
+		wallets, err := w.Wallet.ListMissingAddresses(ctx, afterId, backfillBatchSize)
+		if err != nil {
+			logger.WithError(err).Warn("failed list wallets missing addresses")
+			return updated, err
+		}
+		if len(wallets) == 0 {
+			return updated, nil
+		}
+
+		for _, wallet := range wallets {
+			afterId = wallet.Id
+			if err := w.backfillAddresses(ctx, *wallet.Id); err != nil {
+				logger.WithError(err).WithField("walletId", *wallet.Id).Warn("failed backfill wallet addresses")
+				return updated, err
+			}
+			updated++
+		}
+	}
+}
+
+// backfillAddresses derives the missing addresses of the wallet from its seed phrase
+func (w Wallet) backfillAddresses(ctx context.Context, id string) error {
+	wallet, err := w.Wallet.Get(ctx, &repository.WalletGetFilter{Id: &id}, &w.config.Service.SeedPhraseEncryptionKey)
+	if err != nil {
+		return err
+	}
+
+	update := &model.Wallet{}
+	if wallet.LtcAddress == nil {
+		ltcWallet, err := w.litecoin.GetWallet(ctx, wallet.SeedPhrase)
+		if err != nil {
+			return err
+		}
+		update.LtcAddress = helper.Pointer(ltcWallet.Address.EncodeAddress())
+	}
+	if wallet.DogeAddress == nil {
+		dogeWallet, err := w.dogecoin.GetWallet(ctx, wallet.SeedPhrase)
+		if err != nil {
+			return err
+		}
+		update.DogeAddress = helper.Pointer(dogeWallet.Address.EncodeAddress())
+	}
+
+	_, err = w.Wallet.Update(ctx, id, update)
+	return err
+}
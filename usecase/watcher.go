@@ -10,178 +10,102 @@ import (
 	"github.com/aalexanderkevin/crypto-wallet/model"
 	"github.com/aalexanderkevin/crypto-wallet/repository"
 	"github.com/aalexanderkevin/crypto-wallet/service"
-	"golang.org/x/exp/slices"
 )
 
+// watchDuration is how long the deposits to an address are watched after the watch is triggered
+const watchDuration = 5 * time.Minute
+
 type Watcher struct {
 	config config.Config
-	service.Bitcoin
-	service.Ethereum
-	service.Tron
 	service.Cache
-
-	btcTransactionRepo repository.Transaction
-	ethTransactionRepo repository.Transaction
-	trxTransactionRepo repository.Transaction
 	repository.Wallet
 
+	chains          service.ChainRegistry
+	transactionRepo func(chain string) repository.Transaction
+
+	events             eventPublisher
+	confirmationPolicy service.ConfirmationPolicy
+
 	usecaseTransaction Transaction
 }
 
 func NewWatcher(c *container.Container, t Transaction) *Watcher {
 	return &Watcher{
 		config:             c.Config(),
-		Bitcoin:            c.Bitcoin(),
-		Ethereum:           c.Ethereum(),
-		Tron:               c.Tron(),
-		Wallet:             c.WalletRepo(),
-		btcTransactionRepo: c.TransactionBtcRepo(),
-		ethTransactionRepo: c.TransactionEthRepo(),
-		trxTransactionRepo: c.TransactionTrxRepo(),
 		Cache:              c.Redis(),
+		Wallet:             c.WalletRepo(),
+		chains:             c.ChainRegistry(),
+		transactionRepo:    c.TransactionRepo,
+		events:             newEventPublisher(c),
+		confirmationPolicy: c.ConfirmationPolicy(),
 		usecaseTransaction: t,
 	}
 }
 
-func (w *Watcher) TriggerWatcherEth(ctx context.Context, email *string) (*string, error) {
-	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Watcher.TriggerWatcherEth")
+// TriggerWatcher starts watching the deposits to the wallet of the user on the chain of the token, an address already
+// watched is not watched twice
+func (w *Watcher) TriggerWatcher(ctx context.Context, userId *string, token *string) (*string, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Watcher.TriggerWatcher")
 
-	// get the seedphrase of sender
-	wallet, err := w.Wallet.Get(ctx, &repository.WalletGetFilter{
-		Email: email,
-	}, &w.config.Service.SeedPhraseEncryptionKey)
+	adapter, err := w.chains.Get(helper.Val(token))
 	if err != nil {
-		logger.WithError(err).Warn("failed get wallet")
 		return nil, err
 	}
+	logger = logger.WithField("chain", adapter.Chain())
 
-	res, err := w.Cache.SetList(ctx, "address", *wallet.EthAddress, helper.Pointer(2*time.Minute))
+	// only the address is read, the seed phrase stays encrypted
+	wallet, err := w.Wallet.Get(ctx, &repository.WalletGetFilter{
+		UserId: userId,
+	}, nil)
 	if err != nil {
-		logger.WithError(err).Warn("failed set address on cache")
+		logger.WithError(err).Warn("failed get wallet")
 		return nil, err
 	}
 
-	if res == 1 {
-		go w.RunningWatcherEth(context.Background(), helper.Pointer("address"))
+	address := adapter.Address(wallet)
+	if address == nil {
+		return nil, model.NewFailedPreconditionError(helper.Pointer("the wallet has no address on the chain"))
 	}
 
-	return wallet.EthAddress, nil
-}
-
-func (w *Watcher) RunningWatcherEth(ctx context.Context, cacheKeyList *string) {
-	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Watcher.RunningWatcherEth")
-
-subscribe:
-	subs, txch, err := w.SubscribePendingTransactions(ctx)
+	first, err := w.Cache.PutIfAbsent(ctx, "watch:"+adapter.Chain()+":"+*address, "1", watchDuration)
 	if err != nil {
-		logger.WithError(err).Warn("Failed to SubscribeFullPendingTransactions")
-		return
+		logger.WithError(err).Warn("failed set address on cache")
+		return nil, err
 	}
 
-	defer subs.Unsubscribe()
-
-	for {
-		select {
-		case tx := <-txch:
-			addresses, err := w.Cache.GetList(ctx, *cacheKeyList)
-			if err != nil {
-				logger.WithError(err).Warn("Failed to get list address from cache")
-				return
-			}
-
-			if tx.To() != nil && slices.Contains(addresses, tx.To().Hex()) {
-				go w.usecaseTransaction.CheckTransactionEth(ctx, &model.Transaction{
-					Id:              helper.Pointer(tx.Hash().Hex()),
-					ReceiverAddress: []string{tx.To().Hex()},
-				})
-			}
-
-		case err := <-subs.Err():
-			logger.WithError(err).Warn("subscribe client connection is closed unexpectedly")
-			goto subscribe
-		}
+	if first {
+		go w.watch(helper.ContextWithRequestId(context.Background(), helper.Val(helper.GetRequestId(ctx))), adapter, *address)
 	}
+
+	return address, nil
 }
 
-func (w *Watcher) TriggerWatcherTrx(ctx context.Context, email *string) (*string, error) {
-	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Watcher.TriggerWatcherTrx")
+// watch records and announces the deposits the adapter reports until the watch is over
+func (w *Watcher) watch(ctx context.Context, adapter service.ChainAdapter, address string) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Watcher.watch").WithField("chain", adapter.Chain())
+	ctx, cancel := context.WithTimeout(ctx, watchDuration)
+	defer cancel()
 
-	// get the seedphrase of sender
-	wallet, err := w.Wallet.Get(ctx, &repository.WalletGetFilter{
-		Email: email,
-	}, &w.config.Service.SeedPhraseEncryptionKey)
+	err := adapter.WatchDeposits(ctx, address, func(deposit *model.Transaction) {
+		if err := w.recordDeposit(ctx, adapter.Chain(), deposit); err != nil {
+			logger.WithError(err).WithField("transactionId", helper.Val(deposit.Id)).Warn("failed record deposit")
+		}
+	})
 	if err != nil {
-		logger.WithError(err).Warn("failed get wallet")
-		return nil, err
+		logger.WithError(err).Warn("failed watch deposits")
 	}
-
-	go w.RunningWatcherTrx(context.Background(), wallet.TrxAddress)
-
-	return wallet.TrxAddress, nil
 }
 
-func (w *Watcher) RunningWatcherTrx(ctx context.Context, trxAddress *string) {
-	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Watcher.RunningWatcherTrx")
-	newctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
-	startWatcher := time.Now().UnixMilli()
-
-subscribe:
-	for {
-		select {
-		case <-newctx.Done():
-			cancel()
-			return
-		default:
-			var fingerprint *string
-
-			// get confirmed transaction
-			trx, err := w.Tron.GetTxByAccountAddress(ctx, trxAddress, &service.GetTxByAccountAddressFilter{
-				OnlyConfirmed:  helper.Pointer(true),
-				OnlyTo:         helper.Pointer(true),
-				OrderBy:        helper.Pointer("block_timestamp,asc"),
-				MinTimestampMs: helper.Pointer(startWatcher),
-				Fingerprint:    fingerprint,
-			})
-			if err != nil {
-				logger.WithError(err).Warn("failed get confirmed transaction")
-				goto subscribe
-			}
-
-			for _, data := range trx.Data {
-				var amount *int64
-				var to *string
-				var from *string
-				if data.RawData != nil && len(data.RawData.Contract) > 0 {
-					amount = data.RawData.Contract[0].Parameter.Value.Amount
-					to = helper.ToTrxAddress(*data.RawData.Contract[0].Parameter.Value.ToAddress)
-					from = helper.ToTrxAddress(*data.RawData.Contract[0].Parameter.Value.OwnerAddress)
-				}
-
-				_, err := w.trxTransactionRepo.Upsert(ctx, &model.Transaction{
-					Id:              data.TxID,
-					SenderAddress:   []string{*from},
-					ReceiverAddress: []string{*to},
-					Amount:          amount,
-					ReceivedAt:      helper.Pointer(time.UnixMilli(*data.BlockTimestamp)),
-					Fee:             data.NetFee,
-					Block:           data.BlockNumber,
-					Status:          helper.Pointer("success"),
-				})
-				if err != nil {
-					logger.WithError(err).Warn("failed upsert trx transaction")
-					goto subscribe
-				}
-
-				startWatcher = *data.BlockTimestamp + 1
-			}
-
-			if trx.Meta != nil && trx.Meta.Fingerprint != nil {
-				fingerprint = trx.Meta.Fingerprint
-				goto subscribe
-			}
-
-			time.Sleep(1 * time.Minute)
-		}
+// recordDeposit announces the deposit, a deposit already in a block is stored right away while a pending one is
+// tracked until it is final
+func (w *Watcher) recordDeposit(ctx context.Context, chain string, deposit *model.Transaction) error {
+	w.events.publish(ctx, model.NewTransactionEvent(model.EventDepositDetected, chain, deposit, deposit.ReceiverAddress))
+
+	if deposit.Block == nil {
+		return w.usecaseTransaction.TrackTransaction(ctx, chain, deposit)
 	}
 
+	w.confirmationPolicy.Apply(chain, deposit)
+	_, err := w.transactionRepo(chain).Upsert(ctx, deposit)
+	return err
 }
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/aalexanderkevin/crypto-wallet/config"
 	"github.com/aalexanderkevin/crypto-wallet/container"
@@ -9,29 +10,76 @@ import (
 	"github.com/aalexanderkevin/crypto-wallet/model"
 	"github.com/aalexanderkevin/crypto-wallet/repository"
 	"github.com/aalexanderkevin/crypto-wallet/service"
+	"golang.org/x/exp/slices"
 )
 
 type Webhook struct {
 	config config.Config
-	service.Bitcoin
-	repository.Transaction
+
+	utxo            func(chain string) service.Bitcoin
+	transactionRepo func(chain string) repository.Transaction
+
+	events             eventPublisher
+	confirmationPolicy service.ConfirmationPolicy
 }
 
 func NewWebhook(c *container.Container) *Webhook {
 	return &Webhook{
-		config:      c.Config(),
-		Bitcoin:     c.Bitcoin(),
-		Transaction: c.TransactionBtcRepo(),
+		config:             c.Config(),
+		utxo:               c.Utxo,
+		transactionRepo:    c.TransactionRepo,
+		events:             newEventPublisher(c),
+		confirmationPolicy: c.ConfirmationPolicy(),
 	}
 }
 
-func (w Webhook) UpsertBitcoinTransaction(ctx context.Context, trx *model.Transaction) (err error) {
-	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Webhook.UpsertBitcoinTransaction")
+// UpsertUtxoTransaction stores the tx of the UTXO coin a callback points to, re-fetched from the chain so that
+// neither the confirmations nor the amounts of the callback body are trusted
+func (w Webhook) UpsertUtxoTransaction(ctx context.Context, chain string, hash *string) (err error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.Webhook.UpsertUtxoTransaction").WithField("chain", chain)
+
+	utxo := w.utxo(chain)
+	transactionRepo := w.transactionRepo(chain)
+	if utxo == nil || transactionRepo == nil {
+		return model.NewParameterError(helper.Pointer(fmt.Sprintf("unsupported coin %s", chain)))
+	}
+
+	tx, err := utxo.GetTx(ctx, *hash)
+	if err != nil {
+		logger.WithError(err).Warn("Failed get tx")
+		return err
+	}
+	trx := model.Transaction{}.FromModel(*tx)
+	w.confirmationPolicy.Apply(chain, trx)
+
+	existing, err := transactionRepo.Get(ctx, &repository.TransactionGetFilter{Id: trx.Id})
+	if err != nil && !model.IsNotFoundError(err) {
+		logger.WithError(err).Warn("Failed get existing transaction")
+		return err
+	}
 
-	if _, err = w.Transaction.Upsert(ctx, trx); err != nil {
+	if _, err = transactionRepo.Upsert(ctx, trx); err != nil {
 		logger.WithError(err).Warn("Failed upsert")
 		return err
 	}
 
+	// the outputs also hold the change of the sender, so the deposit goes only to the other outputs
+	deposits := []string{}
+	for _, address := range trx.ReceiverAddress {
+		if !slices.Contains(trx.SenderAddress, address) {
+			deposits = append(deposits, address)
+		}
+	}
+
+	if existing == nil {
+		w.events.publish(ctx, model.NewTransactionEvent(model.EventDepositDetected, chain, trx, deposits))
+	} else if !helper.EqualPointerValue(existing.Confirmation, trx.Confirmation) {
+		w.events.publish(ctx, model.NewTransactionEvent(model.EventConfirmationChanged, chain, trx, append(deposits, trx.SenderAddress...)))
+	}
+
+	if helper.Val(trx.Status) == model.TransactionStatusSuccess && (existing == nil || helper.Val(existing.Status) != model.TransactionStatusSuccess) {
+		w.events.publish(ctx, model.NewTransactionEvent(model.EventSendConfirmed, chain, trx, trx.SenderAddress))
+	}
+
 	return nil
 }
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+)
+
+// WithdrawalAddress manages the withdrawal allowlist of the users, once the allowlist is on the transfers may only go
+// to the allowlisted addresses whose cooling-off period is over
+type WithdrawalAddress struct {
+	config config.Config
+	repository.WithdrawalAddress
+
+	userRepo repository.User
+	chains   service.ChainRegistry
+}
+
+func NewWithdrawalAddress(c *container.Container) *WithdrawalAddress {
+	return &WithdrawalAddress{
+		config:            c.Config(),
+		WithdrawalAddress: c.WithdrawalAddressRepo(),
+		userRepo:          c.UserRepo(),
+		chains:            c.ChainRegistry(),
+	}
+}
+
+func (w WithdrawalAddress) coolingOff() time.Duration {
+	return time.Duration(w.config.Allowlist.CoolingOffSeconds) * time.Second
+}
+
+// Add allowlists the address of the chain for the user, it is usable once the cooling-off period is over
+func (w WithdrawalAddress) Add(ctx context.Context, address *model.WithdrawalAddress) (*model.WithdrawalAddress, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.WithdrawalAddress.Add")
+
+	adapter, err := w.chains.Get(helper.Val(address.Chain))
+	if err != nil {
+		return nil, err
+	}
+	if err := adapter.ValidateAddress(helper.Val(address.Address)); err != nil {
+		return nil, model.NewParameterError(helper.Pointer(fmt.Sprintf("invalid %s address", adapter.Chain())))
+	}
+
+	now := time.Now()
+	address.Id = nil
+	address.Chain = helper.Pointer(adapter.Chain())
+	address.Address = helper.Pointer(model.NormalizeWithdrawalAddress(*address.Address))
+	address.UsableAt = helper.Pointer(now.Add(w.coolingOff()))
+	address.CreatedAt = &now
+
+	address, err = w.WithdrawalAddress.Add(ctx, address)
+	if err != nil {
+		logger.WithError(err).Warn("failed insert withdrawal address")
+		return nil, err
+	}
+
+	return address, nil
+}
+
+// SetAllowlist switches the allowlist of the user on right away, switching it off takes effect after the
+// cooling-off period so a stolen session cannot lift it to drain the wallet
+func (w WithdrawalAddress) SetAllowlist(ctx context.Context, userId *string, enabled bool) (*model.User, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.WithdrawalAddress.SetAllowlist")
+
+	user, err := w.userRepo.Get(ctx, &repository.UserGetFilter{Id: userId})
+	if err != nil {
+		logger.WithError(err).Warn("failed get user")
+		return nil, err
+	}
+
+	now := time.Now()
+	switch {
+	case enabled:
+		if user.AllowlistEnabledAt == nil || !user.AllowlistEnforced(now) {
+			user.AllowlistEnabledAt = &now
+		}
+		user.AllowlistDisabledAt = nil
+	case user.AllowlistEnabledAt == nil:
+		return user, nil
+	case user.AllowlistDisabledAt == nil:
+		user.AllowlistDisabledAt = helper.Pointer(now.Add(w.coolingOff()))
+	default:
+		// a pending switch off keeps its time, asking again does not postpone it
+		return user, nil
+	}
+
+	if err := w.userRepo.SetAllowlist(ctx, *user.Id, user.AllowlistEnabledAt, user.AllowlistDisabledAt); err != nil {
+		logger.WithError(err).Warn("failed update allowlist")
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// CheckSend refuses a transfer of the chain to an address that is not allowlisted or still cooling off when the
+// allowlist of the user is on
+func (w WithdrawalAddress) CheckSend(ctx context.Context, userId *string, chain string, receiver string) error {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.WithdrawalAddress.CheckSend")
+
+	user, err := w.userRepo.Get(ctx, &repository.UserGetFilter{Id: userId})
+	if err != nil {
+		logger.WithError(err).Warn("failed get user")
+		return err
+	}
+
+	now := time.Now()
+	if !user.AllowlistEnforced(now) {
+		return nil
+	}
+
+	address, err := w.WithdrawalAddress.Get(ctx, &repository.WithdrawalAddressFilter{
+		UserId:  userId,
+		Chain:   &chain,
+		Address: helper.Pointer(model.NormalizeWithdrawalAddress(receiver)),
+	})
+	if model.IsNotFoundError(err) {
+		return model.NewFailedPreconditionError(helper.Pointer("receiver address is not in the withdrawal allowlist"))
+	}
+	if err != nil {
+		logger.WithError(err).Warn("failed get withdrawal address")
+		return err
+	}
+
+	if !address.Usable(now) {
+		return model.NewFailedPreconditionError(helper.Pointer(fmt.Sprintf("receiver address is usable from %s", address.UsableAt.UTC().Format(time.RFC3339))))
+	}
+
+	return nil
+}
+
+// ListAddresses returns the addresses of the user, of the chain when it is set, with the user to read the allowlist
+// state from
+func (w WithdrawalAddress) ListAddresses(ctx context.Context, userId *string, chain *string) ([]model.WithdrawalAddress, *model.User, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.WithdrawalAddress.ListAddresses")
+
+	user, err := w.userRepo.Get(ctx, &repository.UserGetFilter{Id: userId})
+	if err != nil {
+		logger.WithError(err).Warn("failed get user")
+		return nil, nil, err
+	}
+
+	filter := &repository.WithdrawalAddressFilter{UserId: userId}
+	if chain != nil {
+		adapter, err := w.chains.Get(*chain)
+		if err != nil {
+			return nil, nil, err
+		}
+		filter.Chain = helper.Pointer(adapter.Chain())
+	}
+
+	addresses, err := w.WithdrawalAddress.List(ctx, filter)
+	if err != nil {
+		logger.WithError(err).Warn("failed list withdrawal addresses")
+		return nil, nil, err
+	}
+
+	return addresses, user, nil
+}
@@ -0,0 +1,436 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aalexanderkevin/crypto-wallet/config"
+	"github.com/aalexanderkevin/crypto-wallet/container"
+	"github.com/aalexanderkevin/crypto-wallet/helper"
+	"github.com/aalexanderkevin/crypto-wallet/model"
+	"github.com/aalexanderkevin/crypto-wallet/repository"
+	"github.com/aalexanderkevin/crypto-wallet/service"
+)
+
+// WithdrawalRequest runs the transfers the approval policy of a wallet covers, such a transfer is requested, waits for
+// the quorum of the approvers and is only signed once it is approved
+type WithdrawalRequest struct {
+	config config.Config
+	repository.WithdrawalRequest
+
+	approvalPolicyRepo repository.ApprovalPolicy
+	walletRepo         repository.Wallet
+	spendingLimitRepo  repository.SpendingLimit
+	userRepo           repository.User
+	chains             service.ChainRegistry
+	transaction        *Transaction
+	totp               *Totp
+	withdrawalAddress  *WithdrawalAddress
+	audit              *Audit
+}
+
+func NewWithdrawalRequest(c *container.Container) *WithdrawalRequest {
+	return &WithdrawalRequest{
+		config:             c.Config(),
+		WithdrawalRequest:  c.WithdrawalRequestRepo(),
+		approvalPolicyRepo: c.ApprovalPolicyRepo(),
+		walletRepo:         c.WalletRepo(),
+		spendingLimitRepo:  c.SpendingLimitRepo(),
+		userRepo:           c.UserRepo(),
+		chains:             c.ChainRegistry(),
+		transaction:        NewTransaction(c),
+		totp:               NewTotp(c),
+		withdrawalAddress:  NewWithdrawalAddress(c),
+		audit:              NewAudit(c),
+	}
+}
+
+// SetPolicy stores the approval policy of the wallet on the chain, the approvers must be distinct users allowed to
+// approve withdrawals and enough of them to reach the quorum
+func (w WithdrawalRequest) SetPolicy(ctx context.Context, policy *model.ApprovalPolicy) (*model.ApprovalPolicy, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.WithdrawalRequest.SetPolicy")
+
+	adapter, err := w.chains.Get(helper.Val(policy.Chain))
+	if err != nil {
+		return nil, err
+	}
+	policy.Chain = helper.Pointer(adapter.Chain())
+
+	if helper.Val(policy.Threshold) < 0 {
+		return nil, model.NewParameterError(helper.Pointer("threshold cannot be negative"))
+	}
+	if helper.Val(policy.RequiredApprovals) < 1 {
+		return nil, model.NewParameterError(helper.Pointer("at least one approval is required"))
+	}
+
+	seen := map[string]bool{}
+	for _, approverId := range policy.ApproverIds {
+		if seen[approverId] {
+			return nil, model.NewParameterError(helper.Pointer("approvers must be distinct"))
+		}
+		seen[approverId] = true
+	}
+	if len(policy.ApproverIds) < *policy.RequiredApprovals {
+		return nil, model.NewParameterError(helper.Pointer("there are fewer approvers than required approvals"))
+	}
+
+	wallet, err := w.walletRepo.Get(ctx, &repository.WalletGetFilter{Id: policy.WalletId}, nil)
+	if err != nil {
+		logger.WithError(err).Warn("failed get wallet")
+		return nil, err
+	}
+
+	for _, approverId := range policy.ApproverIds {
+		approverId := approverId
+		// the owner of the wallet cannot approve its own transfers
+		if wallet.UserId != nil && *wallet.UserId == approverId {
+			return nil, model.NewParameterError(helper.Pointer("the owner of the wallet cannot be an approver"))
+		}
+		approver, err := w.userRepo.Get(ctx, &repository.UserGetFilter{Id: &approverId})
+		if err != nil {
+			logger.WithError(err).Warn("failed get approver")
+			if model.IsNotFoundError(err) {
+				return nil, model.NewParameterError(helper.Pointer(fmt.Sprintf("unknown approver %s", approverId)))
+			}
+			return nil, err
+		}
+		if !approver.HasScope(model.ScopeWithdrawalApprove) {
+			return nil, model.NewParameterError(helper.Pointer(fmt.Sprintf("approver %s is not allowed to approve withdrawals", approverId)))
+		}
+	}
+
+	policy, err = w.approvalPolicyRepo.Upsert(ctx, policy)
+	if err != nil {
+		logger.WithError(err).Warn("failed upsert approval policy")
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// Request records a transfer the approval policy of the wallet of the user covers, the same checks as a direct
+// transfer run before it is recorded. The quorum and the approvers of the policy are copied into the request
+func (w WithdrawalRequest) Request(ctx context.Context, reqSend *model.SendToken) (*model.WithdrawalRequest, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.WithdrawalRequest.Request")
+
+	adapter, err := w.chains.Get(*reqSend.Token)
+	if err != nil {
+		return nil, err
+	}
+	logger = logger.WithField("chain", adapter.Chain())
+
+	if err := adapter.ValidateAddress(*reqSend.ReceiverAddress); err != nil {
+		return nil, model.NewParameterError(helper.Pointer(fmt.Sprintf("invalid receiver %s address", adapter.Chain())))
+	}
+
+	wallet, err := w.walletRepo.Get(ctx, &repository.WalletGetFilter{UserId: reqSend.UserId}, nil)
+	if err != nil {
+		logger.WithError(err).Warn("failed get wallet")
+		return nil, err
+	}
+
+	policy, err := w.approvalPolicyRepo.Get(ctx, *wallet.Id, adapter.Chain())
+	if model.IsNotFoundError(err) || (err == nil && !policy.Requires(*reqSend.Amount)) {
+		return nil, model.NewFailedPreconditionError(helper.Pointer("transfer does not need approvals, send it directly"))
+	}
+	if err != nil {
+		logger.WithError(err).Warn("failed get approval policy")
+		return nil, err
+	}
+
+	if err := w.withdrawalAddress.CheckSend(ctx, reqSend.UserId, adapter.Chain(), *reqSend.ReceiverAddress); err != nil {
+		return nil, err
+	}
+	if err := w.totp.CheckSend(ctx, reqSend.UserId, adapter.Chain(), *reqSend.Amount, reqSend.Otp); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	request, err := w.WithdrawalRequest.Add(ctx, &model.WithdrawalRequest{
+		WalletId:          wallet.Id,
+		UserId:            reqSend.UserId,
+		Chain:             helper.Pointer(adapter.Chain()),
+		ReceiverAddress:   reqSend.ReceiverAddress,
+		Amount:            reqSend.Amount,
+		Status:            helper.Pointer(model.WithdrawalRequested),
+		RequiredApprovals: policy.RequiredApprovals,
+		ApproverIds:       policy.ApproverIds,
+		ExpiresAt:         helper.Pointer(now.Add(time.Duration(w.config.Withdrawal.ExpirySeconds) * time.Second)),
+		CreatedAt:         &now,
+		UpdatedAt:         &now,
+	})
+	if err != nil {
+		logger.WithError(err).Warn("failed insert withdrawal request")
+		return nil, err
+	}
+
+	_ = w.audit.Record(ctx, model.AuditWithdrawalRequested, nil, request.Id, map[string]string{
+		"chain":    *request.Chain,
+		"receiver": *request.ReceiverAddress,
+		"amount":   strconv.FormatInt(*request.Amount, 10),
+	})
+
+	return request, nil
+}
+
+// Approve records the approval of the approver on the request, the request is signed and broadcast by the approval
+// that meets the quorum
+func (w WithdrawalRequest) Approve(ctx context.Context, approverId string, id string) (*model.WithdrawalRequest, error) {
+	request, err := w.decide(ctx, approverId, id, model.DecisionApproved, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if *request.Status == model.WithdrawalApproved {
+		return w.execute(ctx, request)
+	}
+
+	return request, nil
+}
+
+// Reject records the rejection of the approver on the request, a single rejection rejects it
+func (w WithdrawalRequest) Reject(ctx context.Context, approverId string, id string, reason *string) (*model.WithdrawalRequest, error) {
+	return w.decide(ctx, approverId, id, model.DecisionRejected, reason)
+}
+
+func (w WithdrawalRequest) decide(ctx context.Context, approverId string, id string, decision string, reason *string) (*model.WithdrawalRequest, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.WithdrawalRequest.decide")
+
+	now := time.Now()
+	request, err := w.WithdrawalRequest.Decide(ctx, id, func(request *model.WithdrawalRequest) (*model.WithdrawalApproval, string, error) {
+		if !request.IsApprover(approverId) {
+			return nil, "", model.NewPermissionDeniedError(helper.Pointer("not an approver of the withdrawal request"))
+		}
+		if *request.Status != model.WithdrawalRequested {
+			return nil, "", model.NewFailedPreconditionError(helper.Pointer(fmt.Sprintf("withdrawal request is %s", *request.Status)))
+		}
+		if !request.ExpiresAt.After(now) {
+			return nil, model.WithdrawalExpired, nil
+		}
+
+		approval := &model.WithdrawalApproval{
+			ApproverId: &approverId,
+			Decision:   &decision,
+			Reason:     reason,
+		}
+		if decision == model.DecisionRejected {
+			return approval, model.WithdrawalRejected, nil
+		}
+		if request.ApprovalCount()+1 >= helper.Val(request.RequiredApprovals) {
+			return approval, model.WithdrawalApproved, nil
+		}
+
+		return approval, "", nil
+	})
+	if model.IsDuplicateError(err) {
+		return nil, model.NewFailedPreconditionError(helper.Pointer("already decided on the withdrawal request"))
+	}
+	if err != nil {
+		logger.WithError(err).Warn("failed decide on withdrawal request")
+		return nil, err
+	}
+
+	if *request.Status == model.WithdrawalExpired {
+		return nil, model.NewFailedPreconditionError(helper.Pointer("withdrawal request expired"))
+	}
+
+	action := model.AuditWithdrawalApproved
+	if decision == model.DecisionRejected {
+		action = model.AuditWithdrawalRejected
+	}
+	_ = w.audit.Record(ctx, action, &approverId, &id, map[string]string{
+		"status": *request.Status,
+		"reason": helper.Val(reason),
+	})
+
+	return request, nil
+}
+
+// execute signs and broadcasts the approved request, the transition to signing makes a single caller sign it
+func (w WithdrawalRequest) execute(ctx context.Context, request *model.WithdrawalRequest) (*model.WithdrawalRequest, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.WithdrawalRequest.execute").WithField("withdrawal_request_id", *request.Id)
+
+	ok, err := w.WithdrawalRequest.Transition(ctx, *request.Id, model.WithdrawalApproved, model.WithdrawalSigning, nil)
+	if err != nil {
+		logger.WithError(err).Warn("failed move withdrawal request to signing")
+		return nil, err
+	}
+	if !ok {
+		return request, nil
+	}
+	request.Status = helper.Pointer(model.WithdrawalSigning)
+
+	adapter, err := w.chains.Get(*request.Chain)
+	if err == nil {
+		request.TransactionId, err = w.transaction.sign(ctx, adapter, &model.SendToken{
+			UserId:          request.UserId,
+			Token:           request.Chain,
+			ReceiverAddress: request.ReceiverAddress,
+			Amount:          request.Amount,
+		}, request.Id)
+	}
+	if err != nil {
+		logger.WithError(err).Warn("failed send withdrawal")
+		request.Status = helper.Pointer(model.WithdrawalFailed)
+		request.FailureReason = helper.Pointer(err.Error())
+		if _, err := w.WithdrawalRequest.Transition(ctx, *request.Id, model.WithdrawalSigning, model.WithdrawalFailed, request); err != nil {
+			logger.WithError(err).Warn("failed move withdrawal request to failed")
+		}
+		return request, nil
+	}
+
+	request.Status = helper.Pointer(model.WithdrawalBroadcast)
+	if _, err := w.WithdrawalRequest.Transition(ctx, *request.Id, model.WithdrawalSigning, model.WithdrawalBroadcast, request); err != nil {
+		logger.WithError(err).Warn("failed move withdrawal request to broadcast")
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// GetRequest returns the request to its requester or one of its approvers
+func (w WithdrawalRequest) GetRequest(ctx context.Context, userId string, id string) (*model.WithdrawalRequest, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.WithdrawalRequest.GetRequest")
+
+	request, err := w.WithdrawalRequest.Get(ctx, id)
+	if err != nil {
+		logger.WithError(err).Warn("failed get withdrawal request")
+		return nil, err
+	}
+
+	// the requests of the other users are not disclosed
+	if helper.Val(request.UserId) != userId && !request.IsApprover(userId) {
+		return nil, model.NewNotFoundError()
+	}
+
+	return request, nil
+}
+
+// ListPending returns the requests waiting for a decision of the approver
+func (w WithdrawalRequest) ListPending(ctx context.Context, approverId string) ([]model.WithdrawalRequest, error) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.WithdrawalRequest.ListPending")
+
+	requests, err := w.WithdrawalRequest.ListPending(ctx, approverId, time.Now())
+	if err != nil {
+		logger.WithError(err).Warn("failed list pending withdrawal requests")
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+// RunExpiry expires the stale requests, executes the approved ones that were not executed yet and settles the ones left
+// in signing until the context is done, an approved request is left approved when the process that approved it stopped
+// before signing it and in signing when it stopped while sending it
+func (w WithdrawalRequest) RunExpiry(ctx context.Context) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.WithdrawalRequest.RunExpiry")
+	interval := time.Duration(w.config.Withdrawal.ExpireIntervalSeconds) * time.Second
+
+	for {
+		expired, err := w.WithdrawalRequest.ExpireStale(ctx, time.Now())
+		if err != nil {
+			logger.WithError(err).Warn("failed expire withdrawal requests")
+		} else if expired > 0 {
+			logger.WithField("expired", expired).Info("withdrawal requests expired")
+		}
+		w.resumeApproved(ctx)
+		w.recoverSigning(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// resumeApproved executes the approved requests that were not moved to signing, the transition to signing keeps a
+// request the approver is executing at the same time from being signed twice
+func (w WithdrawalRequest) resumeApproved(ctx context.Context) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.WithdrawalRequest.resumeApproved")
+
+	requests, err := w.WithdrawalRequest.ListApproved(ctx)
+	if err != nil {
+		logger.WithError(err).Warn("failed list approved withdrawal requests")
+		return
+	}
+
+	for _, request := range requests {
+		request := request
+		if _, err := w.execute(ctx, &request); err != nil {
+			logger.WithError(err).WithField("withdrawal_request_id", *request.Id).Warn("failed execute approved withdrawal request")
+		}
+	}
+}
+
+// recoverSigning settles the requests left in signing for longer than the signing timeout, the process that sent them
+// stopped before it recorded how the send went
+func (w WithdrawalRequest) recoverSigning(ctx context.Context) {
+	logger := helper.GetLogger(ctx).WithField("method", "Usecase.WithdrawalRequest.recoverSigning")
+	signingTimeout := time.Duration(w.config.Withdrawal.SigningTimeoutSeconds) * time.Second
+
+	requests, err := w.WithdrawalRequest.ListStaleSigning(ctx, time.Now().Add(-signingTimeout))
+	if err != nil {
+		logger.WithError(err).Warn("failed list stale signing withdrawal requests")
+		return
+	}
+
+	for _, request := range requests {
+		request := request
+		if err := w.recover(ctx, &request); err != nil {
+			logger.WithError(err).WithField("withdrawal_request_id", *request.Id).Warn("failed recover signing withdrawal request")
+		}
+	}
+}
+
+// recover settles the request from its reservation. A request without a reservation was not sent, and a request whose
+// reservation is linked to a transaction was broadcast. A reservation without a transaction is of a send whose outcome
+// is unknown, the request fails and the reservation is kept so the amount stays counted against the limits
+func (w WithdrawalRequest) recover(ctx context.Context, request *model.WithdrawalRequest) error {
+	spending, err := w.spendingLimitRepo.GetByWithdrawalRequest(ctx, *request.Id)
+	if err != nil && !model.IsNotFoundError(err) {
+		return err
+	}
+
+	if spending == nil || spending.TransactionId == nil {
+		request.Status = helper.Pointer(model.WithdrawalFailed)
+		request.FailureReason = helper.Pointer("the withdrawal was not sent")
+		if spending != nil {
+			request.FailureReason = helper.Pointer("the outcome of the withdrawal is unknown, check the chain before requesting it again")
+		}
+		_, err := w.WithdrawalRequest.Transition(ctx, *request.Id, model.WithdrawalSigning, model.WithdrawalFailed, request)
+		return err
+	}
+
+	request.Status = helper.Pointer(model.WithdrawalBroadcast)
+	request.TransactionId = spending.TransactionId
+	ok, err := w.WithdrawalRequest.Transition(ctx, *request.Id, model.WithdrawalSigning, model.WithdrawalBroadcast, request)
+	if err != nil || !ok {
+		return err
+	}
+
+	// the process may have stopped before the transaction was stored, the worker then follows it from the chain
+	_, err = w.transaction.transactionRepo(*request.Chain).Get(ctx, &repository.TransactionGetFilter{Id: request.TransactionId})
+	if !model.IsNotFoundError(err) {
+		return err
+	}
+
+	adapter, err := w.chains.Get(*request.Chain)
+	if err != nil {
+		return err
+	}
+	wallet, err := w.walletRepo.Get(ctx, &repository.WalletGetFilter{Id: request.WalletId}, nil)
+	if err != nil {
+		return err
+	}
+
+	return w.transaction.TrackTransaction(ctx, adapter.Chain(), &model.Transaction{
+		Id:              request.TransactionId,
+		SenderAddress:   []string{helper.Val(adapter.Address(wallet))},
+		ReceiverAddress: []string{*request.ReceiverAddress},
+		Amount:          request.Amount,
+		Status:          helper.Pointer(model.TransactionStatusPending),
+	})
+}